@@ -7,9 +7,11 @@ const (
 	LabelCacheEnabled       = "cache.kyverno.io/enabled"
 	LabelCertManagedBy      = "cert.kyverno.io/managed-by"
 	LabelCleanupTtl         = "cleanup.kyverno.io/ttl"
+	LabelCleanupArchive     = "cleanup.kyverno.io/archive"
 	LabelWebhookManagedBy   = "webhook.kyverno.io/managed-by"
 	LabelExcludeReporting   = "reports.kyverno.io/disabled"
 	LabelEnableVAPReporting = "reports.kyverno.io/enabled"
+	LabelPolicySource       = "kyverno.io/policy-source"
 	// Well known annotations
 	AnnotationAutogenControllers       = "pod-policies.kyverno.io/autogen-controllers"
 	AnnotationImageVerify              = "kyverno.io/verify-images"
@@ -228,6 +228,15 @@ type ContextAPICall struct {
 	// of deployments across all namespaces.
 	// +kubebuilder:validation:Optional
 	JMESPath string `json:"jmesPath,omitempty"`
+
+	// CacheTTL is the maximum duration a successful service call response is cached
+	// and reused across admission requests, when the response cache is enabled.
+	// The Cache-Control and Expires headers of the response can further restrict caching.
+	// Responses are not cached if unset.
+	// +kubebuilder:validation:Format=duration
+	// +kubebuilder:validation:Optional
+	// +optional
+	CacheTTL *metav1.Duration `json:"cacheTTL,omitempty"`
 }
 
 type GlobalContextEntryReference struct {
@@ -242,6 +251,14 @@ type GlobalContextEntryReference struct {
 	// of deployments across all namespaces.
 	// +kubebuilder:validation:Optional
 	JMESPath string `json:"jmesPath,omitempty"`
+
+	// MaxAge is the maximum age of the global context entry data.
+	// If the entry was not refreshed successfully within this duration, loading it fails
+	// and the rule is handled according to the policy failure policy.
+	// +kubebuilder:validation:Format=duration
+	// +kubebuilder:validation:Optional
+	// +optional
+	MaxAge *metav1.Duration `json:"maxAge,omitempty"`
 }
 
 type ServiceCall struct {
@@ -256,6 +273,18 @@ type ServiceCall struct {
 	// the server certificate.
 	// +kubebuilder:validation:Optional
 	CABundle string `json:"caBundle"`
+
+	// ClientCertSecret is the name of a kubernetes.io/tls Secret in the Kyverno namespace
+	// holding the client certificate and key presented for mutual TLS.
+	// The certificate is reloaded when the Secret changes.
+	// +kubebuilder:validation:Optional
+	ClientCertSecret string `json:"clientCertSecret,omitempty"`
+
+	// TokenAudience is the audience of a projected service account token sent as a bearer token.
+	// The token is read from a file named after the audience in the projected token directory
+	// and reloaded when the file is rotated. The Authorization header takes precedence if set.
+	// +kubebuilder:validation:Optional
+	TokenAudience string `json:"tokenAudience,omitempty"`
 }
 
 // Method is a HTTP request type.
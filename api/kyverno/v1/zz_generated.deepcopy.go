@@ -456,6 +456,11 @@ func (in *ContextAPICall) DeepCopyInto(out *ContextAPICall) {
 		*out = new(apiextensionsv1.JSON)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.CacheTTL != nil {
+		in, out := &in.CacheTTL, &out.CacheTTL
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 	return
 }
 
@@ -495,7 +500,7 @@ func (in *ContextEntry) DeepCopyInto(out *ContextEntry) {
 	if in.GlobalReference != nil {
 		in, out := &in.GlobalReference, &out.GlobalReference
 		*out = new(GlobalContextEntryReference)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	return
 }
@@ -724,6 +729,11 @@ func (in *Generation) DeepCopy() *Generation {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GlobalContextEntryReference) DeepCopyInto(out *GlobalContextEntryReference) {
 	*out = *in
+	if in.MaxAge != nil {
+		in, out := &in.MaxAge, &out.MaxAge
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 	return
 }
 
@@ -23,6 +23,7 @@ import (
 	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
 	datautils "github.com/kyverno/kyverno/pkg/utils/data"
 	"github.com/robfig/cron"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -229,12 +230,88 @@ type CleanupPolicySpec struct {
 	// +optional
 	// +kubebuilder:validation:Enum=Foreground;Background;Orphan
 	DeletionPropagationPolicy *metav1.DeletionPropagation `json:"deletionPropagationPolicy,omitempty"`
+
+	// DeletionBudget limits the resources deleted by the policy, a run is aborted when the budget is exceeded.
+	// +optional
+	DeletionBudget *DeletionBudget `json:"deletionBudget,omitempty"`
+
+	// DryRun performs the matching and the conditions evaluation without deleting the selected resources.
+	// The first 100 resources that would have been deleted are recorded in the policy status and in a policy report, an event summarizes each execution.
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
 }
 
+// DeletionBudget limits the resources deleted by a policy. Zero means no limit.
+type DeletionBudget struct {
+	// MaxDeletionsPerRun is the maximum number of resources deleted by a single run of the policy.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxDeletionsPerRun int32 `json:"maxDeletionsPerRun,omitempty"`
+
+	// MaxDeletionsPerMinute is the maximum number of resources deleted by the policy within a minute.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxDeletionsPerMinute int32 `json:"maxDeletionsPerMinute,omitempty"`
+
+	// MaxPercentage is the maximum percentage of the resources of a kind deleted by a single run of the policy.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	MaxPercentage int32 `json:"maxPercentage,omitempty"`
+}
+
+const (
+	// CleanupPolicyConditionBudgetExceeded reports whether the last run of the policy exceeded its deletion budget.
+	CleanupPolicyConditionBudgetExceeded = "BudgetExceeded"
+	// CleanupPolicyReasonBudgetExceeded means the last run of the policy was aborted because its deletion budget was exceeded.
+	CleanupPolicyReasonBudgetExceeded = "BudgetExceeded"
+	// CleanupPolicyReasonWithinBudget means the last run of the policy completed within its deletion budget.
+	CleanupPolicyReasonWithinBudget = "WithinBudget"
+)
+
 // CleanupPolicyStatus stores the status of the policy.
 type CleanupPolicyStatus struct {
 	Conditions        []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
 	LastExecutionTime metav1.Time        `json:"lastExecutionTime,omitempty"`
+
+	// DryRun contains the resources selected by the last dry run execution.
+	// +optional
+	DryRun *DryRunStatus `json:"dryRun,omitempty"`
+}
+
+// SetBudgetExceeded sets the condition reporting whether the last run of the policy was aborted
+// because its deletion budget was exceeded.
+func (status *CleanupPolicyStatus) SetBudgetExceeded(exceeded bool, message string) {
+	condition := metav1.Condition{
+		Type:    CleanupPolicyConditionBudgetExceeded,
+		Reason:  CleanupPolicyReasonWithinBudget,
+		Status:  metav1.ConditionFalse,
+		Message: message,
+	}
+	if exceeded {
+		condition.Reason = CleanupPolicyReasonBudgetExceeded
+		condition.Status = metav1.ConditionTrue
+	}
+	meta.SetStatusCondition(&status.Conditions, condition)
+}
+
+// DryRunStatus stores the resources that a dry run execution would have deleted.
+type DryRunStatus struct {
+	// Count is the number of resources that would have been deleted.
+	Count int `json:"count"`
+
+	// Resources lists the resources that would have been deleted, it is limited to the first 100 resources.
+	// +optional
+	Resources []DryRunResource `json:"resources,omitempty"`
+}
+
+// DryRunResource identifies a resource that a dry run execution would have deleted.
+type DryRunResource struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
 }
 
 // Validate implements programmatic validation
@@ -167,6 +167,11 @@ func (in *CleanupPolicySpec) DeepCopyInto(out *CleanupPolicySpec) {
 		*out = new(metav1.DeletionPropagation)
 		**out = **in
 	}
+	if in.DeletionBudget != nil {
+		in, out := &in.DeletionBudget, &out.DeletionBudget
+		*out = new(DeletionBudget)
+		**out = **in
+	}
 	return
 }
 
@@ -191,6 +196,11 @@ func (in *CleanupPolicyStatus) DeepCopyInto(out *CleanupPolicyStatus) {
 		}
 	}
 	in.LastExecutionTime.DeepCopyInto(&out.LastExecutionTime)
+	if in.DryRun != nil {
+		in, out := &in.DryRun, &out.DryRun
+		*out = new(DryRunStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -289,6 +299,59 @@ func (in *Condition) DeepCopy() *Condition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeletionBudget) DeepCopyInto(out *DeletionBudget) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeletionBudget.
+func (in *DeletionBudget) DeepCopy() *DeletionBudget {
+	if in == nil {
+		return nil
+	}
+	out := new(DeletionBudget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DryRunResource) DeepCopyInto(out *DryRunResource) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DryRunResource.
+func (in *DryRunResource) DeepCopy() *DryRunResource {
+	if in == nil {
+		return nil
+	}
+	out := new(DryRunResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DryRunStatus) DeepCopyInto(out *DryRunStatus) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]DryRunResource, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DryRunStatus.
+func (in *DryRunStatus) DeepCopy() *DryRunStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DryRunStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Exception) DeepCopyInto(out *Exception) {
 	*out = *in
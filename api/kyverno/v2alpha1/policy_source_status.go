@@ -0,0 +1,94 @@
+package v2alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// PolicySourceConditionReady means that the policysource was synced
+	PolicySourceConditionReady = "Ready"
+	// PolicySourceConditionDrifted means that synced policies were changed in the cluster since the previous sync
+	PolicySourceConditionDrifted = "Drifted"
+)
+
+const (
+	// PolicySourceReasonSucceeded is the reason set when the policysource was synced
+	PolicySourceReasonSucceeded = "Succeeded"
+	// PolicySourceReasonFailed is the reason set when the policysource failed to sync
+	PolicySourceReasonFailed = "Failed"
+	// PolicySourceReasonSuspended is the reason set when the policysource sync is suspended
+	PolicySourceReasonSuspended = "Suspended"
+	// PolicySourceReasonDrifted is the reason set when synced policies were changed in the cluster
+	PolicySourceReasonDrifted = "Drifted"
+	// PolicySourceReasonInSync is the reason set when synced policies match the source
+	PolicySourceReasonInSync = "InSync"
+)
+
+type PolicySourceStatus struct {
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// Revision is the digest of the OCI artifact or the hash of the Git commit last synced
+	// +optional
+	Revision string `json:"revision,omitempty"`
+	// Indicates the time when the policysource was last synced successfully
+	// +optional
+	LastSyncTime metav1.Time `json:"lastSyncTime,omitempty"`
+	// Policies are the policies synced from the source
+	// +optional
+	Policies []SyncedPolicy `json:"policies,omitempty"`
+}
+
+// SyncedPolicy describes a policy synced from a policysource
+type SyncedPolicy struct {
+	// APIVersion of the policy.
+	APIVersion string `json:"apiVersion"`
+	// Kind of the policy.
+	Kind string `json:"kind"`
+	// Namespace of the policy, empty for cluster wide policies.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// Name of the policy.
+	Name string `json:"name"`
+	// Generation is the generation of the policy when it was synced, a different generation means the policy was changed.
+	// +optional
+	Generation int64 `json:"generation,omitempty"`
+	// Drifted indicates the policy was changed in the cluster since the previous sync, the change was reverted.
+	// +optional
+	Drifted bool `json:"drifted,omitempty"`
+}
+
+func (status *PolicySourceStatus) SetReady(ready bool, reason, message string) {
+	condition := metav1.Condition{
+		Type:    PolicySourceConditionReady,
+		Reason:  reason,
+		Message: message,
+	}
+	if ready {
+		condition.Status = metav1.ConditionTrue
+	} else {
+		condition.Status = metav1.ConditionFalse
+	}
+	meta.SetStatusCondition(&status.Conditions, condition)
+}
+
+func (status *PolicySourceStatus) SetDrifted(drifted bool, message string) {
+	condition := metav1.Condition{
+		Type:    PolicySourceConditionDrifted,
+		Message: message,
+	}
+	if drifted {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = PolicySourceReasonDrifted
+	} else {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = PolicySourceReasonInSync
+	}
+	meta.SetStatusCondition(&status.Conditions, condition)
+}
+
+// IsReady indicates if the policysource was synced
+func (status *PolicySourceStatus) IsReady() bool {
+	condition := meta.FindStatusCondition(status.Conditions, PolicySourceConditionReady)
+	return condition != nil && condition.Status == metav1.ConditionTrue
+}
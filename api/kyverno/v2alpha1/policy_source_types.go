@@ -0,0 +1,202 @@
+/*
+Copyright 2022 The Kubernetes authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v2alpha1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:shortName=polsrc,categories=kyverno,scope="Cluster"
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="INTERVAL",type="string",JSONPath=".spec.interval"
+// +kubebuilder:printcolumn:name="REVISION",type="string",JSONPath=".status.revision"
+// +kubebuilder:printcolumn:name="LAST SYNC",type="date",JSONPath=".status.lastSyncTime"
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type == "Ready")].status"
+
+// PolicySource declares an OCI artifact or a Git repository policies are synced from.
+// Policies are applied with the privileges of Kyverno, in any namespace, and generating policies create resources
+// with them too: creating a policy source is as privileged as creating cluster wide policies and should be limited
+// to cluster administrators.
+type PolicySource struct {
+	metav1.TypeMeta   `json:",inline,omitempty"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec declares where policies are synced from and how.
+	Spec PolicySourceSpec `json:"spec"`
+
+	// Status contains policysource runtime data.
+	// +optional
+	Status PolicySourceStatus `json:"status,omitempty"`
+}
+
+// Validate implements programmatic validation
+func (s *PolicySource) Validate() (errs field.ErrorList) {
+	errs = append(errs, s.Spec.Validate(field.NewPath("spec"))...)
+	return errs
+}
+
+// PolicySourceSpec stores policysource spec
+// +kubebuilder:oneOf:={required:{oci}}
+// +kubebuilder:oneOf:={required:{git}}
+type PolicySourceSpec struct {
+	// OCI is an OCI artifact pushed with `kyverno oci push`.
+	// Mutually exclusive with Git.
+	// +kubebuilder:validation:Optional
+	OCI *OCIPolicySource `json:"oci,omitempty"`
+
+	// Git is a path in a Git repository.
+	// Mutually exclusive with OCI.
+	// +kubebuilder:validation:Optional
+	Git *GitPolicySource `json:"git,omitempty"`
+
+	// Interval defines the interval in duration at which the source is synced.
+	// The duration is a sequence of decimal numbers, each with optional fraction and a unit suffix,
+	// such as "300ms", "1.5h" or "2h45m". Valid time units are "ns", "us" (or "µs"), "ms", "s", "m", "h".
+	// Changes made in the cluster to the synced policies are only detected through their generation, and reverted, at each interval.
+	// +kubebuilder:validation:Format=duration
+	// +kubebuilder:default=`10m`
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// Prune deletes the policies synced from the source that were removed from it.
+	// +kubebuilder:default=true
+	// +optional
+	Prune *bool `json:"prune,omitempty"`
+
+	// Suspend stops syncing the source, synced policies are left as they are.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+}
+
+func (s *PolicySourceSpec) IsOCI() bool {
+	return s.OCI != nil
+}
+
+func (s *PolicySourceSpec) IsGit() bool {
+	return s.Git != nil
+}
+
+// GetInterval returns the interval at which the source is synced.
+func (s *PolicySourceSpec) GetInterval() time.Duration {
+	if s.Interval == nil || s.Interval.Duration <= 0 {
+		return 10 * time.Minute
+	}
+	return s.Interval.Duration
+}
+
+// PruneEnabled indicates if policies removed from the source are deleted.
+func (s *PolicySourceSpec) PruneEnabled() bool {
+	return s.Prune == nil || *s.Prune
+}
+
+// Validate implements programmatic validation
+func (s *PolicySourceSpec) Validate(path *field.Path) (errs field.ErrorList) {
+	if s.IsOCI() == s.IsGit() {
+		errs = append(errs, field.Forbidden(path.Child("oci"), "A policy source should either have OCI or Git"))
+	}
+	if s.IsOCI() {
+		errs = append(errs, s.OCI.Validate(path.Child("oci"))...)
+	}
+	if s.IsGit() {
+		errs = append(errs, s.Git.Validate(path.Child("git"))...)
+	}
+	if s.Interval != nil && s.Interval.Duration <= 0 {
+		errs = append(errs, field.Invalid(path.Child("interval"), s.Interval.Duration.String(), "A policy source requires an interval greater than 0 seconds"))
+	}
+	return errs
+}
+
+// OCIPolicySource stores infos about the OCI artifact policies are pulled from
+type OCIPolicySource struct {
+	// Image is the reference of the OCI artifact.
+	// +kubebuilder:validation:Required
+	Image string `json:"image"`
+
+	// ImagePullSecrets are the names of the secrets, in the Kyverno namespace, used to pull the artifact.
+	// +kubebuilder:validation:Optional
+	// +optional
+	ImagePullSecrets []string `json:"imagePullSecrets,omitempty"`
+
+	// PublicKey is the cosign public key the artifact signature is verified with.
+	// It can be a PEM encoded key or a key reference supported by cosign, like a KMS or a Kubernetes secret.
+	// The artifact isn't verified if it is not set.
+	// +kubebuilder:validation:Optional
+	// +optional
+	PublicKey string `json:"publicKey,omitempty"`
+}
+
+// Validate implements programmatic validation
+func (o *OCIPolicySource) Validate(path *field.Path) (errs field.ErrorList) {
+	if o.Image == "" {
+		errs = append(errs, field.Required(path.Child("image"), "An OCI policy source requires an image"))
+	}
+	return errs
+}
+
+// GitPolicySource stores infos about the Git repository policies are pulled from
+type GitPolicySource struct {
+	// URL is the URL of the Git repository.
+	// +kubebuilder:validation:Required
+	URL string `json:"url"`
+
+	// Branch is the branch policies are pulled from.
+	// +kubebuilder:default=main
+	// +optional
+	Branch string `json:"branch,omitempty"`
+
+	// Path is the directory of the repository policies are pulled from, the whole repository is used if it is not set.
+	// +kubebuilder:validation:Optional
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// SecretRef is the name of a secret, in the Kyverno namespace, holding the `username` and `password`
+	// used to clone the repository.
+	// +kubebuilder:validation:Optional
+	// +optional
+	SecretRef string `json:"secretRef,omitempty"`
+
+	// PublicKeys is an armored PGP key ring the signature of the last commit is verified with.
+	// The commit isn't verified if it is not set.
+	// +kubebuilder:validation:Optional
+	// +optional
+	PublicKeys string `json:"publicKeys,omitempty"`
+}
+
+// Validate implements programmatic validation
+func (g *GitPolicySource) Validate(path *field.Path) (errs field.ErrorList) {
+	if g.URL == "" {
+		errs = append(errs, field.Required(path.Child("url"), "A Git policy source requires a URL"))
+	}
+	return errs
+}
+
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PolicySourceList is a list of policy sources
+type PolicySourceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []PolicySource `json:"items"`
+}
@@ -0,0 +1,110 @@
+package v2alpha1
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestPolicySourceSpecValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    PolicySourceSpec
+		wantErr bool
+	}{
+		{
+			name: "valid OCI",
+			spec: PolicySourceSpec{
+				OCI: &OCIPolicySource{
+					Image: "ghcr.io/kyverno/policies:v1",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid Git",
+			spec: PolicySourceSpec{
+				Git: &GitPolicySource{
+					URL:    "https://github.com/kyverno/policies",
+					Branch: "main",
+					Path:   "best-practices",
+				},
+				Interval: &metav1.Duration{Duration: time.Hour},
+			},
+			wantErr: false,
+		},
+		{
+			name: "both OCI and Git",
+			spec: PolicySourceSpec{
+				OCI: &OCIPolicySource{
+					Image: "ghcr.io/kyverno/policies:v1",
+				},
+				Git: &GitPolicySource{
+					URL: "https://github.com/kyverno/policies",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "neither OCI nor Git",
+			spec:    PolicySourceSpec{},
+			wantErr: true,
+		},
+		{
+			name: "missing image",
+			spec: PolicySourceSpec{
+				OCI: &OCIPolicySource{},
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing URL",
+			spec: PolicySourceSpec{
+				Git: &GitPolicySource{
+					Path: "best-practices",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative interval",
+			spec: PolicySourceSpec{
+				OCI: &OCIPolicySource{
+					Image: "ghcr.io/kyverno/policies:v1",
+				},
+				Interval: &metav1.Duration{Duration: -time.Minute},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := tt.spec.Validate(field.NewPath("spec"))
+			if (len(errs) > 0) != tt.wantErr {
+				t.Errorf("PolicySourceSpec.Validate() error = %v, wantErr %v", errs, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPolicySourceSpecDefaults(t *testing.T) {
+	spec := PolicySourceSpec{}
+	if got := spec.GetInterval(); got != 10*time.Minute {
+		t.Errorf("PolicySourceSpec.GetInterval() = %v, want %v", got, 10*time.Minute)
+	}
+	if !spec.PruneEnabled() {
+		t.Errorf("PolicySourceSpec.PruneEnabled() = false, want true")
+	}
+	prune := false
+	spec.Prune = &prune
+	spec.Interval = &metav1.Duration{Duration: time.Minute}
+	if got := spec.GetInterval(); got != time.Minute {
+		t.Errorf("PolicySourceSpec.GetInterval() = %v, want %v", got, time.Minute)
+	}
+	if spec.PruneEnabled() {
+		t.Errorf("PolicySourceSpec.PruneEnabled() = true, want false")
+	}
+}
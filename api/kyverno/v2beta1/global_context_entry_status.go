@@ -17,6 +17,9 @@ const (
 	GlobalContextEntryReasonFailed = "Failed"
 )
 
+// maxRefreshErrors is the number of refresh errors kept in the globalcontextentry status
+const maxRefreshErrors = 5
+
 type GlobalContextEntryStatus struct {
 	// Deprecated in favor of Conditions
 	Ready *bool `json:"ready,omitempty"`
@@ -25,6 +28,17 @@ type GlobalContextEntryStatus struct {
 	// Indicates the time when the globalcontextentry was last refreshed successfully for the API Call
 	// +optional
 	LastRefreshTime metav1.Time `json:"lastRefreshTime,omitempty"`
+	// RefreshErrors contains the most recent refresh errors, the latest error comes first
+	// +optional
+	RefreshErrors []GlobalContextEntryRefreshError `json:"refreshErrors,omitempty"`
+}
+
+// GlobalContextEntryRefreshError describes a failed refresh of the globalcontextentry
+type GlobalContextEntryRefreshError struct {
+	// Time is the time when the refresh failed
+	Time metav1.Time `json:"time"`
+	// Message is the refresh error message
+	Message string `json:"message"`
 }
 
 func (status *GlobalContextEntryStatus) SetReady(ready bool, message string) {
@@ -47,6 +61,18 @@ func (status *GlobalContextEntryStatus) UpdateRefreshTime() {
 	status.LastRefreshTime = metav1.Now()
 }
 
+// AddRefreshError records a refresh error, only the most recent errors are kept
+func (status *GlobalContextEntryStatus) AddRefreshError(err error) {
+	refreshError := GlobalContextEntryRefreshError{
+		Time:    metav1.Now(),
+		Message: err.Error(),
+	}
+	status.RefreshErrors = append([]GlobalContextEntryRefreshError{refreshError}, status.RefreshErrors...)
+	if len(status.RefreshErrors) > maxRefreshErrors {
+		status.RefreshErrors = status.RefreshErrors[:maxRefreshErrors]
+	}
+}
+
 // IsReady indicates if the globalcontextentry has loaded
 func (status *GlobalContextEntryStatus) IsReady() bool {
 	condition := meta.FindStatusCondition(status.Conditions, GlobalContextEntryConditionReady)
@@ -162,6 +162,12 @@ type ExternalAPICall struct {
 	// +kubebuilder:validation:Optional
 	// +optional
 	RetryLimit int `json:"retryLimit,omitempty"`
+	// Watch defines a server-sent events stream notifying changes of the data.
+	// Each received event triggers an immediate refresh of the entry,
+	// polling with the RefreshInterval is used as a fallback.
+	// +kubebuilder:validation:Optional
+	// +optional
+	Watch *kyvernov1.ServiceCall `json:"watch,omitempty"`
 }
 
 // Validate implements programmatic validation
@@ -175,6 +181,9 @@ func (e *ExternalAPICall) Validate(path *field.Path) (errs field.ErrorList) {
 	if e.Data != nil && e.Method != "POST" {
 		errs = append(errs, field.Forbidden(path.Child("method"), "An External API call with data should have method as POST"))
 	}
+	if e.Watch != nil && e.Watch.URL == "" {
+		errs = append(errs, field.Required(path.Child("watch", "url"), "An External API call watch requires a URL"))
+	}
 	return errs
 }
 
@@ -224,6 +224,30 @@ func TestExternalAPICallValidate(t *testing.T) {
 
 			wantErr: true,
 		},
+		{
+			name: "valid watch",
+			apiCall: ExternalAPICall{
+				APICall: kyvernov1.APICall{
+					URLPath: "/api/v1/namespaces",
+				},
+				RefreshInterval: &metav1.Duration{Duration: 10 * time.Minute},
+				Watch: &kyvernov1.ServiceCall{
+					URL: "https://cmdb.example.com/events",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "watch without URL",
+			apiCall: ExternalAPICall{
+				APICall: kyvernov1.APICall{
+					URLPath: "/api/v1/namespaces",
+				},
+				RefreshInterval: &metav1.Duration{Duration: 10 * time.Minute},
+				Watch:           &kyvernov1.ServiceCall{},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -145,6 +145,11 @@ func (in *CleanupPolicySpec) DeepCopyInto(out *CleanupPolicySpec) {
 		*out = new(metav1.DeletionPropagation)
 		**out = **in
 	}
+	if in.DeletionBudget != nil {
+		in, out := &in.DeletionBudget, &out.DeletionBudget
+		*out = new(DeletionBudget)
+		**out = **in
+	}
 	return
 }
 
@@ -169,6 +174,11 @@ func (in *CleanupPolicyStatus) DeepCopyInto(out *CleanupPolicyStatus) {
 		}
 	}
 	in.LastExecutionTime.DeepCopyInto(&out.LastExecutionTime)
+	if in.DryRun != nil {
+		in, out := &in.DryRun, &out.DryRun
+		*out = new(DryRunStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -328,6 +338,22 @@ func (in *Condition) DeepCopy() *Condition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeletionBudget) DeepCopyInto(out *DeletionBudget) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeletionBudget.
+func (in *DeletionBudget) DeepCopy() *DeletionBudget {
+	if in == nil {
+		return nil
+	}
+	out := new(DeletionBudget)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Deny) DeepCopyInto(out *Deny) {
 	*out = *in
@@ -349,6 +375,43 @@ func (in *Deny) DeepCopy() *Deny {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DryRunResource) DeepCopyInto(out *DryRunResource) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DryRunResource.
+func (in *DryRunResource) DeepCopy() *DryRunResource {
+	if in == nil {
+		return nil
+	}
+	out := new(DryRunResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DryRunStatus) DeepCopyInto(out *DryRunStatus) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]DryRunResource, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DryRunStatus.
+func (in *DryRunStatus) DeepCopy() *DryRunStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DryRunStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Exception) DeepCopyInto(out *Exception) {
 	*out = *in
@@ -379,6 +442,11 @@ func (in *ExternalAPICall) DeepCopyInto(out *ExternalAPICall) {
 		*out = new(metav1.Duration)
 		**out = **in
 	}
+	if in.Watch != nil {
+		in, out := &in.Watch, &out.Watch
+		*out = new(v1.ServiceCall)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -469,6 +537,23 @@ func (in *GlobalContextEntryProjection) DeepCopy() *GlobalContextEntryProjection
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GlobalContextEntryRefreshError) DeepCopyInto(out *GlobalContextEntryRefreshError) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlobalContextEntryRefreshError.
+func (in *GlobalContextEntryRefreshError) DeepCopy() *GlobalContextEntryRefreshError {
+	if in == nil {
+		return nil
+	}
+	out := new(GlobalContextEntryRefreshError)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GlobalContextEntrySpec) DeepCopyInto(out *GlobalContextEntrySpec) {
 	*out = *in
@@ -516,6 +601,13 @@ func (in *GlobalContextEntryStatus) DeepCopyInto(out *GlobalContextEntryStatus)
 		}
 	}
 	in.LastRefreshTime.DeepCopyInto(&out.LastRefreshTime)
+	if in.RefreshErrors != nil {
+		in, out := &in.RefreshErrors, &out.RefreshErrors
+		*out = make([]GlobalContextEntryRefreshError, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -356,6 +356,11 @@ func (in *DeletingPolicySpec) DeepCopyInto(out *DeletingPolicySpec) {
 		*out = new(v1.DeletionPropagation)
 		**out = **in
 	}
+	if in.DeletionBudget != nil {
+		in, out := &in.DeletionBudget, &out.DeletionBudget
+		*out = new(DeletionBudget)
+		**out = **in
+	}
 	return
 }
 
@@ -374,6 +379,11 @@ func (in *DeletingPolicyStatus) DeepCopyInto(out *DeletingPolicyStatus) {
 	*out = *in
 	in.ConditionStatus.DeepCopyInto(&out.ConditionStatus)
 	in.LastExecutionTime.DeepCopyInto(&out.LastExecutionTime)
+	if in.DryRun != nil {
+		in, out := &in.DryRun, &out.DryRun
+		*out = new(DryRunStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -387,6 +397,59 @@ func (in *DeletingPolicyStatus) DeepCopy() *DeletingPolicyStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeletionBudget) DeepCopyInto(out *DeletionBudget) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeletionBudget.
+func (in *DeletionBudget) DeepCopy() *DeletionBudget {
+	if in == nil {
+		return nil
+	}
+	out := new(DeletionBudget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DryRunResource) DeepCopyInto(out *DryRunResource) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DryRunResource.
+func (in *DryRunResource) DeepCopy() *DryRunResource {
+	if in == nil {
+		return nil
+	}
+	out := new(DryRunResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DryRunStatus) DeepCopyInto(out *DryRunStatus) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]DryRunResource, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DryRunStatus.
+func (in *DryRunStatus) DeepCopy() *DryRunStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DryRunStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EvaluationConfiguration) DeepCopyInto(out *EvaluationConfiguration) {
 	*out = *in
@@ -1803,6 +1866,11 @@ func (in *ValidationConfiguration) DeepCopyInto(out *ValidationConfiguration) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.CacheTTL != nil {
+		in, out := &in.CacheTTL, &out.CacheTTL
+		*out = new(v1.Duration)
+		**out = **in
+	}
 	return
 }
 
@@ -13,6 +13,7 @@ const (
 	PolicyConditionTypeWebhookConfigured      PolicyConditionType = "WebhookConfigured"
 	PolicyConditionTypePolicyCached           PolicyConditionType = "PolicyCached"
 	PolicyConditionTypeRBACPermissionsGranted PolicyConditionType = "RBACPermissionsGranted"
+	PolicyConditionTypeBudgetExceeded         PolicyConditionType = "BudgetExceeded"
 )
 
 // ConditionStatus is the shared status across all policy types
@@ -5,6 +5,7 @@ import (
 
 	"github.com/aptible/supercronic/cronexpr"
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
@@ -76,6 +77,7 @@ type DeletingPolicyLike interface {
 	metav1.Object
 	runtime.Object
 	GetDeletingPolicySpec() *DeletingPolicySpec
+	GetDeletingPolicyStatus() *DeletingPolicyStatus
 	GetKind() string
 	GetExecutionTime() (*time.Time, error)
 	GetNextExecutionTime(time.Time) (*time.Time, error)
@@ -126,12 +128,79 @@ type DeletingPolicySpec struct {
 	// +optional
 	// +kubebuilder:validation:Enum=Foreground;Background;Orphan
 	DeletionPropagationPolicy *metav1.DeletionPropagation `json:"deletionPropagationPolicy,omitempty"`
+
+	// DeletionBudget limits the resources deleted by the policy, a run is aborted when the budget is exceeded.
+	// +optional
+	DeletionBudget *DeletionBudget `json:"deletionBudget,omitempty"`
+
+	// DryRun performs the matching and the conditions evaluation without deleting the selected resources.
+	// The first 100 resources that would have been deleted are recorded in the policy status and in a policy report, an event summarizes each execution.
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+// DeletionBudget limits the resources deleted by a policy. Zero means no limit.
+type DeletionBudget struct {
+	// MaxDeletionsPerRun is the maximum number of resources deleted by a single run of the policy.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxDeletionsPerRun int32 `json:"maxDeletionsPerRun,omitempty"`
+
+	// MaxDeletionsPerMinute is the maximum number of resources deleted by the policy within a minute.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxDeletionsPerMinute int32 `json:"maxDeletionsPerMinute,omitempty"`
+
+	// MaxPercentage is the maximum percentage of the resources of a kind deleted by a single run of the policy.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	MaxPercentage int32 `json:"maxPercentage,omitempty"`
 }
 
 type DeletingPolicyStatus struct {
 	// +optional
 	ConditionStatus   ConditionStatus `json:"conditionStatus,omitempty"`
 	LastExecutionTime metav1.Time     `json:"lastExecutionTime,omitempty"`
+
+	// DryRun contains the resources selected by the last dry run execution.
+	// +optional
+	DryRun *DryRunStatus `json:"dryRun,omitempty"`
+}
+
+// SetBudgetExceeded sets the condition reporting whether the last run of the policy was aborted
+// because its deletion budget was exceeded.
+func (status *DeletingPolicyStatus) SetBudgetExceeded(exceeded bool, message string) {
+	condition := metav1.Condition{
+		Type:    string(PolicyConditionTypeBudgetExceeded),
+		Reason:  "WithinBudget",
+		Status:  metav1.ConditionFalse,
+		Message: message,
+	}
+	if exceeded {
+		condition.Reason = "BudgetExceeded"
+		condition.Status = metav1.ConditionTrue
+	}
+	meta.SetStatusCondition(&status.ConditionStatus.Conditions, condition)
+}
+
+// DryRunStatus stores the resources that a dry run execution would have deleted.
+type DryRunStatus struct {
+	// Count is the number of resources that would have been deleted.
+	Count int `json:"count"`
+
+	// Resources lists the resources that would have been deleted, it is limited to the first 100 resources.
+	// +optional
+	Resources []DryRunResource `json:"resources,omitempty"`
+}
+
+// DryRunResource identifies a resource that a dry run execution would have deleted.
+type DryRunResource struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
 }
 
 // GetExecutionTime returns the execution time of the policy
@@ -155,6 +224,13 @@ func (p *DeletingPolicy) GetDeletingPolicySpec() *DeletingPolicySpec {
 	return &p.Spec
 }
 
+func (p *DeletingPolicy) GetDeletingPolicyStatus() *DeletingPolicyStatus {
+	if p == nil {
+		return nil
+	}
+	return &p.Status
+}
+
 // GetExecutionTime returns the execution time of the namespaced policy
 func (p *NamespacedDeletingPolicy) GetExecutionTime() (*time.Time, error) {
 	return computeDeletingPolicyExecutionTime(p.Spec.Schedule, p.Status.LastExecutionTime, p.GetCreationTimestamp().Time)
@@ -176,6 +252,13 @@ func (p *NamespacedDeletingPolicy) GetDeletingPolicySpec() *DeletingPolicySpec {
 	return &p.Spec
 }
 
+func (p *NamespacedDeletingPolicy) GetDeletingPolicyStatus() *DeletingPolicyStatus {
+	if p == nil {
+		return nil
+	}
+	return &p.Status
+}
+
 func computeDeletingPolicyExecutionTime(schedule string, lastExecution metav1.Time, creationTime time.Time) (*time.Time, error) {
 	referenceTime := creationTime
 	if !lastExecution.IsZero() {
@@ -383,6 +383,11 @@ type ValidationConfiguration struct {
 	// +kubebuilder:default=true
 	// +optional
 	Required *bool `json:"required,omitempty"`
+
+	// CacheTTL is the duration successful image signature verifications are cached for.
+	// Defaults to the image verify cache TTL configured in Kyverno, a zero duration disables caching.
+	// +optional
+	CacheTTL *metav1.Duration `json:"cacheTTL,omitempty"`
 }
 
 type ImageExtractor struct {
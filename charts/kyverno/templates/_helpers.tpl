@@ -86,6 +86,15 @@
     {{- $flags = append $flags (print "--exceptionNamespace=" .) -}}
   {{- end -}}
 {{- end -}}
+{{- with .imageVerifyCache -}}
+  {{- $flags = append $flags (print "--imageVerifyCacheBackend=" .backend) -}}
+  {{- if eq .backend "configmap" -}}
+    {{- $flags = append $flags (print "--imageVerifyCacheConfigMap=" .configMapName) -}}
+  {{- end -}}
+{{- end -}}
+{{- with .policySources -}}
+  {{- $flags = append $flags (print "--enablePolicySources=" .enabled) -}}
+{{- end -}}
 {{- with .protectManagedResources -}}
   {{- $flags = append $flags (print "--protectManagedResources=" .enabled) -}}
 {{- end -}}
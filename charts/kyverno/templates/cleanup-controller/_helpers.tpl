@@ -38,3 +38,7 @@
     {{ required "A service account name is required when `rbac.create` is set to `false`" .Values.cleanupController.rbac.serviceAccount.name }}
 {{- end -}}
 {{- end -}}
+
+{{- define "kyverno.cleanup-controller.archiveNamespace" -}}
+{{ default (include "kyverno.namespace" .) .Values.cleanupController.archive.namespace }}
+{{- end -}}
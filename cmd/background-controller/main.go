@@ -15,6 +15,7 @@ import (
 	"github.com/kyverno/kyverno/pkg/background/gpol"
 	"github.com/kyverno/kyverno/pkg/breaker"
 	"github.com/kyverno/kyverno/pkg/cel/libs"
+	httplib "github.com/kyverno/kyverno/pkg/cel/libs/http"
 	"github.com/kyverno/kyverno/pkg/cel/matching"
 	gpolcompiler "github.com/kyverno/kyverno/pkg/cel/policies/gpol/compiler"
 	gpolengine "github.com/kyverno/kyverno/pkg/cel/policies/gpol/engine"
@@ -73,6 +74,7 @@ func createrLeaderControllers(
 	mpolEngine mpolengine.Engine,
 	mapper meta.RESTMapper,
 	reportsConfig reportutils.ReportingConfiguration,
+	httpConfig *httplib.Config,
 ) ([]internal.Controller, error) {
 	watchManager := gpol.NewWatchManager(logging.WithName("WatchManager"), dynamicClient)
 	policyCtrl, err := policy.NewPolicyController(
@@ -114,6 +116,7 @@ func createrLeaderControllers(
 		configuration,
 		jp,
 		reportsConfig,
+		httpConfig,
 	)
 	return []internal.Controller{
 		internal.NewController("policy-controller", policyCtrl, 2),
@@ -148,6 +151,8 @@ func main() {
 		internal.WithConfigMapCaching(),
 		internal.WithDeferredLoading(),
 		internal.WithRegistryClient(),
+		internal.WithCircuitBreaker(),
+		internal.WithServiceCalls(),
 		internal.WithLeaderElection(),
 		internal.WithKyvernoClient(),
 		internal.WithDynamicClient(),
@@ -199,6 +204,7 @@ func main() {
 			event.Workers,
 		)
 		urGenerator := generator.NewUpdateRequestGenerator(setup.Configuration, setup.MetadataClient)
+		apiCallConfig := apicall.NewAPICallConfiguration(maxAPICallResponseLength).WithCredentials(setup.ServiceCallCredentials)
 		gcstore := store.New()
 		gceController := internal.NewController(
 			globalcontextcontroller.ControllerName,
@@ -209,7 +215,7 @@ func main() {
 				setup.KyvernoClient,
 				gcstore,
 				eventGenerator,
-				maxAPICallResponseLength,
+				apiCallConfig,
 				false,
 				setup.Jp,
 			),
@@ -231,7 +237,7 @@ func main() {
 			setup.KubeClient,
 			setup.KyvernoClient,
 			setup.RegistrySecretLister,
-			apicall.NewAPICallConfiguration(maxAPICallResponseLength),
+			apiCallConfig,
 			polexCache,
 			gcstore,
 		)
@@ -313,7 +319,7 @@ func main() {
 				}
 
 				// create compiler
-				compiler := gpolcompiler.NewCompiler()
+				compiler := gpolcompiler.NewCompiler(gpolcompiler.WithHTTPConfig(setup.HTTPConfig))
 				// create provider
 				gpolProvider := gpolengine.NewFetchProvider(
 					compiler,
@@ -360,7 +366,7 @@ func main() {
 					os.Exit(1)
 				}
 
-				c := mpolcompiler.NewCompiler()
+				c := mpolcompiler.NewCompiler(mpolcompiler.WithHTTPConfig(setup.HTTPConfig))
 				mpolProvider, typeConverter, err := mpolengine.NewKubeProvider(mgrCtx, c, mgr, setup.KubeClient.Discovery().OpenAPIV3(), kyvernoInformer.Policies().V1alpha1().PolicyExceptions().Lister(), internal.PolicyExceptionEnabled())
 				if err != nil {
 					setup.Logger.Error(err, "failed to create mpol provider")
@@ -395,6 +401,7 @@ func main() {
 					mpolEngine,
 					restMapper,
 					setup.ReportingConfiguration,
+					setup.HTTPConfig,
 				)
 				if err != nil {
 					logger.Error(err, "failed to create leader controllers")
@@ -11,7 +11,9 @@ import (
 	policyhandlers "github.com/kyverno/kyverno/cmd/cleanup-controller/handlers/admission/policy"
 	resourcehandlers "github.com/kyverno/kyverno/cmd/cleanup-controller/handlers/admission/resource"
 	"github.com/kyverno/kyverno/cmd/internal"
+	"github.com/kyverno/kyverno/pkg/archive"
 	"github.com/kyverno/kyverno/pkg/auth/checker"
+	"github.com/kyverno/kyverno/pkg/budget"
 	"github.com/kyverno/kyverno/pkg/cel/libs"
 	"github.com/kyverno/kyverno/pkg/cel/matching"
 	"github.com/kyverno/kyverno/pkg/cel/policies/dpol/compiler"
@@ -26,6 +28,7 @@ import (
 	globalcontextcontroller "github.com/kyverno/kyverno/pkg/controllers/globalcontext"
 	ttlcontroller "github.com/kyverno/kyverno/pkg/controllers/ttl"
 	webhookcontroller "github.com/kyverno/kyverno/pkg/controllers/webhook"
+	"github.com/kyverno/kyverno/pkg/engine/apicall"
 	"github.com/kyverno/kyverno/pkg/event"
 	"github.com/kyverno/kyverno/pkg/globalcontext/store"
 	"github.com/kyverno/kyverno/pkg/informers"
@@ -88,6 +91,8 @@ func main() {
 		renewBefore              time.Duration
 		maxAPICallResponseLength int64
 		autoDeleteWebhooks       bool
+		deletionLimits           budget.Limits
+		archiveConfig            archive.Config
 	)
 	flagset := flag.NewFlagSet("cleanup-controller", flag.ExitOnError)
 	flagset.BoolVar(&dumpPayload, "dumpPayload", false, "Set this flag to activate/deactivate debug mode.")
@@ -103,12 +108,24 @@ func main() {
 	flagset.DurationVar(&renewBefore, "renewBefore", 15*24*time.Hour, "The certificate renewal time before expiration")
 	flagset.Int64Var(&maxAPICallResponseLength, "maxAPICallResponseLength", 2*1000*1000, "Maximum allowed response size from API Calls. A value of 0 bypasses checks (not recommended).")
 	flagset.BoolVar(&autoDeleteWebhooks, "autoDeleteWebhooks", false, "Set this flag to 'true' to enable autodeletion of webhook configurations using finalizers (requires extra permissions).")
+	flagset.IntVar(&deletionLimits.MaxPerRun, "maxDeletionsPerRun", 0, "Maximum number of resources deleted by a single run of a cleanup or deleting policy, a run exceeding it is aborted. A value of 0 disables the limit.")
+	flagset.IntVar(&deletionLimits.MaxPerMinute, "maxDeletionsPerMinute", 0, "Maximum number of resources deleted by all the cleanup and deleting policies within a minute, a run exceeding it is aborted. A value of 0 disables the limit.")
+	flagset.IntVar(&deletionLimits.MaxPercentage, "maxDeletionPercentage", 0, "Maximum percentage of the resources of a kind deleted by a single run of a cleanup or deleting policy, a run exceeding it is aborted. A value of 0 disables the limit.")
+	flagset.StringVar(&archiveConfig.Sink, "archiveSink", "", "Archive the manifest of resources before they are deleted by cleanup policies, deleting policies or ttl labels, one of 'directory', 'configmap', 'secret' or 's3'. A resource that can't be archived is not deleted. Archiving is disabled when empty.")
+	flagset.StringVar(&archiveConfig.Path, "archivePath", "", "Directory where deleted resources are archived by the 'directory' archive sink, typically a persistent volume mount.")
+	flagset.StringVar(&archiveConfig.Namespace, "archiveNamespace", "", "Namespace where deleted resources are archived by the 'configmap' and 'secret' archive sinks.")
+	flagset.StringVar(&archiveConfig.S3.Endpoint, "archiveS3Endpoint", "", "URL of the S3 compatible endpoint where deleted resources are archived by the 's3' archive sink, credentials are read from the standard AWS environment variables and files.")
+	flagset.StringVar(&archiveConfig.S3.Bucket, "archiveS3Bucket", "", "Bucket where deleted resources are archived by the 's3' archive sink.")
+	flagset.StringVar(&archiveConfig.S3.Prefix, "archiveS3Prefix", "", "Prefix of the keys of deleted resources archived by the 's3' archive sink.")
+	flagset.StringVar(&archiveConfig.S3.Region, "archiveS3Region", "", "Region used to sign the requests of the 's3' archive sink (defaults to us-east-1).")
 	// config
 	appConfig := internal.NewConfiguration(
 		internal.WithProfiling(),
 		internal.WithMetrics(),
 		internal.WithTracing(),
 		internal.WithKubeconfig(),
+		internal.WithCircuitBreaker(),
+		internal.WithServiceCalls(),
 		internal.WithLeaderElection(),
 		internal.WithKyvernoClient(),
 		internal.WithKyvernoDynamicClient(),
@@ -181,6 +198,7 @@ func main() {
 			eventGenerator,
 			event.Workers,
 		)
+		apiCallConfig := apicall.NewAPICallConfiguration(maxAPICallResponseLength).WithCredentials(setup.ServiceCallCredentials)
 		gcstore := store.New()
 		gceController := internal.NewController(
 			globalcontextcontroller.ControllerName,
@@ -191,7 +209,7 @@ func main() {
 				setup.KyvernoClient,
 				gcstore,
 				eventGenerator,
-				maxAPICallResponseLength,
+				apiCallConfig,
 				false,
 				setup.Jp,
 			),
@@ -219,6 +237,14 @@ func main() {
 			setup.Logger.Error(err, "failed to create CEL context provider")
 			os.Exit(1)
 		}
+		// deletion budget shared by the cleanup and deleting controllers
+		deletionBudget := budget.New(deletionLimits)
+		// archive sink shared by the cleanup, deleting and ttl controllers
+		archiveSink, err := archive.New(archiveConfig, setup.KubeClient)
+		if err != nil {
+			setup.Logger.Error(err, "failed to create archive sink")
+			os.Exit(1)
+		}
 
 		// setup leader election
 		le, err := leaderelection.New(
@@ -236,7 +262,7 @@ func main() {
 
 				cmResolver := internal.NewConfigMapResolver(ctx, setup.Logger, setup.KubeClient, setup.ResyncPeriod)
 				provider := engine.NewFetchProvider(
-					compiler.NewCompiler(),
+					compiler.NewCompiler(compiler.WithHTTPConfig(setup.HTTPConfig)),
 					kyvernoInformer.Policies().V1beta1().DeletingPolicies().Lister(),
 					kyvernoInformer.Policies().V1beta1().NamespacedDeletingPolicies().Lister(),
 					kyvernoInformer.Policies().V1alpha1().PolicyExceptions().Lister(),
@@ -358,6 +384,7 @@ func main() {
 					cleanup.NewController(
 						setup.KyvernoDynamicClient,
 						setup.KyvernoClient,
+						setup.MetadataClient,
 						kyvernoInformer.Kyverno().V2().ClusterCleanupPolicies(),
 						kyvernoInformer.Kyverno().V2().CleanupPolicies(),
 						nsLister,
@@ -366,6 +393,9 @@ func main() {
 						setup.Jp,
 						eventGenerator,
 						gcstore,
+						apiCallConfig,
+						deletionBudget,
+						archiveSink,
 					),
 					cleanup.Workers,
 				)
@@ -374,6 +404,7 @@ func main() {
 					deleting.NewController(
 						setup.KyvernoDynamicClient,
 						setup.KyvernoClient,
+						setup.MetadataClient,
 						kyvernoInformer.Policies().V1beta1().DeletingPolicies(),
 						kyvernoInformer.Policies().V1beta1().NamespacedDeletingPolicies(),
 						provider,
@@ -393,6 +424,8 @@ func main() {
 						setup.Configuration,
 						cmResolver,
 						eventGenerator,
+						deletionBudget,
+						archiveSink,
 					),
 					deleting.Workers,
 				)
@@ -404,6 +437,8 @@ func main() {
 						checker,
 						interval,
 						setup.ResyncPeriod,
+						setup.KyvernoDynamicClient.GetDynamicInterface(),
+						archiveSink,
 					),
 					ttlcontroller.Workers,
 				)
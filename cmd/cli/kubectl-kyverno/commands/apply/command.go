@@ -6,6 +6,7 @@ import (
 	"io"
 	"net/url"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
 	"time"
@@ -40,13 +41,13 @@ import (
 	"github.com/kyverno/kyverno/pkg/clients/dclient"
 	"github.com/kyverno/kyverno/pkg/config"
 	engineapi "github.com/kyverno/kyverno/pkg/engine/api"
+	"github.com/kyverno/kyverno/pkg/engine/explain"
 	eval "github.com/kyverno/kyverno/pkg/imageverification/evaluator"
 	"github.com/kyverno/kyverno/pkg/imageverification/imagedataloader"
 	gitutils "github.com/kyverno/kyverno/pkg/utils/git"
 	utils "github.com/kyverno/kyverno/pkg/utils/restmapper"
 	policyvalidation "github.com/kyverno/kyverno/pkg/validation/policy"
 	"github.com/spf13/cobra"
-	admissionv1 "k8s.io/api/admission/v1"
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
 	authenticationv1 "k8s.io/api/authentication/v1"
@@ -100,10 +101,16 @@ type ApplyCommandConfig struct {
 	BatchSize             int
 	ContinueOnError       bool
 	ShowPerformance       bool
+	ExplainFormat         string
+	AdmissionRequestPaths []string
+	// Trace records the steps evaluated by the engines when explain mode is enabled
+	Trace *explain.Trace
+	// whatIf collects the resources and admission requests that would be denied when what-if mode is enabled
+	whatIf *whatIf
 }
 
 func Command() *cobra.Command {
-	var removeColor, detailedResults, table bool
+	var removeColor, detailedResults, table, explainMode, watchMode, whatIfMode bool
 	applyCommandConfig := &ApplyCommandConfig{}
 	cmd := &cobra.Command{
 		Use:          "apply",
@@ -112,59 +119,101 @@ func Command() *cobra.Command {
 		Example:      command.FormatExamples(examples...),
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			ctx := cmd.Context()
 			out := cmd.OutOrStdout()
 			color.Init(removeColor)
 			applyCommandConfig.PolicyPaths = args
-			rc, _, skipInvalidPolicies, responses, err := applyCommandConfig.applyCommandHelper(out)
-			if err != nil {
-				return err
+			if watchMode {
+				if err := applyCommandConfig.checkWatch(); err != nil {
+					return err
+				}
 			}
-			cmd.SilenceErrors = true
-			printSkippedAndInvalidPolicies(out, skipInvalidPolicies)
-			if applyCommandConfig.PolicyReport {
-				printReports(out, responses, applyCommandConfig.AuditWarn, applyCommandConfig.OutputFormat)
-			} else if applyCommandConfig.GenerateExceptions {
-				printExceptions(out, responses, applyCommandConfig.AuditWarn, applyCommandConfig.OutputFormat, applyCommandConfig.GeneratedExceptionTTL)
-			} else if table {
-				printTable(out, detailedResults, applyCommandConfig.AuditWarn, responses...)
-			} else {
-				for _, response := range responses {
-					var failedRules []engineapi.RuleResponse
-					resPath := fmt.Sprintf("%s/%s/%s", response.Resource.GetNamespace(), response.Resource.GetKind(), response.Resource.GetName())
-					if resPath == "//" {
-						resPath = "JSON payload"
+			run := func() error {
+				if explainMode {
+					applyCommandConfig.Trace = explain.NewTrace()
+				}
+				if whatIfMode {
+					applyCommandConfig.whatIf = newWhatIf()
+					// the what-if report replaces the progress messages and the results
+					applyCommandConfig.PolicyReport = true
+				}
+				sarifOutput := applyCommandConfig.OutputFormat == "sarif"
+				if sarifOutput {
+					// like policy reports, the sarif log must not be mixed with progress messages
+					applyCommandConfig.PolicyReport = true
+				}
+				rc, _, skipInvalidPolicies, responses, err := applyCommandConfig.applyCommandHelper(ctx, out)
+				if err != nil {
+					return err
+				}
+				cmd.SilenceErrors = true
+				if applyCommandConfig.whatIf != nil {
+					return printWhatIf(out, applyCommandConfig.whatIf, applyCommandConfig.OutputFormat)
+				}
+				if sarifOutput {
+					if err := printSarif(out, responses, applyCommandConfig.AuditWarn, applyCommandConfig.ResourcePaths); err != nil {
+						return err
 					}
-					for _, rule := range response.PolicyResponse.Rules {
-						if rule.Status() == engineapi.RuleStatusFail {
-							failedRules = append(failedRules, rule)
+					return exit(out, rc, applyCommandConfig.warnExitCode, applyCommandConfig.warnNoPassed)
+				}
+				printSkippedAndInvalidPolicies(out, skipInvalidPolicies)
+				if applyCommandConfig.PolicyReport {
+					printReports(out, responses, applyCommandConfig.AuditWarn, applyCommandConfig.OutputFormat)
+				} else if applyCommandConfig.GenerateExceptions {
+					printExceptions(out, responses, applyCommandConfig.AuditWarn, applyCommandConfig.OutputFormat, applyCommandConfig.GeneratedExceptionTTL)
+				} else if table {
+					printTable(out, detailedResults, applyCommandConfig.AuditWarn, responses...)
+				} else {
+					for _, response := range responses {
+						var failedRules []engineapi.RuleResponse
+						resPath := fmt.Sprintf("%s/%s/%s", response.Resource.GetNamespace(), response.Resource.GetKind(), response.Resource.GetName())
+						if resPath == "//" {
+							resPath = "JSON payload"
 						}
-						if rule.RuleType() == engineapi.Mutation {
-							if rule.Status() == engineapi.RuleStatusSkip {
-								fmt.Fprintln(out, "\nskipped mutate policy", response.Policy().GetName(), "->", "resource", resPath)
-							} else if rule.Status() == engineapi.RuleStatusError {
-								fmt.Fprintln(out, "\nerror while applying mutate policy", response.Policy().GetName(), "->", "resource", resPath, "\nerror: ", rule.Message())
+						for _, rule := range response.PolicyResponse.Rules {
+							if rule.Status() == engineapi.RuleStatusFail {
+								failedRules = append(failedRules, rule)
+							}
+							if rule.RuleType() == engineapi.Mutation {
+								if rule.Status() == engineapi.RuleStatusSkip {
+									fmt.Fprintln(out, "\nskipped mutate policy", response.Policy().GetName(), "->", "resource", resPath)
+								} else if rule.Status() == engineapi.RuleStatusError {
+									fmt.Fprintln(out, "\nerror while applying mutate policy", response.Policy().GetName(), "->", "resource", resPath, "\nerror: ", rule.Message())
+								}
 							}
 						}
-					}
-					if len(failedRules) > 0 {
-						auditWarn := false
-						if applyCommandConfig.AuditWarn && response.GetValidationFailureAction().Audit() {
-							auditWarn = true
-						}
-						if auditWarn {
-							fmt.Fprintln(out, "policy", response.Policy().GetName(), "->", "resource", resPath, "failed as audit warning:")
-						} else {
-							fmt.Fprintln(out, "policy", response.Policy().GetName(), "->", "resource", resPath, "failed:")
-						}
-						for i, rule := range failedRules {
-							fmt.Fprintln(out, i+1, "-", rule.Name(), rule.Message())
+						if len(failedRules) > 0 {
+							auditWarn := false
+							if applyCommandConfig.AuditWarn && response.GetValidationFailureAction().Audit() {
+								auditWarn = true
+							}
+							if auditWarn {
+								fmt.Fprintln(out, "policy", response.Policy().GetName(), "->", "resource", resPath, "failed as audit warning:")
+							} else {
+								fmt.Fprintln(out, "policy", response.Policy().GetName(), "->", "resource", resPath, "failed:")
+							}
+							for i, rule := range failedRules {
+								fmt.Fprintln(out, i+1, "-", rule.Name(), rule.Message())
+							}
+							fmt.Fprintln(out, "")
 						}
-						fmt.Fprintln(out, "")
+					}
+					printViolations(out, rc)
+				}
+				if applyCommandConfig.Trace != nil {
+					if err := printExplain(out, applyCommandConfig.Trace, applyCommandConfig.ExplainFormat); err != nil {
+						return err
 					}
 				}
-				printViolations(out, rc)
+				return exit(out, rc, applyCommandConfig.warnExitCode, applyCommandConfig.warnNoPassed)
 			}
-			return exit(out, rc, applyCommandConfig.warnExitCode, applyCommandConfig.warnNoPassed)
+			if !watchMode {
+				return run()
+			}
+			// runs see the interrupt through ctx
+			ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+			defer stop()
+			return applyCommandConfig.watch(ctx, out, run)
 		},
 	}
 
@@ -181,7 +230,7 @@ func Command() *cobra.Command {
 	cmd.Flags().StringVarP(&applyCommandConfig.ValuesFile, "values-file", "f", "", "File containing values for policy variables")
 	cmd.Flags().StringVarP(&applyCommandConfig.ContextPath, "context-file", "", "", "File containing context data for CEL policies")
 	cmd.Flags().BoolVarP(&applyCommandConfig.PolicyReport, "policy-report", "p", false, "Generates policy report when passed (default policyviolation)")
-	cmd.Flags().StringVarP(&applyCommandConfig.OutputFormat, "output-format", "", "yaml", "Specifies the policy report format (json or yaml). Default: yaml.")
+	cmd.Flags().StringVarP(&applyCommandConfig.OutputFormat, "output-format", "", "yaml", "Specifies the policy report format (json or yaml), or sarif to print failed and warned rules as a SARIF log. Default: yaml.")
 	cmd.Flags().StringVarP(&applyCommandConfig.Namespace, "namespace", "n", "", "Optional Policy parameter passed with cluster flag")
 	cmd.Flags().BoolVarP(&applyCommandConfig.Stdin, "stdin", "i", false, "Optional mutate policy parameter to pipe directly through to kubectl")
 	cmd.Flags().BoolVar(&applyCommandConfig.RegistryAccess, "registry", false, "If set to true, access the image registry using local docker credentials to populate external data")
@@ -208,10 +257,15 @@ func Command() *cobra.Command {
 	cmd.Flags().IntVar(&applyCommandConfig.BatchSize, "batch-size", 100, "Number of resources to fetch per API call")
 	cmd.Flags().BoolVar(&applyCommandConfig.ContinueOnError, "continue-on-error", true, "Continue processing despite resource loading errors")
 	cmd.Flags().BoolVar(&applyCommandConfig.ShowPerformance, "show-performance", false, "Show resource loading performance metrics")
+	cmd.Flags().BoolVar(&explainMode, "explain", false, "If set to true, print a trace of the steps evaluated by the engines after the results")
+	cmd.Flags().StringVar(&applyCommandConfig.ExplainFormat, "explain-format", "tree", "Specifies the explain trace format (tree or json)")
+	cmd.Flags().BoolVar(&watchMode, "watch", false, "If set to true, apply the policies again when the local files they use change")
+	cmd.Flags().BoolVar(&whatIfMode, "what-if", false, "If set to true, report the resources and admission requests that would be denied if the policies were enforced")
+	cmd.Flags().StringSliceVar(&applyCommandConfig.AdmissionRequestPaths, "admission-requests", nil, "Path to Kyverno JSON logs of admission requests captured with --dumpPayload, evaluated in what-if mode")
 	return cmd
 }
 
-func (c *ApplyCommandConfig) applyCommandHelper(out io.Writer) (*processor.ResultCounts, []*unstructured.Unstructured, SkippedInvalidPolicies, []engineapi.EngineResponse, error) {
+func (c *ApplyCommandConfig) applyCommandHelper(ctx context.Context, out io.Writer) (*processor.ResultCounts, []*unstructured.Unstructured, SkippedInvalidPolicies, []engineapi.EngineResponse, error) {
 	var skippedInvalidPolicies SkippedInvalidPolicies
 	err := c.checkArguments()
 	if err != nil {
@@ -356,6 +410,7 @@ func (c *ApplyCommandConfig) applyCommandHelper(out io.Writer) (*processor.Resul
 		maps,
 		mapBindings,
 		resources,
+		nil,
 		parameterResources,
 		jsonPayloads,
 		exceptions,
@@ -377,7 +432,7 @@ func (c *ApplyCommandConfig) applyCommandHelper(out io.Writer) (*processor.Resul
 		allImageValidatingPolicies = append(allImageValidatingPolicies, &nivps[i])
 	}
 
-	responses4, err := c.applyImageValidatingPolicies(allImageValidatingPolicies, jsonPayloads, resources1, celexceptions, variables.Namespace, userInfo, rc, dClient)
+	responses4, err := c.applyImageValidatingPolicies(ctx, allImageValidatingPolicies, jsonPayloads, resources1, nil, celexceptions, variables.Namespace, userInfo, rc, dClient)
 	if err != nil {
 		return rc, resources1, skippedInvalidPolicies, responses4, err
 	}
@@ -390,12 +445,12 @@ func (c *ApplyCommandConfig) applyCommandHelper(out io.Writer) (*processor.Resul
 		allDeletingPolicies = append(allDeletingPolicies, &ndps[i])
 	}
 
-	responses5, err := c.applyDeletingPolicies(allDeletingPolicies, resources1, celexceptions, variables.Namespace, rc, dClient, "resource")
+	responses5, err := c.applyDeletingPolicies(ctx, allDeletingPolicies, resources1, celexceptions, variables.Namespace, rc, dClient, "resource")
 	if err != nil {
 		return rc, resources1, skippedInvalidPolicies, responses4, err
 	}
 
-	responses6, err := c.applyDeletingPolicies(allDeletingPolicies, jsonPayloads, celexceptions, variables.Namespace, rc, dClient, "json")
+	responses6, err := c.applyDeletingPolicies(ctx, allDeletingPolicies, jsonPayloads, celexceptions, variables.Namespace, rc, dClient, "json")
 	if err != nil {
 		return rc, resources1, skippedInvalidPolicies, responses4, err
 	}
@@ -405,9 +460,40 @@ func (c *ApplyCommandConfig) applyCommandHelper(out io.Writer) (*processor.Resul
 	responses = append(responses, responses4...)
 	responses = append(responses, responses5...)
 	responses = append(responses, responses6...)
+	if c.whatIf != nil {
+		c.whatIf.record(ctx, dClient, "", responses...)
+		requests, err := loadAdmissionRequests(c.AdmissionRequestPaths...)
+		if err != nil {
+			return rc, resources1, skippedInvalidPolicies, responses, err
+		}
+		for _, group := range groupAdmissionRequests(ctx, dClient, requests) {
+			// policies were already validated and counted above, the results of the requests only go to the what-if report
+			var skipped SkippedInvalidPolicies
+			_, _, requestResponses, err := c.applyPolicies(out, &store, variables, kpols, vaps, vapBindings, vps, nvps, gps, mps, nmps, maps, mapBindings, group.resources, group.oldResources, parameterResources, nil, exceptions, celexceptions, &skipped, dClient, &group.userInfo, mutateLogPathIsDir)
+			if err != nil {
+				return rc, resources1, skippedInvalidPolicies, responses, err
+			}
+			imageResponses, err := c.applyImageValidatingPolicies(ctx, allImageValidatingPolicies, nil, group.resources, group.oldResources, celexceptions, variables.Namespace, &group.userInfo, &processor.ResultCounts{}, dClient)
+			if err != nil {
+				return rc, resources1, skippedInvalidPolicies, responses, err
+			}
+			c.whatIf.record(ctx, dClient, group.userInfo.AdmissionUserInfo.Username, append(requestResponses, imageResponses...)...)
+		}
+	}
 	return rc, resources1, skippedInvalidPolicies, responses, nil
 }
 
+// Explain applies the policies on the resources without printing results and returns the steps evaluated by the engines.
+func (c *ApplyCommandConfig) Explain(ctx context.Context) ([]*explain.Step, error) {
+	c.Trace = explain.NewTrace()
+	// like policy reports, the trace must not be mixed with progress messages
+	c.PolicyReport = true
+	if _, _, _, _, err := c.applyCommandHelper(ctx, io.Discard); err != nil {
+		return nil, err
+	}
+	return c.Trace.Steps(), nil
+}
+
 func (c *ApplyCommandConfig) getMutateLogPathIsDir() (bool, error) {
 	mutateLogPathIsDir, err := checkMutateLogPath(c.MutateLogPath)
 	if err != nil {
@@ -431,6 +517,7 @@ func (c *ApplyCommandConfig) applyPolicies(
 	maps []admissionregistrationv1beta1.MutatingAdmissionPolicy,
 	mapBindings []admissionregistrationv1beta1.MutatingAdmissionPolicyBinding,
 	resources []*unstructured.Unstructured,
+	oldResources map[*unstructured.Unstructured]*unstructured.Unstructured,
 	parameterResources []*unstructured.Unstructured,
 	jsonPayloads []*unstructured.Unstructured,
 	exceptions []*kyvernov2.PolicyException,
@@ -482,6 +569,7 @@ func (c *ApplyCommandConfig) applyPolicies(
 			MutatingAdmissionPolicies:         maps,
 			MutatingAdmissionPolicyBindings:   mapBindings,
 			Resource:                          *resource,
+			OldResource:                       oldResources[resource],
 			PolicyExceptions:                  exceptions,
 			CELExceptions:                     celExceptions,
 			MutateLogPath:                     c.MutateLogPath,
@@ -501,6 +589,7 @@ func (c *ApplyCommandConfig) applyPolicies(
 			Subresources:                      vars.Subresources(),
 			Out:                               out,
 			NamespaceCache:                    namespaceCache,
+			Trace:                             c.Trace,
 		}
 		ers, err := processor.ApplyPoliciesOnResource()
 		if err != nil {
@@ -541,6 +630,7 @@ func (c *ApplyCommandConfig) applyPolicies(
 			Subresources:                      vars.Subresources(),
 			Out:                               out,
 			NamespaceCache:                    namespaceCache,
+			Trace:                             c.Trace,
 		}
 		ers, err := processor.ApplyPoliciesOnResource()
 		if err != nil {
@@ -561,9 +651,11 @@ func (c *ApplyCommandConfig) applyPolicies(
 }
 
 func (c *ApplyCommandConfig) applyImageValidatingPolicies(
+	ctx context.Context,
 	ivps []policiesv1beta1.ImageValidatingPolicyLike,
 	jsonPayloads []*unstructured.Unstructured,
 	resources []*unstructured.Unstructured,
+	oldResources map[*unstructured.Unstructured]*unstructured.Unstructured,
 	celExceptions []*policiesv1alpha1.PolicyException,
 	namespaceProvider func(string) *corev1.Namespace,
 	userInfo *kyvernov2.RequestInfo,
@@ -587,6 +679,8 @@ func (c *ApplyCommandConfig) applyImageValidatingPolicies(
 		matching.NewMatcher(),
 		lister,
 		[]imagedataloader.Option{imagedataloader.WithLocalCredentials(c.RegistryAccess)},
+		nil,
+		nil,
 	)
 
 	restMapper, err := utils.GetRESTMapper(dclient, !c.Cluster)
@@ -615,6 +709,7 @@ func (c *ApplyCommandConfig) applyImageValidatingPolicies(
 		if userInfo != nil {
 			user = userInfo.AdmissionUserInfo
 		}
+		operation, oldObject := processor.AdmissionOperation(oldResources[resource])
 		request := celengine.Request(
 			contextProvider,
 			resource.GroupVersionKind(),
@@ -622,14 +717,15 @@ func (c *ApplyCommandConfig) applyImageValidatingPolicies(
 			"",
 			resource.GetName(),
 			resource.GetNamespace(),
-			admissionv1.Create,
+			operation,
 			user,
 			resource,
-			nil,
+			oldObject,
 			false,
 			nil,
 		)
-		engineResponse, _, err := engine.HandleMutating(context.TODO(), request, nil)
+		ctx, _ := explain.Resume(explain.WithTrace(ctx, c.Trace), explain.TypeResource, processor.ExplainKey(*resource))
+		engineResponse, _, err := engine.HandleMutating(ctx, request, nil)
 		if err != nil {
 			if c.ContinueOnFail {
 				fmt.Printf("failed to apply image validating policies on resource %s (%v)\n", resource.GetName(), err)
@@ -658,7 +754,8 @@ func (c *ApplyCommandConfig) applyImageValidatingPolicies(
 		ivpols = append(ivpols, &eval.CompiledImageValidatingPolicy{Policy: p})
 	}
 	for _, json := range jsonPayloads {
-		result, err := eval.Evaluate(context.TODO(), ivpols, json.Object, nil, nil, nil)
+		ctx, _ := explain.Resume(explain.WithTrace(ctx, c.Trace), explain.TypeResource, "JSON payload")
+		result, err := eval.Evaluate(ctx, ivpols, json.Object, nil, nil, nil)
 		if err != nil {
 			if c.ContinueOnFail {
 				fmt.Printf("failed to apply image validating policies on JSON payload: %v\n", err)
@@ -693,6 +790,7 @@ func (c *ApplyCommandConfig) applyImageValidatingPolicies(
 }
 
 func (c *ApplyCommandConfig) applyDeletingPolicies(
+	ctx context.Context,
 	dps []policiesv1beta1.DeletingPolicyLike,
 	resources []*unstructured.Unstructured,
 	celExceptions []*policiesv1alpha1.PolicyException,
@@ -701,6 +799,9 @@ func (c *ApplyCommandConfig) applyDeletingPolicies(
 	dclient dclient.Interface,
 	payloadType string,
 ) ([]engineapi.EngineResponse, error) {
+	if len(dps) == 0 {
+		return nil, nil
+	}
 	provider, err := dpolengine.NewProvider(dpolcompiler.NewCompiler(), dps, celExceptions)
 	if err != nil {
 		return nil, err
@@ -718,20 +819,25 @@ func (c *ApplyCommandConfig) applyDeletingPolicies(
 
 	engine := dpolengine.NewEngine(namespaceProvider, restMapper, contextProvider, matching.NewMatcher())
 
-	policies, err := provider.Fetch(context.Background())
+	policies, err := provider.Fetch(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	responses := make([]engineapi.EngineResponse, 0)
 	for _, resource := range resources {
+		key := "JSON payload"
+		if payloadType != "json" {
+			key = processor.ExplainKey(*resource)
+		}
+		ctx, _ := explain.Resume(explain.WithTrace(ctx, c.Trace), explain.TypeResource, key)
 		for _, dpol := range policies {
 			genericPolicy := engineapi.NewDeletingPolicyFromLike(dpol.Policy)
 			if genericPolicy == nil {
 				return nil, fmt.Errorf("unsupported deleting policy type %T", dpol.Policy)
 			}
 			policyName := dpol.Policy.GetName()
-			resp, err := engine.Handle(context.TODO(), dpol, *resource)
+			resp, err := engine.Handle(ctx, dpol, *resource)
 			if err != nil {
 				response := engineapi.NewEngineResponse(*resource, genericPolicy, nil)
 				response = response.WithPolicyResponse(engineapi.PolicyResponse{Rules: []engineapi.RuleResponse{
@@ -989,6 +1095,24 @@ func (c *ApplyCommandConfig) checkArguments() error {
 	if len(c.ResourcePaths) == 0 && len(c.JSONPaths) == 0 && !c.Cluster {
 		return fmt.Errorf("resource file(s) or cluster required")
 	}
+	if c.whatIf != nil {
+		if !c.Cluster {
+			return fmt.Errorf("what-if mode requires the cluster flag")
+		}
+		if c.OutputFormat == "sarif" || c.GenerateExceptions {
+			return fmt.Errorf("what-if mode can not be used with the sarif output format or to generate exceptions")
+		}
+	} else if len(c.AdmissionRequestPaths) > 0 {
+		return fmt.Errorf("admission requests can only be evaluated in what-if mode")
+	}
+	if c.Trace != nil {
+		if c.OutputFormat == "sarif" {
+			return fmt.Errorf("explain mode can not be used with the sarif output format")
+		}
+		if c.ExplainFormat != "tree" && c.ExplainFormat != "json" {
+			return fmt.Errorf("invalid explain format %q, use tree or json", c.ExplainFormat)
+		}
+	}
 	return nil
 }
 
@@ -2,6 +2,7 @@ package apply
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -437,7 +438,7 @@ func Test_Apply(t *testing.T) {
 		}
 		desc := fmt.Sprintf("Policies: [%s], / Resources: [%s]", strings.Join(tc.config.PolicyPaths, ","), strings.Join(tc.config.ResourcePaths, ","))
 
-		_, _, _, responses, err := tc.config.applyCommandHelper(os.Stdout)
+		_, _, _, responses, err := tc.config.applyCommandHelper(context.TODO(), os.Stdout)
 		assert.NoError(t, err, desc)
 
 		clustered, _ := report.ComputePolicyReports(tc.config.AuditWarn, responses...)
@@ -1030,7 +1031,7 @@ func verifyTestcase(t *testing.T, tc *TestCase, compareSummary func(*testing.T,
 		strings.Join(tc.config.JSONPaths, ","),
 	)
 
-	_, _, _, responses, err := tc.config.applyCommandHelper(os.Stdout)
+	_, _, _, responses, err := tc.config.applyCommandHelper(context.TODO(), os.Stdout)
 	assert.NoError(t, err, desc)
 
 	clustered, _ := report.ComputePolicyReports(tc.config.AuditWarn, responses...)
@@ -31,4 +31,12 @@ var examples = [][]string{
 		"# Apply multiple policy with variable on multiple resource",
 		"kyverno apply /path/to/policy1.yaml /path/to/policy2.yaml --resource /path/to/resource1.yaml --resource /path/to/resource2.yaml -f /path/to/value.yaml",
 	},
+	{
+		"# Apply policies again each time the policies or resources change",
+		"kyverno apply /path/to/policy.yaml --resource=/path/to/resources/ --watch",
+	},
+	{
+		"# Report the resources and captured admission requests that would be denied if the policies were enforced",
+		"kyverno apply /path/to/policy.yaml --cluster --what-if --admission-requests /path/to/kyverno.log",
+	},
 }
@@ -10,9 +10,11 @@ import (
 
 	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
 	kyvernov2beta1 "github.com/kyverno/kyverno/api/kyverno/v2beta1"
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/output/sarif"
 	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/processor"
 	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/report"
 	engineapi "github.com/kyverno/kyverno/pkg/engine/api"
+	"github.com/kyverno/kyverno/pkg/engine/explain"
 	kyvernoreports "github.com/kyverno/kyverno/pkg/utils/report"
 	openreportsv1alpha1 "github.com/openreports/reports-api/apis/openreports.io/v1alpha1"
 	"github.com/opentracing/opentracing-go/log"
@@ -42,6 +44,21 @@ func printSkippedAndInvalidPolicies(out io.Writer, skipInvalidPolicies SkippedIn
 	}
 }
 
+func printSarif(out io.Writer, engineResponses []engineapi.EngineResponse, auditWarn bool, resourcePaths []string) error {
+	builder := sarif.NewBuilder()
+	builder.AddEngineResponses(sarif.NewResourceIndex(nil, resourcePaths...), auditWarn, engineResponses...)
+	return builder.Write(out)
+}
+
+func printExplain(out io.Writer, trace *explain.Trace, format string) error {
+	if format == "json" {
+		return explain.WriteJSON(out, trace.Steps())
+	}
+	fmt.Fprintln(out, divider)
+	explain.WriteTree(out, trace.Steps())
+	return nil
+}
+
 func printReports(out io.Writer, engineResponses []engineapi.EngineResponse, auditWarn bool, outputFormat string) {
 	clustered, namespaced := report.ComputePolicyReports(auditWarn, engineResponses...)
 
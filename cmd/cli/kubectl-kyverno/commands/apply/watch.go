@@ -0,0 +1,83 @@
+package apply
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/source"
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/watch"
+)
+
+// checkWatch returns an error if the inputs can't be watched.
+func (c *ApplyCommandConfig) checkWatch() error {
+	if c.Stdin {
+		return fmt.Errorf("watch mode can not be used with stdin")
+	}
+	for _, path := range c.paths() {
+		if path == "-" {
+			return fmt.Errorf("watch mode can not be used with stdin")
+		}
+		if source.IsGit(path) {
+			return fmt.Errorf("watch mode doesn't support git repositories (%s)", path)
+		}
+	}
+	return nil
+}
+
+// paths returns the paths of the files and directories read by the command.
+func (c *ApplyCommandConfig) paths() []string {
+	var paths []string
+	paths = append(paths, c.PolicyPaths...)
+	paths = append(paths, c.ResourcePaths...)
+	paths = append(paths, c.TargetResourcePaths...)
+	paths = append(paths, c.ParamResources...)
+	paths = append(paths, c.Exception...)
+	paths = append(paths, c.JSONPaths...)
+	paths = append(paths, c.ValuesFile, c.UserInfoPath, c.ContextPath)
+	return paths
+}
+
+// inputs returns the local files and directories read by the command.
+func (c *ApplyCommandConfig) inputs() []string {
+	var inputs []string
+	for _, path := range c.paths() {
+		if path == "" || path == "-" || source.IsHttp(path) || source.IsGit(path) {
+			continue
+		}
+		inputs = append(inputs, path)
+	}
+	return inputs
+}
+
+// watch runs apply then runs it again when the local files it reads change, until the context is cancelled.
+// Errors of a run are printed and don't stop watching.
+func (c *ApplyCommandConfig) watch(ctx context.Context, out io.Writer, run func() error) error {
+	watcher, err := watch.New()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+	for {
+		if err := run(); err != nil {
+			fmt.Fprintln(out, "Error:", err)
+		}
+		// directories can contain new files, the watched set is refreshed after each run
+		if err := watcher.Watch(c.inputs()...); err != nil {
+			return err
+		}
+		fmt.Fprintln(out, "\nWatching for changes, press Ctrl+C to stop ...")
+		changes, err := watcher.Next(ctx)
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				return nil
+			}
+			return err
+		}
+		fmt.Fprintln(out)
+		for _, change := range changes {
+			fmt.Fprintln(out, "Changed", change)
+		}
+	}
+}
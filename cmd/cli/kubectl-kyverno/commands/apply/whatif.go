@@ -0,0 +1,365 @@
+package apply
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	kyvernov2 "github.com/kyverno/kyverno/api/kyverno/v2"
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/output/table"
+	"github.com/kyverno/kyverno/ext/output/pluralize"
+	"github.com/kyverno/kyverno/pkg/clients/dclient"
+	engineapi "github.com/kyverno/kyverno/pkg/engine/api"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// whatIfSourceResource is the source of the denials found on live resources.
+	whatIfSourceResource = "resource"
+	// whatIfSourceRequest is the source of the denials found on captured admission requests.
+	whatIfSourceRequest = "request"
+	// admissionRequestLogKey is the key of the payload in the entries logged by the webhook dump handler.
+	admissionRequestLogKey = "admission.request"
+	// maxOwnerDepth bounds the owner references followed to find the owner of a resource.
+	maxOwnerDepth = 10
+)
+
+// admissionRequest holds the fields of an admission request payload logged by the webhook dump handler
+// that are needed to evaluate policies against it.
+type admissionRequest struct {
+	Kind         metav1.GroupVersionKind   `json:"kind"`
+	Name         string                    `json:"name,omitempty"`
+	Namespace    string                    `json:"namespace,omitempty"`
+	Operation    string                    `json:"operation"`
+	UserInfo     authenticationv1.UserInfo `json:"userInfo"`
+	Roles        []string                  `json:"roles"`
+	ClusterRoles []string                  `json:"clusterRoles"`
+	Object       map[string]interface{}    `json:"object,omitempty"`
+	OldObject    map[string]interface{}    `json:"oldObject,omitempty"`
+}
+
+// loadAdmissionRequests reads the admission requests captured by the webhook dump handler in the given files.
+func loadAdmissionRequests(paths ...string) ([]admissionRequest, error) {
+	var requests []admissionRequest
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read admission requests (%w)", err)
+		}
+		loaded, err := parseAdmissionRequests(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse admission requests from %s (%w)", path, err)
+		}
+		requests = append(requests, loaded...)
+	}
+	return requests, nil
+}
+
+// parseAdmissionRequests parses one admission request per line, either a JSON log entry of the webhook dump handler
+// or a raw payload. Other lines are skipped, like the non JSON lines of a log or the entries of other log messages.
+// Only the requests creating or updating an object are kept.
+func parseAdmissionRequests(data []byte) ([]admissionRequest, error) {
+	var requests []admissionRequest
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if !bytes.HasPrefix(line, []byte("{")) {
+			continue
+		}
+		var entry map[string]json.RawMessage
+		if err := json.Unmarshal(line, &entry); err != nil {
+			continue
+		}
+		raw := line
+		if payload, ok := entry[admissionRequestLogKey]; ok {
+			raw = payload
+		} else if _, ok := entry["operation"]; !ok {
+			continue
+		}
+		var request admissionRequest
+		if err := json.Unmarshal(raw, &request); err != nil {
+			return nil, err
+		}
+		if request.Operation != "CREATE" && request.Operation != "UPDATE" {
+			continue
+		}
+		if len(request.Object) == 0 {
+			continue
+		}
+		requests = append(requests, request)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+// requestGroup holds the objects of the admission requests sent by the same user.
+type requestGroup struct {
+	userInfo  kyvernov2.RequestInfo
+	resources []*unstructured.Unstructured
+	// oldResources holds the objects updated by the UPDATE requests, by resource
+	oldResources map[*unstructured.Unstructured]*unstructured.Unstructured
+}
+
+// groupAdmissionRequests groups the admission requests by user, roles and cluster roles, in the order they were captured.
+// UPDATE requests are evaluated against the object they update, see oldObject.
+func groupAdmissionRequests(ctx context.Context, dClient dclient.Interface, requests []admissionRequest) []*requestGroup {
+	var groups []*requestGroup
+	index := map[string]*requestGroup{}
+	for _, request := range requests {
+		key := strings.Join([]string{
+			request.UserInfo.Username,
+			request.UserInfo.UID,
+			strings.Join(request.UserInfo.Groups, ","),
+			strings.Join(request.Roles, ","),
+			strings.Join(request.ClusterRoles, ","),
+		}, "|")
+		group, ok := index[key]
+		if !ok {
+			group = &requestGroup{
+				userInfo: kyvernov2.RequestInfo{
+					Roles:             request.Roles,
+					ClusterRoles:      request.ClusterRoles,
+					AdmissionUserInfo: request.UserInfo,
+				},
+				oldResources: map[*unstructured.Unstructured]*unstructured.Unstructured{},
+			}
+			index[key] = group
+			groups = append(groups, group)
+		}
+		resource := &unstructured.Unstructured{Object: request.Object}
+		if resource.GetNamespace() == "" && request.Namespace != "" {
+			resource.SetNamespace(request.Namespace)
+		}
+		group.resources = append(group.resources, resource)
+		if request.Operation == "UPDATE" {
+			if old := oldObject(ctx, dClient, request, resource); old != nil {
+				group.oldResources[resource] = old
+			}
+		}
+	}
+	return groups
+}
+
+// oldObject returns the object updated by an UPDATE request. The live object is preferred to the old object captured
+// with the request so that the request is evaluated against the current state of the cluster, it is nil when none is
+// available and the request is then evaluated as if the object was created.
+func oldObject(ctx context.Context, dClient dclient.Interface, request admissionRequest, resource *unstructured.Unstructured) *unstructured.Unstructured {
+	if dClient != nil && resource.GetName() != "" {
+		if live, err := dClient.GetResource(ctx, resource.GetAPIVersion(), resource.GetKind(), resource.GetNamespace(), resource.GetName()); err == nil {
+			return live
+		}
+	}
+	if len(request.OldObject) != 0 {
+		return &unstructured.Unstructured{Object: request.OldObject}
+	}
+	return nil
+}
+
+// WhatIfDenial is a resource or an admission request that would be denied by a policy rule.
+type WhatIfDenial struct {
+	Resource string `json:"resource"`
+	User     string `json:"user,omitempty"`
+	Policy   string `json:"policy"`
+	Rule     string `json:"rule,omitempty"`
+	Message  string `json:"message,omitempty"`
+	Source   string `json:"source"`
+}
+
+// WhatIfGroup holds the denials of the resources in the same namespace with the same owner.
+type WhatIfGroup struct {
+	Namespace string         `json:"namespace,omitempty"`
+	Owner     string         `json:"owner,omitempty"`
+	Denials   []WhatIfDenial `json:"denials"`
+}
+
+type whatIfKey struct {
+	namespace string
+	owner     string
+}
+
+// whatIf collects the resources and admission requests that would be denied if the policies were enforced.
+type whatIf struct {
+	denials map[whatIfKey]map[WhatIfDenial]struct{}
+	owners  map[string]string
+}
+
+func newWhatIf() *whatIf {
+	return &whatIf{
+		denials: map[whatIfKey]map[WhatIfDenial]struct{}{},
+		owners:  map[string]string{},
+	}
+}
+
+// record adds the failed validation and image verification rules of the responses, regardless of the
+// validation failure action of the policies. The user is empty for live resources.
+func (w *whatIf) record(ctx context.Context, dClient dclient.Interface, user string, responses ...engineapi.EngineResponse) {
+	source := whatIfSourceResource
+	if user != "" {
+		source = whatIfSourceRequest
+	}
+	for _, response := range responses {
+		resource := response.Resource
+		name := resource.GetName()
+		if name == "" {
+			name = resource.GetGenerateName() + "*"
+		}
+		for _, rule := range response.PolicyResponse.Rules {
+			if rule.Status() != engineapi.RuleStatusFail {
+				continue
+			}
+			if rule.RuleType() != engineapi.Validation && rule.RuleType() != engineapi.ImageVerify {
+				continue
+			}
+			key := whatIfKey{
+				namespace: resource.GetNamespace(),
+				owner:     w.owner(ctx, dClient, resource.GetNamespace(), resource.GetOwnerReferences(), 0),
+			}
+			if w.denials[key] == nil {
+				w.denials[key] = map[WhatIfDenial]struct{}{}
+			}
+			w.denials[key][WhatIfDenial{
+				Resource: resource.GetKind() + "/" + name,
+				User:     user,
+				Policy:   response.Policy().GetName(),
+				Rule:     rule.Name(),
+				Message:  rule.Message(),
+				Source:   source,
+			}] = struct{}{}
+		}
+	}
+}
+
+// owner follows the controller references of a resource up to the top level controller and returns its kind and name.
+// An owner that can not be fetched ends the chain, it is empty when the resource has no controller.
+func (w *whatIf) owner(ctx context.Context, dClient dclient.Interface, namespace string, refs []metav1.OwnerReference, depth int) string {
+	ref := controllerOf(refs)
+	if ref == nil {
+		return ""
+	}
+	key := strings.Join([]string{ref.APIVersion, ref.Kind, namespace, ref.Name}, "/")
+	if owner, ok := w.owners[key]; ok {
+		return owner
+	}
+	owner := ref.Kind + "/" + ref.Name
+	if dClient != nil && depth < maxOwnerDepth {
+		if parent, err := dClient.GetResource(ctx, ref.APIVersion, ref.Kind, namespace, ref.Name); err == nil {
+			if top := w.owner(ctx, dClient, namespace, parent.GetOwnerReferences(), depth+1); top != "" {
+				owner = top
+			}
+		}
+	}
+	w.owners[key] = owner
+	return owner
+}
+
+func controllerOf(refs []metav1.OwnerReference) *metav1.OwnerReference {
+	for i := range refs {
+		if refs[i].Controller != nil && *refs[i].Controller {
+			return &refs[i]
+		}
+	}
+	if len(refs) > 0 {
+		return &refs[0]
+	}
+	return nil
+}
+
+// groups returns the denials grouped by namespace and owner, sorted by namespace, owner, resource, user, policy and rule.
+func (w *whatIf) groups() []WhatIfGroup {
+	groups := make([]WhatIfGroup, 0, len(w.denials))
+	for key, denials := range w.denials {
+		group := WhatIfGroup{
+			Namespace: key.namespace,
+			Owner:     key.owner,
+			Denials:   make([]WhatIfDenial, 0, len(denials)),
+		}
+		for denial := range denials {
+			group.Denials = append(group.Denials, denial)
+		}
+		slices.SortFunc(group.Denials, func(a, b WhatIfDenial) int {
+			return cmp.Or(
+				cmp.Compare(a.Resource, b.Resource),
+				cmp.Compare(a.User, b.User),
+				cmp.Compare(a.Policy, b.Policy),
+				cmp.Compare(a.Rule, b.Rule),
+				cmp.Compare(a.Message, b.Message),
+			)
+		})
+		groups = append(groups, group)
+	}
+	slices.SortFunc(groups, func(a, b WhatIfGroup) int {
+		return cmp.Or(cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Owner, b.Owner))
+	})
+	return groups
+}
+
+type whatIfRow struct {
+	Namespace string `header:"namespace"`
+	Owner     string `header:"owner"`
+	Resource  string `header:"resource"`
+	User      string `header:"user"`
+	Policy    string `header:"policy"`
+	Rule      string `header:"rule"`
+}
+
+func printWhatIf(out io.Writer, w *whatIf, outputFormat string) error {
+	groups := w.groups()
+	if outputFormat == "json" {
+		data, err := json.MarshalIndent(groups, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, string(data))
+		return nil
+	}
+	resources := map[string]struct{}{}
+	namespaces := map[string]struct{}{}
+	users := map[string]struct{}{}
+	var rows []whatIfRow
+	for _, group := range groups {
+		namespaces[group.Namespace] = struct{}{}
+		for _, denial := range group.Denials {
+			resources[group.Namespace+"/"+denial.Resource] = struct{}{}
+			if denial.User != "" {
+				users[denial.User] = struct{}{}
+			}
+			rows = append(rows, whatIfRow{
+				Namespace: orDash(group.Namespace),
+				Owner:     orDash(group.Owner),
+				Resource:  denial.Resource,
+				User:      orDash(denial.User),
+				Policy:    denial.Policy,
+				Rule:      denial.Rule,
+			})
+		}
+	}
+	if len(rows) == 0 {
+		fmt.Fprintln(out, "\nNo resources or admission requests would be denied")
+		return nil
+	}
+	fmt.Fprintf(out, "\n%d %s in %d %s and requests from %d %s would be denied\n\n",
+		len(resources), pluralize.Pluralize(len(resources), "resource", "resources"),
+		len(namespaces), pluralize.Pluralize(len(namespaces), "namespace", "namespaces"),
+		len(users), pluralize.Pluralize(len(users), "user", "users"))
+	table.NewTablePrinter(out).Print(rows)
+	return nil
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
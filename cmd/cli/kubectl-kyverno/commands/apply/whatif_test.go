@@ -0,0 +1,95 @@
+package apply
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	engineapi "github.com/kyverno/kyverno/pkg/engine/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const admissionRequestsLog = `starting kyverno
+{"level":"info","msg":"admission request dump","admission.request":{"uid":"1","kind":{"group":"","version":"v1","kind":"Pod"},"name":"nginx","namespace":"team-a","operation":"CREATE","userInfo":{"username":"alice","groups":["dev"]},"roles":["team-a:edit"],"clusterRoles":null,"object":{"apiVersion":"v1","kind":"Pod","metadata":{"name":"nginx"}}}}
+{"level":"info","msg":"admission request dump","admission.request":{"uid":"2","kind":{"group":"","version":"v1","kind":"Pod"},"name":"nginx","namespace":"team-a","operation":"DELETE","userInfo":{"username":"alice"},"object":{"apiVersion":"v1","kind":"Pod","metadata":{"name":"nginx","namespace":"team-a"}}}}
+{"level":"info","msg":"server started"}
+{"uid":"3","kind":{"group":"apps","version":"v1","kind":"Deployment"},"name":"web","namespace":"team-b","operation":"UPDATE","userInfo":{"username":"system:serviceaccount:ci:deployer"},"object":{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"web","namespace":"team-b"},"spec":{"replicas":3}},"oldObject":{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"web","namespace":"team-b"},"spec":{"replicas":1}}}
+{"level":"info","msg":"admission request dump","admission.request":{"uid":"4","kind":{"group":"","version":"v1","kind":"Pod"},"name":"busybox","namespace":"team-a","operation":"CREATE","userInfo":{"username":"alice","groups":["dev"]},"roles":["team-a:edit"],"clusterRoles":null,"object":{"apiVersion":"v1","kind":"Pod","metadata":{"name":"busybox","namespace":"team-a"}}}}
+`
+
+func Test_parseAdmissionRequests(t *testing.T) {
+	requests, err := parseAdmissionRequests([]byte(admissionRequestsLog))
+	require.NoError(t, err)
+	require.Len(t, requests, 3)
+	assert.Equal(t, "nginx", requests[0].Name)
+	assert.Equal(t, "alice", requests[0].UserInfo.Username)
+	assert.Equal(t, "UPDATE", requests[1].Operation)
+	assert.Equal(t, "system:serviceaccount:ci:deployer", requests[1].UserInfo.Username)
+	assert.Equal(t, "busybox", requests[2].Name)
+
+	groups := groupAdmissionRequests(context.TODO(), nil, requests)
+	require.Len(t, groups, 2)
+	assert.Equal(t, "alice", groups[0].userInfo.AdmissionUserInfo.Username)
+	assert.Equal(t, []string{"team-a:edit"}, groups[0].userInfo.Roles)
+	require.Len(t, groups[0].resources, 2)
+	assert.Equal(t, "team-a", groups[0].resources[0].GetNamespace())
+	assert.Equal(t, "busybox", groups[0].resources[1].GetName())
+	assert.Empty(t, groups[0].oldResources)
+	require.Len(t, groups[1].resources, 1)
+	assert.Equal(t, "Deployment", groups[1].resources[0].GetKind())
+	// without a cluster the update is evaluated against the captured old object
+	old := groups[1].oldResources[groups[1].resources[0]]
+	require.NotNil(t, old)
+	replicas, _, _ := unstructured.NestedFieldNoCopy(old.Object, "spec", "replicas")
+	assert.Equal(t, float64(1), replicas)
+}
+
+func Test_whatIf(t *testing.T) {
+	policy := engineapi.NewKyvernoPolicy(&kyvernov1.ClusterPolicy{ObjectMeta: metav1.ObjectMeta{Name: "require-labels"}})
+	response := func(namespace, name string, owners []metav1.OwnerReference, rules ...engineapi.RuleResponse) engineapi.EngineResponse {
+		var resource unstructured.Unstructured
+		resource.SetAPIVersion("v1")
+		resource.SetKind("Pod")
+		resource.SetNamespace(namespace)
+		resource.SetName(name)
+		resource.SetOwnerReferences(owners)
+		return engineapi.NewEngineResponse(resource, policy, nil).WithPolicyResponse(engineapi.PolicyResponse{Rules: rules})
+	}
+	controller := true
+	owners := []metav1.OwnerReference{
+		{APIVersion: "v1", Kind: "ConfigMap", Name: "config"},
+		{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "web-7d9f", Controller: &controller},
+	}
+	fail := *engineapi.RuleFail("check-team", engineapi.Validation, "the team label is required", nil)
+	w := newWhatIf()
+	w.record(context.TODO(), nil, "",
+		response("team-a", "web-7d9f-x2k4", owners, fail),
+		response("team-a", "web-7d9f-x2k4", owners, fail),
+		response("team-a", "nginx", nil, *engineapi.RulePass("check-team", engineapi.Validation, "", nil)),
+		response("team-b", "nginx", nil, *engineapi.RuleFail("add-labels", engineapi.Mutation, "", nil)),
+	)
+	w.record(context.TODO(), nil, "alice", response("team-a", "nginx", nil, fail))
+	assert.Equal(t, []WhatIfGroup{{
+		Namespace: "team-a",
+		Denials: []WhatIfDenial{
+			{Resource: "Pod/nginx", User: "alice", Policy: "require-labels", Rule: "check-team", Message: "the team label is required", Source: whatIfSourceRequest},
+		},
+	}, {
+		Namespace: "team-a",
+		Owner:     "ReplicaSet/web-7d9f",
+		Denials: []WhatIfDenial{
+			{Resource: "Pod/web-7d9f-x2k4", Policy: "require-labels", Rule: "check-team", Message: "the team label is required", Source: whatIfSourceResource},
+		},
+	}}, w.groups())
+
+	var out bytes.Buffer
+	require.NoError(t, printWhatIf(&out, w, "table"))
+	assert.Contains(t, out.String(), "2 resources in 1 namespace and requests from 1 user would be denied")
+	out.Reset()
+	require.NoError(t, printWhatIf(&out, newWhatIf(), "table"))
+	assert.Equal(t, "\nNo resources or admission requests would be denied\n", out.String())
+}
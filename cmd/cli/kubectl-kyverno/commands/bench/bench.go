@@ -0,0 +1,507 @@
+package bench
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"time"
+
+	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	policiesv1beta1 "github.com/kyverno/kyverno/api/policies.kyverno.io/v1beta1"
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/processor"
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/store"
+	celcompiler "github.com/kyverno/kyverno/pkg/cel/compiler"
+	celengine "github.com/kyverno/kyverno/pkg/cel/engine"
+	"github.com/kyverno/kyverno/pkg/cel/libs"
+	"github.com/kyverno/kyverno/pkg/cel/matching"
+	dpolcompiler "github.com/kyverno/kyverno/pkg/cel/policies/dpol/compiler"
+	dpolengine "github.com/kyverno/kyverno/pkg/cel/policies/dpol/engine"
+	gpolcompiler "github.com/kyverno/kyverno/pkg/cel/policies/gpol/compiler"
+	gpolengine "github.com/kyverno/kyverno/pkg/cel/policies/gpol/engine"
+	ivpolengine "github.com/kyverno/kyverno/pkg/cel/policies/ivpol/engine"
+	mpolcompiler "github.com/kyverno/kyverno/pkg/cel/policies/mpol/compiler"
+	mpolengine "github.com/kyverno/kyverno/pkg/cel/policies/mpol/engine"
+	vpolcompiler "github.com/kyverno/kyverno/pkg/cel/policies/vpol/compiler"
+	vpolengine "github.com/kyverno/kyverno/pkg/cel/policies/vpol/engine"
+	"github.com/kyverno/kyverno/pkg/config"
+	"github.com/kyverno/kyverno/pkg/engine"
+	"github.com/kyverno/kyverno/pkg/engine/adapters"
+	engineapi "github.com/kyverno/kyverno/pkg/engine/api"
+	"github.com/kyverno/kyverno/pkg/engine/factories"
+	"github.com/kyverno/kyverno/pkg/engine/jmespath"
+	"github.com/kyverno/kyverno/pkg/imageverification/imagedataloader"
+	"github.com/kyverno/kyverno/pkg/imageverifycache"
+	"github.com/kyverno/kyverno/pkg/registryclient"
+	"github.com/kyverno/kyverno/pkg/utils/restmapper"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Levels of the measurements.
+const (
+	levelPolicy     = "policy"
+	levelRule       = "rule"
+	levelExpression = "expression"
+)
+
+type sample struct {
+	elapsed time.Duration
+	allocs  uint64
+	bytes   uint64
+}
+
+type sampleKey struct {
+	level  string
+	policy string
+	name   string
+}
+
+// recorder collects the samples of the measured evaluations, it also observes the CEL expressions
+// evaluated by CEL policies.
+type recorder struct {
+	enabled bool
+	samples map[sampleKey][]sample
+}
+
+func newRecorder() *recorder {
+	return &recorder{
+		samples: map[sampleKey][]sample{},
+	}
+}
+
+func (r *recorder) add(key sampleKey, sample sample) {
+	if r.enabled {
+		r.samples[key] = append(r.samples[key], sample)
+	}
+}
+
+// measure runs evaluate and records the latency and the allocations of the policy, with the latencies
+// of the rules it returns. Nothing is recorded when the policy doesn't apply to the resource.
+func (r *recorder) measure(policy string, evaluate func() ([]engineapi.RuleResponse, error)) error {
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	rules, err := evaluate()
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+	if err != nil {
+		return err
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+	r.add(sampleKey{level: levelPolicy, policy: policy}, sample{
+		elapsed: elapsed,
+		allocs:  after.Mallocs - before.Mallocs,
+		bytes:   after.TotalAlloc - before.TotalAlloc,
+	})
+	for _, rule := range rules {
+		r.add(sampleKey{level: levelRule, policy: policy, name: rule.Name()}, sample{elapsed: rule.Stats().ProcessingTime()})
+	}
+	return nil
+}
+
+func (r *recorder) Elapsed(kind, policy, expression string, elapsed time.Duration) {
+	r.add(sampleKey{level: levelExpression, policy: policy, name: expression}, sample{elapsed: elapsed})
+}
+
+func (r *recorder) Expression(string, string, string, bool, error) {}
+
+// evaluation evaluates a policy against a resource.
+type evaluation struct {
+	policy   string
+	evaluate func() ([]engineapi.RuleResponse, error)
+}
+
+type benchmark struct {
+	policies                []kyvernov1.PolicyInterface
+	validatingPolicies      []policiesv1beta1.ValidatingPolicyLike
+	mutatingPolicies        []policiesv1beta1.MutatingPolicyLike
+	imageValidatingPolicies []policiesv1beta1.ImageValidatingPolicyLike
+	generatingPolicies      []policiesv1beta1.GeneratingPolicy
+	deletingPolicies        []policiesv1beta1.DeletingPolicyLike
+	resources               []*unstructured.Unstructured
+	contextPath             string
+}
+
+// run evaluates each policy against each resource for the warm-up iterations, then for the measured iterations.
+func (b benchmark) run(ctx context.Context, iterations, warmup int) (map[sampleKey][]sample, error) {
+	recorder := newRecorder()
+	ctx = celcompiler.WithObserver(ctx, recorder)
+	evaluations := b.kyvernoEvaluations(ctx)
+	for _, build := range []func(context.Context) ([]evaluation, error){
+		b.validatingEvaluations,
+		b.mutatingEvaluations,
+		b.imageValidatingEvaluations,
+		b.generatingEvaluations,
+		b.deletingEvaluations,
+	} {
+		celEvaluations, err := build(ctx)
+		if err != nil {
+			return nil, err
+		}
+		evaluations = append(evaluations, celEvaluations...)
+	}
+	for i := 0; i < warmup+iterations; i++ {
+		recorder.enabled = i >= warmup
+		for _, evaluation := range evaluations {
+			if err := recorder.measure(evaluation.policy, evaluation.evaluate); err != nil {
+				return nil, fmt.Errorf("failed to evaluate policy %s (%w)", evaluation.policy, err)
+			}
+		}
+	}
+	return recorder.samples, nil
+}
+
+func (b benchmark) kyvernoEvaluations(ctx context.Context) []evaluation {
+	if len(b.policies) == 0 {
+		return nil
+	}
+	cfg := config.NewDefaultConfiguration(false)
+	jp := jmespath.New(cfg)
+	isCluster := false
+	eng := engine.NewEngine(
+		cfg,
+		jp,
+		nil,
+		factories.DefaultRegistryClientFactory(adapters.RegistryClient(registryclient.NewOrDie()), nil),
+		imageverifycache.DisabledImageVerifyCache(),
+		store.ContextLoaderFactory(&store.Store{}, nil),
+		nil,
+		&isCluster,
+	)
+	newPolicyContext := func(policy kyvernov1.PolicyInterface, resource unstructured.Unstructured) (engineapi.PolicyContext, error) {
+		policyContext, err := engine.NewPolicyContext(jp, resource, kyvernov1.Create, nil, cfg)
+		if err != nil {
+			return nil, err
+		}
+		return policyContext.WithPolicy(policy).WithResourceKind(resource.GroupVersionKind(), ""), nil
+	}
+	var evaluations []evaluation
+	for _, resource := range b.resources {
+		for _, policy := range b.policies {
+			spec := policy.GetSpec()
+			if !supported(spec) {
+				continue
+			}
+			name, _ := cache.MetaNamespaceKeyFunc(policy)
+			evaluations = append(evaluations, evaluation{
+				policy: name,
+				evaluate: func() ([]engineapi.RuleResponse, error) {
+					var rules []engineapi.RuleResponse
+					// rules are evaluated in the order of admission controllers
+					if spec.HasMutate() {
+						policyContext, err := newPolicyContext(policy, *resource)
+						if err != nil {
+							return nil, err
+						}
+						rules = append(rules, eng.Mutate(ctx, policyContext).PolicyResponse.Rules...)
+					}
+					if spec.HasVerifyImages() {
+						policyContext, err := newPolicyContext(policy, *resource)
+						if err != nil {
+							return nil, err
+						}
+						response, _ := eng.VerifyAndPatchImages(ctx, policyContext)
+						rules = append(rules, response.PolicyResponse.Rules...)
+					}
+					if spec.HasValidate() {
+						policyContext, err := newPolicyContext(policy, *resource)
+						if err != nil {
+							return nil, err
+						}
+						rules = append(rules, eng.Validate(ctx, policyContext).PolicyResponse.Rules...)
+					}
+					if spec.HasGenerate() {
+						policyContext, err := newPolicyContext(policy, *resource)
+						if err != nil {
+							return nil, err
+						}
+						rules = append(rules, eng.Generate(ctx, policyContext).PolicyResponse.Rules...)
+					}
+					return rules, nil
+				},
+			})
+		}
+	}
+	return evaluations
+}
+
+// supported returns true if the benchmark evaluates rules of the policy.
+func supported(spec *kyvernov1.Spec) bool {
+	return spec.HasMutate() || spec.HasValidate() || spec.HasVerifyImages() || spec.HasGenerate()
+}
+
+// celEnvironment returns the rest mapper and the context provider of CEL engines, with the admission requests
+// creating the resources in order. There is no access to a cluster.
+func (b benchmark) celEnvironment() (meta.RESTMapper, libs.Context, []celengine.EngineRequest, error) {
+	restMapper, err := restmapper.GetRESTMapper(nil, true)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	contextProvider, err := processor.NewContextProvider(nil, restMapper, nil, b.contextPath, false, true)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	requests := make([]celengine.EngineRequest, 0, len(b.resources))
+	for _, resource := range b.resources {
+		gvk := resource.GroupVersionKind()
+		mapping, err := restMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to map gvk to gvr %s (%w)", gvk, err)
+		}
+		requests = append(requests, celengine.Request(
+			contextProvider,
+			gvk,
+			mapping.Resource,
+			"",
+			resource.GetName(),
+			resource.GetNamespace(),
+			admissionv1.Create,
+			authenticationv1.UserInfo{},
+			resource,
+			nil,
+			false,
+			nil,
+		))
+	}
+	return restMapper, contextProvider, requests, nil
+}
+
+func noNamespace(string) *corev1.Namespace { return nil }
+
+func (b benchmark) validatingEvaluations(ctx context.Context) ([]evaluation, error) {
+	if len(b.validatingPolicies) == 0 {
+		return nil, nil
+	}
+	// policies are compiled once, like in admission controllers
+	provider, err := vpolengine.NewProvider(vpolcompiler.NewCompiler(), b.validatingPolicies, nil)
+	if err != nil {
+		return nil, err
+	}
+	_, _, requests, err := b.celEnvironment()
+	if err != nil {
+		return nil, err
+	}
+	eng := vpolengine.NewEngine(provider, noNamespace, matching.NewMatcher())
+	var evaluations []evaluation
+	for _, request := range requests {
+		for _, policy := range b.validatingPolicies {
+			name, _ := cache.MetaNamespaceKeyFunc(policy)
+			predicate := func(candidate policiesv1beta1.ValidatingPolicyLike) bool {
+				return candidate.GetNamespace() == policy.GetNamespace() && candidate.GetName() == policy.GetName()
+			}
+			evaluations = append(evaluations, evaluation{
+				policy: name,
+				evaluate: func() ([]engineapi.RuleResponse, error) {
+					response, err := eng.Handle(ctx, request, predicate)
+					if err != nil {
+						return nil, err
+					}
+					var rules []engineapi.RuleResponse
+					for _, policy := range response.Policies {
+						rules = append(rules, policy.Rules...)
+					}
+					return rules, nil
+				},
+			})
+		}
+	}
+	return evaluations, nil
+}
+
+func (b benchmark) mutatingEvaluations(ctx context.Context) ([]evaluation, error) {
+	if len(b.mutatingPolicies) == 0 {
+		return nil, nil
+	}
+	provider, err := mpolengine.NewProvider(mpolcompiler.NewCompiler(), b.mutatingPolicies, nil)
+	if err != nil {
+		return nil, err
+	}
+	_, contextProvider, requests, err := b.celEnvironment()
+	if err != nil {
+		return nil, err
+	}
+	typeConverter := mpolcompiler.NewStaticTypeConverterManager(processor.NewLocalOpenAPIClient())
+	eng := mpolengine.NewEngine(provider, noNamespace, matching.NewMatcher(), typeConverter, contextProvider)
+	var evaluations []evaluation
+	for _, request := range requests {
+		for _, policy := range b.mutatingPolicies {
+			name, _ := cache.MetaNamespaceKeyFunc(policy)
+			predicate := func(candidate policiesv1beta1.MutatingPolicyLike) bool {
+				return candidate.GetNamespace() == policy.GetNamespace() && candidate.GetName() == policy.GetName()
+			}
+			evaluations = append(evaluations, evaluation{
+				policy: name,
+				evaluate: func() ([]engineapi.RuleResponse, error) {
+					response, err := eng.Handle(ctx, request, predicate)
+					if err != nil {
+						return nil, err
+					}
+					var rules []engineapi.RuleResponse
+					for _, policy := range response.Policies {
+						rules = append(rules, policy.Rules...)
+					}
+					return rules, nil
+				},
+			})
+		}
+	}
+	return evaluations, nil
+}
+
+// imageValidatingEvaluations evaluates image validating policies like the mutating webhook, images are fetched
+// from registries without credentials.
+func (b benchmark) imageValidatingEvaluations(ctx context.Context) ([]evaluation, error) {
+	if len(b.imageValidatingPolicies) == 0 {
+		return nil, nil
+	}
+	provider, err := ivpolengine.NewProvider(b.imageValidatingPolicies, nil)
+	if err != nil {
+		return nil, err
+	}
+	_, _, requests, err := b.celEnvironment()
+	if err != nil {
+		return nil, err
+	}
+	eng := ivpolengine.NewEngine(
+		provider,
+		noNamespace,
+		matching.NewMatcher(),
+		nil,
+		[]imagedataloader.Option{imagedataloader.WithLocalCredentials(false)},
+		nil,
+		nil,
+	)
+	var evaluations []evaluation
+	for _, request := range requests {
+		for _, policy := range b.imageValidatingPolicies {
+			name, _ := cache.MetaNamespaceKeyFunc(policy)
+			predicate := func(candidate policiesv1beta1.ImageValidatingPolicyLike) bool {
+				return candidate.GetNamespace() == policy.GetNamespace() && candidate.GetName() == policy.GetName()
+			}
+			evaluations = append(evaluations, evaluation{
+				policy: name,
+				evaluate: func() ([]engineapi.RuleResponse, error) {
+					response, _, err := eng.HandleMutating(ctx, request, predicate)
+					if err != nil {
+						return nil, err
+					}
+					var rules []engineapi.RuleResponse
+					for _, policy := range response.Policies {
+						// policies that don't match have no result
+						if policy.Result.Status() != "" {
+							rules = append(rules, policy.Result)
+						}
+					}
+					return rules, nil
+				},
+			})
+		}
+	}
+	return evaluations, nil
+}
+
+func (b benchmark) generatingEvaluations(ctx context.Context) ([]evaluation, error) {
+	if len(b.generatingPolicies) == 0 {
+		return nil, nil
+	}
+	compiler := gpolcompiler.NewCompiler()
+	policies := make([]gpolengine.Policy, 0, len(b.generatingPolicies))
+	for i := range b.generatingPolicies {
+		policy := &b.generatingPolicies[i]
+		compiled, errs := compiler.Compile(policy, nil)
+		if len(errs) > 0 {
+			return nil, fmt.Errorf("failed to compile policy %s (%w)", policy.GetName(), errs.ToAggregate())
+		}
+		policies = append(policies, gpolengine.Policy{
+			Policy:         policy,
+			CompiledPolicy: compiled,
+		})
+	}
+	_, contextProvider, requests, err := b.celEnvironment()
+	if err != nil {
+		return nil, err
+	}
+	eng := gpolengine.NewEngine(noNamespace, matching.NewMatcher())
+	var evaluations []evaluation
+	for _, request := range requests {
+		for _, policy := range policies {
+			name, _ := cache.MetaNamespaceKeyFunc(policy.Policy)
+			evaluations = append(evaluations, evaluation{
+				policy: name,
+				evaluate: func() ([]engineapi.RuleResponse, error) {
+					// the generated resources are not applied, they are dropped to not accumulate across iterations
+					defer contextProvider.ClearGeneratedResources()
+					response, err := eng.Handle(ctx, request, policy, false)
+					if err != nil {
+						return nil, err
+					}
+					var rules []engineapi.RuleResponse
+					for _, policy := range response.Policies {
+						if policy.Result != nil {
+							rules = append(rules, *policy.Result)
+						}
+					}
+					return rules, nil
+				},
+			})
+		}
+	}
+	return evaluations, nil
+}
+
+// deletingEvaluations evaluates deleting policies against the resources they match, like the cleanup controller
+// does at each scheduled run.
+func (b benchmark) deletingEvaluations(ctx context.Context) ([]evaluation, error) {
+	if len(b.deletingPolicies) == 0 {
+		return nil, nil
+	}
+	provider, err := dpolengine.NewProvider(dpolcompiler.NewCompiler(), b.deletingPolicies, nil)
+	if err != nil {
+		return nil, err
+	}
+	policies, err := provider.Fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	restMapper, contextProvider, _, err := b.celEnvironment()
+	if err != nil {
+		return nil, err
+	}
+	eng := dpolengine.NewEngine(noNamespace, restMapper, contextProvider, matching.NewMatcher())
+	var evaluations []evaluation
+	for _, resource := range b.resources {
+		for _, policy := range policies {
+			matches, err := eng.Match(policy, *resource)
+			if err != nil {
+				return nil, err
+			}
+			if !matches {
+				continue
+			}
+			name, _ := cache.MetaNamespaceKeyFunc(policy.Policy)
+			evaluations = append(evaluations, evaluation{
+				policy: name,
+				evaluate: func() ([]engineapi.RuleResponse, error) {
+					start := time.Now()
+					response, err := eng.Handle(ctx, policy, *resource)
+					if err != nil {
+						return nil, err
+					}
+					status := engineapi.RuleStatusFail
+					if response.Match {
+						status = engineapi.RuleStatusPass
+					}
+					rule := engineapi.NewRuleResponse(policy.Policy.GetName(), engineapi.Deletion, "", status, nil)
+					return []engineapi.RuleResponse{rule.WithStats(engineapi.NewExecutionStats(start, time.Now()))}, nil
+				},
+			})
+		}
+	}
+	return evaluations, nil
+}
@@ -0,0 +1,35 @@
+package bench
+
+import (
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/command"
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/output/color"
+	"github.com/spf13/cobra"
+)
+
+func Command() *cobra.Command {
+	var options options
+	var removeColor bool
+	cmd := &cobra.Command{
+		Use:          "bench [policy]...",
+		Short:        command.FormatDescription(true, websiteUrl, false, description...),
+		Long:         command.FormatDescription(false, websiteUrl, false, description...),
+		Example:      command.FormatExamples(examples...),
+		Args:         cobra.MinimumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := options.validate(); err != nil {
+				return err
+			}
+			color.Init(removeColor || options.outputFormat != "table")
+			return options.execute(cmd.Context(), cmd.OutOrStdout(), args...)
+		},
+	}
+	cmd.Flags().StringSliceVarP(&options.resourcePaths, "resource", "r", nil, "Path to resource files")
+	cmd.Flags().StringSliceVar(&options.resourcePaths, "resources", nil, "Path to resource files")
+	cmd.Flags().StringVar(&options.contextPath, "context-file", "", "File containing context data for CEL policies")
+	cmd.Flags().IntVar(&options.iterations, "iterations", 100, "Number of measured evaluations of each policy against each resource")
+	cmd.Flags().IntVar(&options.warmup, "warmup", 1, "Number of evaluations of each policy against each resource before measuring")
+	cmd.Flags().StringVarP(&options.outputFormat, "output-format", "o", "table", "Specifies the output format (table, json)")
+	cmd.Flags().BoolVar(&removeColor, "remove-color", false, "Remove any color from output")
+	return cmd
+}
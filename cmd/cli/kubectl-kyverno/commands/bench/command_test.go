@@ -0,0 +1,250 @@
+package bench
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const policies = `
+apiVersion: policies.kyverno.io/v1alpha1
+kind: ValidatingPolicy
+metadata:
+  name: check-deployment-labels
+spec:
+  matchConstraints:
+    resourceRules:
+    - apiGroups:   ["apps"]
+      apiVersions: ["v1"]
+      operations:  ["CREATE", "UPDATE"]
+      resources:   ["deployments"]
+  variables:
+    - name: environment
+      expression: "has(object.metadata.labels) && 'env' in object.metadata.labels"
+  validations:
+    - expression: "variables.environment == true"
+      message: "Deployment labels must have env"
+---
+apiVersion: kyverno.io/v1
+kind: ClusterPolicy
+metadata:
+  name: require-image-tag
+spec:
+  rules:
+  - name: require-image-tag
+    match:
+      any:
+      - resources:
+          kinds:
+          - Pod
+    validate:
+      message: An image tag is required.
+      pattern:
+        spec:
+          containers:
+          - image: '*:*'
+`
+
+const resources = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+  labels:
+    env: prod
+spec:
+  selector:
+    matchLabels:
+      app: nginx
+  template:
+    metadata:
+      labels:
+        app: nginx
+    spec:
+      containers:
+      - name: nginx
+        image: nginx:1.27
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: nginx
+spec:
+  containers:
+  - name: nginx
+    image: nginx
+`
+
+func TestCommand(t *testing.T) {
+	dir := t.TempDir()
+	policyPath := filepath.Join(dir, "policies.yaml")
+	resourcePath := filepath.Join(dir, "resources.yaml")
+	require.NoError(t, os.WriteFile(policyPath, []byte(policies), 0o600))
+	require.NoError(t, os.WriteFile(resourcePath, []byte(resources), 0o600))
+	cmd := Command()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{policyPath, "--resource", resourcePath, "--iterations", "3", "--output-format", "json"})
+	require.NoError(t, cmd.Execute())
+	var result Result
+	require.NoError(t, json.Unmarshal(out.Bytes(), &result))
+	assert.Equal(t, 3, result.Iterations)
+	assert.Equal(t, 2, result.Resources)
+	counts := map[string]int{}
+	for _, stats := range result.Stats {
+		counts[stats.Level+" "+stats.Policy+" "+stats.Name] = stats.Count
+	}
+	assert.Equal(t, map[string]int{
+		"policy check-deployment-labels ":                          3,
+		"rule check-deployment-labels ":                            3,
+		"expression check-deployment-labels variables.environment": 3,
+		"expression check-deployment-labels validations[0]":        3,
+		"policy require-image-tag ":                                6,
+		"rule require-image-tag require-image-tag":                 3,
+		"rule require-image-tag autogen-require-image-tag":         3,
+	}, counts)
+}
+
+func TestCommandInvalidFlags(t *testing.T) {
+	tests := [][]string{
+		{"policy.yaml"},
+		{"policy.yaml", "--resource", "resource.yaml", "--iterations", "0"},
+		{"policy.yaml", "--resource", "resource.yaml", "--warmup", "-1"},
+		{"policy.yaml", "--resource", "resource.yaml", "--output-format", "yaml"},
+		{"--resource", "resource.yaml"},
+	}
+	for _, args := range tests {
+		cmd := Command()
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs(args)
+		assert.Error(t, cmd.Execute(), args)
+	}
+}
+
+const celPolicies = `
+apiVersion: policies.kyverno.io/v1alpha1
+kind: MutatingPolicy
+metadata:
+  name: add-managed-label
+spec:
+  matchConstraints:
+    resourceRules:
+    - apiGroups:   ["apps"]
+      apiVersions: ["v1"]
+      operations:  ["CREATE"]
+      resources:   ["deployments"]
+  mutations:
+  - patchType: ApplyConfiguration
+    applyConfiguration:
+      expression: >-
+        Object{metadata: Object.metadata{labels: {"managed": "true"}}}
+---
+apiVersion: policies.kyverno.io/v1alpha1
+kind: GeneratingPolicy
+metadata:
+  name: generate-cm
+spec:
+  matchConstraints:
+    resourceRules:
+    - apiGroups:   [""]
+      apiVersions: ["v1"]
+      operations:  ["CREATE"]
+      resources:   ["namespaces"]
+  variables:
+  - name: configmap
+    expression: >-
+      [{"kind": dyn("ConfigMap"), "apiVersion": dyn("v1"), "metadata": dyn({"name": "config", "namespace": string(object.metadata.name)})}]
+  generate:
+  - expression: generator.Apply(string(object.metadata.name), variables.configmap)
+---
+apiVersion: policies.kyverno.io/v1alpha1
+kind: DeletingPolicy
+metadata:
+  name: delete-nginx
+spec:
+  schedule: '*/1 * * * *'
+  matchConstraints:
+    resourceRules:
+    - apiGroups:   [""]
+      apiVersions: ["v1"]
+      resources:   ["pods"]
+  conditions:
+  - name: is-nginx
+    expression: object.metadata.name == "nginx"
+---
+apiVersion: kyverno.io/v1
+kind: ClusterPolicy
+metadata:
+  name: generate-quota
+spec:
+  rules:
+  - name: generate-quota
+    match:
+      any:
+      - resources:
+          kinds:
+          - Namespace
+    generate:
+      apiVersion: v1
+      kind: ResourceQuota
+      name: default
+      namespace: '{{request.object.metadata.name}}'
+      data:
+        spec:
+          hard:
+            pods: "10"
+---
+apiVersion: admissionregistration.k8s.io/v1
+kind: ValidatingAdmissionPolicy
+metadata:
+  name: check-replicas
+spec:
+  matchConstraints:
+    resourceRules:
+    - apiGroups:   ["apps"]
+      apiVersions: ["v1"]
+      operations:  ["CREATE"]
+      resources:   ["deployments"]
+  validations:
+  - expression: object.spec.replicas <= 5
+`
+
+func TestCommandPolicyKinds(t *testing.T) {
+	dir := t.TempDir()
+	policyPath := filepath.Join(dir, "policies.yaml")
+	resourcePath := filepath.Join(dir, "resources.yaml")
+	require.NoError(t, os.WriteFile(policyPath, []byte(celPolicies), 0o600))
+	require.NoError(t, os.WriteFile(resourcePath, []byte(resources+"---\napiVersion: v1\nkind: Namespace\nmetadata:\n  name: team-a\n"), 0o600))
+	cmd := Command()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{policyPath, "--resource", resourcePath, "--iterations", "2", "--output-format", "json"})
+	require.NoError(t, cmd.Execute())
+	var result Result
+	require.NoError(t, json.Unmarshal(out.Bytes(), &result))
+	counts := map[string]int{}
+	for _, stats := range result.Stats {
+		counts[stats.Level+" "+stats.Policy+" "+stats.Name] = stats.Count
+	}
+	assert.Equal(t, map[string]int{
+		"policy add-managed-label ":                  2,
+		"rule add-managed-label ":                    2,
+		"expression add-managed-label mutations[0]":  2,
+		"policy generate-cm ":                        2,
+		"rule generate-cm generate-cm":               2,
+		"expression generate-cm variables.configmap": 2,
+		"expression generate-cm generate[0]":         2,
+		"policy delete-nginx ":                       2,
+		"rule delete-nginx delete-nginx":             2,
+		"expression delete-nginx conditions[0]":      2,
+		"policy generate-quota ":                     2,
+		"rule generate-quota generate-quota":         2,
+	}, counts)
+	assert.Equal(t, []string{"ValidatingAdmissionPolicy/check-replicas"}, result.Skipped)
+}
@@ -0,0 +1,32 @@
+package bench
+
+var websiteUrl = `https://kyverno.io/docs/kyverno-cli/#bench`
+
+var description = []string{
+	`Benchmark the evaluation of policies against resources.`,
+	``,
+	`Policies are evaluated against each resource repeatedly, with the engines used by admission controllers.`,
+	`The command reports the p50, p95 and p99 latencies per policy, rule and CEL expression, and the allocations per policy.`,
+	`Policies are only measured against the resources they apply to, after warm-up iterations that are not measured.`,
+	``,
+	`ClusterPolicies and Policies (mutate, verifyImages, validate and generate rules) and validating, mutating,`,
+	`image validating, generating and deleting policies are supported, expressions are measured for the latter.`,
+	`ValidatingAdmissionPolicies, MutatingAdmissionPolicies and other rules are skipped.`,
+	`Generated resources are not created and images are fetched from registries without credentials.`,
+	`Resources read by CEL libraries are loaded from the context file, there is no access to a cluster.`,
+}
+
+var examples = [][]string{
+	{
+		`# Benchmark policies against a folder of resources`,
+		`kyverno bench /path/to/policies/ --resource /path/to/resources/`,
+	},
+	{
+		`# Benchmark a policy with more iterations and print the results in JSON`,
+		`kyverno bench /path/to/policy.yaml --resource /path/to/resource.yaml --iterations 1000 --output-format json`,
+	},
+	{
+		`# Benchmark a policy listing resources with the CEL resource library`,
+		`kyverno bench /path/to/policy.yaml --resource /path/to/resource.yaml --context-file /path/to/context.yaml`,
+	},
+}
@@ -0,0 +1,117 @@
+package bench
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/policy"
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/utils/common"
+	"github.com/kyverno/kyverno/pkg/cli/loader"
+	"k8s.io/client-go/tools/cache"
+)
+
+type options struct {
+	resourcePaths []string
+	contextPath   string
+	iterations    int
+	warmup        int
+	outputFormat  string
+}
+
+func (o options) validate() error {
+	if len(o.resourcePaths) == 0 {
+		return fmt.Errorf("resource file(s) required")
+	}
+	if o.iterations < 1 {
+		return fmt.Errorf("iterations must be at least 1, got %d", o.iterations)
+	}
+	if o.warmup < 0 {
+		return fmt.Errorf("warmup must not be negative, got %d", o.warmup)
+	}
+	switch o.outputFormat {
+	case "table", "json":
+		return nil
+	default:
+		return fmt.Errorf("invalid output format %q, expected (table, json)", o.outputFormat)
+	}
+}
+
+func (o options) execute(ctx context.Context, out io.Writer, policyPaths ...string) error {
+	results, err := policy.Load(nil, "", policyPaths...)
+	if err != nil {
+		return fmt.Errorf("failed to load policies (%w)", err)
+	}
+	resources, err := common.GetResourceAccordingToResourcePath(io.Discard, nil, o.resourcePaths, false, nil, nil, "", true, false, "", loader.ResourceOptions{Timeout: time.Minute}, false)
+	if err != nil {
+		return fmt.Errorf("failed to load resources (%w)", err)
+	}
+	bench := benchmark{
+		policies:           results.Policies,
+		generatingPolicies: results.GeneratingPolicies,
+		resources:          resources,
+		contextPath:        o.contextPath,
+	}
+	for i := range results.ValidatingPolicies {
+		bench.validatingPolicies = append(bench.validatingPolicies, &results.ValidatingPolicies[i])
+	}
+	for i := range results.NamespacedValidatingPolicies {
+		bench.validatingPolicies = append(bench.validatingPolicies, &results.NamespacedValidatingPolicies[i])
+	}
+	for i := range results.MutatingPolicies {
+		bench.mutatingPolicies = append(bench.mutatingPolicies, &results.MutatingPolicies[i])
+	}
+	for i := range results.NamespacedMutatingPolicies {
+		bench.mutatingPolicies = append(bench.mutatingPolicies, &results.NamespacedMutatingPolicies[i])
+	}
+	for i := range results.ImageValidatingPolicies {
+		bench.imageValidatingPolicies = append(bench.imageValidatingPolicies, &results.ImageValidatingPolicies[i])
+	}
+	for i := range results.NamespacedImageValidatingPolicies {
+		bench.imageValidatingPolicies = append(bench.imageValidatingPolicies, &results.NamespacedImageValidatingPolicies[i])
+	}
+	for i := range results.DeletingPolicies {
+		bench.deletingPolicies = append(bench.deletingPolicies, &results.DeletingPolicies[i])
+	}
+	for i := range results.NamespacedDeletingPolicies {
+		bench.deletingPolicies = append(bench.deletingPolicies, &results.NamespacedDeletingPolicies[i])
+	}
+	samples, err := bench.run(ctx, o.iterations, o.warmup)
+	if err != nil {
+		return err
+	}
+	result := Result{
+		Iterations: o.iterations,
+		Resources:  len(resources),
+		Stats:      summarize(samples),
+		Skipped:    skipped(results),
+	}
+	if o.outputFormat == "json" {
+		return printJSON(out, result)
+	}
+	printTable(out, result)
+	return nil
+}
+
+// skipped returns the kinds and names of the loaded policies the benchmark doesn't support,
+// including the Kyverno policies without rules the benchmark evaluates.
+func skipped(results *policy.LoaderResults) []string {
+	var names []string
+	add := func(kind string, obj any) {
+		name, _ := cache.MetaNamespaceKeyFunc(obj)
+		names = append(names, kind+"/"+name)
+	}
+	for _, policy := range results.Policies {
+		if !supported(policy.GetSpec()) {
+			add(policy.GetKind(), policy)
+		}
+	}
+	for i := range results.VAPs {
+		add("ValidatingAdmissionPolicy", &results.VAPs[i])
+	}
+	for i := range results.MAPs {
+		add("MutatingAdmissionPolicy", &results.MAPs[i])
+	}
+	return names
+}
@@ -0,0 +1,76 @@
+package bench
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/output/table"
+	"github.com/kyverno/kyverno/ext/output/pluralize"
+)
+
+// Result holds the stats of a benchmark, Skipped holds the policies of unsupported kinds.
+type Result struct {
+	Iterations int      `json:"iterations"`
+	Resources  int      `json:"resources"`
+	Stats      []Stats  `json:"stats"`
+	Skipped    []string `json:"skipped,omitempty"`
+}
+
+type row struct {
+	Policy string `header:"policy"`
+	Level  string `header:"level"`
+	Name   string `header:"name"`
+	Count  int    `header:"count"`
+	P50    string `header:"p50"`
+	P95    string `header:"p95"`
+	P99    string `header:"p99"`
+	Allocs string `header:"allocs/op"`
+	Bytes  string `header:"bytes/op"`
+}
+
+func printTable(out io.Writer, result Result) {
+	fmt.Fprintf(out, "%d %s against %d %s\n",
+		result.Iterations, pluralize.Pluralize(result.Iterations, "iteration", "iterations"),
+		result.Resources, pluralize.Pluralize(result.Resources, "resource", "resources"))
+	for _, skipped := range result.Skipped {
+		fmt.Fprintf(out, "skipped %s, the policy kind is not supported\n", skipped)
+	}
+	if len(result.Stats) == 0 {
+		fmt.Fprintln(out, "no policy applies to the resources")
+		return
+	}
+	rows := make([]row, 0, len(result.Stats))
+	for _, stats := range result.Stats {
+		row := row{
+			Policy: stats.Policy,
+			Level:  stats.Level,
+			Name:   stats.Name,
+			Count:  stats.Count,
+			P50:    stats.P50.String(),
+			P95:    stats.P95.String(),
+			P99:    stats.P99.String(),
+			Allocs: "-",
+			Bytes:  "-",
+		}
+		if row.Name == "" {
+			row.Name = "-"
+		}
+		if stats.Level == levelPolicy {
+			row.Allocs = fmt.Sprint(stats.AllocsPerOp)
+			row.Bytes = fmt.Sprint(stats.BytesPerOp)
+		}
+		rows = append(rows, row)
+	}
+	fmt.Fprintln(out)
+	table.NewTablePrinter(out).Print(rows)
+}
+
+func printJSON(out io.Writer, result Result) error {
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(out, string(data))
+	return nil
+}
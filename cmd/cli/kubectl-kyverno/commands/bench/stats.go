@@ -0,0 +1,85 @@
+package bench
+
+import (
+	"cmp"
+	"slices"
+	"time"
+)
+
+// Stats summarizes the measurements of a policy, a rule or an expression, latencies are in nanoseconds in JSON.
+type Stats struct {
+	Level       string        `json:"level"`
+	Policy      string        `json:"policy"`
+	Name        string        `json:"name,omitempty"`
+	Count       int           `json:"count"`
+	P50         time.Duration `json:"p50"`
+	P95         time.Duration `json:"p95"`
+	P99         time.Duration `json:"p99"`
+	AllocsPerOp uint64        `json:"allocsPerOp,omitempty"`
+	BytesPerOp  uint64        `json:"bytesPerOp,omitempty"`
+}
+
+// summarize computes the stats of the samples. Policies are sorted by decreasing p99 latency,
+// each policy is followed by its rules then its expressions, also sorted by decreasing p99 latency.
+func summarize(samples map[sampleKey][]sample) []Stats {
+	byPolicy := map[string][]Stats{}
+	var policies []Stats
+	for key, samples := range samples {
+		durations := make([]time.Duration, 0, len(samples))
+		var allocs, bytes uint64
+		for _, sample := range samples {
+			durations = append(durations, sample.elapsed)
+			allocs += sample.allocs
+			bytes += sample.bytes
+		}
+		slices.Sort(durations)
+		stats := Stats{
+			Level:  key.level,
+			Policy: key.policy,
+			Name:   key.name,
+			Count:  len(samples),
+			P50:    percentile(durations, 50),
+			P95:    percentile(durations, 95),
+			P99:    percentile(durations, 99),
+		}
+		if key.level == levelPolicy {
+			stats.AllocsPerOp = allocs / uint64(len(samples))
+			stats.BytesPerOp = bytes / uint64(len(samples))
+			policies = append(policies, stats)
+		} else {
+			byPolicy[key.policy] = append(byPolicy[key.policy], stats)
+		}
+	}
+	byLatency := func(a, b Stats) int {
+		return cmp.Or(cmp.Compare(b.P99, a.P99), cmp.Compare(a.Name, b.Name))
+	}
+	slices.SortFunc(policies, func(a, b Stats) int {
+		return cmp.Or(byLatency(a, b), cmp.Compare(a.Policy, b.Policy))
+	})
+	result := make([]Stats, 0, len(samples))
+	for _, policy := range policies {
+		result = append(result, policy)
+		children := byPolicy[policy.Policy]
+		slices.SortFunc(children, func(a, b Stats) int {
+			return cmp.Or(cmp.Compare(levelOrder(a.Level), levelOrder(b.Level)), byLatency(a, b))
+		})
+		result = append(result, children...)
+	}
+	return result
+}
+
+// percentile returns the nearest rank percentile of sorted durations.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (p*len(sorted) + 99) / 100
+	return sorted[max(rank, 1)-1]
+}
+
+func levelOrder(level string) int {
+	if level == levelRule {
+		return 0
+	}
+	return 1
+}
@@ -0,0 +1,39 @@
+package bench
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_percentile(t *testing.T) {
+	var durations []time.Duration
+	for i := 1; i <= 200; i++ {
+		durations = append(durations, time.Duration(i))
+	}
+	assert.Equal(t, time.Duration(100), percentile(durations, 50))
+	assert.Equal(t, time.Duration(190), percentile(durations, 95))
+	assert.Equal(t, time.Duration(198), percentile(durations, 99))
+	assert.Equal(t, time.Duration(7), percentile([]time.Duration{7}, 50))
+	assert.Equal(t, time.Duration(0), percentile(nil, 99))
+}
+
+func Test_summarize(t *testing.T) {
+	samples := map[sampleKey][]sample{
+		{level: levelPolicy, policy: "fast"}:                             {{elapsed: 1, allocs: 10, bytes: 100}, {elapsed: 3, allocs: 20, bytes: 300}},
+		{level: levelRule, policy: "fast", name: "check"}:                {{elapsed: 1}, {elapsed: 2}},
+		{level: levelPolicy, policy: "slow"}:                             {{elapsed: 10, allocs: 5, bytes: 50}},
+		{level: levelExpression, policy: "slow", name: "validations[0]"}: {{elapsed: 8}},
+		{level: levelExpression, policy: "slow", name: "validations[1]"}: {{elapsed: 1}},
+		{level: levelRule, policy: "slow", name: "check"}:                {{elapsed: 9}},
+	}
+	assert.Equal(t, []Stats{
+		{Level: levelPolicy, Policy: "slow", Count: 1, P50: 10, P95: 10, P99: 10, AllocsPerOp: 5, BytesPerOp: 50},
+		{Level: levelRule, Policy: "slow", Name: "check", Count: 1, P50: 9, P95: 9, P99: 9},
+		{Level: levelExpression, Policy: "slow", Name: "validations[0]", Count: 1, P50: 8, P95: 8, P99: 8},
+		{Level: levelExpression, Policy: "slow", Name: "validations[1]", Count: 1, P50: 1, P95: 1, P99: 1},
+		{Level: levelPolicy, Policy: "fast", Count: 2, P50: 1, P95: 3, P99: 3, AllocsPerOp: 15, BytesPerOp: 200},
+		{Level: levelRule, Policy: "fast", Name: "check", Count: 2, P50: 1, P95: 2, P99: 2},
+	}, summarize(samples))
+}
@@ -3,14 +3,18 @@ package commands
 import (
 	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/command"
 	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/commands/apply"
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/commands/bench"
 	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/commands/completion"
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/commands/convert"
 	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/commands/create"
 	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/commands/docs"
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/commands/explain"
 	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/commands/fix"
 	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/commands/jp"
 	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/commands/json"
 	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/commands/migrate"
 	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/commands/oci"
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/commands/report"
 	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/commands/test"
 	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/commands/version"
 	"github.com/spf13/cobra"
@@ -28,12 +32,16 @@ func RootCommand(experimental bool) *cobra.Command {
 	}
 	cmd.AddCommand(
 		apply.Command(),
+		bench.Command(),
 		completion.Command(),
+		convert.Command(),
 		create.Command(),
 		docs.Command(cmd),
+		explain.Command(),
 		jp.Command(),
 		json.Command(),
 		migrate.Command(),
+		report.Command(),
 		test.Command(),
 		version.Command(),
 	)
@@ -0,0 +1,28 @@
+package convert
+
+import (
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/command"
+	"github.com/spf13/cobra"
+)
+
+func Command() *cobra.Command {
+	var options options
+	cmd := &cobra.Command{
+		Use:          "convert [path]...",
+		Short:        command.FormatDescription(true, websiteUrl, false, description...),
+		Long:         command.FormatDescription(false, websiteUrl, false, description...),
+		Example:      command.FormatExamples(examples...),
+		Args:         cobra.MinimumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := options.validate(args...); err != nil {
+				return err
+			}
+			return options.execute(cmd.OutOrStdout(), cmd.ErrOrStderr(), args...)
+		},
+	}
+	cmd.Flags().StringVarP(&options.outputDir, "output-dir", "o", "", "Directory where converted files are written, policies are printed when not set")
+	cmd.Flags().BoolVar(&options.tests, "test", false, "Convert the tests found in the paths along with the policies")
+	cmd.Flags().StringVarP(&options.testFileName, "test-file-name", "f", "kyverno-test.yaml", "Test filename")
+	return cmd
+}
@@ -0,0 +1,127 @@
+package convert
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const legacyPolicy = `
+apiVersion: kyverno.io/v1
+kind: ClusterPolicy
+metadata:
+  name: require-team
+spec:
+  validationFailureAction: Enforce
+  rules:
+  - name: check-team
+    match:
+      any:
+      - resources:
+          kinds:
+          - Pod
+    validate:
+      message: the team label is required
+      pattern:
+        metadata:
+          labels:
+            team: "?*"
+`
+
+const kyvernoTest = `
+apiVersion: cli.kyverno.io/v1alpha1
+kind: Test
+metadata:
+  name: require-team
+policies:
+- policy.yaml
+resources:
+- resources.yaml
+results:
+- policy: require-team
+  rule: check-team
+  kind: Pod
+  resources:
+  - bad-pod
+  result: fail
+`
+
+func TestCommand(t *testing.T) {
+	cmd := Command()
+	assert.NotNil(t, cmd)
+	err := cmd.Execute()
+	assert.Error(t, err)
+}
+
+func TestCommandTestWithoutOutputDir(t *testing.T) {
+	cmd := Command()
+	assert.NotNil(t, cmd)
+	cmd.SetArgs([]string{".", "--test"})
+	err := cmd.Execute()
+	assert.Error(t, err)
+}
+
+func TestCommandWithInvalidFlag(t *testing.T) {
+	cmd := Command()
+	assert.NotNil(t, cmd)
+	b := bytes.NewBufferString("")
+	cmd.SetErr(b)
+	cmd.SetArgs([]string{"--xxx"})
+	err := cmd.Execute()
+	assert.Error(t, err)
+	out, err := io.ReadAll(b)
+	assert.NoError(t, err)
+	expected := `Error: unknown flag: --xxx`
+	assert.Equal(t, strings.TrimSpace(expected), strings.TrimSpace(string(out)))
+}
+
+func TestCommandHelp(t *testing.T) {
+	cmd := Command()
+	assert.NotNil(t, cmd)
+	b := bytes.NewBufferString("")
+	cmd.SetOut(b)
+	cmd.SetArgs([]string{"--help"})
+	err := cmd.Execute()
+	assert.NoError(t, err)
+	out, err := io.ReadAll(b)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(out), cmd.Long))
+}
+
+func TestCommandStdout(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "policy.yaml"), []byte(legacyPolicy), 0o600))
+	cmd := Command()
+	b := bytes.NewBufferString("")
+	cmd.SetOut(b)
+	cmd.SetArgs([]string{filepath.Join(dir, "policy.yaml")})
+	assert.NoError(t, cmd.Execute())
+	assert.Contains(t, b.String(), "kind: ValidatingPolicy")
+	assert.NotContains(t, b.String(), "status:")
+}
+
+func TestCommandTests(t *testing.T) {
+	dir := t.TempDir()
+	output := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "policy.yaml"), []byte(legacyPolicy), 0o600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "kyverno-test.yaml"), []byte(kyvernoTest), 0o600))
+	cmd := Command()
+	cmd.SetOut(io.Discard)
+	cmd.SetArgs([]string{dir, "--output-dir", output, "--test"})
+	assert.NoError(t, cmd.Execute())
+	converted, err := os.ReadFile(filepath.Join(output, "policy.yaml"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(converted), "kind: ValidatingPolicy")
+	test, err := os.ReadFile(filepath.Join(output, "kyverno-test.yaml"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(test), "isValidatingPolicy: true")
+	assert.NotContains(t, string(test), "rule: check-team")
+	resources, err := filepath.Rel(output, filepath.Join(dir, "resources.yaml"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(test), "- "+resources)
+}
@@ -0,0 +1,27 @@
+package convert
+
+var websiteUrl = `https://kyverno.io/docs/kyverno-cli/#convert`
+
+var description = []string{
+	`Convert legacy Kyverno policies to CEL policies.`,
+	``,
+	`Each rule of a ClusterPolicy or Policy is converted to a ValidatingPolicy, MutatingPolicy or GeneratingPolicy.`,
+	`Rules using constructs that have no CEL equivalent are not converted and the reasons are reported as warnings.`,
+	``,
+	`When tests are converted, results of converted rules are mapped to the converted policies so the same resources are expected to give the same results.`,
+}
+
+var examples = [][]string{
+	{
+		`# Convert policies and print them`,
+		`kyverno convert policy.yaml`,
+	},
+	{
+		`# Convert a directory of policies`,
+		`kyverno convert ./policies --output-dir ./converted`,
+	},
+	{
+		`# Convert policies and their tests`,
+		`kyverno convert ./policies --output-dir ./converted --test`,
+	},
+}
@@ -0,0 +1,321 @@
+package convert
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/apis/v1alpha1"
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/convert"
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/data"
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/policy"
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/test"
+	gitutils "github.com/kyverno/kyverno/pkg/utils/git"
+	kubeutils "github.com/kyverno/kyverno/pkg/utils/kube"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+type options struct {
+	outputDir    string
+	tests        bool
+	testFileName string
+}
+
+func (o options) validate(paths ...string) error {
+	if len(paths) == 0 {
+		return errors.New("at least one path is required")
+	}
+	if o.tests && o.outputDir == "" {
+		return errors.New("output-dir is required to convert tests")
+	}
+	if o.testFileName == "" {
+		return errors.New("test-file-name must not be set to an empty string")
+	}
+	return nil
+}
+
+// converted holds the policies converted from a policy file and where they were written.
+type converted struct {
+	// legacy is false when the file has no legacy policies, tests keep using it as is
+	legacy   bool
+	policies []convert.Policy
+	output   string
+}
+
+type runner struct {
+	options
+	out       io.Writer
+	errOut    io.Writer
+	converter *convert.Converter
+	files     map[string]*converted
+	issues    int
+}
+
+func (o options) execute(out, errOut io.Writer, paths ...string) error {
+	groups, err := data.APIGroupResources()
+	if err != nil {
+		return err
+	}
+	r := runner{
+		options:   o,
+		out:       out,
+		errOut:    errOut,
+		converter: convert.New(groups),
+		files:     map[string]*converted{},
+	}
+	for _, path := range paths {
+		root, files, err := r.find(path)
+		if err != nil {
+			return err
+		}
+		for _, file := range files {
+			if _, err := r.convertFile(root, file); err != nil {
+				return err
+			}
+		}
+		if o.tests {
+			if err := r.convertTests(root, path); err != nil {
+				return err
+			}
+		}
+	}
+	if o.outputDir != "" {
+		policies := 0
+		for _, file := range r.files {
+			policies += len(file.policies)
+		}
+		fmt.Fprintf(out, "Converted %d policies, %d issues.\n", policies, r.issues)
+	}
+	return nil
+}
+
+// find returns the root of a path, the output tree mirrors the tree under it, and the YAML files it contains.
+func (r *runner) find(path string) (string, []string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", nil, err
+	}
+	if !info.IsDir() {
+		return filepath.Dir(path), []string{path}, nil
+	}
+	var files []string
+	err = filepath.Walk(path, func(file string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if gitutils.IsYaml(info) && info.Name() != r.testFileName {
+			files = append(files, file)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	return path, files, nil
+}
+
+// convertFile converts the legacy policies of a file once, files without legacy policies are ignored.
+func (r *runner) convertFile(root, file string) (*converted, error) {
+	file = filepath.Clean(file)
+	if result, ok := r.files[file]; ok {
+		return result, nil
+	}
+	r.files[file] = &converted{}
+	results, err := policy.LoadWithLoader(nil, nil, "", file)
+	if err != nil || results == nil || len(results.Policies) == 0 {
+		return r.files[file], nil
+	}
+	r.files[file].legacy = true
+	var documents [][]byte
+	for _, legacy := range results.Policies {
+		result := r.converter.Convert(legacy)
+		for _, issue := range result.Issues {
+			r.warn(file, issue)
+		}
+		for _, policy := range result.Policies {
+			document, err := toYaml(policy.Object)
+			if err != nil {
+				return nil, err
+			}
+			documents = append(documents, document)
+		}
+		r.files[file].policies = append(r.files[file].policies, result.Policies...)
+	}
+	if len(documents) == 0 {
+		return r.files[file], nil
+	}
+	if r.outputDir == "" {
+		for _, document := range documents {
+			fmt.Fprintln(r.out, "---")
+			fmt.Fprint(r.out, string(document))
+		}
+		return r.files[file], nil
+	}
+	output := r.output(root, file)
+	if err := write(output, documents...); err != nil {
+		return nil, err
+	}
+	r.files[file].output = output
+	return r.files[file], nil
+}
+
+// convertTests converts the tests found under a path, the policies they use are converted along.
+func (r *runner) convertTests(root, path string) error {
+	testCases, err := test.LoadTests(path, r.testFileName)
+	if err != nil {
+		return err
+	}
+	for _, testCase := range testCases {
+		if testCase.Err != nil {
+			fmt.Fprintf(r.errOut, "ERROR: loading test file (%s): %s\n", testCase.Path, testCase.Err)
+			continue
+		}
+		dir := testCase.Dir()
+		outputDir := filepath.Dir(r.output(root, testCase.Path))
+		var policies []convert.Policy
+		var files []string
+		for _, file := range testCase.Test.Policies {
+			if !isLocal(file) {
+				fmt.Fprintf(r.errOut, "WARNING: %s: policy %s isn't a local file and is dropped\n", testCase.Path, file)
+				continue
+			}
+			result, err := r.convertFile(root, filepath.Join(dir, file))
+			if err != nil {
+				return err
+			}
+			policies = append(policies, result.policies...)
+			source := result.output
+			if !result.legacy {
+				source = filepath.Join(dir, file)
+			}
+			if source != "" {
+				rel, err := relative(outputDir, source)
+				if err != nil {
+					return err
+				}
+				files = append(files, rel)
+			}
+		}
+		converted, issues := convert.ConvertTest(*testCase.Test, policies)
+		for _, issue := range issues {
+			r.warn(testCase.Path, issue)
+		}
+		converted.Policies = files
+		if err := rebase(&converted, dir, outputDir); err != nil {
+			return err
+		}
+		document, err := toYaml(converted)
+		if err != nil {
+			return err
+		}
+		if err := write(r.output(root, testCase.Path), document); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *runner) warn(file string, issue convert.Issue) {
+	r.issues++
+	location := file
+	if issue.Policy != "" {
+		location += ": " + issue.Policy
+	}
+	if issue.Rule != "" {
+		location += "/" + issue.Rule
+	}
+	fmt.Fprintf(r.errOut, "WARNING: %s: %s\n", location, issue.Error())
+}
+
+// output returns where a file is written, files outside of the root are written at the top of the output directory.
+func (r *runner) output(root, file string) string {
+	rel, err := filepath.Rel(root, file)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		rel = filepath.Base(file)
+	}
+	return filepath.Join(r.outputDir, rel)
+}
+
+// rebase makes the local paths of a test relative to the directory it is written to.
+func rebase(test *v1alpha1.Test, from, to string) error {
+	var err error
+	path := func(path *string) {
+		if err != nil || !isLocal(*path) {
+			return
+		}
+		*path, err = relative(to, filepath.Join(from, *path))
+	}
+	paths := func(paths []string) {
+		for i := range paths {
+			path(&paths[i])
+		}
+	}
+	paths(test.Resources)
+	paths(test.TargetResources)
+	paths(test.ParamResources)
+	path(&test.Variables)
+	path(&test.UserInfo)
+	path(&test.Context)
+	for i := range test.Results {
+		result := &test.Results[i]
+		path(&result.PatchedResources)
+		path(&result.GeneratedResource)
+		path(&result.CloneSourceResource)
+	}
+	return err
+}
+
+// relative returns path relative to the directory dir, either can be relative to the working directory.
+func relative(dir, path string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	path, err = filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Rel(dir, path)
+}
+
+func isLocal(path string) bool {
+	return path != "" && !filepath.IsAbs(path) && !strings.Contains(path, "://")
+}
+
+// toYaml marshals an object to YAML, fields set by the API server are pruned.
+func toYaml(object any) ([]byte, error) {
+	untyped, err := kubeutils.ObjToUnstructured(object)
+	if err != nil {
+		return nil, err
+	}
+	unstructured.RemoveNestedField(untyped.UnstructuredContent(), "status")
+	unstructured.RemoveNestedField(untyped.UnstructuredContent(), "metadata", "creationTimestamp")
+	if item, _, _ := unstructured.NestedMap(untyped.UnstructuredContent(), "metadata"); len(item) == 0 {
+		unstructured.RemoveNestedField(untyped.UnstructuredContent(), "metadata")
+	}
+	jsonBytes, err := untyped.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return yaml.JSONToYAML(jsonBytes)
+}
+
+func write(path string, documents ...[]byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(joinDocuments(documents)), 0o600)
+}
+
+func joinDocuments(documents [][]byte) string {
+	parts := make([]string, 0, len(documents))
+	for _, document := range documents {
+		parts = append(parts, string(document))
+	}
+	return strings.Join(parts, "---\n")
+}
@@ -0,0 +1,50 @@
+package explain
+
+import (
+	"fmt"
+
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/command"
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/commands/apply"
+	"github.com/kyverno/kyverno/pkg/engine/explain"
+	"github.com/spf13/cobra"
+)
+
+func Command() *cobra.Command {
+	var outputFormat string
+	applyCommandConfig := &apply.ApplyCommandConfig{}
+	cmd := &cobra.Command{
+		Use:          "explain",
+		Short:        command.FormatDescription(true, websiteUrl, false, description...),
+		Long:         command.FormatDescription(false, websiteUrl, false, description...),
+		Example:      command.FormatExamples(examples...),
+		Args:         cobra.MinimumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if outputFormat != "tree" && outputFormat != "json" {
+				return fmt.Errorf("invalid output format %q, use tree or json", outputFormat)
+			}
+			applyCommandConfig.PolicyPaths = args
+			applyCommandConfig.ExplainFormat = outputFormat
+			steps, err := applyCommandConfig.Explain(cmd.Context())
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			if outputFormat == "json" {
+				return explain.WriteJSON(out, steps)
+			}
+			explain.WriteTree(out, steps)
+			return nil
+		},
+	}
+	cmd.Flags().StringSliceVarP(&applyCommandConfig.ResourcePaths, "resource", "r", nil, "Path to resource files")
+	cmd.Flags().StringSliceVarP(&applyCommandConfig.JSONPaths, "json", "", nil, "Path to JSON payload files")
+	cmd.Flags().StringSliceVarP(&applyCommandConfig.Variables, "set", "s", nil, "Variables that are required")
+	cmd.Flags().StringVarP(&applyCommandConfig.ValuesFile, "values-file", "f", "", "File containing values for policy variables")
+	cmd.Flags().StringVarP(&applyCommandConfig.ContextPath, "context-file", "", "", "File containing context data for CEL policies")
+	cmd.Flags().StringVarP(&applyCommandConfig.UserInfoPath, "userinfo", "u", "", "Admission Info including Roles, Cluster Roles and Subjects")
+	cmd.Flags().StringSliceVarP(&applyCommandConfig.Exception, "exception", "e", nil, "Policy exception to be considered when evaluating policies against resources")
+	cmd.Flags().StringSliceVarP(&applyCommandConfig.ParamResources, "parameter-resource", "", nil, "Path to resource files that act as ValidatingAdmissionPolicy/MutatingAdmissionPolicy parameters")
+	cmd.Flags().StringVar(&outputFormat, "output-format", "tree", "Specifies the trace format (tree or json)")
+	return cmd
+}
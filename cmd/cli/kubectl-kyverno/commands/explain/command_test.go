@@ -0,0 +1,63 @@
+package explain
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/kyverno/kyverno/pkg/engine/explain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommand(t *testing.T) {
+	cmd := Command()
+	assert.NotNil(t, cmd)
+	b := bytes.NewBufferString("")
+	cmd.SetOut(b)
+	cmd.SetArgs([]string{
+		"../../../../../test/best_practices/disallow_latest_tag.yaml",
+		"--resource",
+		"../../../../../test/resources/pod_with_latest_tag.yaml",
+		"--output-format",
+		"json",
+	})
+	err := cmd.Execute()
+	assert.NoError(t, err)
+	var steps []*explain.Step
+	assert.NoError(t, json.Unmarshal(b.Bytes(), &steps))
+	assert.Len(t, steps, 1)
+	assert.Equal(t, explain.TypeResource, steps[0].Type)
+	assert.NotEmpty(t, steps[0].Steps)
+	assert.Equal(t, explain.TypePolicy, steps[0].Steps[0].Type)
+	assert.Equal(t, "disallow-latest-tag", steps[0].Steps[0].Name)
+	assert.Equal(t, "fail", steps[0].Steps[0].Result)
+}
+
+func TestCommandWithInvalidFormat(t *testing.T) {
+	cmd := Command()
+	assert.NotNil(t, cmd)
+	b := bytes.NewBufferString("")
+	cmd.SetErr(b)
+	cmd.SetArgs([]string{"policy.yaml", "--resource", "resource.yaml", "--output-format", "yaml"})
+	err := cmd.Execute()
+	assert.Error(t, err)
+	out, err := io.ReadAll(b)
+	assert.NoError(t, err)
+	expected := `Error: invalid output format "yaml", use tree or json`
+	assert.Equal(t, strings.TrimSpace(expected), strings.TrimSpace(string(out)))
+}
+
+func TestCommandHelp(t *testing.T) {
+	cmd := Command()
+	assert.NotNil(t, cmd)
+	b := bytes.NewBufferString("")
+	cmd.SetOut(b)
+	cmd.SetArgs([]string{"--help"})
+	err := cmd.Execute()
+	assert.NoError(t, err)
+	out, err := io.ReadAll(b)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(out), cmd.Long))
+}
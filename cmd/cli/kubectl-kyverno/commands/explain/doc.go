@@ -0,0 +1,25 @@
+package explain
+
+var websiteUrl = `https://kyverno.io/docs/kyverno-cli/#explain`
+
+var description = []string{
+	`Explains the evaluation of policies on resources.`,
+	``,
+	`Policies are applied on resources like with the apply command and the steps evaluated by the engines are printed as a tree or JSON.`,
+	`The trace shows match and exclude blocks, loaded context entries, preconditions and deny conditions with their resolved values, patterns, and CEL expressions with the values of variables.`,
+}
+
+var examples = [][]string{
+	{
+		"# Explain a policy on a resource",
+		"kyverno explain /path/to/policy.yaml --resource /path/to/resource.yaml",
+	},
+	{
+		"# Explain policies on a JSON payload and print the trace as JSON",
+		"kyverno explain /path/to/policy.yaml --json /path/to/payload.json --output-format json",
+	},
+	{
+		"# Explain a policy with variables",
+		"kyverno explain /path/to/policy.yaml --resource /path/to/resource.yaml --set <variable1>=<value1>,<variable2>=<value2>",
+	},
+}
@@ -2,28 +2,34 @@ package internal
 
 import (
 	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	ociutils "github.com/kyverno/kyverno/pkg/utils/oci"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
-const (
-	PolicyConfigMediaType = "application/vnd.cncf.kyverno.config.v1+json"
-	PolicyLayerMediaType  = "application/vnd.cncf.kyverno.policy.layer.v1+yaml"
-	AnnotationKind        = "io.kyverno.image.kind"
-	AnnotationName        = "io.kyverno.image.name"
-	AnnotationApiVersion  = "io.kyverno.image.apiVersion"
-)
-
-func Annotations(policy kyvernov1.PolicyInterface) map[string]string {
-	if policy == nil {
+// Annotations returns the annotations of the layer holding a policy, of any kind, or a policy exception.
+func Annotations(object metav1.Object) map[string]string {
+	if object == nil {
 		return nil
 	}
-	kind := "ClusterPolicy"
-	if policy.IsNamespaced() {
-		kind = "Policy"
+	if policy, ok := object.(kyvernov1.PolicyInterface); ok {
+		kind := "ClusterPolicy"
+		if policy.IsNamespaced() {
+			kind = "Policy"
+		}
+		return map[string]string{
+			ociutils.AnnotationKind:       kind,
+			ociutils.AnnotationName:       policy.GetName(),
+			ociutils.AnnotationApiVersion: kyvernov1.SchemeGroupVersion.String(),
+		}
+	}
+	var apiVersion, kind string
+	if typed, ok := object.(runtime.Object); ok {
+		apiVersion, kind = typed.GetObjectKind().GroupVersionKind().ToAPIVersionAndKind()
 	}
 	return map[string]string{
-		AnnotationKind: kind,
-		AnnotationName: policy.GetName(),
-		// TODO: we need a way to get apiVersion
-		AnnotationApiVersion: "kyverno.io/v1",
+		ociutils.AnnotationKind:       kind,
+		ociutils.AnnotationName:       object.GetName(),
+		ociutils.AnnotationApiVersion: apiVersion,
 	}
 }
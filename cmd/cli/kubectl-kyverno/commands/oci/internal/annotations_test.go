@@ -5,13 +5,17 @@ import (
 	"testing"
 
 	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	kyvernov2 "github.com/kyverno/kyverno/api/kyverno/v2"
+	policiesv1alpha1 "github.com/kyverno/kyverno/api/policies.kyverno.io/v1alpha1"
+	policiesv1beta1 "github.com/kyverno/kyverno/api/policies.kyverno.io/v1beta1"
+	ociutils "github.com/kyverno/kyverno/pkg/utils/oci"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestAnnotations(t *testing.T) {
 	tests := []struct {
 		name   string
-		policy kyvernov1.PolicyInterface
+		policy metav1.Object
 		want   map[string]string
 	}{{
 		name:   "nil",
@@ -29,9 +33,9 @@ func TestAnnotations(t *testing.T) {
 			},
 		},
 		want: map[string]string{
-			AnnotationKind:       "ClusterPolicy",
-			AnnotationName:       "test",
-			AnnotationApiVersion: "kyverno.io/v1",
+			ociutils.AnnotationKind:       "ClusterPolicy",
+			ociutils.AnnotationName:       "test",
+			ociutils.AnnotationApiVersion: "kyverno.io/v1",
 		},
 	}, {
 		name: "policy",
@@ -45,9 +49,74 @@ func TestAnnotations(t *testing.T) {
 			},
 		},
 		want: map[string]string{
-			AnnotationKind:       "Policy",
-			AnnotationName:       "test",
-			AnnotationApiVersion: "kyverno.io/v1",
+			ociutils.AnnotationKind:       "Policy",
+			ociutils.AnnotationName:       "test",
+			ociutils.AnnotationApiVersion: "kyverno.io/v1",
+		},
+	}, {
+		name: "validating policy",
+		policy: &policiesv1beta1.ValidatingPolicy{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "ValidatingPolicy",
+				APIVersion: "policies.kyverno.io/v1beta1",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test",
+			},
+		},
+		want: map[string]string{
+			ociutils.AnnotationKind:       "ValidatingPolicy",
+			ociutils.AnnotationName:       "test",
+			ociutils.AnnotationApiVersion: "policies.kyverno.io/v1beta1",
+		},
+	}, {
+		name: "namespaced mutating policy",
+		policy: &policiesv1beta1.NamespacedMutatingPolicy{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "NamespacedMutatingPolicy",
+				APIVersion: "policies.kyverno.io/v1beta1",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test",
+				Namespace: "default",
+			},
+		},
+		want: map[string]string{
+			ociutils.AnnotationKind:       "NamespacedMutatingPolicy",
+			ociutils.AnnotationName:       "test",
+			ociutils.AnnotationApiVersion: "policies.kyverno.io/v1beta1",
+		},
+	}, {
+		name: "policy exception",
+		policy: &kyvernov2.PolicyException{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "PolicyException",
+				APIVersion: "kyverno.io/v2",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test",
+			},
+		},
+		want: map[string]string{
+			ociutils.AnnotationKind:       "PolicyException",
+			ociutils.AnnotationName:       "test",
+			ociutils.AnnotationApiVersion: "kyverno.io/v2",
+		},
+	}, {
+		name: "cel policy exception",
+		policy: &policiesv1alpha1.PolicyException{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "PolicyException",
+				APIVersion: "policies.kyverno.io/v1alpha1",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test",
+			},
+		},
+		want: map[string]string{
+			ociutils.AnnotationKind:       "PolicyException",
+			ociutils.AnnotationName:       "test",
+			ociutils.AnnotationApiVersion: "policies.kyverno.io/v1alpha1",
 		},
 	}}
 	for _, tt := range tests {
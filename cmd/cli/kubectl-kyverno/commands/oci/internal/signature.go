@@ -0,0 +1,93 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	gcrremote "github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/kyverno/kyverno/pkg/cosign"
+	"github.com/kyverno/kyverno/pkg/images"
+	"github.com/sigstore/cosign/v2/pkg/oci/mutate"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/sigstore/cosign/v2/pkg/oci/static"
+	"github.com/sigstore/cosign/v2/pkg/signature"
+	"github.com/sigstore/sigstore/pkg/signature/options"
+	"github.com/sigstore/sigstore/pkg/signature/payload"
+)
+
+// PasswordEnv is the environment variable holding the password of an encrypted signing key, as with cosign.
+const PasswordEnv = "COSIGN_PASSWORD"
+
+// Sign signs an image digest with a cosign key and pushes the signature next to the image.
+// The key can be a file path or any key reference supported by cosign (KMS, k8s secret, ...).
+func Sign(ctx context.Context, digest name.Digest, key string, keychain authn.Keychain) error {
+	signer, err := signature.SignerVerifierFromKeyRef(ctx, key, func(bool) ([]byte, error) {
+		return []byte(os.Getenv(PasswordEnv)), nil
+	})
+	if err != nil {
+		return fmt.Errorf("loading signing key: %w", err)
+	}
+	message, err := payload.Cosign{Image: digest}.MarshalJSON()
+	if err != nil {
+		return fmt.Errorf("creating signature payload: %w", err)
+	}
+	signed, err := signer.SignMessage(bytes.NewReader(message), options.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("signing payload: %w", err)
+	}
+	sig, err := static.NewSignature(message, base64.StdEncoding.EncodeToString(signed))
+	if err != nil {
+		return fmt.Errorf("creating signature: %w", err)
+	}
+	remoteOptions := ociremote.WithRemoteOptions(gcrremote.WithContext(ctx), gcrremote.WithAuthFromKeychain(keychain))
+	entity, err := ociremote.SignedEntity(digest, remoteOptions)
+	if err != nil {
+		return fmt.Errorf("getting image: %w", err)
+	}
+	entity, err = mutate.AttachSignatureToEntity(entity, sig)
+	if err != nil {
+		return fmt.Errorf("attaching signature: %w", err)
+	}
+	if err := ociremote.WriteSignatures(digest.Repository, entity, remoteOptions); err != nil {
+		return fmt.Errorf("writing signature: %w", err)
+	}
+	return nil
+}
+
+// Verify verifies the cosign signature of an image with a public key and returns the digest that was signed.
+// Signatures are expected to be made with a key, they are not looked up in a transparency log.
+func Verify(ctx context.Context, ref name.Reference, key string, keychain authn.Keychain) (name.Digest, error) {
+	response, err := cosign.NewVerifier().VerifySignature(ctx, images.Options{
+		ImageRef:   ref.String(),
+		Client:     client{keychain: keychain},
+		Key:        key,
+		IgnoreTlog: true,
+		IgnoreSCT:  true,
+	})
+	if err != nil {
+		return name.Digest{}, fmt.Errorf("verifying signature: %w", err)
+	}
+	return ref.Context().Digest(response.Digest), nil
+}
+
+// client provides the registry access needed by the cosign verifier from a keychain.
+type client struct {
+	keychain authn.Keychain
+}
+
+func (c client) Keychain() authn.Keychain {
+	return c.keychain
+}
+
+func (c client) Options(ctx context.Context) ([]gcrremote.Option, error) {
+	return []gcrremote.Option{gcrremote.WithContext(ctx), gcrremote.WithAuthFromKeychain(c.keychain)}, nil
+}
+
+func (c client) NameOptions() []name.Option {
+	return nil
+}
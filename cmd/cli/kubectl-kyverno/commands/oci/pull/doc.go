@@ -4,6 +4,8 @@ var websiteUrl = `https://kyverno.io/docs/kyverno-cli/#pulling`
 
 var description = []string{
 	`Pulls policie(s) that are included in an OCI image from OCI registry and saves them to a local directory.`,
+	``,
+	`Files are saved at the path they were pushed from, tests included. The image signature can be verified with a cosign key.`,
 }
 
 var examples = [][]string{
@@ -11,4 +13,8 @@ var examples = [][]string{
 		`# Pull policy from an OCI image and save it to the specific directory`,
 		`kyverno oci pull . -i <imgref>`,
 	},
+	{
+		`# Verify the signature of an OCI image with a cosign key before pulling it`,
+		`kyverno oci pull . -i <imgref> --verify-key cosign.pub`,
+	},
 }
@@ -7,18 +7,20 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 
 	securejoin "github.com/cyphar/filepath-securejoin"
 	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/commands/oci/internal"
-	policyutils "github.com/kyverno/kyverno/pkg/utils/policy"
-	yamlutils "github.com/kyverno/kyverno/pkg/utils/yaml"
+	ociutils "github.com/kyverno/kyverno/pkg/utils/oci"
 )
 
 type options struct {
-	imageRef string
+	imageRef  string
+	verifyKey string
 }
 
 func (o options) validate(dir string) error {
@@ -57,6 +59,15 @@ func (o options) execute(ctx context.Context, dir string, keychain authn.Keychai
 	if err != nil {
 		return fmt.Errorf("parsing image reference: %v", err)
 	}
+	if o.verifyKey != "" {
+		fmt.Fprintf(os.Stderr, "Verifying image signature [%s]...\n", ref.Name())
+		digest, err := internal.Verify(ctx, ref, o.verifyKey, keychain)
+		if err != nil {
+			return err
+		}
+		// pull the digest that was verified, the tag could have moved since
+		ref = digest
+	}
 	fmt.Fprintf(os.Stderr, "Downloading policies from an image [%s]...\n", ref.Name())
 	rmt, err := remote.Get(ref, remote.WithContext(ctx), remote.WithAuthFromKeychain(keychain))
 	if err != nil {
@@ -66,43 +77,61 @@ func (o options) execute(ctx context.Context, dir string, keychain authn.Keychai
 	if err != nil {
 		return fmt.Errorf("getting image: %v", err)
 	}
-	l, err := img.Layers()
+	manifest, err := img.Manifest()
 	if err != nil {
-		return fmt.Errorf("getting image layers: %v", err)
+		return fmt.Errorf("getting image manifest: %v", err)
 	}
-	for _, layer := range l {
-		lmt, err := layer.MediaType()
+	// layers coming from the same file are written back together, in order
+	var paths []string
+	files := map[string][]string{}
+	for _, descriptor := range manifest.Layers {
+		if descriptor.MediaType != ociutils.PolicyLayerMediaType && descriptor.MediaType != ociutils.TestLayerMediaType {
+			continue
+		}
+		layer, err := img.LayerByDigest(descriptor.Digest)
 		if err != nil {
-			return fmt.Errorf("getting layer media type: %v", err)
+			return fmt.Errorf("getting image layer: %v", err)
 		}
-		if lmt == internal.PolicyLayerMediaType {
-			blob, err := layer.Compressed()
-			if err != nil {
-				return fmt.Errorf("getting layer blob: %v", err)
-			}
-			defer blob.Close()
-
-			layerBytes, err := io.ReadAll(blob)
-			if err != nil {
-				return fmt.Errorf("reading layer blob: %v", err)
-			}
-			policies, _, _, _, _, _, _, err := yamlutils.GetPolicy(layerBytes)
-			if err != nil {
-				return fmt.Errorf("unmarshaling layer blob: %v", err)
-			}
-			for _, policy := range policies {
-				policyBytes, err := policyutils.ToYaml(policy)
-				if err != nil {
-					return fmt.Errorf("converting policy to yaml: %v", err)
-				}
-				pp := filepath.Join(dir, policy.GetName()+".yaml")
-				fmt.Fprintf(os.Stderr, "Saving policy into disk [%s]...\n", pp)
-				if err := os.WriteFile(pp, policyBytes, 0o600); err != nil {
-					return fmt.Errorf("creating file: %v", err)
-				}
-			}
+		layerBytes, err := readLayer(layer)
+		if err != nil {
+			return err
+		}
+		path := descriptor.Annotations[ociutils.AnnotationPath]
+		if path == "" {
+			// images pushed before paths were recorded only hold policies
+			path = descriptor.Annotations[ociutils.AnnotationName] + ".yaml"
+		}
+		if _, ok := files[path]; !ok {
+			paths = append(paths, path)
+		}
+		files[path] = append(files[path], string(layerBytes))
+	}
+	for _, path := range paths {
+		pp, err := securejoin.SecureJoin(dir, path)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Saving file into disk [%s]...\n", pp)
+		if err := os.MkdirAll(filepath.Dir(pp), 0o750); err != nil {
+			return fmt.Errorf("unable to create directory %s: %w", filepath.Dir(pp), err)
+		}
+		if err := os.WriteFile(pp, []byte(strings.Join(files[path], "---\n")), 0o600); err != nil {
+			return fmt.Errorf("creating file: %v", err)
 		}
 	}
 	fmt.Fprintf(os.Stderr, "Done.")
 	return nil
 }
+
+func readLayer(layer v1.Layer) ([]byte, error) {
+	blob, err := layer.Compressed()
+	if err != nil {
+		return nil, fmt.Errorf("getting layer blob: %v", err)
+	}
+	defer blob.Close()
+	layerBytes, err := io.ReadAll(blob)
+	if err != nil {
+		return nil, fmt.Errorf("reading layer blob: %v", err)
+	}
+	return layerBytes, nil
+}
@@ -3,6 +3,8 @@ package push
 import (
 	"bytes"
 	"io"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -76,3 +78,48 @@ func TestCommandHelp(t *testing.T) {
 	assert.NoError(t, err)
 	assert.True(t, strings.HasPrefix(string(out), cmd.Long))
 }
+
+func TestLoadInvalidValidatingPolicy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	policy := `
+apiVersion: policies.kyverno.io/v1beta1
+kind: ValidatingPolicy
+metadata:
+  name: check-labels
+spec:
+  matchConstraints:
+    resourceRules:
+    - apiGroups: [""]
+      apiVersions: [v1]
+      operations: [CREATE]
+      resources: [pods]
+  validations:
+  - expression: object.metadata.labels.?team ==
+`
+	assert.NoError(t, os.WriteFile(path, []byte(policy), 0o600))
+	_, err := load(path)
+	assert.ErrorContains(t, err, "validating policy check-labels")
+}
+
+func TestLoadValidatingPolicy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	policy := `
+apiVersion: policies.kyverno.io/v1beta1
+kind: ValidatingPolicy
+metadata:
+  name: check-labels
+spec:
+  matchConstraints:
+    resourceRules:
+    - apiGroups: [""]
+      apiVersions: [v1]
+      operations: [CREATE]
+      resources: [pods]
+  validations:
+  - expression: has(object.metadata.labels)
+`
+	assert.NoError(t, os.WriteFile(path, []byte(policy), 0o600))
+	documents, err := load(path)
+	assert.NoError(t, err)
+	assert.Len(t, documents, 1)
+}
@@ -4,6 +4,9 @@ var websiteUrl = `https://kyverno.io/docs/kyverno-cli/#pushing`
 
 var description = []string{
 	`Push policie(s) that are included in an OCI image to OCI registry.`,
+	``,
+	`Policies of every kind and policy exceptions are pushed, along with the tests found in the directory and the files they use.`,
+	`The image can be signed with a cosign key.`,
 }
 
 var examples = [][]string{
@@ -15,4 +18,8 @@ var examples = [][]string{
 		`# Push multiple policies to an OCI image from a given directory that includes policies`,
 		`kyverno oci push . -i <imgref>`,
 	},
+	{
+		`# Push policies and sign the OCI image with a cosign key`,
+		`COSIGN_PASSWORD=<password> kyverno oci push . -i <imgref> --sign-key cosign.key`,
+	},
 }
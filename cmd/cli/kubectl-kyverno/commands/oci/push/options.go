@@ -4,24 +4,45 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/google/go-containerregistry/pkg/v1/empty"
 	"github.com/google/go-containerregistry/pkg/v1/mutate"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/google/go-containerregistry/pkg/v1/static"
 	"github.com/google/go-containerregistry/pkg/v1/types"
+	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	policiesv1beta1 "github.com/kyverno/kyverno/api/policies.kyverno.io/v1beta1"
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/apis/v1alpha1"
 	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/commands/oci/internal"
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/exception"
 	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/policy"
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/test"
+	extyaml "github.com/kyverno/kyverno/ext/yaml"
+	dpolvalidation "github.com/kyverno/kyverno/pkg/cel/policies/dpol"
+	gpolvalidation "github.com/kyverno/kyverno/pkg/cel/policies/gpol"
+	mpolvalidation "github.com/kyverno/kyverno/pkg/cel/policies/mpol"
+	vpolvalidation "github.com/kyverno/kyverno/pkg/cel/policies/vpol"
 	"github.com/kyverno/kyverno/pkg/config"
-	policyutils "github.com/kyverno/kyverno/pkg/utils/policy"
+	eval "github.com/kyverno/kyverno/pkg/imageverification/evaluator"
+	gitutils "github.com/kyverno/kyverno/pkg/utils/git"
+	ociutils "github.com/kyverno/kyverno/pkg/utils/oci"
 	policyvalidation "github.com/kyverno/kyverno/pkg/validation/policy"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
 )
 
 type options struct {
 	imageRef string
+	fileName string
+	signKey  string
 }
 
 func (o options) validate(policy string) error {
@@ -31,49 +52,267 @@ func (o options) validate(policy string) error {
 	if policy == "" {
 		return errors.New("policy is required")
 	}
+	if o.fileName == "" {
+		return errors.New("file-name must not be set to an empty string")
+	}
 	return nil
 }
 
 func (o options) execute(ctx context.Context, dir string, keychain authn.Keychain) error {
-	results, err := policy.Load(nil, "", dir)
+	files, tests, err := o.find(dir)
 	if err != nil {
-		return fmt.Errorf("unable to read policy file or directory %s (%w)", dir, err)
-	}
-	for _, policy := range results.Policies {
-		sa := config.KyvernoUserName(config.KyvernoServiceAccountName())
-		if _, err := policyvalidation.Validate(policy, nil, nil, true, sa, sa); err != nil {
-			return fmt.Errorf("validating policy %s: %v", policy.GetName(), err)
-		}
+		return err
 	}
 	img := mutate.MediaType(empty.Image, types.OCIManifestSchema1)
-	img = mutate.ConfigMediaType(img, internal.PolicyConfigMediaType)
+	img = mutate.ConfigMediaType(img, ociutils.PolicyConfigMediaType)
 	ref, err := name.ParseReference(o.imageRef)
 	if err != nil {
 		return fmt.Errorf("parsing image reference: %v", err)
 	}
-	for _, policy := range results.Policies {
-		if policy.IsNamespaced() {
-			fmt.Fprintf(os.Stderr, "Adding policy [%s]\n", policy.GetName())
-		} else {
-			fmt.Fprintf(os.Stderr, "Adding cluster policy [%s]\n", policy.GetName())
+	for _, file := range files {
+		documents, err := load(file.path)
+		if err != nil {
+			return fmt.Errorf("unable to read policy file %s (%w)", file.path, err)
 		}
-		policyBytes, err := policyutils.ToYaml(policy)
+		for _, document := range documents {
+			fmt.Fprintf(os.Stderr, "Adding %s [%s]\n", describe(document.object), document.object.GetName())
+			annotations := internal.Annotations(document.object)
+			annotations[ociutils.AnnotationPath] = file.name
+			img, err = appendLayer(img, document.content, ociutils.PolicyLayerMediaType, annotations)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	for _, file := range tests {
+		fmt.Fprintf(os.Stderr, "Adding test file [%s]\n", file.name)
+		fileBytes, err := os.ReadFile(file.path)
 		if err != nil {
-			return fmt.Errorf("converting policy to yaml: %v", err)
+			return fmt.Errorf("reading test file: %v", err)
 		}
-		policyLayer := static.NewLayer(policyBytes, internal.PolicyLayerMediaType)
-		img, err = mutate.Append(img, mutate.Addendum{
-			Layer:       policyLayer,
-			Annotations: internal.Annotations(policy),
+		img, err = appendLayer(img, fileBytes, ociutils.TestLayerMediaType, map[string]string{
+			ociutils.AnnotationPath: file.name,
 		})
 		if err != nil {
-			return fmt.Errorf("mutating image: %v", err)
+			return err
 		}
 	}
 	fmt.Fprintf(os.Stderr, "Uploading [%s]...\n", ref.Name())
 	if err = remote.Write(ref, img, remote.WithContext(ctx), remote.WithAuthFromKeychain(keychain)); err != nil {
 		return fmt.Errorf("writing image: %v", err)
 	}
+	if o.signKey != "" {
+		digest, err := img.Digest()
+		if err != nil {
+			return fmt.Errorf("getting image digest: %v", err)
+		}
+		signed := ref.Context().Digest(digest.String())
+		fmt.Fprintf(os.Stderr, "Signing [%s]...\n", signed.Name())
+		if err := internal.Sign(ctx, signed, o.signKey, keychain); err != nil {
+			return err
+		}
+	}
 	fmt.Fprintf(os.Stderr, "Done.")
 	return nil
 }
+
+// file is a file to push, name is its path relative to the pushed directory.
+type file struct {
+	path string
+	name string
+}
+
+// find returns the policy files and the test files under a path. Test files are the tests and the
+// local files they use, except for policies and exceptions that are pushed as policy files.
+func (o options) find(path string) ([]file, []file, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to read policy file or directory %s (%w)", path, err)
+	}
+	if !info.IsDir() {
+		return []file{{path: path, name: filepath.Base(path)}}, nil, nil
+	}
+	root, err := filepath.Abs(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	testCases, err := test.LoadTests(root, o.fileName)
+	if err != nil {
+		return nil, nil, err
+	}
+	testFiles := map[string]bool{}
+	for _, testCase := range testCases {
+		if testCase.Err != nil {
+			return nil, nil, fmt.Errorf("loading test file (%s): %w", testCase.Path, testCase.Err)
+		}
+		testFiles[testCase.Path] = true
+		for _, resource := range resources(testCase.Test) {
+			if resource == "" || filepath.IsAbs(resource) || strings.Contains(resource, "://") {
+				continue
+			}
+			resource = filepath.Join(testCase.Dir(), resource)
+			if rel, err := filepath.Rel(root, resource); err != nil || strings.HasPrefix(rel, "..") {
+				fmt.Fprintf(os.Stderr, "WARNING: %s: file %s is outside of %s and isn't pushed\n", testCase.Path, resource, path)
+				continue
+			}
+			testFiles[resource] = true
+		}
+	}
+	var files, tests []file
+	err = filepath.Walk(root, func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(info.Name(), ".") && path != root {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !gitutils.IsYaml(info) && !testFiles[path] {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		if testFiles[path] {
+			tests = append(tests, file{path: path, name: filepath.ToSlash(rel)})
+		} else {
+			files = append(files, file{path: path, name: filepath.ToSlash(rel)})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return files, tests, nil
+}
+
+// resources returns the files a test uses, policies and exceptions aside.
+func resources(test *v1alpha1.Test) []string {
+	resources := []string{test.JSONPayload, test.Variables, test.UserInfo, test.Context}
+	resources = append(resources, test.Resources...)
+	resources = append(resources, test.TargetResources...)
+	resources = append(resources, test.ParamResources...)
+	for _, result := range test.Results {
+		resources = append(resources, result.PatchedResources, result.GeneratedResource, result.CloneSourceResource)
+	}
+	return resources
+}
+
+// document is a policy, of any kind, or an exception as written in a file.
+type document struct {
+	object  *metav1.PartialObjectMetadata
+	content []byte
+}
+
+// load validates the policies and exceptions of a file and returns their documents. Documents are pushed as
+// they are written, so that the policies pulled from the image are exactly the ones that were pushed.
+func load(path string) ([]document, error) {
+	results, err := policy.Load(nil, "", path)
+	if err != nil {
+		if _, exceptionErr := exception.Load(path); exceptionErr != nil {
+			return nil, err
+		}
+	} else if err := validate(results); err != nil {
+		return nil, err
+	}
+	fileBytes, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	contents, err := extyaml.SplitDocuments(fileBytes)
+	if err != nil {
+		return nil, err
+	}
+	var documents []document
+	for _, content := range contents {
+		var object metav1.PartialObjectMetadata
+		if err := yaml.Unmarshal(content, &object); err != nil {
+			return nil, err
+		}
+		// documents the loaders skipped, like non-Kubernetes YAMLs, aren't policies
+		switch object.GroupVersionKind().Group {
+		case kyvernov1.SchemeGroupVersion.Group, policiesv1beta1.SchemeGroupVersion.Group, admissionregistrationv1.SchemeGroupVersion.Group:
+			documents = append(documents, document{object: &object, content: content})
+		}
+	}
+	return documents, nil
+}
+
+// validate validates the policies of a file the way the admission webhook does.
+func validate(results *policy.LoaderResults) error {
+	sa := config.KyvernoUserName(config.KyvernoServiceAccountName())
+	for _, policy := range results.Policies {
+		if _, err := policyvalidation.Validate(policy, nil, nil, true, sa, sa); err != nil {
+			return fmt.Errorf("validating policy %s: %v", policy.GetName(), err)
+		}
+	}
+	var errs []error
+	for i := range results.ValidatingPolicies {
+		_, err := vpolvalidation.Validate(&results.ValidatingPolicies[i])
+		errs = append(errs, validationError(&results.ValidatingPolicies[i], err))
+	}
+	for i := range results.NamespacedValidatingPolicies {
+		_, err := vpolvalidation.Validate(&results.NamespacedValidatingPolicies[i])
+		errs = append(errs, validationError(&results.NamespacedValidatingPolicies[i], err))
+	}
+	for i := range results.ImageValidatingPolicies {
+		_, err := eval.Validate(&results.ImageValidatingPolicies[i], nil)
+		errs = append(errs, validationError(&results.ImageValidatingPolicies[i], err))
+	}
+	for i := range results.NamespacedImageValidatingPolicies {
+		_, err := eval.Validate(&results.NamespacedImageValidatingPolicies[i], nil)
+		errs = append(errs, validationError(&results.NamespacedImageValidatingPolicies[i], err))
+	}
+	for i := range results.MutatingPolicies {
+		_, err := mpolvalidation.Validate(&results.MutatingPolicies[i])
+		errs = append(errs, validationError(&results.MutatingPolicies[i], err))
+	}
+	for i := range results.NamespacedMutatingPolicies {
+		_, err := mpolvalidation.Validate(&results.NamespacedMutatingPolicies[i])
+		errs = append(errs, validationError(&results.NamespacedMutatingPolicies[i], err))
+	}
+	for i := range results.GeneratingPolicies {
+		_, err := gpolvalidation.Validate(&results.GeneratingPolicies[i])
+		errs = append(errs, validationError(&results.GeneratingPolicies[i], err))
+	}
+	for i := range results.DeletingPolicies {
+		_, err := dpolvalidation.Validate(&results.DeletingPolicies[i])
+		errs = append(errs, validationError(&results.DeletingPolicies[i], err))
+	}
+	for i := range results.NamespacedDeletingPolicies {
+		_, err := dpolvalidation.Validate(&results.NamespacedDeletingPolicies[i])
+		errs = append(errs, validationError(&results.NamespacedDeletingPolicies[i], err))
+	}
+	return errors.Join(errs...)
+}
+
+func validationError(policy metav1.Object, err error) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("validating policy %s: %v", policy.GetName(), err)
+}
+
+func describe(object *metav1.PartialObjectMetadata) string {
+	switch object.Kind {
+	case "ClusterPolicy":
+		return "cluster policy"
+	case "Policy":
+		return "policy"
+	}
+	return object.Kind
+}
+
+func appendLayer(img v1.Image, content []byte, mediaType types.MediaType, annotations map[string]string) (v1.Image, error) {
+	img, err := mutate.Append(img, mutate.Addendum{
+		Layer:       static.NewLayer(content, mediaType),
+		Annotations: annotations,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("mutating image: %v", err)
+	}
+	return img, nil
+}
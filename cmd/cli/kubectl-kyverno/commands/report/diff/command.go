@@ -0,0 +1,35 @@
+package diff
+
+import (
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/command"
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/output/color"
+	"github.com/spf13/cobra"
+)
+
+func Command() *cobra.Command {
+	var options options
+	var removeColor bool
+	cmd := &cobra.Command{
+		Use:          "diff [before] [after]",
+		Short:        command.FormatDescription(true, websiteUrl, false, description...),
+		Long:         command.FormatDescription(false, websiteUrl, false, description...),
+		Example:      command.FormatExamples(examples...),
+		Args:         cobra.ExactArgs(2),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := options.validate(); err != nil {
+				return err
+			}
+			color.Init(removeColor || options.outputFormat != "table")
+			return options.execute(cmd.Context(), cmd.OutOrStdout(), args[0], args[1])
+		},
+	}
+	cmd.Flags().StringVarP(&options.outputFormat, "output-format", "o", "table", "Specifies the output format (table, json, markdown)")
+	cmd.Flags().StringVarP(&options.namespace, "namespace", "n", "", "Only compare the results of resources in this namespace")
+	cmd.Flags().BoolVar(&options.policyReports, "policy-reports", false, "Read wgpolicyk8s.io policy reports from the cluster instead of openreports.io reports")
+	cmd.Flags().StringVar(&options.kubeConfig, "kubeconfig", "", "path to kubeconfig file with authorization and master location information")
+	cmd.Flags().StringVar(&options.context, "context", "", "The name of the kubeconfig context to use")
+	cmd.Flags().BoolVar(&options.failOnNewFailures, "fail-on-new-failures", false, "If set to true, return an error when results are newly failing")
+	cmd.Flags().BoolVar(&removeColor, "remove-color", false, "Remove any color from output")
+	return cmd
+}
@@ -0,0 +1,86 @@
+package diff
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommand(t *testing.T) {
+	dir := t.TempDir()
+	before := filepath.Join(dir, "before.yaml")
+	after := filepath.Join(dir, "after.yaml")
+	require.NoError(t, os.WriteFile(before, []byte(policyReports), 0o600))
+	require.NoError(t, os.WriteFile(after, []byte(clusterReport), 0o600))
+	tests := []struct {
+		name    string
+		args    []string
+		want    string
+		wantErr bool
+	}{{
+		name: "markdown",
+		args: []string{before, after, "--output-format", "markdown"},
+		want: `### New failures (2)
+
+| POLICY | RULE | RESOURCE | BEFORE | AFTER | MESSAGE |
+|----|----|----|----|----|----|
+| require-labels | check-team | v1/Pod/default/nginx | Pass | Fail | the team label is required |
+| require-labels | check-team | v1/Pod/team-a/nginx | - | Fail | the team label is required |
+
+### New passes (1)
+
+| POLICY | RULE | RESOURCE | BEFORE | AFTER | MESSAGE |
+|----|----|----|----|----|----|
+| require-labels | check-team | v1/Namespace/team-a | - | Pass |  |
+
+### Other changes (0)
+
+### Removed results (1)
+
+| POLICY | RULE | RESOURCE | BEFORE | AFTER | MESSAGE |
+|----|----|----|----|----|----|
+| disallow-latest-tag | validate-image-tag | v1/Pod/default/nginx | Fail | - | using a mutable image tag is not allowed |
+`,
+	}, {
+		name:    "new failures",
+		args:    []string{before, after, "--output-format", "json", "--fail-on-new-failures"},
+		wantErr: true,
+	}, {
+		name: "no new failures",
+		args: []string{after, after, "--fail-on-new-failures", "--remove-color"},
+	}, {
+		name:    "invalid output format",
+		args:    []string{before, after, "--output-format", "yaml"},
+		wantErr: true,
+	}, {
+		name:    "missing file",
+		args:    []string{before, filepath.Join(dir, "missing.yaml")},
+		wantErr: true,
+	}, {
+		name:    "missing argument",
+		args:    []string{before},
+		wantErr: true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := Command()
+			var out bytes.Buffer
+			cmd.SetOut(&out)
+			cmd.SetErr(&bytes.Buffer{})
+			cmd.SetArgs(tt.args)
+			err := cmd.Execute()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			if tt.want != "" {
+				assert.Equal(t, tt.want, out.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package diff
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/kyverno/kyverno/pkg/openreports"
+)
+
+// Change is a result that changed between two sets of reports, Before is empty for new results
+// and After is empty for removed results.
+type Change struct {
+	Policy   string `json:"policy"`
+	Rule     string `json:"rule,omitempty"`
+	Resource string `json:"resource"`
+	Before   string `json:"before,omitempty"`
+	After    string `json:"after,omitempty"`
+	Message  string `json:"message,omitempty"`
+}
+
+// Diff holds the changes between two sets of reports, sorted by policy, rule and resource.
+type Diff struct {
+	// NewFailures are the results failing after and not failing or missing before
+	NewFailures []Change `json:"newFailures"`
+	// NewPasses are the results passing after and not passing or missing before
+	NewPasses []Change `json:"newPasses"`
+	// Changed are the other results added or whose status changed, like results now erroring or skipped
+	Changed []Change `json:"changed"`
+	// Removed are the results missing after
+	Removed []Change `json:"removed"`
+}
+
+func compare(before, after results) Diff {
+	diff := Diff{
+		NewFailures: []Change{},
+		NewPasses:   []Change{},
+		Changed:     []Change{},
+		Removed:     []Change{},
+	}
+	for key, current := range after {
+		previous, ok := before[key]
+		change := Change{
+			Policy:   key.policy,
+			Rule:     key.rule,
+			Resource: key.resource,
+			Before:   string(previous.status),
+			After:    string(current.status),
+			Message:  current.message,
+		}
+		if ok && previous.status == current.status {
+			continue
+		}
+		switch current.status {
+		case openreports.StatusFail:
+			diff.NewFailures = append(diff.NewFailures, change)
+		case openreports.StatusPass:
+			diff.NewPasses = append(diff.NewPasses, change)
+		default:
+			diff.Changed = append(diff.Changed, change)
+		}
+	}
+	for key, previous := range before {
+		if _, ok := after[key]; ok {
+			continue
+		}
+		diff.Removed = append(diff.Removed, Change{
+			Policy:   key.policy,
+			Rule:     key.rule,
+			Resource: key.resource,
+			Before:   string(previous.status),
+			Message:  previous.message,
+		})
+	}
+	for _, changes := range [][]Change{diff.NewFailures, diff.NewPasses, diff.Changed, diff.Removed} {
+		slices.SortFunc(changes, func(a, b Change) int {
+			if x := cmp.Compare(a.Policy, b.Policy); x != 0 {
+				return x
+			}
+			if x := cmp.Compare(a.Rule, b.Rule); x != 0 {
+				return x
+			}
+			return cmp.Compare(a.Resource, b.Resource)
+		})
+	}
+	return diff
+}
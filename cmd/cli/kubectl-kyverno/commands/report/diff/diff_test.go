@@ -0,0 +1,167 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const clusterReport = `
+apiVersion: openreports.io/v1alpha1
+kind: ClusterReport
+metadata:
+  name: merged
+results:
+- policy: require-labels
+  rule: check-team
+  result: fail
+  message: the team label is required
+  resources:
+  - apiVersion: v1
+    kind: Pod
+    namespace: default
+    name: nginx
+  - apiVersion: v1
+    kind: Pod
+    namespace: team-a
+    name: nginx
+- policy: require-labels
+  rule: check-team
+  result: pass
+  resources:
+  - apiVersion: v1
+    kind: Namespace
+    name: team-a
+`
+
+const policyReports = `
+apiVersion: v1
+kind: List
+items:
+- apiVersion: wgpolicyk8s.io/v1alpha2
+  kind: PolicyReport
+  metadata:
+    name: 7e3d1a4b
+    namespace: default
+  scope:
+    apiVersion: v1
+    kind: Pod
+    namespace: default
+    name: nginx
+  results:
+  - policy: require-labels
+    rule: check-team
+    result: pass
+  - policy: disallow-latest-tag
+    rule: validate-image-tag
+    result: fail
+    message: using a mutable image tag is not allowed
+`
+
+func Test_load(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		namespace string
+		want      results
+		wantErr   bool
+	}{{
+		name:    "results split by resource",
+		content: clusterReport,
+		want: results{
+			{policy: "require-labels", rule: "check-team", resource: "v1/Pod/default/nginx"}: {status: "fail", message: "the team label is required"},
+			{policy: "require-labels", rule: "check-team", resource: "v1/Pod/team-a/nginx"}:  {status: "fail", message: "the team label is required"},
+			{policy: "require-labels", rule: "check-team", resource: "v1/Namespace/team-a"}:  {status: "pass"},
+		},
+	}, {
+		name:      "results filtered by namespace",
+		content:   clusterReport,
+		namespace: "team-a",
+		want: results{
+			{policy: "require-labels", rule: "check-team", resource: "v1/Pod/team-a/nginx"}: {status: "fail", message: "the team label is required"},
+		},
+	}, {
+		name:    "list of policy reports with scope",
+		content: policyReports,
+		want: results{
+			{policy: "require-labels", rule: "check-team", resource: "v1/Pod/default/nginx"}:              {status: "pass"},
+			{policy: "disallow-latest-tag", rule: "validate-image-tag", resource: "v1/Pod/default/nginx"}: {status: "fail", message: "using a mutable image tag is not allowed"},
+		},
+	}, {
+		name:    "several documents",
+		content: clusterReport + "---\n" + policyReports,
+		want: results{
+			{policy: "require-labels", rule: "check-team", resource: "v1/Pod/default/nginx"}:              {status: "fail", message: "the team label is required"},
+			{policy: "require-labels", rule: "check-team", resource: "v1/Pod/team-a/nginx"}:               {status: "fail", message: "the team label is required"},
+			{policy: "require-labels", rule: "check-team", resource: "v1/Namespace/team-a"}:               {status: "pass"},
+			{policy: "disallow-latest-tag", rule: "validate-image-tag", resource: "v1/Pod/default/nginx"}: {status: "fail", message: "using a mutable image tag is not allowed"},
+		},
+	}, {
+		name: "unsupported kind",
+		content: `
+apiVersion: kyverno.io/v1
+kind: ClusterPolicy
+metadata:
+  name: require-labels`,
+		wantErr: true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loaded := results{}
+			err := loaded.load([]byte(tt.content), tt.namespace)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, loaded)
+		})
+	}
+}
+
+func Test_compare(t *testing.T) {
+	key := func(policy, resource string) resultKey {
+		return resultKey{policy: policy, rule: "rule", resource: resource}
+	}
+	before := results{
+		key("a", "unchanged"):  {status: "fail"},
+		key("a", "fixed"):      {status: "fail"},
+		key("a", "broken"):     {status: "pass"},
+		key("a", "skipped"):    {status: "fail"},
+		key("b", "removed"):    {status: "pass"},
+		key("b", "now-failed"): {status: "skip"},
+		key("b", "erroring"):   {status: "pass"},
+	}
+	after := results{
+		key("a", "unchanged"):  {status: "fail"},
+		key("a", "fixed"):      {status: "pass"},
+		key("a", "broken"):     {status: "fail", message: "broken"},
+		key("a", "skipped"):    {status: "skip"},
+		key("b", "now-failed"): {status: "fail"},
+		key("b", "added"):      {status: "pass"},
+		key("a", "added"):      {status: "fail"},
+		key("b", "erroring"):   {status: "error", message: "failed to evaluate"},
+		key("b", "warned"):     {status: "warn"},
+	}
+	assert.Equal(t, Diff{
+		NewFailures: []Change{
+			{Policy: "a", Rule: "rule", Resource: "added", After: "fail"},
+			{Policy: "a", Rule: "rule", Resource: "broken", Before: "pass", After: "fail", Message: "broken"},
+			{Policy: "b", Rule: "rule", Resource: "now-failed", Before: "skip", After: "fail"},
+		},
+		NewPasses: []Change{
+			{Policy: "a", Rule: "rule", Resource: "fixed", Before: "fail", After: "pass"},
+			{Policy: "b", Rule: "rule", Resource: "added", After: "pass"},
+		},
+		Changed: []Change{
+			{Policy: "a", Rule: "rule", Resource: "skipped", Before: "fail", After: "skip"},
+			{Policy: "b", Rule: "rule", Resource: "erroring", Before: "pass", After: "error", Message: "failed to evaluate"},
+			{Policy: "b", Rule: "rule", Resource: "warned", After: "warn"},
+		},
+		Removed: []Change{
+			{Policy: "b", Rule: "rule", Resource: "removed", Before: "pass"},
+		},
+	}, compare(before, after))
+	assert.Equal(t, Diff{NewFailures: []Change{}, NewPasses: []Change{}, Changed: []Change{}, Removed: []Change{}}, compare(after, after))
+}
@@ -0,0 +1,32 @@
+package diff
+
+var websiteUrl = `https://kyverno.io/docs/kyverno-cli/#report`
+
+var description = []string{
+	`Compare two sets of policy reports.`,
+	``,
+	`Each set is read from a file or a directory containing reports, like the ones printed by kyverno apply --policy-report,`,
+	`or from the cluster when the set is named cluster (use ./cluster for a local file named cluster).`,
+	`Reports and ClusterReports (openreports.io) and PolicyReports and ClusterPolicyReports (wgpolicyk8s.io) are supported,`,
+	`lists of reports are supported too.`,
+	``,
+	`Results are matched by policy, rule and resource, the command prints the results that are newly failing,`,
+	`the results that are newly passing, the other results that were added or whose status changed (for example to error`,
+	`or skip) and the results that were removed.`,
+}
+
+var examples = [][]string{
+	{
+		`# Compare the reports of two files`,
+		`kyverno report diff before.yaml after.yaml`,
+	},
+	{
+		`# Compare the reports of the cluster with the reports of a policy change, and fail on new violations`,
+		`kyverno apply ./policies --cluster --policy-report > after.yaml`,
+		`kyverno report diff cluster after.yaml --fail-on-new-failures`,
+	},
+	{
+		`# Compare the policy reports of a namespace in markdown`,
+		`kyverno report diff cluster after.yaml --policy-reports --namespace default --output-format markdown`,
+	},
+}
@@ -0,0 +1,229 @@
+package diff
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	policyreportv1alpha2 "github.com/kyverno/kyverno/api/policyreport/v1alpha2"
+	extyaml "github.com/kyverno/kyverno/ext/yaml"
+	"github.com/kyverno/kyverno/pkg/client/clientset/versioned"
+	"github.com/kyverno/kyverno/pkg/config"
+	"github.com/kyverno/kyverno/pkg/openreports"
+	openreportsv1alpha1 "github.com/openreports/reports-api/apis/openreports.io/v1alpha1"
+	openreportsclient "github.com/openreports/reports-api/pkg/client/clientset/versioned/typed/openreports.io/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+)
+
+// resultKey identifies the result of a rule for a resource.
+type resultKey struct {
+	policy   string
+	rule     string
+	resource string
+}
+
+type result struct {
+	status  openreportsv1alpha1.Result
+	message string
+}
+
+// results are the results of a set of reports, a result applying to several resources is split by resource.
+type results map[resultKey]result
+
+// add adds the results of a report, results without resources apply to the report scope.
+// When a namespace is set, only the results of the resources in the namespace are added.
+func (r results) add(namespace string, scope *corev1.ObjectReference, reportResults ...openreportsv1alpha1.ReportResult) {
+	for _, reportResult := range reportResults {
+		subjects := reportResult.Subjects
+		if len(subjects) == 0 && scope != nil {
+			subjects = []corev1.ObjectReference{*scope}
+		}
+		for _, subject := range subjects {
+			if namespace != "" && subject.Namespace != namespace {
+				continue
+			}
+			key := resultKey{
+				policy:   reportResult.Policy,
+				rule:     reportResult.Rule,
+				resource: resourceName(subject),
+			}
+			// the same result can be reported more than once, failures win
+			if previous, ok := r[key]; ok && previous.status == openreports.StatusFail {
+				continue
+			}
+			r[key] = result{
+				status:  reportResult.Result,
+				message: reportResult.Description,
+			}
+		}
+	}
+}
+
+func resourceName(ref corev1.ObjectReference) string {
+	var parts []string
+	for _, part := range []string{ref.APIVersion, ref.Kind, ref.Namespace, ref.Name} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// loadPath loads the reports of a file or of the YAML and JSON files of a directory.
+func loadPath(path string, namespace string) (results, error) {
+	var files []string
+	err := filepath.WalkDir(path, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		// files in directories are filtered by extension, files passed explicitly are always read
+		switch filepath.Ext(file) {
+		case ".yaml", ".yml", ".json":
+			files = append(files, file)
+		default:
+			if file == path {
+				files = append(files, file)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	loaded := results{}
+	for _, file := range files {
+		content, err := os.ReadFile(file) // #nosec G304
+		if err != nil {
+			return nil, err
+		}
+		if err := loaded.load(content, namespace); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+	}
+	return loaded, nil
+}
+
+// load adds the results of the reports found in the documents of a file.
+func (r results) load(content []byte, namespace string) error {
+	documents, err := extyaml.SplitDocuments(content)
+	if err != nil {
+		return err
+	}
+	for _, document := range documents {
+		var object unstructured.Unstructured
+		if err := yaml.Unmarshal(document, &object.Object); err != nil {
+			return err
+		}
+		if len(object.Object) == 0 {
+			continue
+		}
+		if err := r.addObject(object, namespace); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r results) addObject(object unstructured.Unstructured, namespace string) error {
+	gvk := object.GroupVersionKind()
+	switch {
+	case object.IsList():
+		return object.EachListItem(func(item runtime.Object) error {
+			return r.addObject(*item.(*unstructured.Unstructured), namespace)
+		})
+	case gvk.Group == openreportsv1alpha1.SchemeGroupVersion.Group && gvk.Kind == "Report":
+		var report openreportsv1alpha1.Report
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(object.Object, &report); err != nil {
+			return err
+		}
+		r.add(namespace, report.Scope, report.Results...)
+	case gvk.Group == openreportsv1alpha1.SchemeGroupVersion.Group && gvk.Kind == "ClusterReport":
+		var report openreportsv1alpha1.ClusterReport
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(object.Object, &report); err != nil {
+			return err
+		}
+		r.add(namespace, report.Scope, report.Results...)
+	case gvk.Group == policyreportv1alpha2.SchemeGroupVersion.Group && gvk.Kind == "PolicyReport":
+		var report policyreportv1alpha2.PolicyReport
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(object.Object, &report); err != nil {
+			return err
+		}
+		converted := report.ToOpenReports()
+		r.add(namespace, converted.Scope, converted.Results...)
+	case gvk.Group == policyreportv1alpha2.SchemeGroupVersion.Group && gvk.Kind == "ClusterPolicyReport":
+		var report policyreportv1alpha2.ClusterPolicyReport
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(object.Object, &report); err != nil {
+			return err
+		}
+		converted := report.ToOpenReports()
+		r.add(namespace, converted.Scope, converted.Results...)
+	default:
+		return fmt.Errorf("unsupported kind %s", gvk)
+	}
+	return nil
+}
+
+// loadCluster loads the reports of the cluster, cluster reports are skipped when a namespace is set.
+func (o options) loadCluster(ctx context.Context) (results, error) {
+	restConfig, err := config.CreateClientConfigWithContext(o.kubeConfig, o.context)
+	if err != nil {
+		return nil, err
+	}
+	loaded := results{}
+	if o.policyReports {
+		client, err := versioned.NewForConfig(restConfig)
+		if err != nil {
+			return nil, err
+		}
+		reports, err := client.Wgpolicyk8sV1alpha2().PolicyReports(o.namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for i := range reports.Items {
+			converted := reports.Items[i].ToOpenReports()
+			loaded.add(o.namespace, converted.Scope, converted.Results...)
+		}
+		if o.namespace == "" {
+			clusterReports, err := client.Wgpolicyk8sV1alpha2().ClusterPolicyReports().List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			for i := range clusterReports.Items {
+				converted := clusterReports.Items[i].ToOpenReports()
+				loaded.add(o.namespace, converted.Scope, converted.Results...)
+			}
+		}
+		return loaded, nil
+	}
+	client, err := openreportsclient.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	reports, err := client.Reports(o.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, report := range reports.Items {
+		loaded.add(o.namespace, report.Scope, report.Results...)
+	}
+	if o.namespace == "" {
+		clusterReports, err := client.ClusterReports().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for _, report := range clusterReports.Items {
+			loaded.add(o.namespace, report.Scope, report.Results...)
+		}
+	}
+	return loaded, nil
+}
@@ -0,0 +1,63 @@
+package diff
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/kyverno/kyverno/ext/output/pluralize"
+)
+
+// clusterSource is the name of the source reading the reports from the cluster.
+const clusterSource = "cluster"
+
+type options struct {
+	outputFormat      string
+	namespace         string
+	policyReports     bool
+	kubeConfig        string
+	context           string
+	failOnNewFailures bool
+}
+
+func (o options) validate() error {
+	switch o.outputFormat {
+	case "table", "json", "markdown":
+		return nil
+	default:
+		return fmt.Errorf("invalid output format %q, expected (table, json, markdown)", o.outputFormat)
+	}
+}
+
+func (o options) execute(ctx context.Context, out io.Writer, before, after string) error {
+	previous, err := o.load(ctx, before)
+	if err != nil {
+		return fmt.Errorf("failed to load reports from %s (%w)", before, err)
+	}
+	current, err := o.load(ctx, after)
+	if err != nil {
+		return fmt.Errorf("failed to load reports from %s (%w)", after, err)
+	}
+	diff := compare(previous, current)
+	switch o.outputFormat {
+	case "json":
+		if err := printJSON(out, diff); err != nil {
+			return err
+		}
+	case "markdown":
+		printMarkdown(out, diff)
+	default:
+		printTable(out, diff)
+	}
+	if o.failOnNewFailures && len(diff.NewFailures) > 0 {
+		return fmt.Errorf("%d %s newly failing", len(diff.NewFailures), pluralize.Pluralize(len(diff.NewFailures), "result is", "results are"))
+	}
+	return nil
+}
+
+func (o options) load(ctx context.Context, source string) (results, error) {
+	if source == clusterSource {
+		return o.loadCluster(ctx)
+	}
+	return loadPath(source, o.namespace)
+}
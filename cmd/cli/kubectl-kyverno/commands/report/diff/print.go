@@ -0,0 +1,94 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/output/table"
+	"github.com/kyverno/kyverno/ext/output/pluralize"
+)
+
+type row struct {
+	Change   string `header:"change"`
+	Policy   string `header:"policy"`
+	Rule     string `header:"rule"`
+	Resource string `header:"resource"`
+	Before   string `header:"before"`
+	After    string `header:"after"`
+}
+
+// sections returns the titles of the changes of a diff with the changes, in print order.
+func sections(diff Diff) ([]string, [][]Change) {
+	return []string{"New failure", "New pass", "Changed", "Removed"}, [][]Change{diff.NewFailures, diff.NewPasses, diff.Changed, diff.Removed}
+}
+
+func printTable(out io.Writer, diff Diff) {
+	fmt.Fprintf(out, "%d new %s, %d new %s, %d other %s, %d removed %s\n",
+		len(diff.NewFailures), pluralize.Pluralize(len(diff.NewFailures), "failure", "failures"),
+		len(diff.NewPasses), pluralize.Pluralize(len(diff.NewPasses), "pass", "passes"),
+		len(diff.Changed), pluralize.Pluralize(len(diff.Changed), "change", "changes"),
+		len(diff.Removed), pluralize.Pluralize(len(diff.Removed), "result", "results"))
+	var rows []row
+	titles, changes := sections(diff)
+	for i, title := range titles {
+		for _, change := range changes[i] {
+			rows = append(rows, row{
+				Change:   title,
+				Policy:   change.Policy,
+				Rule:     change.Rule,
+				Resource: change.Resource,
+				Before:   status(change.Before),
+				After:    status(change.After),
+			})
+		}
+	}
+	if len(rows) == 0 {
+		return
+	}
+	fmt.Fprintln(out)
+	table.NewTablePrinter(out).Print(rows)
+}
+
+func printJSON(out io.Writer, diff Diff) error {
+	data, err := json.MarshalIndent(diff, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(out, string(data))
+	return nil
+}
+
+func printMarkdown(out io.Writer, diff Diff) {
+	headings := []string{"New failures", "New passes", "Other changes", "Removed results"}
+	_, changes := sections(diff)
+	var b strings.Builder
+	for i, heading := range headings {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(fmt.Sprintf("### %s (%d)\n", heading, len(changes[i])))
+		if len(changes[i]) == 0 {
+			continue
+		}
+		b.WriteString("\n| POLICY | RULE | RESOURCE | BEFORE | AFTER | MESSAGE |\n")
+		b.WriteString("|----|----|----|----|----|----|\n")
+		for _, change := range changes[i] {
+			b.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s |\n",
+				change.Policy, change.Rule, change.Resource, status(change.Before), status(change.After), markdownEscape(change.Message)))
+		}
+	}
+	fmt.Fprint(out, b.String())
+}
+
+func status(status string) string {
+	if status == "" {
+		return "-"
+	}
+	return strings.ToUpper(status[:1]) + status[1:]
+}
+
+func markdownEscape(text string) string {
+	return strings.NewReplacer("|", "\\|", "\n", " ").Replace(text)
+}
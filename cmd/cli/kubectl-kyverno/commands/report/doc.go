@@ -0,0 +1,14 @@
+package report
+
+var websiteUrl = `https://kyverno.io/docs/kyverno-cli/#report`
+
+var description = []string{
+	`Work with policy reports.`,
+}
+
+var examples = [][]string{
+	{
+		`# Compare the reports produced by the current policies and by a policy change`,
+		`kyverno report diff before.yaml after.yaml`,
+	},
+}
@@ -3,15 +3,20 @@ package test
 import (
 	"fmt"
 	"io"
+	"os"
+	"os/signal"
 	"path/filepath"
 
 	"github.com/go-git/go-billy/v5"
 	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/apis/v1alpha1"
 	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/command"
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/coverage"
 	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/deprecations"
 	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/output/color"
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/output/sarif"
 	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/output/table"
 	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/report"
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/test"
 	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/test/filter"
 	engineapi "github.com/kyverno/kyverno/pkg/engine/api"
 	"github.com/sergi/go-diff/diffmatchpatch"
@@ -22,7 +27,10 @@ import (
 func Command() *cobra.Command {
 	var testCase, outputFormat string
 	var fileName, gitBranch string
-	var registryAccess, failOnly, removeColor, detailedResults, requireTests bool
+	var registryAccess, failOnly, removeColor, detailedResults, requireTests, coverageEnabled, watchMode bool
+	var coverageFile string
+	var parallel int
+	var coverageThreshold float64
 	cmd := &cobra.Command{
 		Use:          "test [local folder or git repository]...",
 		Short:        command.FormatDescription(true, websiteUrl, false, description...),
@@ -35,18 +43,48 @@ func Command() *cobra.Command {
 				removeColor = true
 			}
 			color.Init(removeColor)
-			return testCommandExecute(cmd.OutOrStdout(), dirPath, fileName, gitBranch, testCase, outputFormat, registryAccess, failOnly, detailedResults, requireTests)
+			if parallel < 1 {
+				return fmt.Errorf("invalid parallel value %d, expected at least 1", parallel)
+			}
+			if coverageThreshold < 0 || coverageThreshold > 100 {
+				return fmt.Errorf("invalid coverage threshold %v, expected a percentage between 0 and 100", coverageThreshold)
+			}
+			if watchMode {
+				if len(outputFormat) > 0 || failOnly || coverageEnabled || coverageFile != "" || coverageThreshold > 0 || gitBranch != "" {
+					return fmt.Errorf("--watch can't be used with --output-format, --fail-only, --coverage, --coverage-file, --coverage-threshold or --git-branch")
+				}
+				ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+				defer stop()
+				return watchTests(ctx, cmd.OutOrStdout(), dirPath, fileName, testCase, registryAccess, detailedResults)
+			}
+			var collector *coverage.Collector
+			if coverageEnabled || coverageFile != "" || coverageThreshold > 0 {
+				collector = coverage.NewCollector()
+			}
+			err = testCommandExecute(cmd.OutOrStdout(), dirPath, fileName, gitBranch, testCase, outputFormat, registryAccess, failOnly, detailedResults, requireTests, parallel, collector)
+			if collector != nil {
+				// the coverage is reported even if tests failed, test failures take precedence
+				if coverageErr := reportCoverage(cmd.OutOrStdout(), collector.Report(), coverageFile, coverageThreshold, outputFormat != "sarif"); err == nil {
+					err = coverageErr
+				}
+			}
+			return err
 		},
 	}
 	cmd.Flags().StringVarP(&fileName, "file-name", "f", "kyverno-test.yaml", "Test filename")
 	cmd.Flags().StringVarP(&gitBranch, "git-branch", "b", "", "Test github repository branch")
 	cmd.Flags().StringVarP(&testCase, "test-case-selector", "t", "policy=*,rule=*,resource=*", "Filter test cases to run")
-	cmd.Flags().StringVarP(&outputFormat, "output-format", "o", "", "Specifies the output format (json, yaml, markdown, junit)")
+	cmd.Flags().StringVarP(&outputFormat, "output-format", "o", "", "Specifies the output format (json, yaml, markdown, junit, sarif)")
 	cmd.Flags().BoolVar(&registryAccess, "registry", false, "If set to true, access the image registry using local docker credentials to populate external data")
 	cmd.Flags().BoolVar(&failOnly, "fail-only", false, "If set to true, display all the failing test only as output for the test command")
 	cmd.Flags().BoolVar(&removeColor, "remove-color", false, "Remove any color from output")
 	cmd.Flags().BoolVar(&detailedResults, "detailed-results", false, "If set to true, display detailed results")
 	cmd.Flags().BoolVar(&requireTests, "require-tests", false, "If set to true, return an error if no tests are found")
+	cmd.Flags().BoolVar(&coverageEnabled, "coverage", false, "If set to true, report which policies, rules and CEL expressions were evaluated by the tests")
+	cmd.Flags().StringVar(&coverageFile, "coverage-file", "", "File the coverage report is written to in json format (enables coverage)")
+	cmd.Flags().Float64Var(&coverageThreshold, "coverage-threshold", 0, "Minimum coverage percentage, the command fails below it (enables coverage)")
+	cmd.Flags().IntVar(&parallel, "parallel", 1, "Number of test files run concurrently, the output stays in the order of the test files")
+	cmd.Flags().BoolVar(&watchMode, "watch", false, "If set to true, run the tests again when the files they use change and print the results that changed")
 	return cmd
 }
 
@@ -67,6 +105,8 @@ func testCommandExecute(
 	failOnly bool,
 	detailedResults bool,
 	requireTests bool,
+	parallel int,
+	collector *coverage.Collector,
 ) (err error) {
 	// check input dir
 	if len(dirPath) == 0 {
@@ -79,9 +119,10 @@ func testCommandExecute(
 			"yaml":     true,
 			"markdown": true,
 			"junit":    true,
+			"sarif":    true,
 		}
 		if !validFormats[outputFormat] {
-			return fmt.Errorf("invalid format, expected (json, yaml, markdown, junit)")
+			return fmt.Errorf("invalid format, expected (json, yaml, markdown, junit, sarif)")
 		}
 	}
 	// fetch resource filters
@@ -127,52 +168,57 @@ func testCommandExecute(
 			return errors[0]
 		}
 	}
+	// the sarif log covers all tests, it is printed once and must not be mixed with progress messages
+	var sarifBuilder *sarif.Builder
+	progress := out
+	if outputFormat == "sarif" {
+		sarifBuilder = sarif.NewBuilder()
+		progress = io.Discard
+	}
+	check := func(progress io.Writer, test test.TestCase, collector *coverage.Collector, rc *resultCounts) (*table.Table, *TestResponse, error) {
+		if deprecations.CheckTest(progress, test.Path, test.Test) {
+			return nil, nil, fmt.Errorf("test file %s uses a deprecated schema — please migrate to the latest format", test.Path)
+		}
+		return checkTest(progress, test, filter, resourceFilters, registryAccess, collector, rc)
+	}
 	rc := &resultCounts{}
 	var fullTable table.Table
-	for _, test := range tests {
-		if test.Err == nil {
-			if deprecations.CheckTest(out, test.Path, test.Test) {
-				return fmt.Errorf("test file %s uses a deprecated schema — please migrate to the latest format", test.Path)
-			}
-
-			// filter results
-			var filteredResults []v1alpha1.TestResult
-			for _, res := range test.Test.Results {
-				if filter.Apply(res) {
-					if len(resourceFilters) > 0 {
-						res.Resources = resourceFilters
-					}
-					filteredResults = append(filteredResults, res)
-				}
-			}
-			if len(filteredResults) == 0 {
-				continue
-			}
-			resourcePath := filepath.Dir(test.Path)
-			responses, err := runTest(out, test, registryAccess)
-			if err != nil {
-				return fmt.Errorf("failed to run test (%w)", err)
-			}
-			fmt.Fprintln(out, "  Checking results ...")
-			var resultsTable table.Table
-			if err := printTestResult(filteredResults, responses, rc, &resultsTable, test.Fs, resourcePath); err != nil {
-				return fmt.Errorf("failed to print test result (%w)", err)
-			}
-			if err := printCheckResult(test.Test.Checks, *responses, rc, &resultsTable); err != nil {
-				return fmt.Errorf("failed to print test result (%w)", err)
-			}
-			fullTable.AddFailed(resultsTable.RawRows...)
-			if !failOnly {
-				if len(outputFormat) > 0 {
-					printOutputFormats(out, outputFormat, resultsTable, detailedResults)
-				} else {
-					printer := table.NewTablePrinter(out)
-					fmt.Fprintln(out)
-					printer.Print(resultsTable.Rows(detailedResults))
-					fmt.Fprintln(out)
-				}
+	report := func(i int, run *testRun) error {
+		if _, err := run.progress.WriteTo(progress); err != nil {
+			return err
+		}
+		// runs are reported in order, merging their coverage here keeps the report independent of the order they complete
+		collector.Merge(run.coverage)
+		if run.err != nil {
+			return run.err
+		}
+		rc.add(run.rc)
+		if run.table == nil {
+			return nil
+		}
+		fullTable.AddFailed(run.table.RawRows...)
+		if sarifBuilder != nil {
+			addSarifResults(sarifBuilder, tests[i], run.responses)
+		} else if !failOnly {
+			if len(outputFormat) > 0 {
+				printOutputFormats(out, outputFormat, *run.table, detailedResults)
+			} else {
+				printTable(out, *run.table, detailedResults)
 			}
 		}
+		return nil
+	}
+	if err := runTests(progress, tests, parallel, collector != nil, check, report); err != nil {
+		return err
+	}
+	if sarifBuilder != nil {
+		if err := sarifBuilder.Write(out); err != nil {
+			return err
+		}
+		if rc.Fail > 0 {
+			return fmt.Errorf("%d tests failed", rc.Fail)
+		}
+		return nil
 	}
 	if !failOnly {
 		fmt.Fprintf(out, "\nTest Summary: %d tests passed and %d tests failed\n", rc.Pass+rc.Skip, rc.Fail)
@@ -193,6 +239,51 @@ func testCommandExecute(
 	return nil
 }
 
+// checkTest runs a test case and checks the results selected by the filter, the table is nil if no result was selected.
+func checkTest(
+	progress io.Writer,
+	testCase test.TestCase,
+	testFilter filter.Filter,
+	resourceFilters []string,
+	registryAccess bool,
+	collector *coverage.Collector,
+	rc *resultCounts,
+) (*table.Table, *TestResponse, error) {
+	var filteredResults []v1alpha1.TestResult
+	for _, res := range testCase.Test.Results {
+		if testFilter.Apply(res) {
+			if len(resourceFilters) > 0 {
+				res.Resources = resourceFilters
+			}
+			filteredResults = append(filteredResults, res)
+		}
+	}
+	if len(filteredResults) == 0 {
+		return nil, nil, nil
+	}
+	resourcePath := filepath.Dir(testCase.Path)
+	responses, err := runTest(progress, testCase, registryAccess, collector)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to run test (%w)", err)
+	}
+	fmt.Fprintln(progress, "  Checking results ...")
+	var resultsTable table.Table
+	if err := printTestResult(filteredResults, responses, rc, &resultsTable, testCase.Fs, resourcePath); err != nil {
+		return nil, nil, fmt.Errorf("failed to print test result (%w)", err)
+	}
+	if err := printCheckResult(testCase.Test.Checks, *responses, rc, &resultsTable); err != nil {
+		return nil, nil, fmt.Errorf("failed to print test result (%w)", err)
+	}
+	return &resultsTable, responses, nil
+}
+
+func printTable(out io.Writer, resultsTable table.Table, detailedResults bool) {
+	printer := table.NewTablePrinter(out)
+	fmt.Fprintln(out)
+	printer.Print(resultsTable.Rows(detailedResults))
+	fmt.Fprintln(out)
+}
+
 func checkResult(test v1alpha1.TestResult, fs billy.Filesystem, resoucePath string, response engineapi.EngineResponse, rule engineapi.RuleResponse, actualResource unstructured.Unstructured) (bool, string, string) {
 	expected := test.Result
 	expectedPatchResources := test.PatchedResources
@@ -113,7 +113,7 @@ func Test_JSONPayload(t *testing.T) {
 
 	out := &bytes.Buffer{}
 	t.Logf("Running test with files from %s", testCase.Dir())
-	testResponse, err := runTest(out, testCase, false)
+	testResponse, err := runTest(out, testCase, false, nil)
 	require.NoError(t, err, "Failed to run test")
 
 	t.Logf("Test output: %s", out.String())
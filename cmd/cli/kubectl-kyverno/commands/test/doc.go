@@ -25,4 +25,12 @@ var examples = [][]string{
 		`# Test some specific test cases out of many test cases in a local folder`,
 		`kyverno test . --test-case-selector "policy=disallow-latest-tag, rule=require-image-tag, resource=test-require-image-tag-pass"`,
 	},
+	{
+		`# Run the tests of a local folder again each time the files they use change`,
+		`kyverno test . --watch`,
+	},
+	{
+		`# Run the test files of a local folder with 8 workers`,
+		`kyverno test . --parallel 8`,
+	},
 }
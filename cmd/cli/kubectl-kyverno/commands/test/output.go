@@ -5,13 +5,19 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"os"
+	"sort"
 	"strings"
 
 	"github.com/go-git/go-billy/v5"
 	"github.com/kyverno/kyverno-json/pkg/engine/assert"
 	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/apis/v1alpha1"
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/coverage"
 	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/output/color"
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/output/sarif"
 	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/output/table"
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/path"
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/test"
 	engineapi "github.com/kyverno/kyverno/pkg/engine/api"
 	"github.com/kyverno/kyverno/pkg/openreports"
 	"gopkg.in/yaml.v3"
@@ -440,6 +446,24 @@ func printFailedTestResult(out io.Writer, resultsTable table.Table, detailedResu
 	printer.Print(resultsTable.Rows(detailedResults))
 }
 
+// addSarifResults adds the failed and warned rule responses of a test, located in the test resource files.
+func addSarifResults(builder *sarif.Builder, testCase test.TestCase, responses *TestResponse) {
+	var paths []string
+	paths = append(paths, testCase.Test.Resources...)
+	paths = append(paths, testCase.Test.TargetResources...)
+	index := sarif.NewResourceIndex(testCase.Fs, path.GetFullPaths(paths, testCase.Dir(), testCase.Fs != nil)...)
+	for _, m := range []map[string][]engineapi.EngineResponse{responses.Trigger, responses.Target} {
+		keys := make([]string, 0, len(m))
+		for key := range m {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			builder.AddEngineResponses(index, false, m[key]...)
+		}
+	}
+}
+
 func printOutputFormats(out io.Writer, outputFormat string, resultTable table.Table, detailedResults bool) {
 	output := make([]interface{}, 0, len(resultTable.RawRows))
 	failedTests := 0
@@ -529,3 +553,55 @@ func printOutputFormats(out io.Writer, outputFormat string, resultTable table.Ta
 		fmt.Fprintln(out)
 	}
 }
+
+type coverageRow struct {
+	Kind      string `header:"kind"`
+	Policy    string `header:"policy"`
+	Rule      string `header:"rule/expression"`
+	Evaluated int    `header:"evaluated"`
+	Pass      int    `header:"pass"`
+	Fail      int    `header:"fail"`
+	Skip      int    `header:"skip"`
+	Error     int    `header:"error"`
+	IsCovered bool   `header:"covered"`
+}
+
+// reportCoverage prints the coverage summary, writes the coverage file and checks the coverage threshold.
+func reportCoverage(out io.Writer, report coverage.Report, file string, threshold float64, printSummary bool) error {
+	if printSummary {
+		rows := make([]coverageRow, 0, len(report.Entries))
+		for _, entry := range report.Entries {
+			rule := entry.Rule
+			if entry.Expression != "" {
+				rule = entry.Expression
+			}
+			rows = append(rows, coverageRow{
+				Kind:      entry.Kind,
+				Policy:    entry.Policy,
+				Rule:      rule,
+				Evaluated: entry.Evaluated,
+				Pass:      entry.Pass,
+				Fail:      entry.Fail,
+				Skip:      entry.Skip,
+				Error:     entry.Error,
+				IsCovered: entry.Covered(),
+			})
+		}
+		fmt.Fprintln(out, "Coverage:")
+		table.NewTablePrinter(out).Print(rows)
+		fmt.Fprintf(out, "\nCoverage Summary: %d out of %d policies, rules and expressions evaluated (%.2f%%)\n\n", report.Covered, report.Total, report.Percentage)
+	}
+	if file != "" {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(file, data, 0o644); err != nil {
+			return fmt.Errorf("failed to write coverage file (%w)", err)
+		}
+	}
+	if report.Percentage < threshold {
+		return fmt.Errorf("coverage %.2f%% is below the threshold of %.2f%%", report.Percentage, threshold)
+	}
+	return nil
+}
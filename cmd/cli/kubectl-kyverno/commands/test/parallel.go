@@ -0,0 +1,124 @@
+package test
+
+import (
+	"bytes"
+	"io"
+	"sync"
+
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/coverage"
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/output/table"
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/test"
+)
+
+// testRun is the outcome of a test case.
+type testRun struct {
+	// progress holds the progress messages of the test case when test files are run concurrently
+	progress  bytes.Buffer
+	table     *table.Table
+	responses *TestResponse
+	rc        resultCounts
+	// coverage holds the coverage of the test case when coverage is collected
+	coverage *coverage.Collector
+	err      error
+}
+
+type checkFunc func(progress io.Writer, testCase test.TestCase, collector *coverage.Collector, rc *resultCounts) (*table.Table, *TestResponse, error)
+
+// runTests runs the test cases that were loaded without errors and reports their runs grouped by test file, in order,
+// it stops at the first error returned by report.
+// Test files are run by up to parallel workers and the test cases of a test file are run in order, a test file stops at
+// the first test case that fails to run. When test files are run concurrently the progress messages are buffered in the
+// runs so that the output doesn't depend on the order test files complete, otherwise they are written to progress.
+// When collect is true every run records its coverage in a collector of its own.
+func runTests(progress io.Writer, tests test.TestCases, parallel int, collect bool, check checkFunc, report func(int, *testRun) error) error {
+	var paths []string
+	files := map[string][]int{}
+	for i, testCase := range tests {
+		if testCase.Err != nil {
+			continue
+		}
+		if _, ok := files[testCase.Path]; !ok {
+			paths = append(paths, testCase.Path)
+		}
+		files[testCase.Path] = append(files[testCase.Path], i)
+	}
+	newRun := func() *testRun {
+		run := &testRun{}
+		if collect {
+			run.coverage = coverage.NewCollector()
+		}
+		return run
+	}
+	if parallel <= 1 {
+		for _, path := range paths {
+			for _, i := range files[path] {
+				run := newRun()
+				run.table, run.responses, run.err = check(progress, tests[i], run.coverage, &run.rc)
+				if err := report(i, run); err != nil {
+					return err
+				}
+				if run.err != nil {
+					break
+				}
+			}
+		}
+		return nil
+	}
+	runs := make([]*testRun, len(tests))
+	done := make([]chan struct{}, len(tests))
+	for i := range done {
+		done[i] = make(chan struct{})
+	}
+	stop := make(chan struct{})
+	queue := make(chan string)
+	var wg sync.WaitGroup
+	for w := 0; w < parallel && w < len(paths); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range queue {
+				failed := false
+				for _, i := range files[path] {
+					// the test cases following a failure are not run
+					if !failed {
+						run := newRun()
+						run.table, run.responses, run.err = check(&run.progress, tests[i], run.coverage, &run.rc)
+						runs[i] = run
+						failed = run.err != nil
+					}
+					close(done[i])
+				}
+			}
+		}()
+	}
+	go func() {
+		defer close(queue)
+		for _, path := range paths {
+			select {
+			case queue <- path:
+			case <-stop:
+				return
+			}
+		}
+	}()
+	defer wg.Wait()
+	defer close(stop)
+	for _, path := range paths {
+		for _, i := range files[path] {
+			<-done[i]
+			if runs[i] == nil {
+				continue
+			}
+			if err := report(i, runs[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (rc *resultCounts) add(other resultCounts) {
+	rc.Skip += other.Skip
+	rc.Pass += other.Pass
+	rc.Fail += other.Fail
+}
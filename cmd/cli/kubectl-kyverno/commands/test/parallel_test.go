@@ -0,0 +1,254 @@
+package test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/apis/v1alpha1"
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/coverage"
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/output/color"
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/output/table"
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/test"
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/test/filter"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_runTests(t *testing.T) {
+	tests := test.TestCases{
+		{Path: "a/kyverno-test.yaml", Test: &v1alpha1.Test{}},
+		{Path: "b/kyverno-test.yaml", Test: &v1alpha1.Test{}},
+		{Path: "a/kyverno-test.yaml", Test: &v1alpha1.Test{}},
+		{Path: "c/kyverno-test.yaml", Err: errors.New("invalid test")},
+		{Path: "d/kyverno-test.yaml", Test: &v1alpha1.Test{}},
+	}
+	check := func(progress io.Writer, testCase test.TestCase, collector *coverage.Collector, rc *resultCounts) (*table.Table, *TestResponse, error) {
+		// every run has a collector of its own
+		assert.NotNil(t, collector)
+		// the first test files complete last
+		if testCase.Path == "a/kyverno-test.yaml" {
+			time.Sleep(50 * time.Millisecond)
+		}
+		fmt.Fprintln(progress, "run", testCase.Path)
+		rc.Pass++
+		return &table.Table{}, nil, nil
+	}
+	for _, parallel := range []int{1, 4} {
+		t.Run(fmt.Sprintf("parallel %d", parallel), func(t *testing.T) {
+			var out strings.Builder
+			var reported []int
+			rc := &resultCounts{}
+			err := runTests(&out, tests, parallel, true, check, func(i int, run *testRun) error {
+				_, err := run.progress.WriteTo(&out)
+				reported = append(reported, i)
+				rc.add(run.rc)
+				return err
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, []int{0, 2, 1, 4}, reported)
+			assert.Equal(t, "run a/kyverno-test.yaml\nrun a/kyverno-test.yaml\nrun b/kyverno-test.yaml\nrun d/kyverno-test.yaml\n", out.String())
+			assert.Equal(t, resultCounts{Pass: 4}, *rc)
+		})
+	}
+}
+
+func Test_runTestsError(t *testing.T) {
+	tests := test.TestCases{
+		{Path: "a/kyverno-test.yaml", Test: &v1alpha1.Test{}},
+		{Path: "a/kyverno-test.yaml", Test: &v1alpha1.Test{}},
+		{Path: "b/kyverno-test.yaml", Test: &v1alpha1.Test{}},
+	}
+	check := func(progress io.Writer, testCase test.TestCase, collector *coverage.Collector, rc *resultCounts) (*table.Table, *TestResponse, error) {
+		assert.Nil(t, collector)
+		if testCase.Path == "a/kyverno-test.yaml" {
+			return nil, nil, errors.New("failed to run test")
+		}
+		return &table.Table{}, nil, nil
+	}
+	for _, parallel := range []int{1, 4} {
+		t.Run(fmt.Sprintf("parallel %d", parallel), func(t *testing.T) {
+			var reported []int
+			err := runTests(io.Discard, tests, parallel, false, check, func(i int, run *testRun) error {
+				reported = append(reported, i)
+				return run.err
+			})
+			assert.EqualError(t, err, "failed to run test")
+			assert.Equal(t, []int{0}, reported)
+		})
+	}
+}
+
+// Test_testCommandExecuteParallel runs test files concurrently, the CI runs it with the race detector to catch
+// state shared between test runs.
+func Test_testCommandExecuteParallel(t *testing.T) {
+	dirs := []string{
+		"../../../../../test/cli/test-validating-policy",
+		"../../../../../test/cli/test-mutating-policy",
+		"../../../../../test/cli/test-generating-policy",
+		"../../../../../test/cli/test-deleting-policy",
+	}
+	color.Init(true)
+	run := func(parallel int) (string, coverage.Report, error) {
+		var out strings.Builder
+		collector := coverage.NewCollector()
+		err := testCommandExecute(&out, dirs, "kyverno-test.yaml", "", "", "", false, false, false, false, parallel, collector)
+		// the rows of a result table are not sorted, only the other lines are compared
+		var lines []string
+		for _, line := range strings.Split(out.String(), "\n") {
+			if !strings.HasPrefix(line, "│") {
+				lines = append(lines, line)
+			}
+		}
+		return strings.Join(lines, "\n"), collector.Report(), err
+	}
+	wantOut, wantReport, wantErr := run(1)
+	gotOut, gotReport, gotErr := run(8)
+	assert.Equal(t, wantErr, gotErr)
+	assert.Equal(t, wantOut, gotOut)
+	assert.Equal(t, wantReport, gotReport)
+}
+
+// Test_runTestsStoreIsolation runs test files that need conflicting store state concurrently, the image config
+// comes from the registry in the test files run with registry access and from the values in the other ones.
+func Test_runTestsStoreIsolation(t *testing.T) {
+	server := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+	defer server.Close()
+	image, err := random.Image(16, 1)
+	assert.NoError(t, err)
+	image, err = mutate.Config(image, v1.Config{User: "nginx"})
+	assert.NoError(t, err)
+	ref, err := name.ParseReference(strings.TrimPrefix(server.URL, "http://") + "/app:v1")
+	assert.NoError(t, err)
+	assert.NoError(t, remote.Write(ref, image))
+	dir := t.TempDir()
+	files := map[string]string{
+		"policy.yaml": `apiVersion: kyverno.io/v1
+kind: ClusterPolicy
+metadata:
+  name: image-user
+spec:
+  rules:
+  - name: check-user
+    match:
+      any:
+      - resources:
+          kinds:
+          - Pod
+    context:
+    - name: imageData
+      imageRegistry:
+        reference: '{{ request.object.spec.containers[0].image }}'
+    validate:
+      failureAction: Enforce
+      deny:
+        conditions:
+          any:
+          - key: '{{ imageData.configData.config.User }}'
+            operator: NotEquals
+            value: '{{ request.allowedUser }}'
+`,
+		"resource.yaml": `apiVersion: v1
+kind: Pod
+metadata:
+  name: app
+spec:
+  containers:
+  - name: app
+    image: ` + ref.String() + `
+`,
+		"registry/kyverno-test.yaml": `apiVersion: cli.kyverno.io/v1alpha1
+kind: Test
+metadata:
+  name: registry
+policies:
+- ../policy.yaml
+resources:
+- ../resource.yaml
+variables: values.yaml
+results:
+- kind: Pod
+  policy: image-user
+  rule: check-user
+  resources:
+  - app
+  result: pass
+`,
+		"registry/values.yaml": `apiVersion: cli.kyverno.io/v1alpha1
+kind: Values
+globalValues:
+  request.allowedUser: nginx
+`,
+		"offline/kyverno-test.yaml": `apiVersion: cli.kyverno.io/v1alpha1
+kind: Test
+metadata:
+  name: offline
+policies:
+- ../policy.yaml
+resources:
+- ../resource.yaml
+variables: values.yaml
+results:
+- kind: Pod
+  policy: image-user
+  rule: check-user
+  resources:
+  - app
+  result: pass
+`,
+		"offline/values.yaml": `apiVersion: cli.kyverno.io/v1alpha1
+kind: Values
+globalValues:
+  request.allowedUser: root
+policies:
+- name: image-user
+  rules:
+  - name: check-user
+    values:
+      imageData.configData.config.User: root
+`,
+	}
+	for file, content := range files {
+		path := filepath.Join(dir, file)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+	loaded, err := loadTests([]string{dir}, "kyverno-test.yaml", "")
+	assert.NoError(t, err)
+	assert.Len(t, loaded, 2)
+	// the runs of both test files are interleaved
+	var tests test.TestCases
+	for range 4 {
+		tests = append(tests, loaded...)
+	}
+	color.Init(true)
+	testFilter, errs := filter.ParseFilter("")
+	assert.Empty(t, errs)
+	check := func(progress io.Writer, testCase test.TestCase, collector *coverage.Collector, rc *resultCounts) (*table.Table, *TestResponse, error) {
+		registryAccess := filepath.Base(testCase.Dir()) == "registry"
+		return checkTest(progress, testCase, testFilter, nil, registryAccess, collector, rc)
+	}
+	for _, parallel := range []int{1, 8} {
+		t.Run(fmt.Sprintf("parallel %d", parallel), func(t *testing.T) {
+			rc := &resultCounts{}
+			err := runTests(io.Discard, tests, parallel, false, check, func(i int, run *testRun) error {
+				rc.add(run.rc)
+				return run.err
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, resultCounts{Pass: len(tests)}, *rc)
+		})
+	}
+}
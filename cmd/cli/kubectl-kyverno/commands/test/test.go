@@ -13,6 +13,7 @@ import (
 	kyvernov2 "github.com/kyverno/kyverno/api/kyverno/v2"
 	policiesv1alpha1 "github.com/kyverno/kyverno/api/policies.kyverno.io/v1alpha1"
 	policiesv1beta1 "github.com/kyverno/kyverno/api/policies.kyverno.io/v1beta1"
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/coverage"
 	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/deprecations"
 	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/exception"
 	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/log"
@@ -28,6 +29,7 @@ import (
 	"github.com/kyverno/kyverno/ext/output/pluralize"
 	"github.com/kyverno/kyverno/pkg/autogen"
 	"github.com/kyverno/kyverno/pkg/background/generate"
+	celcompiler "github.com/kyverno/kyverno/pkg/cel/compiler"
 	celengine "github.com/kyverno/kyverno/pkg/cel/engine"
 	"github.com/kyverno/kyverno/pkg/cel/matching"
 	dpolcompiler "github.com/kyverno/kyverno/pkg/cel/policies/dpol/compiler"
@@ -55,7 +57,7 @@ type TestResponse struct {
 	Target  map[string][]engineapi.EngineResponse
 }
 
-func runTest(out io.Writer, testCase test.TestCase, registryAccess bool) (*TestResponse, error) {
+func runTest(out io.Writer, testCase test.TestCase, registryAccess bool, collector *coverage.Collector) (*TestResponse, error) {
 	// don't process test case with errors
 	if testCase.Err != nil {
 		return nil, testCase.Err
@@ -97,6 +99,14 @@ func runTest(out io.Writer, testCase test.TestCase, registryAccess bool) (*TestR
 	if err != nil {
 		return nil, fmt.Errorf("error: failed to load policies (%s)", err)
 	}
+	// the observer must stay nil when coverage is disabled
+	var observer celcompiler.Observer
+	ctx := context.TODO()
+	if collector != nil {
+		collector.AddPolicies(results)
+		observer = collector
+		ctx = celcompiler.WithObserver(ctx, collector)
+	}
 	genericPolicies := make([]engineapi.GenericPolicy, 0, len(results.Policies)+len(results.VAPs))
 	for _, pol := range results.Policies {
 		genericPolicies = append(genericPolicies, engineapi.NewKyvernoPolicy(pol))
@@ -286,6 +296,7 @@ func runTest(out io.Writer, testCase test.TestCase, registryAccess bool) (*TestR
 			Client:                            dClient,
 			Subresources:                      vars.Subresources(),
 			Out:                               io.Discard,
+			Observer:                          observer,
 		}
 		ers, err := processor.ApplyPoliciesOnResource()
 		if err != nil {
@@ -293,6 +304,7 @@ func runTest(out io.Writer, testCase test.TestCase, registryAccess bool) (*TestR
 		}
 		if len(results.ImageValidatingPolicies) != 0 {
 			ivpols, err := applyImageValidatingPolicies(
+				ctx,
 				results.ImageValidatingPolicies,
 				nil,
 				[]*unstructured.Unstructured{resource},
@@ -321,6 +333,7 @@ func runTest(out io.Writer, testCase test.TestCase, registryAccess bool) (*TestR
 				dpolInputs = append(dpolInputs, &results.NamespacedDeletingPolicies[i])
 			}
 			dpols, err := applyDeletingPolicies(
+				ctx,
 				dpolInputs,
 				[]*unstructured.Unstructured{resource},
 				polexLoader.CELExceptions,
@@ -370,6 +383,7 @@ func runTest(out io.Writer, testCase test.TestCase, registryAccess bool) (*TestR
 			Client:                            dClient,
 			Subresources:                      vars.Subresources(),
 			Out:                               io.Discard,
+			Observer:                          observer,
 		}
 		ers, err := processor.ApplyPoliciesOnResource()
 		if err != nil {
@@ -377,6 +391,7 @@ func runTest(out io.Writer, testCase test.TestCase, registryAccess bool) (*TestR
 		}
 		if len(results.ImageValidatingPolicies) != 0 {
 			ivpols, err := applyImageValidatingPolicies(
+				ctx,
 				results.ImageValidatingPolicies,
 				[]*unstructured.Unstructured{{Object: json.(map[string]any)}},
 				nil,
@@ -405,6 +420,7 @@ func runTest(out io.Writer, testCase test.TestCase, registryAccess bool) (*TestR
 				dpolInputs = append(dpolInputs, &results.NamespacedDeletingPolicies[i])
 			}
 			dpols, err := applyDeletingPolicies(
+				ctx,
 				dpolInputs,
 				[]*unstructured.Unstructured{{Object: json.(map[string]any)}},
 				polexLoader.CELExceptions,
@@ -424,6 +440,9 @@ func runTest(out io.Writer, testCase test.TestCase, registryAccess bool) (*TestR
 		testResponse.Trigger[testCase.Test.JSONPayload] = append(testResponse.Trigger[testCase.Test.JSONPayload], ers...)
 		engineResponses = append(engineResponses, ers...)
 	}
+	if collector != nil {
+		collector.AddEngineResponses(engineResponses...)
+	}
 	for _, targetResource := range targetResources {
 		for _, engineResponse := range engineResponses {
 			if r, _ := extractPatchedTargetFromEngineResponse(targetResource.GetAPIVersion(), targetResource.GetKind(), targetResource.GetName(), targetResource.GetNamespace(), engineResponse); r != nil {
@@ -437,6 +456,7 @@ func runTest(out io.Writer, testCase test.TestCase, registryAccess bool) (*TestR
 }
 
 func applyImageValidatingPolicies(
+	ctx context.Context,
 	ivps []policiesv1beta1.ImageValidatingPolicy,
 	jsonPayloads []*unstructured.Unstructured,
 	resources []*unstructured.Unstructured,
@@ -469,6 +489,8 @@ func applyImageValidatingPolicies(
 		matching.NewMatcher(),
 		lister,
 		[]imagedataloader.Option{imagedataloader.WithLocalCredentials(registryAccess)},
+		nil,
+		nil,
 	)
 	restMapper, err := utils.GetRESTMapper(dclient, true)
 	if err != nil {
@@ -509,7 +531,7 @@ func applyImageValidatingPolicies(
 			false,
 			nil,
 		)
-		engineResponse, _, err := engine.HandleMutating(context.TODO(), request, nil)
+		engineResponse, _, err := engine.HandleMutating(ctx, request, nil)
 		if err != nil {
 			if continueOnFail {
 				fmt.Printf("failed to apply image validating policies on resource %s (%v)\n", resource.GetName(), err)
@@ -537,7 +559,7 @@ func applyImageValidatingPolicies(
 		ivpols = append(ivpols, &eval.CompiledImageValidatingPolicy{Policy: p})
 	}
 	for _, json := range jsonPayloads {
-		result, err := eval.Evaluate(context.TODO(), ivpols, json.Object, nil, nil, nil)
+		result, err := eval.Evaluate(ctx, ivpols, json.Object, nil, nil, nil)
 		if err != nil {
 			if continueOnFail {
 				fmt.Printf("failed to apply image validating policies on JSON payload: %v\n", err)
@@ -572,6 +594,7 @@ func applyImageValidatingPolicies(
 }
 
 func applyDeletingPolicies(
+	ctx context.Context,
 	dps []policiesv1beta1.DeletingPolicyLike,
 	resources []*unstructured.Unstructured,
 	celExceptions []*policiesv1alpha1.PolicyException,
@@ -599,7 +622,7 @@ func applyDeletingPolicies(
 
 	engine := dpolengine.NewEngine(namespaceProvider, restMapper, contextProvider, matching.NewMatcher())
 
-	policies, err := provider.Fetch(context.Background())
+	policies, err := provider.Fetch(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -612,7 +635,7 @@ func applyDeletingPolicies(
 				return nil, fmt.Errorf("unsupported deleting policy type %T", dpol.Policy)
 			}
 			policyName := dpol.Policy.GetName()
-			resp, err := engine.Handle(context.TODO(), dpol, *resource)
+			resp, err := engine.Handle(ctx, dpol, *resource)
 			if err != nil {
 				fmt.Printf("failed to apply policy %s on resource: %v\n", resource.GetName(), err)
 
@@ -0,0 +1,328 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/output/table"
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/path"
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/source"
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/test"
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/test/filter"
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/watch"
+)
+
+// testFile holds the last results of the tests declared in a test file.
+type testFile struct {
+	// inputs are the local files the tests read, the test file included
+	inputs []string
+	// results are the results of the tests, by result key
+	results map[string]table.Row
+	// keys are the result keys in the order the results were checked
+	keys []string
+	err  error
+}
+
+// resultChange is a result that was added, removed or changed by a run, previous is nil for added results
+// and current is nil for removed results.
+type resultChange struct {
+	key      string
+	previous *table.Row
+	current  *table.Row
+}
+
+type testWatcher struct {
+	testFilter      filter.Filter
+	resourceFilters []string
+	registryAccess  bool
+}
+
+// watchTests runs the tests then runs again the tests affected by the changes made to the files they read,
+// until the context is cancelled. Only the results that changed are printed after the first run.
+func watchTests(ctx context.Context, out io.Writer, dirPath []string, fileName, testCase string, registryAccess, detailedResults bool) error {
+	for _, path := range dirPath {
+		if source.IsGit(path) {
+			return fmt.Errorf("watch mode doesn't support git repositories (%s)", path)
+		}
+	}
+	testFilter, errs := filter.ParseFilter(testCase)
+	if len(errs) > 0 {
+		return errs[0]
+	}
+	w := testWatcher{
+		testFilter:      testFilter,
+		resourceFilters: filter.ExtractResourceFilters(testCase),
+		registryAccess:  registryAccess,
+	}
+	watcher, err := watch.New()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+	tests, err := loadTests(dirPath, fileName, "")
+	if err != nil {
+		return err
+	}
+	files := map[string]*testFile{}
+	for _, path := range testPaths(tests) {
+		file := w.run(path)
+		files[path] = file
+		fmt.Fprintln(out, "Test file", path)
+		if file.err != nil {
+			fmt.Fprintln(out, "  Error:", file.err)
+			continue
+		}
+		var resultsTable table.Table
+		for _, key := range file.keys {
+			row := file.results[key]
+			row.ID = len(resultsTable.RawRows) + 1
+			resultsTable.Add(row)
+		}
+		printTable(out, resultsTable, detailedResults)
+	}
+	printWatchSummary(out, files)
+	fmt.Fprintln(out, "Watching for changes, press Ctrl+C to stop ...")
+	for {
+		inputs := append([]string{}, dirPath...)
+		for _, file := range files {
+			inputs = append(inputs, file.inputs...)
+		}
+		if err := watcher.Watch(inputs...); err != nil {
+			return err
+		}
+		changes, err := watcher.Next(ctx)
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				return nil
+			}
+			return err
+		}
+		affected, relevant := affectedTests(files, changes, fileName)
+		if len(affected) == 0 {
+			continue
+		}
+		fmt.Fprintln(out)
+		for _, change := range relevant {
+			fmt.Fprintln(out, "Changed", change)
+		}
+		for _, path := range affected {
+			previous := files[path]
+			if _, err := os.Stat(path); err != nil {
+				delete(files, path)
+				fmt.Fprintln(out, "Test file", path, "was removed")
+				continue
+			}
+			current := w.run(path)
+			files[path] = current
+			fmt.Fprintln(out, "Test file", path)
+			if current.err != nil {
+				fmt.Fprintln(out, "  Error:", current.err)
+				continue
+			}
+			var results map[string]table.Row
+			if previous != nil {
+				results = previous.results
+			}
+			changes := diffResults(results, current.results)
+			if len(changes) == 0 {
+				fmt.Fprintf(out, "  No result changed (%d results)\n", len(current.results))
+				continue
+			}
+			for _, change := range changes {
+				printResultChange(out, change)
+			}
+		}
+		printWatchSummary(out, files)
+		fmt.Fprintln(out, "Watching for changes, press Ctrl+C to stop ...")
+	}
+}
+
+// run runs the tests of a test file, progress messages are not printed to keep the output incremental.
+func (w testWatcher) run(path string) *testFile {
+	file := &testFile{
+		inputs:  []string{path},
+		results: map[string]table.Row{},
+	}
+	for _, testCase := range test.LoadTest(nil, path) {
+		if testCase.Err != nil {
+			file.err = testCase.Err
+			return file
+		}
+		file.inputs = append(file.inputs, testInputs(testCase)...)
+		resultsTable, _, err := checkTest(io.Discard, testCase, w.testFilter, w.resourceFilters, w.registryAccess, nil, &resultCounts{})
+		if err != nil {
+			file.err = err
+			return file
+		}
+		if resultsTable == nil {
+			continue
+		}
+		testName := testCase.Test.GetName()
+		if testName == "" {
+			testName = testCase.Test.Name
+		}
+		for _, row := range resultsTable.RawRows {
+			key := resultKey(testName, row)
+			// the same result can be declared more than once
+			for i := 2; ; i++ {
+				if _, ok := file.results[key]; !ok {
+					break
+				}
+				key = fmt.Sprintf("%s #%d", resultKey(testName, row), i)
+			}
+			file.results[key] = row
+			file.keys = append(file.keys, key)
+		}
+	}
+	return file
+}
+
+// testInputs returns the local files and directories a test case reads, except the test file.
+func testInputs(testCase test.TestCase) []string {
+	test := testCase.Test
+	var paths []string
+	paths = append(paths, test.Policies...)
+	paths = append(paths, test.Resources...)
+	paths = append(paths, test.PolicyExceptions...)
+	paths = append(paths, test.TargetResources...)
+	paths = append(paths, test.ParamResources...)
+	paths = append(paths, test.JSONPayload, test.Variables, test.UserInfo, test.Context)
+	for _, result := range test.Results {
+		paths = append(paths, result.PatchedResources, result.GeneratedResource, result.CloneSourceResource)
+	}
+	var inputs []string
+	for _, input := range paths {
+		if input == "" || source.IsHttp(input) {
+			continue
+		}
+		inputs = append(inputs, path.GetFullPath(input, testCase.Dir()))
+	}
+	return inputs
+}
+
+// affectedTests returns the sorted paths of the test files reading one of the changed files, test files
+// that were created are included, and the changes that affected them.
+func affectedTests(files map[string]*testFile, changes []string, fileName string) ([]string, []string) {
+	affected := map[string]bool{}
+	var relevant []string
+	for _, change := range changes {
+		isRelevant := false
+		if filepath.Base(change) == fileName {
+			affected[change] = true
+			isRelevant = true
+		}
+		for path, file := range files {
+			for _, input := range file.inputs {
+				if input, err := filepath.Abs(input); err == nil && watch.Contains(input, change) {
+					affected[path] = true
+					isRelevant = true
+					break
+				}
+			}
+		}
+		if isRelevant {
+			relevant = append(relevant, change)
+		}
+	}
+	return sortedKeys(affected), relevant
+}
+
+// diffResults returns the results that were added, removed or changed, sorted by key.
+func diffResults(previous, current map[string]table.Row) []resultChange {
+	keys := map[string]bool{}
+	for key := range previous {
+		keys[key] = true
+	}
+	for key := range current {
+		keys[key] = true
+	}
+	var changes []resultChange
+	for _, key := range sortedKeys(keys) {
+		previousRow, hadPrevious := previous[key]
+		currentRow, hasCurrent := current[key]
+		change := resultChange{key: key}
+		if hadPrevious {
+			change.previous = &previousRow
+		}
+		if hasCurrent {
+			change.current = &currentRow
+		}
+		if hadPrevious && hasCurrent && sameResult(previousRow, currentRow) {
+			continue
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+func sameResult(previous, current table.Row) bool {
+	return previous.IsFailure == current.IsFailure && previous.Result == current.Result && previous.Reason == current.Reason
+}
+
+func resultKey(testName string, row table.Row) string {
+	parts := []string{row.Policy, row.Rule, row.Resource}
+	if testName != "" {
+		parts = append([]string{testName}, parts...)
+	}
+	return strings.Join(parts, " / ")
+}
+
+func printResultChange(out io.Writer, change resultChange) {
+	switch {
+	case change.previous == nil:
+		fmt.Fprintf(out, "  + %s: %s (%s)\n", change.key, change.current.Result, change.current.Reason)
+	case change.current == nil:
+		fmt.Fprintf(out, "  - %s: %s\n", change.key, change.previous.Result)
+	default:
+		fmt.Fprintf(out, "  ~ %s: %s -> %s (%s)\n", change.key, change.previous.Result, change.current.Result, change.current.Reason)
+	}
+}
+
+func printWatchSummary(out io.Writer, files map[string]*testFile) {
+	var pass, fail, errs int
+	for _, file := range files {
+		if file.err != nil {
+			errs++
+		}
+		for _, row := range file.results {
+			if row.IsFailure {
+				fail++
+			} else {
+				pass++
+			}
+		}
+	}
+	fmt.Fprintf(out, "\nTest Summary: %d tests passed and %d tests failed", pass, fail)
+	if errs > 0 {
+		fmt.Fprintf(out, ", %d test files have errors", errs)
+	}
+	fmt.Fprintln(out)
+	fmt.Fprintln(out)
+}
+
+func testPaths(tests test.TestCases) []string {
+	paths := map[string]bool{}
+	for _, testCase := range tests {
+		path, err := filepath.Abs(testCase.Path)
+		if err != nil {
+			path = testCase.Path
+		}
+		paths[path] = true
+	}
+	return sortedKeys(paths)
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
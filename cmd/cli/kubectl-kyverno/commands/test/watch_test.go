@@ -0,0 +1,110 @@
+package test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/apis/v1alpha1"
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/output/table"
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_testInputs(t *testing.T) {
+	testCase := test.TestCase{
+		Path: "/tests/require-labels/kyverno-test.yaml",
+		Test: &v1alpha1.Test{
+			Policies:  []string{"policy.yaml", "https://github.com/kyverno/policies/raw/main/policy.yaml"},
+			Resources: []string{"resources", "/shared/pod.yaml"},
+			Variables: "values.yaml",
+			Results: []v1alpha1.TestResult{{
+				TestResultBase: v1alpha1.TestResultBase{
+					PatchedResources: "patched.yaml",
+				},
+			}},
+		},
+	}
+	assert.Equal(t, []string{
+		"/tests/require-labels/policy.yaml",
+		"/tests/require-labels/resources",
+		"/shared/pod.yaml",
+		"/tests/require-labels/values.yaml",
+		"/tests/require-labels/patched.yaml",
+	}, testInputs(testCase))
+}
+
+func Test_affectedTests(t *testing.T) {
+	files := map[string]*testFile{
+		"/tests/a/kyverno-test.yaml": {
+			inputs: []string{"/tests/a/kyverno-test.yaml", "/tests/a/policy.yaml", "/tests/resources"},
+		},
+		"/tests/b/kyverno-test.yaml": {
+			inputs: []string{"/tests/b/kyverno-test.yaml", "/tests/b/policy.yaml", "/tests/resources/pod.yaml"},
+		},
+	}
+	tests := []struct {
+		name    string
+		changes []string
+		want    []string
+	}{{
+		name:    "policy of a test",
+		changes: []string{"/tests/a/policy.yaml"},
+		want:    []string{"/tests/a/kyverno-test.yaml"},
+	}, {
+		name:    "file in a directory",
+		changes: []string{"/tests/resources/deployment.yaml"},
+		want:    []string{"/tests/a/kyverno-test.yaml"},
+	}, {
+		name:    "shared file",
+		changes: []string{"/tests/resources/pod.yaml"},
+		want:    []string{"/tests/a/kyverno-test.yaml", "/tests/b/kyverno-test.yaml"},
+	}, {
+		name:    "new test file",
+		changes: []string{"/tests/c/kyverno-test.yaml"},
+		want:    []string{"/tests/c/kyverno-test.yaml"},
+	}, {
+		name:    "unrelated file",
+		changes: []string{"/tests/c/policy.yaml"},
+		want:    []string{},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var changes []string
+			for _, change := range tt.changes {
+				changes = append(changes, filepath.FromSlash(change))
+			}
+			affected, relevant := affectedTests(files, changes, "kyverno-test.yaml")
+			assert.Equal(t, tt.want, affected)
+			if len(tt.want) == 0 {
+				assert.Empty(t, relevant)
+			} else {
+				assert.Equal(t, changes, relevant)
+			}
+		})
+	}
+}
+
+func Test_diffResults(t *testing.T) {
+	pass := table.Row{RowCompact: table.RowCompact{Result: "Pass", Reason: "Ok"}}
+	fail := table.Row{RowCompact: table.RowCompact{Result: "Fail", Reason: "Want pass, got fail", IsFailure: true}}
+	previous := map[string]table.Row{
+		"unchanged": pass,
+		"changed":   pass,
+		"removed":   fail,
+	}
+	moved := pass
+	moved.ID = 42
+	current := map[string]table.Row{
+		"unchanged": moved,
+		"changed":   fail,
+		"added":     pass,
+	}
+	changes := diffResults(previous, current)
+	assert.Equal(t, []resultChange{
+		{key: "added", current: &pass},
+		{key: "changed", previous: &pass, current: &fail},
+		{key: "removed", previous: &fail},
+	}, changes)
+	assert.Empty(t, diffResults(current, current))
+	assert.Len(t, diffResults(nil, current), 3)
+}
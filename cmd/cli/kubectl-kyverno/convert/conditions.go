@@ -0,0 +1,269 @@
+package convert
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	"github.com/kyverno/kyverno/ext/wildcard"
+	"github.com/kyverno/kyverno/pkg/engine/utils"
+	"github.com/kyverno/kyverno/pkg/engine/variables/regex"
+	apiresource "k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// conditions translates any/all conditions to a CEL expression, raw conditions are
+// either kyvernov1.AnyAllConditions or a list of conditions.
+func (s scope) conditions(path *field.Path, raw any) (string, error) {
+	typed, err := utils.TransformConditions(raw)
+	if err != nil {
+		return "", unsupported(path, "%s", err)
+	}
+	switch conditions := typed.(type) {
+	case kyvernov1.AnyAllConditions:
+		return s.anyAllConditions(path, conditions)
+	case *kyvernov1.AnyAllConditions:
+		return s.anyAllConditions(path, *conditions)
+	case []kyvernov1.Condition:
+		return s.conditionList(path, conditions, " && ")
+	}
+	return "", unsupported(path, "conditions of type %T are not supported", typed)
+}
+
+func (s scope) anyAllConditions(path *field.Path, conditions kyvernov1.AnyAllConditions) (string, error) {
+	var exprs []string
+	if len(conditions.AnyConditions) > 0 {
+		expr, err := s.conditionList(path.Child("any"), conditions.AnyConditions, " || ")
+		if err != nil {
+			return "", err
+		}
+		exprs = append(exprs, expr)
+	}
+	if len(conditions.AllConditions) > 0 {
+		expr, err := s.conditionList(path.Child("all"), conditions.AllConditions, " && ")
+		if err != nil {
+			return "", err
+		}
+		exprs = append(exprs, expr)
+	}
+	if len(exprs) == 0 {
+		return "true", nil
+	}
+	return and(exprs...), nil
+}
+
+func (s scope) conditionList(path *field.Path, conditions []kyvernov1.Condition, operator string) (string, error) {
+	exprs := make([]string, 0, len(conditions))
+	for i, condition := range conditions {
+		expr, err := s.condition(path.Index(i), condition)
+		if err != nil {
+			return "", err
+		}
+		exprs = append(exprs, expr)
+	}
+	return join(operator, exprs...), nil
+}
+
+func (s scope) condition(path *field.Path, condition kyvernov1.Condition) (string, error) {
+	key, err := s.value(path.Child("key"), condition.GetKey())
+	if err != nil {
+		return "", err
+	}
+	rawValue := condition.GetValue()
+	value, err := s.value(path.Child("value"), rawValue)
+	if err != nil {
+		return "", err
+	}
+	switch condition.Operator {
+	case kyvernov1.ConditionOperators["Equals"], kyvernov1.ConditionOperators["Equal"]:
+		return equals(key, value, rawValue), nil
+	case kyvernov1.ConditionOperators["NotEquals"], kyvernov1.ConditionOperators["NotEqual"]:
+		return not(equals(key, value, rawValue)), nil
+	case kyvernov1.ConditionOperators["AnyIn"], kyvernov1.ConditionOperators["AllIn"], kyvernov1.ConditionOperators["In"],
+		kyvernov1.ConditionOperators["AnyNotIn"], kyvernov1.ConditionOperators["AllNotIn"], kyvernov1.ConditionOperators["NotIn"]:
+		member, err := s.member(path.Child("value"), "key", value, rawValue)
+		if err != nil {
+			return "", err
+		}
+		// wildcards in literal keys are matched against the values too
+		if pattern, ok := literal(condition.GetKey()); ok && wildcard.ContainsWildcard(pattern) {
+			if _, ok := literal(rawValue); !ok {
+				member = or(member, fmt.Sprintf("[dyn(%s)].flatten().exists(value, %s)", value, matches("value", pattern)))
+			}
+		}
+		switch condition.Operator {
+		case kyvernov1.ConditionOperators["AnyIn"]:
+			return fmt.Sprintf("[dyn(%s)].flatten().exists(key, %s)", key, member), nil
+		case kyvernov1.ConditionOperators["AnyNotIn"], kyvernov1.ConditionOperators["NotIn"]:
+			return fmt.Sprintf("[dyn(%s)].flatten().exists(key, %s)", key, not(member)), nil
+		case kyvernov1.ConditionOperators["AllNotIn"]:
+			return fmt.Sprintf("[dyn(%s)].flatten().all(key, %s)", key, not(member)), nil
+		default:
+			return fmt.Sprintf("[dyn(%s)].flatten().all(key, %s)", key, member), nil
+		}
+	case kyvernov1.ConditionOperators["GreaterThan"]:
+		return compare(key, ">", value, rawValue), nil
+	case kyvernov1.ConditionOperators["GreaterThanOrEquals"]:
+		return compare(key, ">=", value, rawValue), nil
+	case kyvernov1.ConditionOperators["LessThan"]:
+		return compare(key, "<", value, rawValue), nil
+	case kyvernov1.ConditionOperators["LessThanOrEquals"]:
+		return compare(key, "<=", value, rawValue), nil
+	case kyvernov1.ConditionOperators["DurationGreaterThan"]:
+		return compareDurations(key, ">", value), nil
+	case kyvernov1.ConditionOperators["DurationGreaterThanOrEquals"]:
+		return compareDurations(key, ">=", value), nil
+	case kyvernov1.ConditionOperators["DurationLessThan"]:
+		return compareDurations(key, "<", value), nil
+	case kyvernov1.ConditionOperators["DurationLessThanOrEquals"]:
+		return compareDurations(key, "<=", value), nil
+	}
+	return "", unsupported(path.Child("operator"), "operator %q is not supported", condition.Operator)
+}
+
+// literal returns the raw value as a string when it is a string without variables.
+func literal(raw any) (string, bool) {
+	value, ok := raw.(string)
+	if !ok || regex.IsVariable(value) {
+		return "", false
+	}
+	return value, true
+}
+
+// equals matches wildcards in literal values like the Equals operator does.
+func equals(key, value string, raw any) string {
+	if pattern, ok := literal(raw); ok && wildcard.ContainsWildcard(pattern) {
+		return matches(key, pattern)
+	}
+	return key + " == " + value
+}
+
+// matches checks the string representation of value matches a wildcard pattern.
+func matches(value, pattern string) string {
+	return fmt.Sprintf("string(%s).matches(%s)", value, quote(wildcardRegex(pattern)))
+}
+
+// member checks element is in value, wildcards in literal values are matched against element.
+func (s scope) member(path *field.Path, element, value string, raw any) (string, error) {
+	switch typed := raw.(type) {
+	case string:
+		if _, ok := literal(raw); ok {
+			return equals(element, value, raw), nil
+		}
+	case []any:
+		var items []any
+		var exprs []string
+		for _, item := range typed {
+			if pattern, ok := literal(item); ok && wildcard.ContainsWildcard(pattern) {
+				exprs = append(exprs, matches(element, pattern))
+			} else {
+				items = append(items, item)
+			}
+		}
+		if len(exprs) == 0 {
+			return element + " in " + value, nil
+		}
+		if len(items) > 0 {
+			expr, err := s.value(path, items)
+			if err != nil {
+				return "", err
+			}
+			exprs = append([]string{element + " in " + expr}, exprs...)
+		}
+		return or(exprs...), nil
+	}
+	return element + " in [dyn(" + value + ")].flatten()", nil
+}
+
+// compare compares numbers, quantities and durations like the comparison operators do.
+func compare(key, operator, value string, raw any) string {
+	if pattern, ok := literal(raw); ok {
+		if _, err := time.ParseDuration(pattern); err == nil {
+			return compareDurations(key, operator, value)
+		}
+		if _, err := apiresource.ParseQuantity(pattern); err == nil {
+			return fmt.Sprintf("quantity(string(%s)).compareTo(quantity(%s)) %s 0", key, value, operator)
+		}
+	}
+	switch raw.(type) {
+	case int, int64, float64:
+		return fmt.Sprintf("double(%s) %s double(%s)", key, operator, value)
+	}
+	return key + " " + operator + " " + value
+}
+
+func compareDurations(key, operator, value string) string {
+	return fmt.Sprintf("duration(string(%s)) %s duration(string(%s))", key, operator, value)
+}
+
+// and joins expressions with a logical AND, expressions are parenthesized when needed.
+func and(exprs ...string) string {
+	return join(" && ", exprs...)
+}
+
+// or joins expressions with a logical OR, expressions are parenthesized when needed.
+func or(exprs ...string) string {
+	return join(" || ", exprs...)
+}
+
+func join(operator string, exprs ...string) string {
+	if len(exprs) == 1 {
+		return exprs[0]
+	}
+	parts := make([]string, 0, len(exprs))
+	for _, expr := range exprs {
+		parts = append(parts, parenthesize(expr))
+	}
+	return strings.Join(parts, operator)
+}
+
+// not negates an expression, double negations are removed.
+func not(expr string) string {
+	if strings.HasPrefix(expr, "!") && isTerm(expr) {
+		return strings.TrimPrefix(expr, "!")
+	}
+	return "!" + parenthesize(expr)
+}
+
+// parenthesize wraps an expression in parentheses unless it is a single term.
+func parenthesize(expr string) string {
+	if isTerm(expr) {
+		return expr
+	}
+	return "(" + expr + ")"
+}
+
+// isTerm returns true if expr doesn't contain operators outside of parentheses, brackets, braces and strings.
+func isTerm(expr string) bool {
+	depth := 0
+	var quote, previous rune
+	escaped := false
+	for _, r := range expr {
+		// optional selections like a.?b or a[?b] are not conditional operators
+		if r == '?' && (previous == '.' || previous == '[') {
+			previous = r
+			continue
+		}
+		previous = r
+		switch {
+		case escaped:
+			escaped = false
+		case quote != 0:
+			if r == '\\' {
+				escaped = true
+			} else if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case r == '(' || r == '[' || r == '{':
+			depth++
+		case r == ')' || r == ']' || r == '}':
+			depth--
+		case depth == 0 && strings.ContainsRune(" ?:", r):
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,420 @@
+package convert
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/kyverno/kyverno/api/kyverno"
+	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	policiesv1beta1 "github.com/kyverno/kyverno/api/policies.kyverno.io/v1beta1"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/restmapper"
+)
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9.-]+`)
+
+// Issue is a construct of a legacy policy that can't be converted.
+type Issue struct {
+	Policy  string `json:"policy"`
+	Rule    string `json:"rule,omitempty"`
+	Path    string `json:"path,omitempty"`
+	Message string `json:"message"`
+}
+
+func (i *Issue) Error() string {
+	if i.Path == "" {
+		return i.Message
+	}
+	return i.Path + ": " + i.Message
+}
+
+func unsupported(path *field.Path, format string, args ...any) error {
+	return &Issue{
+		Path:    path.String(),
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// Policy is a CEL policy converted from a rule of a legacy policy.
+type Policy struct {
+	// Source is the name of the legacy policy, prefixed with its namespace when it is namespaced
+	Source string
+	// Rule is the name of the legacy rule
+	Rule   string
+	Object runtime.Object
+}
+
+// Name returns the name of the converted policy.
+func (p Policy) Name() string {
+	if object, ok := p.Object.(metav1.Object); ok {
+		return object.GetName()
+	}
+	return ""
+}
+
+// Kind returns the kind of the converted policy.
+func (p Policy) Kind() string {
+	return p.Object.GetObjectKind().GroupVersionKind().Kind
+}
+
+// Result contains the policies converted from a legacy policy and the constructs that couldn't be converted.
+type Result struct {
+	Policies []Policy
+	Issues   []Issue
+}
+
+// Converter converts legacy policies to CEL policies, each rule is converted to a policy.
+type Converter struct {
+	resolver resolver
+}
+
+// New returns a converter resolving kinds with the given discovery data.
+func New(groups []*restmapper.APIGroupResources) *Converter {
+	return &Converter{
+		resolver: resolver{groups: groups},
+	}
+}
+
+// Convert converts the rules of a legacy policy, rules with constructs that can't be converted are skipped
+// and reported as issues.
+func (c *Converter) Convert(policy kyvernov1.PolicyInterface) Result {
+	var result Result
+	source := policy.GetName()
+	if policy.IsNamespaced() {
+		source = policy.GetNamespace() + "/" + source
+	}
+	spec := policy.GetSpec()
+	rules := spec.Rules
+	if spec.GetApplyRules() == kyvernov1.ApplyOne && len(rules) > 1 {
+		result.Issues = append(result.Issues, Issue{
+			Policy:  source,
+			Path:    field.NewPath("spec", "applyRules").String(),
+			Message: "applyRules One has no equivalent, the converted policies are all applied",
+		})
+	}
+	for i, rule := range rules {
+		converter := ruleConverter{
+			converter: c,
+			policy:    policy,
+			rule:      rule,
+			path:      field.NewPath("spec", "rules").Index(i),
+			name:      policyName(policy.GetName(), rule.Name, len(rules)),
+		}
+		object := converter.convert()
+		for _, issue := range converter.issues {
+			issue.Policy = source
+			issue.Rule = rule.Name
+			result.Issues = append(result.Issues, issue)
+		}
+		if object != nil && len(converter.issues) == 0 {
+			result.Policies = append(result.Policies, Policy{
+				Source: source,
+				Rule:   rule.Name,
+				Object: object,
+			})
+		}
+	}
+	return result
+}
+
+// policyName names the converted policy after the legacy policy, the rule name is appended when the policy has several rules.
+func policyName(policy, rule string, rules int) string {
+	if rules == 1 {
+		return policy
+	}
+	name := invalidNameChars.ReplaceAllString(strings.ToLower(policy+"-"+rule), "-")
+	name = strings.Trim(name, "-.")
+	if len(name) > 253 {
+		name = strings.Trim(name[:253], "-.")
+	}
+	return name
+}
+
+// ruleConverter converts a rule of a legacy policy.
+type ruleConverter struct {
+	converter *Converter
+	policy    kyvernov1.PolicyInterface
+	rule      kyvernov1.Rule
+	path      *field.Path
+	name      string
+	scope     scope
+	issues    []Issue
+}
+
+// common holds the parts shared by all kinds of CEL policies.
+type common struct {
+	matchConstraints *admissionregistrationv1.MatchResources
+	matchConditions  []admissionregistrationv1.MatchCondition
+	variables        []admissionregistrationv1.Variable
+	// guard is set when the preconditions can't be match conditions, the rule body only applies when it holds
+	guard string
+}
+
+// guarded returns an expression holding when the guard doesn't hold or expr holds.
+func (c common) guarded(expr string) string {
+	if c.guard == "" {
+		return expr
+	}
+	return or(not(c.guard), expr)
+}
+
+func (c *ruleConverter) report(err error) {
+	if err == nil {
+		return
+	}
+	var issue *Issue
+	if errors.As(err, &issue) {
+		c.issues = append(c.issues, *issue)
+	} else {
+		c.issues = append(c.issues, Issue{Path: c.path.String(), Message: err.Error()})
+	}
+}
+
+func (c *ruleConverter) convert() runtime.Object {
+	rule := c.rule
+	var kinds []string
+	if rule.HasValidate() {
+		kinds = append(kinds, "validate")
+	}
+	if rule.HasMutate() {
+		kinds = append(kinds, "mutate")
+	}
+	if rule.HasGenerate() {
+		kinds = append(kinds, "generate")
+	}
+	if rule.HasVerifyImages() {
+		c.report(unsupported(c.path.Child("verifyImages"), "verifyImages rules can't be converted, use an ImageValidatingPolicy instead"))
+		return nil
+	}
+	if len(kinds) != 1 {
+		c.report(unsupported(c.path, "rules must contain exactly one of validate, mutate or generate"))
+		return nil
+	}
+	operations := []admissionregistrationv1.OperationType{admissionregistrationv1.Create, admissionregistrationv1.Update}
+	common := c.common(operations)
+	switch kinds[0] {
+	case "validate":
+		return c.validatingPolicy(common)
+	case "mutate":
+		return c.mutatingPolicy(common)
+	default:
+		return c.generatingPolicy(common)
+	}
+}
+
+func (c *ruleConverter) common(operations []admissionregistrationv1.OperationType) common {
+	var result common
+	c.scope.variables = map[string]struct{}{}
+	variables, err := c.variables()
+	c.report(err)
+	result.variables = variables
+	matchConstraints, matchConditions, err := c.match(operations)
+	c.report(err)
+	result.matchConstraints = matchConstraints
+	result.matchConditions = append(result.matchConditions, c.policy.GetSpec().GetMatchConditions()...)
+	result.matchConditions = append(result.matchConditions, matchConditions...)
+	exclude, err := c.exclude()
+	c.report(err)
+	result.matchConditions = append(result.matchConditions, exclude...)
+	result.matchConditions = append(result.matchConditions, c.rule.CELPreconditions...)
+	if conditions := c.rule.GetAnyAllConditions(); conditions != nil {
+		expr, err := c.scope.conditions(c.path.Child("preconditions"), conditions)
+		c.report(err)
+		if err == nil {
+			// match conditions are evaluated before variables
+			if strings.Contains(expr, "variables.") {
+				result.guard = expr
+			} else {
+				result.matchConditions = append(result.matchConditions, admissionregistrationv1.MatchCondition{
+					Name:       "preconditions",
+					Expression: expr,
+				})
+			}
+		}
+	}
+	return result
+}
+
+// variables translates the context entries of the rule to CEL variables.
+func (c *ruleConverter) variables() ([]admissionregistrationv1.Variable, error) {
+	var variables []admissionregistrationv1.Variable
+	for i, entry := range c.rule.Context {
+		path := c.path.Child("context").Index(i)
+		var expr string
+		switch {
+		case entry.Variable != nil:
+			var err error
+			expr, err = c.variable(path.Child("variable"), *entry.Variable)
+			if err != nil {
+				return nil, err
+			}
+		case entry.ConfigMap != nil:
+			namespace := entry.ConfigMap.Namespace
+			if namespace == "" {
+				namespace = "default"
+			}
+			ns, err := c.scope.stringValue(path.Child("configMap", "namespace"), namespace)
+			if err != nil {
+				return nil, err
+			}
+			name, err := c.scope.stringValue(path.Child("configMap", "name"), entry.ConfigMap.Name)
+			if err != nil {
+				return nil, err
+			}
+			expr = fmt.Sprintf("resource.Get('v1', 'configmaps', %s, %s)", ns, name)
+		case entry.APICall != nil:
+			return nil, unsupported(path.Child("apiCall"), "API calls can't be converted, use the resource or http libraries in a variable instead")
+		case entry.ImageRegistry != nil:
+			return nil, unsupported(path.Child("imageRegistry"), "image registry data can't be converted, use the image data library in a variable instead")
+		case entry.GlobalReference != nil:
+			return nil, unsupported(path.Child("globalReference"), "global context references can't be converted, use the globalContext library in a variable instead")
+		default:
+			return nil, unsupported(path, "context entry %q has no source", entry.Name)
+		}
+		if !isIdentifier(entry.Name) {
+			return nil, unsupported(path.Child("name"), "context entry %q is not a valid CEL variable name", entry.Name)
+		}
+		c.scope.variables[entry.Name] = struct{}{}
+		variables = append(variables, admissionregistrationv1.Variable{
+			Name:       entry.Name,
+			Expression: expr,
+		})
+	}
+	return variables, nil
+}
+
+func (c *ruleConverter) variable(path *field.Path, variable kyvernov1.Variable) (string, error) {
+	fallback := "null"
+	if variable.Default != nil {
+		expr, err := c.scope.value(path.Child("default"), variable.GetDefault())
+		if err != nil {
+			return "", err
+		}
+		fallback = expr
+	}
+	switch {
+	case variable.JMESPath != "" && variable.Value != nil:
+		return "", unsupported(path.Child("jmesPath"), "JMESPath expressions applied to values can't be converted")
+	case variable.JMESPath != "":
+		return c.scope.path(path.Child("jmesPath"), variable.JMESPath, fallback)
+	case variable.Value != nil:
+		expr, err := c.scope.value(path.Child("value"), variable.GetValue())
+		if err != nil {
+			return "", err
+		}
+		if fallback != "null" && hasVariables(variable.GetValue()) {
+			return "", unsupported(path.Child("default"), "defaults of values containing variables can't be converted")
+		}
+		return expr, nil
+	}
+	return fallback, nil
+}
+
+// hasVariables returns true if a value contains variables.
+func hasVariables(value any) bool {
+	switch typed := value.(type) {
+	case string:
+		return strings.Contains(typed, "{{")
+	case []any:
+		for _, item := range typed {
+			if hasVariables(item) {
+				return true
+			}
+		}
+	case map[string]any:
+		for _, item := range typed {
+			if hasVariables(item) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (c *ruleConverter) objectMeta() metav1.ObjectMeta {
+	meta := metav1.ObjectMeta{
+		Name:   c.name,
+		Labels: c.policy.GetLabels(),
+	}
+	if c.policy.IsNamespaced() {
+		meta.Namespace = c.policy.GetNamespace()
+	}
+	for key, value := range c.policy.GetAnnotations() {
+		switch key {
+		case kyverno.AnnotationAutogenControllers, corev1.LastAppliedConfigAnnotation:
+			continue
+		}
+		if meta.Annotations == nil {
+			meta.Annotations = map[string]string{}
+		}
+		meta.Annotations[key] = value
+	}
+	return meta
+}
+
+// podControllers translates the autogen annotation of the legacy policy.
+func (c *ruleConverter) podControllers() *policiesv1beta1.PodControllersGenerationConfiguration {
+	value, ok := c.policy.GetAnnotations()[kyverno.AnnotationAutogenControllers]
+	if !ok {
+		return nil
+	}
+	controllers := []string{}
+	if value != "none" {
+		for _, kind := range strings.Split(value, ",") {
+			if kind = strings.ToLower(strings.TrimSpace(kind)); kind != "" {
+				controllers = append(controllers, kind+"s")
+			}
+		}
+	}
+	return &policiesv1beta1.PodControllersGenerationConfiguration{Controllers: controllers}
+}
+
+func (c *ruleConverter) failurePolicy() *admissionregistrationv1.FailurePolicyType {
+	spec := c.policy.GetSpec()
+	var failurePolicy *kyvernov1.FailurePolicyType
+	if spec.WebhookConfiguration != nil && spec.WebhookConfiguration.FailurePolicy != nil {
+		failurePolicy = spec.WebhookConfiguration.FailurePolicy
+	} else if spec.FailurePolicy != nil {
+		failurePolicy = spec.FailurePolicy
+	}
+	if failurePolicy == nil {
+		return nil
+	}
+	converted := admissionregistrationv1.FailurePolicyType(*failurePolicy)
+	return &converted
+}
+
+func (c *ruleConverter) webhookConfiguration() *policiesv1beta1.WebhookConfiguration {
+	timeout := c.policy.GetSpec().GetWebhookTimeoutSeconds()
+	if timeout == nil {
+		return nil
+	}
+	return &policiesv1beta1.WebhookConfiguration{TimeoutSeconds: timeout}
+}
+
+func (c *ruleConverter) admission() *policiesv1beta1.AdmissionConfiguration {
+	if enabled := c.policy.GetSpec().Admission; enabled != nil {
+		return &policiesv1beta1.AdmissionConfiguration{Enabled: enabled}
+	}
+	return nil
+}
+
+func (c *ruleConverter) background() *policiesv1beta1.BackgroundConfiguration {
+	if enabled := c.policy.GetSpec().Background; enabled != nil {
+		return &policiesv1beta1.BackgroundConfiguration{Enabled: enabled}
+	}
+	return nil
+}
+
+func typeMeta(kind string) metav1.TypeMeta {
+	return metav1.TypeMeta{
+		APIVersion: policiesv1beta1.SchemeGroupVersion.String(),
+		Kind:       kind,
+	}
+}
@@ -0,0 +1,449 @@
+package convert
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	policiesv1beta1 "github.com/kyverno/kyverno/api/policies.kyverno.io/v1beta1"
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/apis/v1alpha1"
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/data"
+	gpolcompiler "github.com/kyverno/kyverno/pkg/cel/policies/gpol/compiler"
+	mpolcompiler "github.com/kyverno/kyverno/pkg/cel/policies/mpol/compiler"
+	vpolcompiler "github.com/kyverno/kyverno/pkg/cel/policies/vpol/compiler"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+)
+
+func loadPolicy(t *testing.T, document string) kyvernov1.PolicyInterface {
+	t.Helper()
+	var policy kyvernov1.ClusterPolicy
+	if !assert.NoError(t, yaml.Unmarshal([]byte(document), &policy)) {
+		t.FailNow()
+	}
+	if policy.Kind == "Policy" {
+		return &kyvernov1.Policy{TypeMeta: policy.TypeMeta, ObjectMeta: policy.ObjectMeta, Spec: policy.Spec}
+	}
+	return &policy
+}
+
+// compile checks the converted policy compiles with the engine of its kind.
+func compile(t *testing.T, policy Policy) {
+	t.Helper()
+	switch typed := policy.Object.(type) {
+	case policiesv1beta1.ValidatingPolicyLike:
+		_, errs := vpolcompiler.NewCompiler().Compile(typed, nil)
+		assert.Empty(t, errs.ToAggregate(), policy.Name())
+	case policiesv1beta1.MutatingPolicyLike:
+		_, errs := mpolcompiler.NewCompiler().Compile(typed, nil)
+		assert.Empty(t, errs.ToAggregate(), policy.Name())
+	case policiesv1beta1.GeneratingPolicyLike:
+		_, errs := gpolcompiler.NewCompiler().Compile(typed, nil)
+		assert.Empty(t, errs.ToAggregate(), policy.Name())
+	default:
+		t.Errorf("unexpected policy type %T", policy.Object)
+	}
+}
+
+// document returns the converted policy as unwrapped JSON so expressions can be searched for.
+func document(t *testing.T, object runtime.Object) string {
+	t.Helper()
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	assert.NoError(t, encoder.Encode(object))
+	return buffer.String()
+}
+
+func TestConvert(t *testing.T) {
+	groups, err := data.APIGroupResources()
+	assert.NoError(t, err)
+	tests := []struct {
+		name     string
+		policy   string
+		kinds    []string
+		issues   []string
+		contains []string
+	}{{
+		name: "pattern with anchors",
+		policy: `
+apiVersion: kyverno.io/v1
+kind: ClusterPolicy
+metadata:
+  name: disallow-latest-tag
+spec:
+  validationFailureAction: Enforce
+  rules:
+  - name: require-image-tag
+    match:
+      any:
+      - resources:
+          kinds:
+          - Pod
+          namespaces:
+          - prod
+    validate:
+      message: "An image tag is required in {{request.object.metadata.name}}."
+      pattern:
+        metadata:
+          =(labels):
+            =(app): "?*"
+        spec:
+          containers:
+          - (name): "!istio-*"
+            image: "!*:latest"
+            =(resources):
+              =(limits):
+                =(memory): "<=1Gi"
+`,
+		kinds: []string{"ValidatingPolicy"},
+		contains: []string{
+			"object.spec.containers.all(item0,",
+			"!string(item0.name).matches('^istio-.*$')",
+			"quantity(string(item0.resources.limits.memory)).compareTo(quantity('1Gi')) <= 0",
+		},
+	}, {
+		name: "deny with preconditions and context",
+		policy: `
+apiVersion: kyverno.io/v1
+kind: ClusterPolicy
+metadata:
+  name: restrict-replicas
+spec:
+  rules:
+  - name: max-replicas
+    match:
+      any:
+      - resources:
+          kinds:
+          - apps/v1/Deployment
+          operations:
+          - CREATE
+    exclude:
+      any:
+      - subjects:
+        - kind: Group
+          name: system:masters
+    context:
+    - name: limits
+      configMap:
+        name: limits
+        namespace: kyverno
+    - name: replicas
+      variable:
+        jmesPath: request.object.spec.replicas
+        default: 1
+    preconditions:
+      all:
+      - key: "{{request.object.metadata.labels.tier || ''}}"
+        operator: NotEquals
+        value: ""
+    validate:
+      failureAction: Audit
+      message: too many replicas
+      deny:
+        conditions:
+          any:
+          - key: "{{replicas}}"
+            operator: GreaterThan
+            value: "{{limits.data.max}}"
+          - key: "{{request.object.metadata.namespace}}"
+            operator: AnyIn
+            value:
+            - kube-*
+            - default
+`,
+		issues: []string{"spec.rules[0].preconditions.all[0].key"},
+	}, {
+		name: "deny with context",
+		policy: `
+apiVersion: kyverno.io/v1
+kind: ClusterPolicy
+metadata:
+  name: restrict-replicas
+spec:
+  rules:
+  - name: max-replicas
+    match:
+      any:
+      - resources:
+          kinds:
+          - apps/v1/Deployment
+          operations:
+          - CREATE
+    exclude:
+      any:
+      - subjects:
+        - kind: Group
+          name: system:masters
+    context:
+    - name: limits
+      configMap:
+        name: limits
+        namespace: kyverno
+    - name: replicas
+      variable:
+        jmesPath: request.object.spec.replicas
+        default: 1
+    preconditions:
+      all:
+      - key: "{{replicas}}"
+        operator: GreaterThan
+        value: 0
+    validate:
+      failureAction: Audit
+      message: too many replicas
+      deny:
+        conditions:
+          any:
+          - key: "{{replicas}}"
+            operator: GreaterThan
+            value: "{{limits.data.max}}"
+          - key: "{{request.object.metadata.namespace}}"
+            operator: AnyIn
+            value:
+            - kube-*
+            - default
+`,
+		kinds: []string{"ValidatingPolicy"},
+		contains: []string{
+			"resource.Get('v1', 'configmaps', 'kyverno', 'limits')",
+			"object.?spec.?replicas.orValue(1)",
+			"!('system:masters' in request.userInfo.groups)",
+		},
+	}, {
+		name: "foreach validation",
+		policy: `
+apiVersion: kyverno.io/v1
+kind: ClusterPolicy
+metadata:
+  name: check-images
+spec:
+  rules:
+  - name: check-registry
+    match:
+      any:
+      - resources:
+          kinds:
+          - Pod
+    validate:
+      message: unknown registry
+      foreach:
+      - list: request.object.spec.containers
+        preconditions:
+          all:
+          - key: "{{element.name}}"
+            operator: NotEquals
+            value: sidecar
+        deny:
+          conditions:
+            all:
+            - key: "{{element.image}}"
+              operator: NotEquals
+              value: "ghcr.io/*"
+`,
+		kinds:    []string{"ValidatingPolicy"},
+		contains: []string{"object.?spec.?containers.orValue([]).all(element0,"},
+	}, {
+		name: "mutations",
+		policy: `
+apiVersion: kyverno.io/v1
+kind: ClusterPolicy
+metadata:
+  name: add-defaults
+spec:
+  rules:
+  - name: add-labels
+    match:
+      any:
+      - resources:
+          kinds:
+          - Pod
+          selector:
+            matchLabels:
+              app: "*"
+    mutate:
+      patchStrategicMerge:
+        metadata:
+          labels:
+            +(team): "{{request.object.metadata.namespace}}"
+            managed: "true"
+        spec:
+          containers:
+          - (name): "*"
+            imagePullPolicy: Always
+  - name: add-annotation
+    match:
+      any:
+      - resources:
+          kinds:
+          - Deployment
+    mutate:
+      patchesJson6902: |-
+        - op: add
+          path: /metadata/annotations/owner
+          value: "{{request.userInfo.username}}"
+  - name: set-pull-policy
+    match:
+      any:
+      - resources:
+          kinds:
+          - Pod
+    mutate:
+      foreach:
+      - list: request.object.spec.initContainers
+        patchStrategicMerge:
+          spec:
+            initContainers:
+            - name: "{{element.name}}"
+              imagePullPolicy: IfNotPresent
+`,
+		kinds: []string{"MutatingPolicy", "MutatingPolicy", "MutatingPolicy"},
+		contains: []string{
+			"?'team': object.?metadata.?labels.?team.hasValue() ? optional.none() : optional.of(dyn(object.metadata.?namespace.orValue(null)))",
+			".map(item0, Object.spec.containers{name: item0.name, imagePullPolicy: 'Always'})",
+			"JSONPatch{op: 'add', path: '/metadata/annotations/owner', value: request.userInfo.username}",
+			"object.?spec.?initContainers.orValue([]).map(element0, Object.spec.initContainers{imagePullPolicy: 'IfNotPresent', name: element0.?name.orValue(null)})",
+		},
+	}, {
+		name: "generations",
+		policy: `
+apiVersion: kyverno.io/v1
+kind: ClusterPolicy
+metadata:
+  name: namespace-defaults
+spec:
+  rules:
+  - name: default-quota
+    match:
+      any:
+      - resources:
+          kinds:
+          - Namespace
+    generate:
+      synchronize: true
+      apiVersion: v1
+      kind: ResourceQuota
+      name: default
+      namespace: "{{request.object.metadata.name}}"
+      data:
+        spec:
+          hard:
+            pods: "10"
+  - name: clone-secret
+    match:
+      any:
+      - resources:
+          kinds:
+          - Namespace
+    generate:
+      apiVersion: v1
+      kind: Secret
+      name: regcred
+      namespace: "{{request.object.metadata.name}}"
+      clone:
+        namespace: default
+        name: regcred
+`,
+		kinds: []string{"GeneratingPolicy", "GeneratingPolicy"},
+		contains: []string{
+			"generator.Apply(string(object.metadata.?name.orValue('')), [variables.downstream])",
+			"generator.Apply(string(object.metadata.?name.orValue('')), [resource.Get('v1', 'secrets', 'default', 'regcred')])",
+		},
+	}, {
+		name: "unsupported constructs",
+		policy: `
+apiVersion: kyverno.io/v1
+kind: ClusterPolicy
+metadata:
+  name: unsupported
+spec:
+  rules:
+  - name: api-call
+    match:
+      any:
+      - resources:
+          kinds:
+          - Pod
+    context:
+    - name: pods
+      apiCall:
+        urlPath: /api/v1/pods
+    validate:
+      deny: {}
+  - name: roles
+    match:
+      any:
+      - resources:
+          kinds:
+          - Pod
+        clusterRoles:
+        - admin
+    validate:
+      pattern:
+        spec:
+          hostNetwork: false
+  - name: pod-security
+    match:
+      any:
+      - resources:
+          kinds:
+          - Pod
+    validate:
+      podSecurity:
+        level: baseline
+        version: latest
+`,
+		issues: []string{
+			"spec.rules[0].context[0].apiCall",
+			"spec.rules[1].match.any[0].clusterRoles",
+			"spec.rules[2].validate.podSecurity",
+		},
+	}}
+	converter := New(groups)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := converter.Convert(loadPolicy(t, tt.policy))
+			var issues []string
+			for _, issue := range result.Issues {
+				issues = append(issues, issue.Path)
+			}
+			assert.Equal(t, tt.issues, issues)
+			var kinds []string
+			var expressions string
+			for _, policy := range result.Policies {
+				kinds = append(kinds, policy.Kind())
+				compile(t, policy)
+				expressions += document(t, policy.Object)
+			}
+			assert.Equal(t, tt.kinds, kinds)
+			for _, expected := range tt.contains {
+				assert.Contains(t, expressions, expected)
+			}
+		})
+	}
+}
+
+func TestConvertTest(t *testing.T) {
+	policy := &policiesv1beta1.ValidatingPolicy{TypeMeta: typeMeta(policiesv1beta1.ValidatingPolicyKind)}
+	policy.SetName("require-labels-check-team")
+	test := v1alpha1.Test{
+		Policies:         []string{"policy.yaml"},
+		PolicyExceptions: []string{"exception.yaml"},
+		Results: []v1alpha1.TestResult{{
+			TestResultBase: v1alpha1.TestResultBase{Policy: "require-labels", Rule: "autogen-check-team", Kind: "Deployment"},
+		}, {
+			TestResultBase: v1alpha1.TestResultBase{Policy: "require-labels", Rule: "check-owner", Kind: "Pod"},
+		}},
+	}
+	converted, issues := ConvertTest(test, []Policy{{Source: "require-labels", Rule: "check-team", Object: policy}})
+	assert.Equal(t, []v1alpha1.TestResult{{
+		TestResultBase: v1alpha1.TestResultBase{Policy: "require-labels-check-team", IsValidatingPolicy: true, Kind: "Deployment"},
+	}}, converted.Results)
+	assert.Nil(t, converted.PolicyExceptions)
+	assert.Len(t, issues, 2)
+	assert.Equal(t, "results[1]", issues[0].Path)
+	assert.Equal(t, "exceptions", issues[1].Path)
+}
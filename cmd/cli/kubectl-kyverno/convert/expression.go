@@ -0,0 +1,429 @@
+package convert
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kyverno/kyverno/pkg/engine/variables/regex"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+var (
+	identifierRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*`)
+	elementRegex    = regexp.MustCompile(`^element(\d*)$`)
+)
+
+// requestFields are the admission request fields available in CEL policies.
+var requestFields = []string{
+	"dryRun",
+	"kind",
+	"name",
+	"namespace",
+	"operation",
+	"options",
+	"requestKind",
+	"requestResource",
+	"requestSubResource",
+	"resource",
+	"subResource",
+	"uid",
+	"userInfo",
+}
+
+// scope holds what the variables of a legacy policy can refer to.
+type scope struct {
+	// variables contains the names of the context entries converted to CEL variables
+	variables map[string]struct{}
+	// elements contains the CEL names of the foreach elements, from the outermost to the innermost
+	elements []string
+	// items is the number of enclosing CEL comprehensions over pattern lists
+	items int
+}
+
+// withElement returns a scope nested in a foreach and the CEL name of the foreach element.
+func (s scope) withElement() (scope, string) {
+	name := fmt.Sprintf("element%d", len(s.elements))
+	return scope{
+		variables: s.variables,
+		elements:  append(slices.Clone(s.elements), name),
+		items:     s.items,
+	}, name
+}
+
+// withItem returns a scope nested in a comprehension over a list and the CEL name of the list item.
+func (s scope) withItem() (scope, string) {
+	name := fmt.Sprintf("item%d", s.items)
+	s.items++
+	return s, name
+}
+
+type segment struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// parsePath parses a JMESPath expression made of field names and indexes only.
+func parsePath(expr string) ([]segment, bool) {
+	var segments []segment
+	rest := strings.TrimSpace(expr)
+	for rest != "" {
+		if len(segments) > 0 {
+			switch rest[0] {
+			case '.':
+				rest = rest[1:]
+			case '[':
+			default:
+				return nil, false
+			}
+		}
+		switch {
+		case rest == "":
+			return nil, false
+		case rest[0] == '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 || len(segments) == 0 {
+				return nil, false
+			}
+			index, err := strconv.Atoi(rest[1:end])
+			if err != nil || index < 0 {
+				return nil, false
+			}
+			segments = append(segments, segment{index: index, isIndex: true})
+			rest = rest[end+1:]
+		case rest[0] == '"':
+			end := strings.IndexByte(rest[1:], '"')
+			if end < 0 {
+				return nil, false
+			}
+			segments = append(segments, segment{key: rest[1 : end+1]})
+			rest = rest[end+2:]
+		default:
+			key := identifierRegex.FindString(rest)
+			if key == "" {
+				return nil, false
+			}
+			segments = append(segments, segment{key: key})
+			rest = rest[len(key):]
+		}
+	}
+	return segments, len(segments) > 0
+}
+
+// path translates a JMESPath field path to CEL, missing fields evaluate to fallback.
+func (s scope) path(path *field.Path, expr string, fallback string) (string, error) {
+	segments, ok := parsePath(expr)
+	if !ok {
+		return "", unsupported(path, "JMESPath expression %q is not a plain field path", expr)
+	}
+	root := segments[0].key
+	switch {
+	case root == "request" && len(segments) > 1:
+		switch name := segments[1].key; {
+		case name == "object", name == "oldObject":
+			return access(name, segments[2:], fallback), nil
+		case slices.Contains(requestFields, name):
+			return "request." + name + selectors(segments[2:], false), nil
+		}
+	case root == "serviceAccountName" && len(segments) == 1:
+		return serviceAccount(3), nil
+	case root == "serviceAccountNamespace" && len(segments) == 1:
+		return serviceAccount(2), nil
+	case elementRegex.MatchString(root):
+		name, err := s.element(path, root)
+		if err != nil {
+			return "", err
+		}
+		return access(name, segments[1:], fallback), nil
+	default:
+		if _, ok := s.variables[root]; ok {
+			return access("variables."+root, segments[1:], fallback), nil
+		}
+	}
+	return "", unsupported(path, "variable %q has no equivalent in CEL policies", expr)
+}
+
+func (s scope) element(path *field.Path, name string) (string, error) {
+	if len(s.elements) == 0 {
+		return "", unsupported(path, "%s is used outside of a foreach", name)
+	}
+	depth := elementRegex.FindStringSubmatch(name)[1]
+	if depth == "" {
+		return s.elements[len(s.elements)-1], nil
+	}
+	index, err := strconv.Atoi(depth)
+	if err != nil || index >= len(s.elements) {
+		return "", unsupported(path, "%s doesn't refer to an enclosing foreach", name)
+	}
+	return s.elements[index], nil
+}
+
+func serviceAccount(index int) string {
+	return fmt.Sprintf(
+		"(request.userInfo.username.startsWith('system:serviceaccount:') ? request.userInfo.username.split(':')[%d] : '')",
+		index,
+	)
+}
+
+// access builds a CEL field access, fields are accessed as optionals so that
+// missing fields evaluate to the fallback like they evaluate to null in JMESPath.
+// The metadata of objects is always present and is accessed directly.
+func access(root string, segments []segment, fallback string) string {
+	if len(segments) > 0 && (root == "object" || root == "oldObject") && !segments[0].isIndex && segments[0].key == "metadata" {
+		root += ".metadata"
+		segments = segments[1:]
+	}
+	if len(segments) == 0 {
+		return root
+	}
+	// variables can be typed, optional access falls back to values of any type on dynamic values only
+	if strings.HasPrefix(root, "variables.") {
+		root = "dyn(" + root + ")"
+	}
+	return root + selectors(segments, true) + ".orValue(" + fallback + ")"
+}
+
+func selectors(segments []segment, optional bool) string {
+	var b strings.Builder
+	marker := ""
+	if optional {
+		marker = "?"
+	}
+	for _, segment := range segments {
+		switch {
+		case segment.isIndex:
+			fmt.Fprintf(&b, "[%s%d]", marker, segment.index)
+		case isIdentifier(segment.key):
+			fmt.Fprintf(&b, ".%s%s", marker, segment.key)
+		default:
+			fmt.Fprintf(&b, "[%s%s]", marker, quote(segment.key))
+		}
+	}
+	return b.String()
+}
+
+func isIdentifier(key string) bool {
+	return identifierRegex.FindString(key) == key
+}
+
+// fieldOf returns a CEL expression accessing key in the map expression value.
+func fieldOf(value, key string) string {
+	if isIdentifier(key) {
+		return value + "." + key
+	}
+	return value + "[" + quote(key) + "]"
+}
+
+// has returns a CEL expression checking key is present in the map expression value.
+func has(value, key string) string {
+	if isIdentifier(key) {
+		return "has(" + value + "." + key + ")"
+	}
+	return quote(key) + " in " + value
+}
+
+// quote returns a single quoted CEL string literal.
+func quote(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `'`, `\'`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	return "'" + replacer.Replace(value) + "'"
+}
+
+// literal types that don't need to be wrapped in dyn when they are mixed in lists and maps.
+const (
+	typeString = "string"
+	typeInt    = "int"
+	typeDouble = "double"
+	typeBool   = "bool"
+	typeOther  = "other"
+)
+
+// value translates a JSON value of a legacy policy to CEL, variables in strings are translated too.
+func (s scope) value(path *field.Path, value any) (string, error) {
+	expr, _, err := s.typedValue(path, value)
+	return expr, err
+}
+
+func (s scope) typedValue(path *field.Path, value any) (string, string, error) {
+	switch typed := value.(type) {
+	case nil:
+		return "null", typeOther, nil
+	case bool:
+		return strconv.FormatBool(typed), typeBool, nil
+	case int:
+		return strconv.Itoa(typed), typeInt, nil
+	case int64:
+		return strconv.FormatInt(typed, 10), typeInt, nil
+	case float64:
+		if typed == math.Trunc(typed) && math.Abs(typed) < 1<<53 {
+			return strconv.FormatInt(int64(typed), 10), typeInt, nil
+		}
+		return strconv.FormatFloat(typed, 'f', -1, 64), typeDouble, nil
+	case string:
+		expr, isString, err := s.interpolate(path, typed)
+		if err != nil {
+			return "", "", err
+		}
+		if isString {
+			return expr, typeString, nil
+		}
+		return expr, typeOther, nil
+	case []any:
+		items := make([]string, 0, len(typed))
+		types := make([]string, 0, len(typed))
+		for i, item := range typed {
+			expr, typ, err := s.typedValue(path.Index(i), item)
+			if err != nil {
+				return "", "", err
+			}
+			items = append(items, expr)
+			types = append(types, typ)
+		}
+		items = homogeneous(items, types)
+		return "[" + strings.Join(items, ", ") + "]", typeOther, nil
+	case map[string]any:
+		keys := make([]string, 0, len(typed))
+		for key := range typed {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		items := make([]string, 0, len(typed))
+		types := make([]string, 0, len(typed))
+		for _, key := range keys {
+			expr, typ, err := s.typedValue(path.Key(key), typed[key])
+			if err != nil {
+				return "", "", err
+			}
+			items = append(items, expr)
+			types = append(types, typ)
+		}
+		items = homogeneous(items, types)
+		for i, key := range keys {
+			items[i] = quote(key) + ": " + items[i]
+		}
+		return "{" + strings.Join(items, ", ") + "}", typeOther, nil
+	default:
+		return "", "", unsupported(path, "value of type %T is not supported", value)
+	}
+}
+
+// homogeneous wraps the items of a list or map literal in dyn when they don't have the same type,
+// CEL policies only accept homogeneous aggregate literals.
+func homogeneous(items []string, types []string) []string {
+	if len(items) < 2 {
+		return items
+	}
+	mixed := false
+	for _, typ := range types {
+		if typ == typeOther || typ != types[0] {
+			mixed = true
+			break
+		}
+	}
+	if !mixed {
+		return items
+	}
+	wrapped := make([]string, 0, len(items))
+	for _, item := range items {
+		wrapped = append(wrapped, "dyn("+item+")")
+	}
+	return wrapped
+}
+
+// interpolate translates a string that can contain variables to CEL,
+// it returns true if the result is a string and false if it is a single variable of unknown type.
+func (s scope) interpolate(path *field.Path, value string) (string, bool, error) {
+	if regex.IsReference(value) {
+		return "", false, unsupported(path, "references $(...) have no equivalent in CEL policies")
+	}
+	matches := regex.RegexVariables.FindAllStringSubmatchIndex(value, -1)
+	if len(matches) == 0 {
+		return quote(value), true, nil
+	}
+	// a single variable keeps the type of the value it refers to
+	if len(matches) == 1 && matches[0][4] == 0 && matches[0][5] == len(value) {
+		expr, err := s.variable(path, value)
+		return expr, false, err
+	}
+	// variables nested in variables are JMESPath expressions built from the inner values
+	for _, match := range matches {
+		if strings.Contains(value[:match[4]], "{{") && strings.Contains(value[match[5]:], "}}") {
+			return "", false, unsupported(path, "nested variables in %q can't be converted", value)
+		}
+	}
+	var parts []string
+	start := 0
+	for _, match := range matches {
+		if text := value[start:match[4]]; text != "" {
+			parts = append(parts, quote(text))
+		}
+		// missing fields are interpolated as empty strings
+		expr, err := s.variableOr(path, value[match[4]:match[5]], "''")
+		if err != nil {
+			return "", false, err
+		}
+		parts = append(parts, "string("+expr+")")
+		start = match[5]
+	}
+	if text := value[start:]; text != "" {
+		parts = append(parts, quote(text))
+	}
+	return strings.Join(parts, " + "), true, nil
+}
+
+func (s scope) variable(path *field.Path, variable string) (string, error) {
+	return s.variableOr(path, variable, "null")
+}
+
+// variableOr translates a variable, fallback is used when the path it refers to is missing.
+func (s scope) variableOr(path *field.Path, variable string, fallback string) (string, error) {
+	expr := strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(variable, "{{"), "}}"))
+	if strings.HasPrefix(expr, "-") {
+		return "", unsupported(path, "variable %q uses the JMESPath escape syntax", variable)
+	}
+	if expr == "@" {
+		if len(s.elements) == 0 {
+			return "", unsupported(path, "variable %q is used outside of a foreach", variable)
+		}
+		return s.elements[len(s.elements)-1], nil
+	}
+	return s.path(path, expr, fallback)
+}
+
+// wildcardRegex translates a wildcard pattern to an anchored regular expression.
+func wildcardRegex(pattern string) string {
+	var b strings.Builder
+	b.WriteString("^")
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	return b.String()
+}
+
+// stringValue translates a string that can contain variables to a CEL string expression.
+func (s scope) stringValue(path *field.Path, value string) (string, error) {
+	expr, isString, err := s.interpolate(path, value)
+	if err != nil {
+		return "", err
+	}
+	if !isString {
+		expr, err := s.variableOr(path, value, "''")
+		if err != nil {
+			return "", err
+		}
+		return "string(" + expr + ")", nil
+	}
+	return expr, nil
+}
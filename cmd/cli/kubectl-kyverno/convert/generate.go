@@ -0,0 +1,198 @@
+package convert
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	policiesv1beta1 "github.com/kyverno/kyverno/api/policies.kyverno.io/v1beta1"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func (c *ruleConverter) generatingPolicy(common common) runtime.Object {
+	path := c.path.Child("generate")
+	generation := c.rule.Generation
+	spec := policiesv1beta1.GeneratingPolicySpec{
+		MatchConstraints: common.matchConstraints,
+		MatchConditions:  common.matchConditions,
+		Variables:        common.variables,
+	}
+	var expressions []string
+	switch {
+	case len(generation.ForEachGeneration) > 0:
+		c.report(unsupported(path.Child("foreach"), "foreach generations can't be converted"))
+	case generation.RawData != nil:
+		downstream, err := c.downstream(path)
+		c.report(err)
+		if err == nil {
+			spec.Variables = append(spec.Variables, admissionregistrationv1.Variable{
+				Name:       "downstream",
+				Expression: downstream,
+			})
+			namespace, err := c.scope.stringValue(path.Child("namespace"), generation.Namespace)
+			c.report(err)
+			expressions = append(expressions, fmt.Sprintf("generator.Apply(%s, [variables.downstream])", namespace))
+		}
+	case generation.Clone.Name != "":
+		expr, err := c.clone(path)
+		c.report(err)
+		expressions = append(expressions, expr)
+	case len(generation.CloneList.Kinds) > 0:
+		exprs, err := c.cloneList(path)
+		c.report(err)
+		expressions = append(expressions, exprs...)
+	default:
+		c.report(unsupported(path, "generation has no data, clone, cloneList or foreach"))
+	}
+	for _, expr := range expressions {
+		if common.guard != "" {
+			expr = fmt.Sprintf("%s ? %s : true", parenthesize(common.guard), expr)
+		}
+		spec.Generation = append(spec.Generation, policiesv1beta1.Generation{Expression: expr})
+	}
+	evaluation := &policiesv1beta1.GeneratingPolicyEvaluationConfiguration{
+		Admission: c.admission(),
+	}
+	generateExisting := c.policy.GetSpec().GenerateExisting
+	if generation.GenerateExisting != nil {
+		generateExisting = *generation.GenerateExisting
+	}
+	if generateExisting {
+		evaluation.GenerateExistingConfiguration = &policiesv1beta1.GenerateExistingConfiguration{Enabled: &generateExisting}
+	}
+	if generation.Synchronize {
+		evaluation.SynchronizationConfiguration = &policiesv1beta1.SynchronizationConfiguration{Enabled: &generation.Synchronize}
+	}
+	if generation.OrphanDownstreamOnPolicyDelete {
+		evaluation.OrphanDownstreamOnPolicyDelete = &policiesv1beta1.OrphanDownstreamOnPolicyDeleteConfiguration{Enabled: &generation.OrphanDownstreamOnPolicyDelete}
+	}
+	if *evaluation != (policiesv1beta1.GeneratingPolicyEvaluationConfiguration{}) {
+		spec.EvaluationConfiguration = evaluation
+	}
+	spec.WebhookConfiguration = c.webhookConfiguration()
+	if c.policy.IsNamespaced() {
+		return &policiesv1beta1.NamespacedGeneratingPolicy{
+			TypeMeta:   typeMeta(policiesv1beta1.NamespacedGeneratingPolicyKind),
+			ObjectMeta: c.objectMeta(),
+			Spec:       spec,
+		}
+	}
+	return &policiesv1beta1.GeneratingPolicy{
+		TypeMeta:   typeMeta(policiesv1beta1.GeneratingPolicyKind),
+		ObjectMeta: c.objectMeta(),
+		Spec:       spec,
+	}
+}
+
+// downstream returns the resource generated from the data of the rule as a CEL map literal.
+func (c *ruleConverter) downstream(path *field.Path) (string, error) {
+	generation := c.rule.Generation
+	data, ok := generation.GetData().(map[string]any)
+	if !ok {
+		return "", unsupported(path.Child("data"), "data must be an object")
+	}
+	resource := make(map[string]any, len(data)+2)
+	for key, value := range data {
+		resource[key] = value
+	}
+	metadata := map[string]any{}
+	if existing, ok := data["metadata"].(map[string]any); ok {
+		for key, value := range existing {
+			metadata[key] = value
+		}
+	}
+	metadata["name"] = generation.Name
+	if generation.Namespace != "" {
+		metadata["namespace"] = generation.Namespace
+	}
+	resource["apiVersion"] = generation.APIVersion
+	resource["kind"] = generation.Kind
+	resource["metadata"] = metadata
+	return c.scope.value(path.Child("data"), resource)
+}
+
+// clone translates the clone of a single resource, the downstream resource must have the name of the source.
+func (c *ruleConverter) clone(path *field.Path) (string, error) {
+	generation := c.rule.Generation
+	if generation.Clone.Name != generation.Name {
+		return "", unsupported(path.Child("name"), "clones must have the name of their source")
+	}
+	rule, err := c.converter.resolver.resolve(path.Child("kind"), generation.APIVersion+"/"+generation.Kind)
+	if err != nil {
+		return "", err
+	}
+	sourceNamespace, err := c.scope.stringValue(path.Child("clone", "namespace"), generation.Clone.Namespace)
+	if err != nil {
+		return "", err
+	}
+	name, err := c.scope.stringValue(path.Child("clone", "name"), generation.Clone.Name)
+	if err != nil {
+		return "", err
+	}
+	namespace, err := c.scope.stringValue(path.Child("namespace"), generation.Namespace)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(
+		"generator.Apply(%s, [resource.Get(%s, %s, %s, %s)])",
+		namespace,
+		quote(generation.APIVersion),
+		quote(rule.Resources[0]),
+		sourceNamespace,
+		name,
+	), nil
+}
+
+// cloneList translates the clone of the resources of several kinds, selectors can only use labels.
+func (c *ruleConverter) cloneList(generatePath *field.Path) ([]string, error) {
+	path := generatePath.Child("cloneList")
+	generation := c.rule.Generation
+	cloneList := generation.CloneList
+	labels := ""
+	if cloneList.Selector != nil {
+		if len(cloneList.Selector.MatchExpressions) > 0 {
+			return nil, unsupported(path.Child("selector", "matchExpressions"), "selectors with expressions can't be converted")
+		}
+		keys := make([]string, 0, len(cloneList.Selector.MatchLabels))
+		for key := range cloneList.Selector.MatchLabels {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		entries := make([]string, 0, len(keys))
+		for _, key := range keys {
+			entries = append(entries, quote(key)+": "+quote(cloneList.Selector.MatchLabels[key]))
+		}
+		labels = ", {" + strings.Join(entries, ", ") + "}"
+	}
+	sourceNamespace, err := c.scope.stringValue(path.Child("namespace"), cloneList.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	namespace, err := c.scope.stringValue(generatePath.Child("namespace"), generation.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	exprs := make([]string, 0, len(cloneList.Kinds))
+	for i, kind := range cloneList.Kinds {
+		parts := strings.Split(kind, "/")
+		if len(parts) < 2 {
+			return nil, unsupported(path.Child("kinds").Index(i), "kinds must contain the API version")
+		}
+		rule, err := c.converter.resolver.resolve(path.Child("kinds").Index(i), kind)
+		if err != nil {
+			return nil, err
+		}
+		apiVersion := strings.Join(parts[:len(parts)-1], "/")
+		exprs = append(exprs, fmt.Sprintf(
+			"generator.Apply(%s, resource.List(%s, %s, %s%s).items)",
+			namespace,
+			quote(apiVersion),
+			quote(rule.Resources[0]),
+			sourceNamespace,
+			labels,
+		))
+	}
+	return exprs, nil
+}
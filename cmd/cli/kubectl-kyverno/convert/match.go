@@ -0,0 +1,525 @@
+package convert
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	"github.com/kyverno/kyverno/ext/wildcard"
+	kubeutils "github.com/kyverno/kyverno/pkg/utils/kube"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/restmapper"
+)
+
+const namespaceNameLabel = "kubernetes.io/metadata.name"
+
+// resolver resolves the kinds of legacy policies to the resources of admission rules.
+type resolver struct {
+	groups []*restmapper.APIGroupResources
+}
+
+// resolve returns the admission rule matching a kind selector, kinds missing from
+// the discovery data are resolved with the usual lowercase plural convention.
+func (r resolver) resolve(path *field.Path, selector string) (admissionregistrationv1.Rule, error) {
+	group, version, kind, subresource := kubeutils.ParseKindSelector(selector)
+	if kind == "*" {
+		resource := "*"
+		if subresource != "" {
+			resource += "/" + subresource
+		}
+		return rule([]string{group}, []string{version}, []string{resource}), nil
+	}
+	if wildcard.ContainsWildcard(kind) {
+		return admissionregistrationv1.Rule{}, unsupported(path, "kind %q contains wildcards, only * alone is supported", selector)
+	}
+	groups := map[string]struct{}{}
+	resources := map[string]struct{}{}
+	for _, apiGroup := range r.groups {
+		if group != "*" && apiGroup.Group.Name != group {
+			continue
+		}
+		for apiVersion, apiResources := range apiGroup.VersionedResources {
+			if version != "*" && apiVersion != version {
+				continue
+			}
+			for _, apiResource := range apiResources {
+				if apiResource.Kind != kind || strings.Contains(apiResource.Name, "/") {
+					continue
+				}
+				groups[apiGroup.Group.Name] = struct{}{}
+				resources[apiResource.Name] = struct{}{}
+			}
+		}
+	}
+	if len(resources) == 0 {
+		plural, _ := meta.UnsafeGuessKindToResource(schema.GroupVersionKind{Kind: kind})
+		groups[group] = struct{}{}
+		resources[plural.Resource] = struct{}{}
+	}
+	names := sortedKeys(resources)
+	if subresource != "" {
+		for i := range names {
+			names[i] += "/" + subresource
+		}
+	}
+	return rule(sortedKeys(groups), []string{version}, names), nil
+}
+
+func rule(groups, versions, resources []string) admissionregistrationv1.Rule {
+	return admissionregistrationv1.Rule{
+		APIGroups:   groups,
+		APIVersions: versions,
+		Resources:   resources,
+	}
+}
+
+func sortedKeys(set map[string]struct{}) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// filter is the translation of a legacy resource filter.
+type filter struct {
+	rules []admissionregistrationv1.RuleWithOperations
+	// kinds checks the kind of the request when several filters are combined
+	kinds string
+	// operations checks the operation of the request when several filters are combined
+	operations string
+	// namespaces, objectSelector, namespaceSelector and names can be set on the match constraints of a single filter
+	namespaces        []string
+	objectSelector    *metav1.LabelSelector
+	namespaceSelector *metav1.LabelSelector
+	names             []string
+	// conditions are the parts of the filter that can only be match conditions
+	conditions []string
+}
+
+// predicate returns a CEL expression holding when a request matches the whole filter.
+func (f filter) predicate() string {
+	parts := []string{f.kinds, f.operations}
+	if len(f.namespaces) > 0 {
+		parts = append(parts, membership("request.namespace", f.namespaces))
+	}
+	if f.objectSelector != nil {
+		parts = append(parts, selector("object.metadata", *f.objectSelector))
+	}
+	if f.namespaceSelector != nil {
+		parts = append(parts, and("namespaceObject != null", selector("namespaceObject.metadata", *f.namespaceSelector)))
+	}
+	if len(f.names) > 0 {
+		parts = append(parts, membership("request.name", f.names))
+	}
+	parts = append(parts, f.conditions...)
+	var exprs []string
+	for _, part := range parts {
+		if part != "" && part != "true" {
+			exprs = append(exprs, part)
+		}
+	}
+	if len(exprs) == 0 {
+		return "true"
+	}
+	return and(exprs...)
+}
+
+// match translates the match block of a rule to match constraints and match conditions.
+func (c *ruleConverter) match(operations []admissionregistrationv1.OperationType) (*admissionregistrationv1.MatchResources, []admissionregistrationv1.MatchCondition, error) {
+	path := c.path.Child("match")
+	match := c.rule.MatchResources
+	filters, all := match.Any, false
+	switch {
+	case len(match.Any) > 0:
+		path = path.Child("any")
+	case len(match.All) > 0:
+		filters, all = match.All, true
+		path = path.Child("all")
+	default:
+		filters = kyvernov1.ResourceFilters{{UserInfo: match.UserInfo, ResourceDescription: match.ResourceDescription}}
+	}
+	converted := make([]filter, 0, len(filters))
+	for i, resourceFilter := range filters {
+		path := path
+		if len(match.Any) > 0 || len(match.All) > 0 {
+			path = path.Index(i)
+		}
+		filter, err := c.filter(path, resourceFilter, operations)
+		if err != nil {
+			return nil, nil, err
+		}
+		converted = append(converted, filter)
+	}
+	if all {
+		return matchAll(path, converted)
+	}
+	if len(converted) == 1 {
+		return matchOne(converted[0])
+	}
+	return matchAny(converted)
+}
+
+// matchOne sets as much as possible of a single filter on the match constraints.
+func matchOne(filter filter) (*admissionregistrationv1.MatchResources, []admissionregistrationv1.MatchCondition, error) {
+	constraints := &admissionregistrationv1.MatchResources{
+		ResourceRules: namedRules(filter.rules, nil),
+	}
+	var selector *metav1.LabelSelector
+	if filter.namespaceSelector != nil {
+		selector = filter.namespaceSelector.DeepCopy()
+	}
+	if len(filter.namespaces) > 0 {
+		if slices.ContainsFunc(filter.namespaces, wildcard.ContainsWildcard) {
+			filter.conditions = append([]string{membership("request.namespace", filter.namespaces)}, filter.conditions...)
+		} else {
+			if selector == nil {
+				selector = &metav1.LabelSelector{}
+			}
+			selector.MatchExpressions = append(selector.MatchExpressions, metav1.LabelSelectorRequirement{
+				Key:      namespaceNameLabel,
+				Operator: metav1.LabelSelectorOpIn,
+				Values:   filter.namespaces,
+			})
+		}
+	}
+	constraints.NamespaceSelector = selector
+	constraints.ObjectSelector = filter.objectSelector
+	if len(filter.names) > 0 {
+		if slices.ContainsFunc(filter.names, wildcard.ContainsWildcard) {
+			filter.conditions = append(filter.conditions, membership("request.name", filter.names))
+		} else {
+			constraints.ResourceRules = namedRules(filter.rules, filter.names)
+		}
+	}
+	var conditions []admissionregistrationv1.MatchCondition
+	if len(filter.conditions) > 0 {
+		conditions = append(conditions, admissionregistrationv1.MatchCondition{
+			Name:       "match",
+			Expression: and(filter.conditions...),
+		})
+	}
+	return constraints, conditions, nil
+}
+
+// matchAny matches the union of the filters rules, the parts of the filters that can't be
+// expressed with the union of their rules are checked with a match condition.
+func matchAny(filters []filter) (*admissionregistrationv1.MatchResources, []admissionregistrationv1.MatchCondition, error) {
+	var rules []admissionregistrationv1.RuleWithOperations
+	needsCondition := false
+	for _, filter := range filters {
+		rules = append(rules, filter.rules...)
+		if filter.namespaceSelector != nil || filter.objectSelector != nil || len(filter.namespaces) > 0 || len(filter.names) > 0 || len(filter.conditions) > 0 {
+			needsCondition = true
+		}
+		if !slices.EqualFunc(filter.rules, filters[0].rules, func(a, b admissionregistrationv1.RuleWithOperations) bool {
+			return slices.Equal(a.Operations, b.Operations)
+		}) {
+			needsCondition = true
+		}
+	}
+	constraints := &admissionregistrationv1.MatchResources{
+		ResourceRules: namedRules(dedupe(rules), nil),
+	}
+	if !needsCondition {
+		return constraints, nil, nil
+	}
+	predicates := make([]string, 0, len(filters))
+	for _, filter := range filters {
+		predicates = append(predicates, filter.predicate())
+	}
+	return constraints, []admissionregistrationv1.MatchCondition{{
+		Name:       "match",
+		Expression: or(predicates...),
+	}}, nil
+}
+
+// matchAll uses the rules of the only filter with kinds, the other parts of the filters are checked with a match condition.
+func matchAll(path *field.Path, filters []filter) (*admissionregistrationv1.MatchResources, []admissionregistrationv1.MatchCondition, error) {
+	var rules []admissionregistrationv1.RuleWithOperations
+	var predicates []string
+	for _, filter := range filters {
+		if len(filter.rules) > 0 {
+			if rules != nil {
+				return nil, nil, unsupported(path, "only one filter can match kinds in match.all")
+			}
+			rules = filter.rules
+		}
+		filter.kinds = ""
+		if predicate := filter.predicate(); predicate != "true" {
+			predicates = append(predicates, predicate)
+		}
+	}
+	constraints := &admissionregistrationv1.MatchResources{
+		ResourceRules: namedRules(rules, nil),
+	}
+	if len(predicates) == 0 {
+		return constraints, nil, nil
+	}
+	return constraints, []admissionregistrationv1.MatchCondition{{
+		Name:       "match",
+		Expression: and(predicates...),
+	}}, nil
+}
+
+// exclude translates the exclude block of a rule to a match condition.
+func (c *ruleConverter) exclude() ([]admissionregistrationv1.MatchCondition, error) {
+	exclude := c.rule.ExcludeResources
+	if exclude == nil {
+		return nil, nil
+	}
+	path := c.path.Child("exclude")
+	filters, operator := exclude.Any, " || "
+	switch {
+	case len(exclude.Any) > 0:
+		path = path.Child("any")
+	case len(exclude.All) > 0:
+		filters, operator = exclude.All, " && "
+		path = path.Child("all")
+	default:
+		filters = kyvernov1.ResourceFilters{{UserInfo: exclude.UserInfo, ResourceDescription: exclude.ResourceDescription}}
+	}
+	var predicates []string
+	for i, resourceFilter := range filters {
+		path := path
+		if len(exclude.Any) > 0 || len(exclude.All) > 0 {
+			path = path.Index(i)
+		}
+		filter, err := c.filter(path, resourceFilter, nil)
+		if err != nil {
+			return nil, err
+		}
+		if predicate := filter.predicate(); predicate != "true" {
+			predicates = append(predicates, predicate)
+		}
+	}
+	if len(predicates) == 0 {
+		return nil, nil
+	}
+	return []admissionregistrationv1.MatchCondition{{
+		Name:       "exclude",
+		Expression: not(join(operator, predicates...)),
+	}}, nil
+}
+
+func (c *ruleConverter) filter(path *field.Path, resourceFilter kyvernov1.ResourceFilter, operations []admissionregistrationv1.OperationType) (filter, error) {
+	var result filter
+	description := resourceFilter.ResourceDescription
+	resourcesPath := path.Child("resources")
+	if len(description.Operations) > 0 {
+		operations = nil
+		for _, operation := range description.Operations {
+			operations = append(operations, admissionregistrationv1.OperationType(operation))
+		}
+		values := make([]string, 0, len(operations))
+		for _, operation := range operations {
+			values = append(values, string(operation))
+		}
+		result.operations = membership("request.operation", values)
+	}
+	var kinds []string
+	for i, kind := range description.Kinds {
+		rule, err := c.converter.resolver.resolve(resourcesPath.Child("kinds").Index(i), kind)
+		if err != nil {
+			return result, err
+		}
+		if operations != nil {
+			result.rules = append(result.rules, admissionregistrationv1.RuleWithOperations{
+				Operations: operations,
+				Rule:       rule,
+			})
+		}
+		kinds = append(kinds, kindPredicate(kind))
+	}
+	if slices.Contains(kinds, "true") {
+		kinds = nil
+	}
+	if len(kinds) > 0 {
+		result.kinds = or(kinds...)
+	}
+	result.namespaces = description.Namespaces
+	result.objectSelector = description.Selector
+	result.namespaceSelector = description.NamespaceSelector
+	if result.objectSelector != nil && hasWildcardValues(*result.objectSelector) {
+		result.conditions = append(result.conditions, selector("object.metadata", *result.objectSelector))
+		result.objectSelector = nil
+	}
+	if result.namespaceSelector != nil && hasWildcardValues(*result.namespaceSelector) {
+		result.conditions = append(result.conditions, and("namespaceObject != null", selector("namespaceObject.metadata", *result.namespaceSelector)))
+		result.namespaceSelector = nil
+	}
+	if description.Name != "" {
+		result.names = append(result.names, description.Name)
+	}
+	result.names = append(result.names, description.Names...)
+	if len(description.Annotations) > 0 {
+		keys := make([]string, 0, len(description.Annotations))
+		for key := range description.Annotations {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			value := fmt.Sprintf("object.metadata.?annotations[?%s]", quote(key))
+			result.conditions = append(result.conditions, and(value+".hasValue()", matches(value+".value()", description.Annotations[key])))
+		}
+	}
+	userInfo := resourceFilter.UserInfo
+	if len(userInfo.Roles) > 0 {
+		return result, unsupported(path.Child("roles"), "roles are not available in CEL policies")
+	}
+	if len(userInfo.ClusterRoles) > 0 {
+		return result, unsupported(path.Child("clusterRoles"), "cluster roles are not available in CEL policies")
+	}
+	if len(userInfo.Subjects) > 0 {
+		subjects := make([]string, 0, len(userInfo.Subjects))
+		for i, subject := range userInfo.Subjects {
+			expr, err := subjectPredicate(path.Child("subjects").Index(i), subject)
+			if err != nil {
+				return result, err
+			}
+			subjects = append(subjects, expr)
+		}
+		result.conditions = append(result.conditions, or(subjects...))
+	}
+	return result, nil
+}
+
+func kindPredicate(selector string) string {
+	group, version, kind, subresource := kubeutils.ParseKindSelector(selector)
+	var parts []string
+	if group != "*" {
+		parts = append(parts, "request.kind.group == "+quote(group))
+	}
+	if version != "*" {
+		parts = append(parts, "request.kind.version == "+quote(version))
+	}
+	if kind != "*" {
+		parts = append(parts, "request.kind.kind == "+quote(kind))
+	}
+	if subresource != "" && subresource != "*" {
+		parts = append(parts, "request.subResource == "+quote(subresource))
+	}
+	if len(parts) == 0 {
+		return "true"
+	}
+	return and(parts...)
+}
+
+func subjectPredicate(path *field.Path, subject rbacv1.Subject) (string, error) {
+	switch subject.Kind {
+	case rbacv1.UserKind:
+		return "request.userInfo.username == " + quote(subject.Name), nil
+	case rbacv1.GroupKind:
+		return quote(subject.Name) + " in request.userInfo.groups", nil
+	case rbacv1.ServiceAccountKind:
+		return "request.userInfo.username == " + quote(fmt.Sprintf("system:serviceaccount:%s:%s", subject.Namespace, subject.Name)), nil
+	}
+	return "", unsupported(path.Child("kind"), "subject kind %q is not supported", subject.Kind)
+}
+
+// membership checks value matches one of the patterns, patterns can contain wildcards.
+func membership(value string, patterns []string) string {
+	var literals []string
+	var exprs []string
+	for _, pattern := range patterns {
+		if wildcard.ContainsWildcard(pattern) {
+			exprs = append(exprs, matches(value, pattern))
+		} else {
+			literals = append(literals, quote(pattern))
+		}
+	}
+	switch len(literals) {
+	case 0:
+	case 1:
+		exprs = append([]string{value + " == " + literals[0]}, exprs...)
+	default:
+		exprs = append([]string{value + " in [" + strings.Join(literals, ", ") + "]"}, exprs...)
+	}
+	return or(exprs...)
+}
+
+// selector translates a label selector applied to the labels of metadata, label values can contain wildcards.
+func selector(metadata string, labelSelector metav1.LabelSelector) string {
+	var exprs []string
+	keys := make([]string, 0, len(labelSelector.MatchLabels))
+	for key := range labelSelector.MatchLabels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		exprs = append(exprs, labelMatches(metadata, key, []string{labelSelector.MatchLabels[key]}))
+	}
+	for _, requirement := range labelSelector.MatchExpressions {
+		label := fmt.Sprintf("%s.?labels[?%s]", metadata, quote(requirement.Key))
+		switch requirement.Operator {
+		case metav1.LabelSelectorOpIn:
+			exprs = append(exprs, labelMatches(metadata, requirement.Key, requirement.Values))
+		case metav1.LabelSelectorOpNotIn:
+			exprs = append(exprs, not(labelMatches(metadata, requirement.Key, requirement.Values)))
+		case metav1.LabelSelectorOpExists:
+			exprs = append(exprs, label+".hasValue()")
+		case metav1.LabelSelectorOpDoesNotExist:
+			exprs = append(exprs, "!"+label+".hasValue()")
+		}
+	}
+	if len(exprs) == 0 {
+		return "true"
+	}
+	return and(exprs...)
+}
+
+func labelMatches(metadata, key string, values []string) string {
+	label := fmt.Sprintf("%s.?labels[?%s]", metadata, quote(key))
+	if slices.Contains(values, "*") {
+		return label + ".hasValue()"
+	}
+	return and(label+".hasValue()", membership(label+".value()", values))
+}
+
+func hasWildcardValues(labelSelector metav1.LabelSelector) bool {
+	for _, value := range labelSelector.MatchLabels {
+		if wildcard.ContainsWildcard(value) {
+			return true
+		}
+	}
+	for _, requirement := range labelSelector.MatchExpressions {
+		if slices.ContainsFunc(requirement.Values, wildcard.ContainsWildcard) {
+			return true
+		}
+	}
+	return false
+}
+
+func dedupe(rules []admissionregistrationv1.RuleWithOperations) []admissionregistrationv1.RuleWithOperations {
+	var result []admissionregistrationv1.RuleWithOperations
+	for _, rule := range rules {
+		if !slices.ContainsFunc(result, func(other admissionregistrationv1.RuleWithOperations) bool {
+			return slices.Equal(rule.Operations, other.Operations) &&
+				slices.Equal(rule.APIGroups, other.APIGroups) &&
+				slices.Equal(rule.APIVersions, other.APIVersions) &&
+				slices.Equal(rule.Resources, other.Resources)
+		}) {
+			result = append(result, rule)
+		}
+	}
+	return result
+}
+
+func namedRules(rules []admissionregistrationv1.RuleWithOperations, names []string) []admissionregistrationv1.NamedRuleWithOperations {
+	named := make([]admissionregistrationv1.NamedRuleWithOperations, 0, len(rules))
+	for _, rule := range rules {
+		named = append(named, admissionregistrationv1.NamedRuleWithOperations{
+			ResourceNames:      names,
+			RuleWithOperations: rule,
+		})
+	}
+	return named
+}
@@ -0,0 +1,434 @@
+package convert
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	policiesv1beta1 "github.com/kyverno/kyverno/api/policies.kyverno.io/v1beta1"
+	"github.com/kyverno/kyverno/pkg/engine/anchor"
+	admissionregistrationv1alpha1 "k8s.io/api/admissionregistration/v1alpha1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/yaml"
+)
+
+// mapFields are the fields of Kubernetes resources holding maps rather than objects,
+// they are written as map literals in apply configurations.
+var mapFields = []string{
+	"allocatable",
+	"annotations",
+	"binaryData",
+	"capacity",
+	"data",
+	"labels",
+	"limits",
+	"matchLabels",
+	"overhead",
+	"parameters",
+	"requests",
+	"stringData",
+}
+
+// atomicFields are the fields of Kubernetes resources replaced as a whole by apply configurations,
+// strategic merge patches merging into them can't be converted.
+var atomicFields = []string{
+	"nodeSelector",
+	"selector",
+}
+
+func (c *ruleConverter) mutatingPolicy(common common) runtime.Object {
+	path := c.path.Child("mutate")
+	mutation := c.rule.Mutation
+	if len(mutation.Targets) > 0 {
+		c.report(unsupported(path.Child("targets"), "mutate existing rules can't be converted"))
+		return nil
+	}
+	var mutations []admissionregistrationv1alpha1.Mutation
+	if mutation.RawPatchStrategicMerge != nil {
+		m, err := c.applyConfiguration(path.Child("patchStrategicMerge"), common.guard, mutation.GetPatchStrategicMerge(), nil)
+		c.report(err)
+		mutations = append(mutations, m)
+	}
+	if mutation.PatchesJSON6902 != "" {
+		m, err := c.jsonPatch(path.Child("patchesJson6902"), c.scope, common.guard, mutation.PatchesJSON6902)
+		c.report(err)
+		mutations = append(mutations, m)
+	}
+	for i, foreach := range mutation.ForEachMutation {
+		m, err := c.foreachMutation(path.Child("foreach").Index(i), common.guard, foreach)
+		c.report(err)
+		mutations = append(mutations, m)
+	}
+	spec := policiesv1beta1.MutatingPolicySpec{
+		MatchConstraints: common.matchConstraints,
+		MatchConditions:  common.matchConditions,
+		Variables:        common.variables,
+		FailurePolicy:    c.failurePolicy(),
+		Mutations:        mutations,
+	}
+	if controllers := c.podControllers(); controllers != nil {
+		spec.AutogenConfiguration = &policiesv1beta1.MutatingPolicyAutogenConfiguration{PodControllers: controllers}
+	}
+	spec.WebhookConfiguration = c.webhookConfiguration()
+	if admission, background := c.admission(), c.background(); admission != nil || background != nil {
+		spec.EvaluationConfiguration = &policiesv1beta1.MutatingPolicyEvaluationConfiguration{
+			Admission:  admission,
+			Background: background,
+		}
+	}
+	if c.policy.IsNamespaced() {
+		return &policiesv1beta1.NamespacedMutatingPolicy{
+			TypeMeta:   typeMeta("NamespacedMutatingPolicy"),
+			ObjectMeta: c.objectMeta(),
+			Spec:       spec,
+		}
+	}
+	return &policiesv1beta1.MutatingPolicy{
+		TypeMeta:   typeMeta("MutatingPolicy"),
+		ObjectMeta: c.objectMeta(),
+		Spec:       spec,
+	}
+}
+
+// foreachMutation translates a foreach mutation, the strategic merge patch must patch the
+// list the foreach iterates on and each element is patched by mapping the list.
+func (c *ruleConverter) foreachMutation(path *field.Path, guard string, foreach kyvernov1.ForEachMutation) (admissionregistrationv1alpha1.Mutation, error) {
+	switch {
+	case len(foreach.Context) > 0:
+		return admissionregistrationv1alpha1.Mutation{}, unsupported(path.Child("context"), "context entries in foreach can't be converted")
+	case foreach.ForEachMutation != nil:
+		return admissionregistrationv1alpha1.Mutation{}, unsupported(path.Child("foreach"), "nested foreach mutations can't be converted")
+	case foreach.PatchesJSON6902 != "":
+		return admissionregistrationv1alpha1.Mutation{}, unsupported(path.Child("patchesJson6902"), "JSON patches in foreach can't be converted, element indexes are not available in CEL")
+	case foreach.RawPatchStrategicMerge == nil:
+		return admissionregistrationv1alpha1.Mutation{}, unsupported(path, "foreach has no patchStrategicMerge")
+	}
+	segments, ok := parsePath(foreach.List)
+	if !ok || len(segments) < 3 || segments[0].key != "request" || segments[1].key != "object" {
+		return admissionregistrationv1alpha1.Mutation{}, unsupported(path.Child("list"), "only lists of the request object can be converted")
+	}
+	keys := make([]string, 0, len(segments)-2)
+	for _, segment := range segments[2:] {
+		if segment.isIndex {
+			return admissionregistrationv1alpha1.Mutation{}, unsupported(path.Child("list"), "lists accessed by index can't be converted")
+		}
+		keys = append(keys, segment.key)
+	}
+	scope, element := c.scope.withElement()
+	list := &foreachList{
+		keys:    keys,
+		list:    access("object", segments[2:], "[]"),
+		element: element,
+		scope:   scope,
+	}
+	if foreach.AnyAllConditions != nil {
+		condition, err := scope.conditions(path.Child("preconditions"), foreach.AnyAllConditions)
+		if err != nil {
+			return admissionregistrationv1alpha1.Mutation{}, err
+		}
+		list.condition = condition
+	}
+	return c.applyConfiguration(path.Child("patchStrategicMerge"), guard, foreach.GetPatchStrategicMerge(), list)
+}
+
+// foreachList is the list a foreach mutation iterates on.
+type foreachList struct {
+	// keys is the path of the list in the object
+	keys      []string
+	list      string
+	element   string
+	scope     scope
+	condition string
+	// used is set when the patch contains the list
+	used bool
+}
+
+func (c *ruleConverter) applyConfiguration(path *field.Path, guard string, patch any, foreach *foreachList) (admissionregistrationv1alpha1.Mutation, error) {
+	object, ok := patch.(map[string]any)
+	if !ok {
+		return admissionregistrationv1alpha1.Mutation{}, unsupported(path, "patch must be an object")
+	}
+	w := applyWalker{scope: c.scope, foreach: foreach}
+	expr, _, err := w.object(path, "object", nil, "Object", object)
+	if err != nil {
+		return admissionregistrationv1alpha1.Mutation{}, err
+	}
+	if foreach != nil && !foreach.used {
+		return admissionregistrationv1alpha1.Mutation{}, unsupported(path, "patch doesn't contain the list %s the foreach iterates on", strings.Join(foreach.keys, "."))
+	}
+	if len(w.result.globals) > 0 || len(w.result.conditions) > 0 || guard != "" {
+		var guards []string
+		if guard != "" {
+			guards = append(guards, guard)
+		}
+		guards = append(guards, w.result.globals...)
+		guards = append(guards, w.result.conditions...)
+		expr = fmt.Sprintf("%s ? %s : Object{}", parenthesize(and(guards...)), expr)
+	}
+	return admissionregistrationv1alpha1.Mutation{
+		PatchType: admissionregistrationv1alpha1.PatchTypeApplyConfiguration,
+		ApplyConfiguration: &admissionregistrationv1alpha1.ApplyConfiguration{
+			Expression: expr,
+		},
+	}, nil
+}
+
+// applyWalker translates a strategic merge patch to an apply configuration expression.
+type applyWalker struct {
+	scope   scope
+	foreach *foreachList
+	// identity is set for the elements of resource lists, their name is kept so that they are merged with the right element
+	identity bool
+	// result collects the conditions of the anchors outside of lists, the patch only applies when they hold
+	result patternResult
+}
+
+// object translates a map of the patch, root is the CEL expression of the patched value,
+// keys the path from root to the map and typeName the apply configuration type or empty for maps.
+func (w *applyWalker) object(path *field.Path, root string, keys []string, typeName string, patch map[string]any) (string, string, error) {
+	names := make([]string, 0, len(patch))
+	for key := range patch {
+		names = append(names, key)
+	}
+	sort.Strings(names)
+	isStruct := typeName != ""
+	for _, key := range names {
+		if a := anchor.Parse(key); a != nil {
+			key = a.Key()
+		}
+		if !isIdentifier(key) {
+			isStruct = false
+		}
+	}
+	current := root + fieldPath(keys, false)
+	var entries, values, types, presents []string
+	for _, key := range names {
+		path := path.Key(key)
+		a := anchor.Parse(key)
+		name := key
+		if a != nil {
+			name = a.Key()
+		}
+		switch {
+		case a == nil, anchor.IsAddIfNotPresent(a):
+			if _, ok := patch[key].(map[string]any); ok && slices.Contains(atomicFields, name) {
+				return "", "", unsupported(path, "%s is replaced as a whole by apply configurations, merging into it can't be converted", name)
+			}
+			childType := ""
+			if isStruct && !slices.Contains(mapFields, name) {
+				childType = typeName + "." + name
+			}
+			value, typ, err := w.value(path, root, append(slices.Clone(keys), name), childType, patch[key])
+			if err != nil {
+				return "", "", err
+			}
+			present := ""
+			if a != nil {
+				present = root + fieldPath(append(slices.Clone(keys), name), true) + ".hasValue()"
+			}
+			entries = append(entries, name)
+			values = append(values, value)
+			types = append(types, typ)
+			presents = append(presents, present)
+		case anchor.IsCondition(a), anchor.IsGlobal(a), anchor.IsNegation(a):
+			condition := has(current, name)
+			if !anchor.IsNegation(a) {
+				child, err := w.scope.patternValue(path, fieldOf(current, name), patch[key])
+				if err != nil {
+					return "", "", err
+				}
+				if len(child.conditions) > 0 || len(child.globals) > 0 {
+					return "", "", unsupported(path, "anchors nested in conditional anchors are not supported")
+				}
+				condition = and(condition, child.check())
+			} else {
+				condition = not(condition)
+			}
+			// conditions are checked on the resource, the path to the map must exist
+			for i := len(keys) - 1; i >= 0; i-- {
+				condition = and(has(root+fieldPath(keys[:i], false), keys[i]), condition)
+			}
+			if anchor.IsGlobal(a) {
+				w.result.globals = append(w.result.globals, condition)
+			} else {
+				w.result.conditions = append(w.result.conditions, condition)
+			}
+		default:
+			return "", "", unsupported(path, "anchor %s is not supported in mutation patches", a)
+		}
+	}
+	// list elements are merged by name, the name of the patched element is kept
+	if w.identity && len(keys) == 0 && isStruct && !slices.Contains(entries, "name") {
+		entries = append([]string{"name"}, entries...)
+		values = append([]string{root + ".name"}, values...)
+		types = append([]string{typeOther}, types...)
+		presents = append([]string{""}, presents...)
+	}
+	if !isStruct {
+		values = homogeneous(values, types)
+	}
+	items := make([]string, 0, len(entries))
+	for i, name := range entries {
+		value, marker := values[i], ""
+		if presents[i] != "" {
+			value = fmt.Sprintf("%s ? optional.none() : optional.of(%s)", presents[i], value)
+			marker = "?"
+		}
+		if isStruct {
+			items = append(items, marker+name+": "+value)
+		} else {
+			items = append(items, marker+quote(name)+": "+value)
+		}
+	}
+	if isStruct {
+		return typeName + "{" + strings.Join(items, ", ") + "}", typeOther, nil
+	}
+	return "{" + strings.Join(items, ", ") + "}", typeOther, nil
+}
+
+func (w *applyWalker) value(path *field.Path, root string, keys []string, typeName string, patch any) (string, string, error) {
+	switch typed := patch.(type) {
+	case map[string]any:
+		return w.object(path, root, keys, typeName, typed)
+	case []any:
+		expr, err := w.list(path, root, keys, typeName, typed)
+		return expr, typeOther, err
+	default:
+		return w.scope.typedValue(path, typed)
+	}
+}
+
+// list translates a list of the patch, conditional anchors in the elements select the elements of the resource list to patch.
+func (w *applyWalker) list(path *field.Path, root string, keys []string, typeName string, patch []any) (string, error) {
+	if w.foreach != nil && root == "object" && slices.Equal(keys, w.foreach.keys) {
+		if len(patch) != 1 {
+			return "", unsupported(path, "the list the foreach iterates on must contain a single element")
+		}
+		element, ok := patch[0].(map[string]any)
+		if !ok {
+			return "", unsupported(path.Index(0), "the list the foreach iterates on must contain objects")
+		}
+		foreach := w.foreach
+		foreach.used = true
+		nested := applyWalker{scope: foreach.scope, identity: true}
+		expr, _, err := nested.object(path.Index(0), foreach.element, nil, typeName, element)
+		if err != nil {
+			return "", err
+		}
+		if len(nested.result.globals) > 0 {
+			return "", unsupported(path, "global anchors in lists are not supported")
+		}
+		conditions := nested.result.conditions
+		if foreach.condition != "" {
+			conditions = append([]string{foreach.condition}, conditions...)
+		}
+		return mapList(foreach.list, foreach.element, conditions, expr), nil
+	}
+	var items, types []string
+	for i, item := range patch {
+		path := path.Index(i)
+		element, ok := item.(map[string]any)
+		if !ok {
+			expr, typ, err := w.scope.typedValue(path, item)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, expr)
+			types = append(types, typ)
+			continue
+		}
+		scope, name := w.scope.withItem()
+		nested := applyWalker{scope: scope}
+		expr, _, err := nested.object(path, name, nil, typeName, element)
+		if err != nil {
+			return "", err
+		}
+		if len(nested.result.globals) > 0 {
+			return "", unsupported(path, "global anchors in lists are not supported")
+		}
+		if len(nested.result.conditions) > 0 {
+			if len(patch) != 1 {
+				return "", unsupported(path, "conditional anchors are only supported in lists with a single element")
+			}
+			// the element patches the elements of the resource list matching its conditions
+			nested = applyWalker{scope: scope, identity: true}
+			expr, _, err = nested.object(path, name, nil, typeName, element)
+			if err != nil {
+				return "", err
+			}
+			list := root + fieldPath(keys, true) + ".orValue([])"
+			return mapList(list, name, nested.result.conditions, expr), nil
+		}
+		items = append(items, expr)
+		types = append(types, typeOther)
+	}
+	if typeName == "" {
+		items = homogeneous(items, types)
+	}
+	return "[" + strings.Join(items, ", ") + "]", nil
+}
+
+func mapList(list, element string, conditions []string, expr string) string {
+	if len(conditions) > 0 {
+		list = fmt.Sprintf("%s.filter(%s, %s)", parenthesize(list), element, and(conditions...))
+	}
+	return fmt.Sprintf("%s.map(%s, %s)", parenthesize(list), element, expr)
+}
+
+// fieldPath returns the CEL selectors of keys, optional selectors don't fail on missing fields.
+func fieldPath(keys []string, optional bool) string {
+	segments := make([]segment, 0, len(keys))
+	for _, key := range keys {
+		segments = append(segments, segment{key: key})
+	}
+	return selectors(segments, optional)
+}
+
+// jsonPatch translates JSON 6902 patches to a JSON patch expression.
+func (c *ruleConverter) jsonPatch(path *field.Path, scope scope, guard string, patches string) (admissionregistrationv1alpha1.Mutation, error) {
+	var operations []map[string]any
+	if err := yaml.Unmarshal([]byte(patches), &operations); err != nil {
+		return admissionregistrationv1alpha1.Mutation{}, unsupported(path, "failed to parse patches: %s", err)
+	}
+	items := make([]string, 0, len(operations))
+	for i, operation := range operations {
+		path := path.Index(i)
+		var fields []string
+		for _, key := range []string{"op", "path", "from"} {
+			raw, ok := operation[key]
+			if !ok {
+				continue
+			}
+			value, ok := raw.(string)
+			if !ok {
+				return admissionregistrationv1alpha1.Mutation{}, unsupported(path.Child(key), "%s must be a string", key)
+			}
+			expr, err := scope.stringValue(path.Child(key), value)
+			if err != nil {
+				return admissionregistrationv1alpha1.Mutation{}, err
+			}
+			fields = append(fields, key+": "+expr)
+		}
+		if value, ok := operation["value"]; ok {
+			expr, err := scope.value(path.Child("value"), value)
+			if err != nil {
+				return admissionregistrationv1alpha1.Mutation{}, err
+			}
+			fields = append(fields, "value: "+expr)
+		}
+		items = append(items, "JSONPatch{"+strings.Join(fields, ", ")+"}")
+	}
+	expr := "[" + strings.Join(items, ", ") + "]"
+	if guard != "" {
+		expr = fmt.Sprintf("%s ? %s : []", parenthesize(guard), expr)
+	}
+	return admissionregistrationv1alpha1.Mutation{
+		PatchType: admissionregistrationv1alpha1.PatchTypeJSONPatch,
+		JSONPatch: &admissionregistrationv1alpha1.JSONPatch{
+			Expression: expr,
+		},
+	}, nil
+}
@@ -0,0 +1,312 @@
+package convert
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kyverno/kyverno/ext/wildcard"
+	"github.com/kyverno/kyverno/pkg/engine/anchor"
+	"github.com/kyverno/kyverno/pkg/engine/operator"
+	"github.com/kyverno/kyverno/pkg/engine/variables/regex"
+	apiresource "k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// patternResult is the translation of a validation pattern.
+type patternResult struct {
+	// conditions come from conditional anchors, the closest list element or the rule is skipped when they don't hold
+	conditions []string
+	// globals come from global anchors, the rule is skipped when they don't hold
+	globals []string
+	// checks must hold for the resource to match the pattern
+	checks []string
+}
+
+func (r patternResult) check() string {
+	if len(r.checks) == 0 {
+		return "true"
+	}
+	return and(r.checks...)
+}
+
+// pattern translates a validation pattern applied to value to a CEL expression,
+// the expression holds when the pattern is skipped because of its anchors.
+func (s scope) pattern(path *field.Path, value string, pattern any) (string, error) {
+	guard, check, err := s.guardedPattern(path, value, pattern)
+	if err != nil || guard == "" {
+		return check, err
+	}
+	return or(not(guard), check), nil
+}
+
+// guardedPattern translates a validation pattern applied to value to the guard holding when
+// the pattern isn't skipped because of its anchors, and the check holding when it matches.
+func (s scope) guardedPattern(path *field.Path, value string, pattern any) (string, string, error) {
+	result, err := s.patternValue(path, value, pattern)
+	if err != nil {
+		return "", "", err
+	}
+	guards := append(result.conditions, result.globals...)
+	if len(guards) == 0 {
+		return "", result.check(), nil
+	}
+	return and(guards...), result.check(), nil
+}
+
+// anyPattern translates patterns of which at least one must match,
+// patterns skipped because of their anchors don't count unless they are all skipped.
+func (s scope) anyPattern(path *field.Path, value string, patterns []any) (string, error) {
+	guard, check, err := s.guardedAnyPattern(path, value, patterns)
+	if err != nil || guard == "" {
+		return check, err
+	}
+	return or(check, not(guard)), nil
+}
+
+// guardedAnyPattern translates patterns of which at least one must match to the guard holding when
+// they are not all skipped because of their anchors, and the check holding when one of them matches.
+func (s scope) guardedAnyPattern(path *field.Path, value string, patterns []any) (string, string, error) {
+	var exprs []string
+	var guards []string
+	for i, pattern := range patterns {
+		guard, check, err := s.guardedPattern(path.Index(i), value, pattern)
+		if err != nil {
+			return "", "", err
+		}
+		if guard != "" {
+			guards = append(guards, guard)
+			exprs = append(exprs, and(guard, check))
+		} else {
+			exprs = append(exprs, check)
+		}
+	}
+	if len(guards) > 0 && len(guards) == len(exprs) {
+		return or(guards...), or(exprs...), nil
+	}
+	return "", or(exprs...), nil
+}
+
+func (s scope) patternValue(path *field.Path, value string, pattern any) (patternResult, error) {
+	switch typed := pattern.(type) {
+	case map[string]any:
+		return s.patternMap(path, value, typed)
+	case []any:
+		check, err := s.patternList(path, value, typed)
+		if err != nil {
+			return patternResult{}, err
+		}
+		return patternResult{checks: []string{check}}, nil
+	default:
+		check, err := s.patternScalar(path, value, typed)
+		if err != nil {
+			return patternResult{}, err
+		}
+		return patternResult{checks: []string{check}}, nil
+	}
+}
+
+func (s scope) patternMap(path *field.Path, value string, pattern map[string]any) (patternResult, error) {
+	var result patternResult
+	keys := make([]string, 0, len(pattern))
+	for key := range pattern {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		path := path.Key(key)
+		a := anchor.Parse(key)
+		name := key
+		if a != nil {
+			name = a.Key()
+		}
+		present := has(value, name)
+		child, err := s.patternValue(path, fieldOf(value, name), pattern[key])
+		if err != nil {
+			return result, err
+		}
+		switch {
+		case a == nil:
+			// nested anchors only apply when the field is present
+			if len(child.conditions) > 0 {
+				result.conditions = append(result.conditions, and(present, and(child.conditions...)))
+			}
+			if len(child.globals) > 0 {
+				result.globals = append(result.globals, and(present, and(child.globals...)))
+			}
+			result.checks = append(result.checks, and(present, child.check()))
+		case anchor.IsCondition(a), anchor.IsGlobal(a):
+			if len(child.conditions) > 0 || len(child.globals) > 0 {
+				return result, unsupported(path, "anchors nested in conditional anchors are not supported")
+			}
+			condition := and(present, child.check())
+			if anchor.IsGlobal(a) {
+				result.globals = append(result.globals, condition)
+			} else {
+				result.conditions = append(result.conditions, condition)
+			}
+		case anchor.IsEquality(a):
+			if len(child.conditions) > 0 || len(child.globals) > 0 {
+				return result, unsupported(path, "anchors nested in equality anchors are not supported")
+			}
+			result.checks = append(result.checks, or(not(present), child.check()))
+		case anchor.IsNegation(a):
+			result.checks = append(result.checks, not(present))
+		case anchor.IsExistence(a):
+			list, ok := pattern[key].([]any)
+			if !ok || len(list) != 1 {
+				return result, unsupported(path, "existence anchors must contain a list with a single element")
+			}
+			scope, item := s.withItem()
+			element, err := scope.patternValue(path.Index(0), item, list[0])
+			if err != nil {
+				return result, err
+			}
+			if len(element.globals) > 0 {
+				return result, unsupported(path, "global anchors in lists are not supported")
+			}
+			exists := fmt.Sprintf("%s.exists(%s, %s)", fieldOf(value, name), item, and(append(element.conditions, element.check())...))
+			result.checks = append(result.checks, and(present, exists))
+		default:
+			return result, unsupported(path, "anchor %s is not supported in validation patterns", a)
+		}
+	}
+	return result, nil
+}
+
+func (s scope) patternList(path *field.Path, value string, pattern []any) (string, error) {
+	if len(pattern) == 0 {
+		return "", unsupported(path, "empty lists are not supported")
+	}
+	element, ok := pattern[0].(map[string]any)
+	if !ok {
+		return "", unsupported(path, "lists of scalar values are not supported, only lists of objects are")
+	}
+	// like the engine, only the first element of the pattern applies to the resource elements
+	scope, item := s.withItem()
+	result, err := scope.patternMap(path.Index(0), item, element)
+	if err != nil {
+		return "", err
+	}
+	if len(result.globals) > 0 {
+		return "", unsupported(path, "global anchors in lists are not supported")
+	}
+	check := result.check()
+	if len(result.conditions) > 0 {
+		check = or(not(and(result.conditions...)), check)
+	}
+	return fmt.Sprintf("%s.all(%s, %s)", value, item, check), nil
+}
+
+func (s scope) patternScalar(path *field.Path, value string, pattern any) (string, error) {
+	switch typed := pattern.(type) {
+	case nil:
+		return value + " == null", nil
+	case string:
+		if regex.IsVariable(typed) {
+			expr, err := s.value(path, typed)
+			if err != nil {
+				return "", err
+			}
+			return value + " == " + expr, nil
+		}
+		return s.patternString(path, value, typed)
+	default:
+		expr, err := s.value(path, typed)
+		if err != nil {
+			return "", err
+		}
+		return value + " == " + expr, nil
+	}
+}
+
+// patternString translates string patterns, they can combine operators with | and &.
+func (s scope) patternString(path *field.Path, value string, pattern string) (string, error) {
+	if pattern == "*" {
+		return value + " != null", nil
+	}
+	var alternatives []string
+	for _, alternative := range strings.Split(pattern, "|") {
+		var conditions []string
+		for _, condition := range strings.Split(strings.TrimSpace(alternative), "&") {
+			expr, err := s.patternCondition(path, value, strings.TrimSpace(condition))
+			if err != nil {
+				return "", err
+			}
+			conditions = append(conditions, expr)
+		}
+		alternatives = append(alternatives, and(conditions...))
+	}
+	return or(alternatives...), nil
+}
+
+func (s scope) patternCondition(path *field.Path, value string, pattern string) (string, error) {
+	switch op := operator.GetOperatorFromStringPattern(pattern); op {
+	case operator.Equal:
+		return patternEquals(value, pattern), nil
+	case operator.NotEqual:
+		return not(patternEquals(value, strings.TrimSpace(pattern[len(op):]))), nil
+	case operator.InRange, operator.NotInRange:
+		r := operator.InRangeRegex
+		if op == operator.NotInRange {
+			r = operator.NotInRangeRegex
+		}
+		match := r.FindStringSubmatch(pattern)
+		if len(match) != 3 {
+			return "", unsupported(path, "range %q is not valid", pattern)
+		}
+		if op == operator.InRange {
+			left, err := patternCompare(path, value, string(operator.MoreEqual), match[1])
+			if err != nil {
+				return "", err
+			}
+			right, err := patternCompare(path, value, string(operator.LessEqual), match[2])
+			if err != nil {
+				return "", err
+			}
+			return and(left, right), nil
+		}
+		left, err := patternCompare(path, value, string(operator.Less), match[1])
+		if err != nil {
+			return "", err
+		}
+		right, err := patternCompare(path, value, string(operator.More), match[2])
+		if err != nil {
+			return "", err
+		}
+		return or(left, right), nil
+	default:
+		return patternCompare(path, value, string(op), strings.TrimSpace(pattern[len(op):]))
+	}
+}
+
+func patternEquals(value string, pattern string) string {
+	switch {
+	case pattern == "*":
+		return value + " != null"
+	case pattern == "?*":
+		return "string(" + value + ") != ''"
+	case wildcard.ContainsWildcard(pattern):
+		return matches(value, pattern)
+	}
+	// numbers and quantities are compared with their string representation like the engine does
+	if _, err := apiresource.ParseQuantity(pattern); err == nil {
+		return "string(" + value + ") == " + quote(pattern)
+	}
+	return value + " == " + quote(pattern)
+}
+
+func patternCompare(path *field.Path, value string, operator string, operand string) (string, error) {
+	if number, err := strconv.ParseFloat(operand, 64); err == nil {
+		return fmt.Sprintf("double(%s) %s %s", value, operator, strconv.FormatFloat(number, 'g', -1, 64)), nil
+	}
+	if _, err := time.ParseDuration(operand); err == nil {
+		return fmt.Sprintf("duration(string(%s)) %s duration(%s)", value, operator, quote(operand)), nil
+	}
+	if _, err := apiresource.ParseQuantity(operand); err == nil {
+		return fmt.Sprintf("quantity(string(%s)).compareTo(quantity(%s)) %s 0", value, quote(operand), operator), nil
+	}
+	return "", unsupported(path, "operator %s only applies to numbers, quantities and durations", operator)
+}
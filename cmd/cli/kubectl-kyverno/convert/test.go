@@ -0,0 +1,76 @@
+package convert
+
+import (
+	"fmt"
+	"strings"
+
+	policiesv1beta1 "github.com/kyverno/kyverno/api/policies.kyverno.io/v1beta1"
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/apis/v1alpha1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// ConvertTest converts a test of legacy policies to a test of the policies converted from them, so that
+// the same resources are expected to give the same results. Results of rules that weren't converted,
+// checks and exceptions can't be carried over and are reported as issues.
+func ConvertTest(test v1alpha1.Test, policies []Policy) (v1alpha1.Test, []Issue) {
+	converted := make(map[string]Policy, len(policies))
+	for _, policy := range policies {
+		converted[policy.Source+"/"+policy.Rule] = policy
+	}
+	var issues []Issue
+	out := test
+	out.Results = nil
+	for i, result := range test.Results {
+		path := field.NewPath("results").Index(i)
+		if result.Rule == "" {
+			out.Results = append(out.Results, result)
+			continue
+		}
+		rule := strings.TrimPrefix(strings.TrimPrefix(result.Rule, "autogen-cronjob-"), "autogen-")
+		policy, ok := converted[result.Policy+"/"+rule]
+		if !ok {
+			issues = append(issues, Issue{
+				Policy:  result.Policy,
+				Rule:    rule,
+				Path:    path.String(),
+				Message: "the rule wasn't converted, the result is dropped",
+			})
+			continue
+		}
+		result.Rule = ""
+		result.Policy = policy.Name()
+		if namespace, _, ok := strings.Cut(policy.Source, "/"); ok {
+			result.Policy = namespace + "/" + result.Policy
+		}
+		switch policy.Kind() {
+		case policiesv1beta1.ValidatingPolicyKind, policiesv1beta1.NamespacedValidatingPolicyKind:
+			result.IsValidatingPolicy = true
+		case "MutatingPolicy", "NamespacedMutatingPolicy":
+			result.IsMutatingPolicy = true
+		case policiesv1beta1.GeneratingPolicyKind, policiesv1beta1.NamespacedGeneratingPolicyKind:
+			result.IsGeneratingPolicy = true
+		}
+		out.Results = append(out.Results, result)
+	}
+	if test.Variables != "" {
+		issues = append(issues, Issue{
+			Path:    field.NewPath("variables").String(),
+			Message: "values of policies and resources only apply to legacy policies, results depending on them may differ",
+		})
+	}
+	if len(test.Checks) > 0 {
+		out.Checks = nil
+		issues = append(issues, Issue{
+			Path:    field.NewPath("checks").String(),
+			Message: "checks match rule names and can't be converted, they are dropped",
+		})
+	}
+	if len(test.PolicyExceptions) > 0 {
+		out.PolicyExceptions = nil
+		issues = append(issues, Issue{
+			Path:    field.NewPath("exceptions").String(),
+			Message: fmt.Sprintf("%d exception files target legacy policies and are dropped, use CEL policy exceptions instead", len(test.PolicyExceptions)),
+		})
+	}
+	return out, issues
+}
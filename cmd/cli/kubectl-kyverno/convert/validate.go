@@ -0,0 +1,209 @@
+package convert
+
+import (
+	"fmt"
+	"strings"
+
+	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	policiesv1beta1 "github.com/kyverno/kyverno/api/policies.kyverno.io/v1beta1"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func (c *ruleConverter) validatingPolicy(common common) runtime.Object {
+	path := c.path.Child("validate")
+	validation := c.rule.Validation
+	switch {
+	case c.rule.HasValidatePodSecurity():
+		c.report(unsupported(path.Child("podSecurity"), "pod security rules can't be converted, use the pod security admission instead"))
+		return nil
+	case c.rule.HasVerifyManifests():
+		c.report(unsupported(path.Child("manifests"), "manifest verification can't be converted"))
+		return nil
+	case c.rule.HasValidateAssert():
+		c.report(unsupported(path.Child("assert"), "assertion trees can't be converted"))
+		return nil
+	}
+	if len(validation.FailureActionOverrides) > 0 || len(c.policy.GetSpec().ValidationFailureActionOverrides) > 0 {
+		c.report(unsupported(path.Child("failureActionOverrides"), "failure action overrides have no equivalent, use separate policies per namespace instead"))
+	}
+	if !c.rule.HasValidateAllowExistingViolations() {
+		c.report(unsupported(path.Child("allowExistingViolations"), "allowExistingViolations false has no equivalent"))
+	}
+	spec := policiesv1beta1.ValidatingPolicySpec{
+		MatchConstraints: common.matchConstraints,
+		MatchConditions:  common.matchConditions,
+		Variables:        common.variables,
+		FailurePolicy:    c.failurePolicy(),
+		ValidationAction: c.validationActions(),
+	}
+	if controllers := c.podControllers(); controllers != nil {
+		spec.AutogenConfiguration = &policiesv1beta1.ValidatingPolicyAutogenConfiguration{PodControllers: controllers}
+	}
+	spec.WebhookConfiguration = c.webhookConfiguration()
+	if admission, background := c.admission(), c.background(); admission != nil || background != nil {
+		spec.EvaluationConfiguration = &policiesv1beta1.EvaluationConfiguration{
+			Admission:  admission,
+			Background: background,
+		}
+	}
+	if c.rule.HasValidateCEL() {
+		cel := validation.CEL
+		if cel.ParamKind != nil || cel.ParamRef != nil {
+			c.report(unsupported(path.Child("cel", "paramKind"), "parameters can't be converted, use variables instead"))
+		}
+		spec.Variables = append(spec.Variables, cel.Variables...)
+		spec.AuditAnnotations = cel.AuditAnnotations
+		for _, expression := range cel.Expressions {
+			expression.Expression = common.guarded(expression.Expression)
+			spec.Validations = append(spec.Validations, expression)
+		}
+	} else {
+		anchors, expr, err := c.validation(path, c.scope, *validation)
+		c.report(err)
+		if err == nil {
+			// resources skipped because of the anchors of the patterns are not matched, unless
+			// the anchors use variables that are not available in match conditions
+			if anchors != "" && !strings.Contains(anchors, "variables.") {
+				spec.MatchConditions = append(spec.MatchConditions, admissionregistrationv1.MatchCondition{
+					Name:       "anchors",
+					Expression: anchors,
+				})
+			} else if anchors != "" {
+				expr = or(not(anchors), expr)
+			}
+			message, messageExpression, err := c.message(path.Child("message"), validation.Message)
+			c.report(err)
+			spec.Validations = append(spec.Validations, admissionregistrationv1.Validation{
+				Expression:        common.guarded(expr),
+				Message:           message,
+				MessageExpression: messageExpression,
+			})
+		}
+	}
+	if c.policy.IsNamespaced() {
+		return &policiesv1beta1.NamespacedValidatingPolicy{
+			TypeMeta:   typeMeta(policiesv1beta1.NamespacedValidatingPolicyKind),
+			ObjectMeta: c.objectMeta(),
+			Spec:       spec,
+		}
+	}
+	return &policiesv1beta1.ValidatingPolicy{
+		TypeMeta:   typeMeta(policiesv1beta1.ValidatingPolicyKind),
+		ObjectMeta: c.objectMeta(),
+		Spec:       spec,
+	}
+}
+
+// validationActions translates the failure action of the rule, audit rules emit warnings when the policy asks for them.
+func (c *ruleConverter) validationActions() []admissionregistrationv1.ValidationAction {
+	spec := c.policy.GetSpec()
+	action := spec.ValidationFailureAction
+	if c.rule.Validation.FailureAction != nil {
+		action = *c.rule.Validation.FailureAction
+	}
+	if action.Enforce() {
+		return []admissionregistrationv1.ValidationAction{admissionregistrationv1.Deny}
+	}
+	actions := []admissionregistrationv1.ValidationAction{admissionregistrationv1.Audit}
+	if spec.EmitWarning != nil && *spec.EmitWarning {
+		actions = append(actions, admissionregistrationv1.Warn)
+	}
+	return actions
+}
+
+// message returns a static message, or a message expression when the message contains variables.
+func (c *ruleConverter) message(path *field.Path, message string) (string, string, error) {
+	if !hasVariables(message) {
+		return message, "", nil
+	}
+	expr, err := c.scope.stringValue(path, message)
+	if err != nil {
+		return "", "", err
+	}
+	return "", expr, nil
+}
+
+// validation translates a pattern, anyPattern, deny or foreach validation to a CEL expression holding when the resource is valid,
+// patterns also return the guard holding when they are not skipped because of their anchors.
+func (c *ruleConverter) validation(path *field.Path, scope scope, validation kyvernov1.Validation) (string, string, error) {
+	switch {
+	case validation.RawPattern != nil:
+		return scope.guardedPattern(path.Child("pattern"), "object", validation.GetPattern())
+	case validation.RawAnyPattern != nil:
+		patterns, err := validation.DeserializeAnyPattern()
+		if err != nil {
+			return "", "", unsupported(path.Child("anyPattern"), "%s", err)
+		}
+		return scope.guardedAnyPattern(path.Child("anyPattern"), "object", patterns)
+	case validation.Deny != nil:
+		expr, err := deny(path.Child("deny"), scope, validation.Deny)
+		return "", expr, err
+	case len(validation.ForEachValidation) > 0:
+		expr, err := c.foreach(path.Child("foreach"), scope, validation.ForEachValidation)
+		return "", expr, err
+	}
+	return "", "", unsupported(path, "validation has no pattern, anyPattern, deny, foreach or cel")
+}
+
+func deny(path *field.Path, scope scope, deny *kyvernov1.Deny) (string, error) {
+	conditions := deny.GetAnyAllConditions()
+	if conditions == nil {
+		return "false", nil
+	}
+	expr, err := scope.conditions(path.Child("conditions"), conditions)
+	if err != nil {
+		return "", err
+	}
+	return not(expr), nil
+}
+
+// foreach translates foreach validations, every element of every list must be valid.
+func (c *ruleConverter) foreach(path *field.Path, scope scope, foreach []kyvernov1.ForEachValidation) (string, error) {
+	exprs := make([]string, 0, len(foreach))
+	for i, item := range foreach {
+		path := path.Index(i)
+		if len(item.Context) > 0 {
+			return "", unsupported(path.Child("context"), "context entries in foreach can't be converted")
+		}
+		if item.ElementScope != nil && !*item.ElementScope {
+			return "", unsupported(path.Child("elementScope"), "elementScope false can't be converted")
+		}
+		list, err := scope.path(path.Child("list"), item.List, "[]")
+		if err != nil {
+			return "", err
+		}
+		scope, element := scope.withElement()
+		var check string
+		switch {
+		case item.RawPattern != nil:
+			check, err = scope.pattern(path.Child("pattern"), element, item.GetPattern())
+		case item.RawAnyPattern != nil:
+			patterns, ok := item.GetAnyPattern().([]any)
+			if !ok {
+				err = unsupported(path.Child("anyPattern"), "anyPattern must be a list")
+			} else {
+				check, err = scope.anyPattern(path.Child("anyPattern"), element, patterns)
+			}
+		case item.Deny != nil:
+			check, err = deny(path.Child("deny"), scope, item.Deny)
+		case item.ForEachValidation != nil:
+			check, err = c.foreach(path.Child("foreach"), scope, item.GetForEachValidation())
+		default:
+			err = unsupported(path, "foreach has no pattern, anyPattern, deny or foreach")
+		}
+		if err != nil {
+			return "", err
+		}
+		if item.AnyAllConditions != nil {
+			preconditions, err := scope.conditions(path.Child("preconditions"), item.AnyAllConditions)
+			if err != nil {
+				return "", err
+			}
+			check = or(not(preconditions), check)
+		}
+		exprs = append(exprs, fmt.Sprintf("%s.all(%s, %s)", parenthesize(list), element, check))
+	}
+	return and(exprs...), nil
+}
@@ -0,0 +1,322 @@
+package coverage
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	policiesv1beta1 "github.com/kyverno/kyverno/api/policies.kyverno.io/v1beta1"
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/policy"
+	celcompiler "github.com/kyverno/kyverno/pkg/cel/compiler"
+	engineapi "github.com/kyverno/kyverno/pkg/engine/api"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Counts records the outcomes of the evaluations of a policy, rule or expression.
+type Counts struct {
+	Evaluated int `json:"evaluated"`
+	Pass      int `json:"pass"`
+	Fail      int `json:"fail"`
+	Skip      int `json:"skip"`
+	Error     int `json:"error"`
+}
+
+// Entry is the coverage of a policy, of one of its rules or of one of its CEL expressions.
+// Rule and Expression are empty for policy entries.
+type Entry struct {
+	Kind       string `json:"kind"`
+	Policy     string `json:"policy"`
+	Rule       string `json:"rule,omitempty"`
+	Expression string `json:"expression,omitempty"`
+	Counts
+}
+
+// Covered returns true if the entry was evaluated at least once.
+func (e Entry) Covered() bool {
+	return e.Evaluated > 0
+}
+
+func (e Entry) key() string {
+	return e.Kind + "/" + e.Policy + "/" + e.Rule + "/" + e.Expression
+}
+
+// Report is the coverage computed across all tests.
+type Report struct {
+	Entries    []Entry `json:"entries"`
+	Total      int     `json:"total"`
+	Covered    int     `json:"covered"`
+	Percentage float64 `json:"percentage"`
+}
+
+// Collector accumulates the policies loaded by tests and the outcomes of their evaluations.
+// It implements celcompiler.Observer to record the outcomes of CEL policy expressions.
+type Collector struct {
+	lock    sync.Mutex
+	entries map[string]*Entry
+	order   []string
+}
+
+var _ celcompiler.Observer = &Collector{}
+
+func NewCollector() *Collector {
+	return &Collector{
+		entries: map[string]*Entry{},
+	}
+}
+
+// AddPolicies registers the policies that should be covered, policies loaded by several tests are registered once.
+func (c *Collector) AddPolicies(results *policy.LoaderResults) {
+	if results == nil {
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for _, pol := range results.Policies {
+		name := policyKey(pol)
+		c.register(Entry{Kind: pol.GetKind(), Policy: name})
+		for _, rule := range pol.GetSpec().Rules {
+			c.register(Entry{Kind: pol.GetKind(), Policy: name, Rule: rule.Name})
+		}
+	}
+	for i := range results.ValidatingPolicies {
+		c.registerValidatingPolicy(&results.ValidatingPolicies[i])
+	}
+	for i := range results.NamespacedValidatingPolicies {
+		c.registerValidatingPolicy(&results.NamespacedValidatingPolicies[i])
+	}
+	for i := range results.MutatingPolicies {
+		c.registerMutatingPolicy(&results.MutatingPolicies[i])
+	}
+	for i := range results.NamespacedMutatingPolicies {
+		c.registerMutatingPolicy(&results.NamespacedMutatingPolicies[i])
+	}
+	for i := range results.ImageValidatingPolicies {
+		c.registerImageValidatingPolicy(&results.ImageValidatingPolicies[i])
+	}
+	for i := range results.NamespacedImageValidatingPolicies {
+		c.registerImageValidatingPolicy(&results.NamespacedImageValidatingPolicies[i])
+	}
+	for i := range results.GeneratingPolicies {
+		c.registerGeneratingPolicy(&results.GeneratingPolicies[i])
+	}
+	for i := range results.DeletingPolicies {
+		c.registerDeletingPolicy(&results.DeletingPolicies[i])
+	}
+	for i := range results.NamespacedDeletingPolicies {
+		c.registerDeletingPolicy(&results.NamespacedDeletingPolicies[i])
+	}
+	// admission policies are covered at the policy level
+	for i := range results.VAPs {
+		c.register(Entry{Kind: "ValidatingAdmissionPolicy", Policy: policyKey(&results.VAPs[i])})
+	}
+	for i := range results.MAPs {
+		c.register(Entry{Kind: "MutatingAdmissionPolicy", Policy: policyKey(&results.MAPs[i])})
+	}
+}
+
+// AddEngineResponses records the outcomes of the rules evaluated by the engine,
+// responses without rule responses are policies that didn't match the resource and aren't recorded.
+func (c *Collector) AddEngineResponses(responses ...engineapi.EngineResponse) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for _, response := range responses {
+		pol := response.Policy()
+		if pol == nil || len(response.PolicyResponse.Rules) == 0 {
+			continue
+		}
+		kind := pol.GetKind()
+		name := policyKey(pol)
+		if entry := c.entries[Entry{Kind: kind, Policy: name}.key()]; entry != nil {
+			entry.add(policyStatus(response.PolicyResponse.Rules))
+		}
+		if pol.AsKyvernoPolicy() == nil {
+			continue
+		}
+		for _, rule := range response.PolicyResponse.Rules {
+			if entry := c.ruleEntry(kind, name, rule.Name()); entry != nil {
+				entry.add(rule.Status())
+			}
+		}
+	}
+}
+
+// Expression implements celcompiler.Observer, expressions of policies that weren't registered are ignored.
+func (c *Collector) Expression(kind, policy, expression string, passed bool, err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	entry := c.entries[Entry{Kind: kind, Policy: policy, Expression: expression}.key()]
+	if entry == nil {
+		return
+	}
+	switch {
+	case err != nil:
+		entry.add(engineapi.RuleStatusError)
+	case passed:
+		entry.add(engineapi.RuleStatusPass)
+	default:
+		entry.add(engineapi.RuleStatusFail)
+	}
+}
+
+// Merge adds the entries and counts of other, tests run concurrently record their coverage in collectors of their own
+// that are merged in the order of the tests so that the report doesn't depend on the order they complete.
+func (c *Collector) Merge(other *Collector) {
+	if other == nil {
+		return
+	}
+	other.lock.Lock()
+	defer other.lock.Unlock()
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for _, key := range other.order {
+		entry := *other.entries[key]
+		if existing, ok := c.entries[key]; ok {
+			existing.merge(entry.Counts)
+			continue
+		}
+		c.entries[key] = &entry
+		c.order = append(c.order, key)
+	}
+}
+
+// Report returns the coverage of the registered policies, entries are sorted by kind and policy.
+func (c *Collector) Report() Report {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	report := Report{
+		Entries: make([]Entry, 0, len(c.order)),
+	}
+	for _, key := range c.order {
+		entry := *c.entries[key]
+		report.Entries = append(report.Entries, entry)
+		if entry.Covered() {
+			report.Covered++
+		}
+	}
+	sort.SliceStable(report.Entries, func(i, j int) bool {
+		if report.Entries[i].Kind != report.Entries[j].Kind {
+			return report.Entries[i].Kind < report.Entries[j].Kind
+		}
+		return report.Entries[i].Policy < report.Entries[j].Policy
+	})
+	report.Total = len(report.Entries)
+	report.Percentage = 100
+	if report.Total > 0 {
+		report.Percentage = float64(report.Covered) * 100 / float64(report.Total)
+	}
+	return report
+}
+
+func (c *Collector) register(entry Entry) {
+	key := entry.key()
+	if _, ok := c.entries[key]; ok {
+		return
+	}
+	c.entries[key] = &entry
+	c.order = append(c.order, key)
+}
+
+func (c *Collector) registerValidatingPolicy(pol policiesv1beta1.ValidatingPolicyLike) {
+	c.registerExpressions(pol, pol.GetMatchConditions(), pol.GetVariables(), "validations", len(pol.GetValidatingPolicySpec().Validations))
+}
+
+func (c *Collector) registerMutatingPolicy(pol policiesv1beta1.MutatingPolicyLike) {
+	c.registerExpressions(pol, pol.GetMatchConditions(), pol.GetVariables(), "mutations", len(pol.GetSpec().Mutations))
+}
+
+func (c *Collector) registerImageValidatingPolicy(pol policiesv1beta1.ImageValidatingPolicyLike) {
+	c.registerExpressions(pol, pol.GetMatchConditions(), pol.GetVariables(), "validations", len(pol.GetSpec().Validations))
+}
+
+func (c *Collector) registerGeneratingPolicy(pol policiesv1beta1.GeneratingPolicyLike) {
+	c.registerExpressions(pol, pol.GetMatchConditions(), pol.GetVariables(), "generate", len(pol.GetSpec().Generation))
+}
+
+func (c *Collector) registerDeletingPolicy(pol policiesv1beta1.DeletingPolicyLike) {
+	spec := pol.GetDeletingPolicySpec()
+	c.registerExpressions(pol, nil, spec.Variables, "conditions", len(spec.Conditions))
+}
+
+// registerExpressions registers a CEL policy with its match conditions, its variables and the count
+// expressions of the field named expressions, like validations or mutations.
+func (c *Collector) registerExpressions(
+	pol interface {
+		metav1.Object
+		GetKind() string
+	},
+	matchConditions []admissionregistrationv1.MatchCondition,
+	variables []admissionregistrationv1.Variable,
+	expressions string,
+	count int,
+) {
+	kind := pol.GetKind()
+	name := policyKey(pol)
+	c.register(Entry{Kind: kind, Policy: name})
+	for i := range matchConditions {
+		c.register(Entry{Kind: kind, Policy: name, Expression: fmt.Sprintf("matchConditions[%d]", i)})
+	}
+	for _, variable := range variables {
+		c.register(Entry{Kind: kind, Policy: name, Expression: "variables." + variable.Name})
+	}
+	for i := 0; i < count; i++ {
+		c.register(Entry{Kind: kind, Policy: name, Expression: fmt.Sprintf("%s[%d]", expressions, i)})
+	}
+}
+
+// ruleEntry returns the entry of a declared rule, responses of auto-generated rules are recorded for the rule they were generated from.
+func (c *Collector) ruleEntry(kind, policy, rule string) *Entry {
+	for _, name := range []string{rule, strings.TrimPrefix(rule, "autogen-cronjob-"), strings.TrimPrefix(rule, "autogen-")} {
+		if entry := c.entries[Entry{Kind: kind, Policy: policy, Rule: name}.key()]; entry != nil {
+			return entry
+		}
+	}
+	return nil
+}
+
+func (e *Entry) add(status engineapi.RuleStatus) {
+	e.Evaluated++
+	switch status {
+	case engineapi.RuleStatusPass:
+		e.Pass++
+	case engineapi.RuleStatusFail, engineapi.RuleStatusWarn:
+		e.Fail++
+	case engineapi.RuleStatusSkip:
+		e.Skip++
+	case engineapi.RuleStatusError:
+		e.Error++
+	}
+}
+
+func (c *Counts) merge(other Counts) {
+	c.Evaluated += other.Evaluated
+	c.Pass += other.Pass
+	c.Fail += other.Fail
+	c.Skip += other.Skip
+	c.Error += other.Error
+}
+
+// policyStatus aggregates the status of the rules of a policy, failures and errors take precedence over passes.
+func policyStatus(rules []engineapi.RuleResponse) engineapi.RuleStatus {
+	status := engineapi.RuleStatusSkip
+	for _, rule := range rules {
+		switch rule.Status() {
+		case engineapi.RuleStatusFail, engineapi.RuleStatusWarn:
+			return engineapi.RuleStatusFail
+		case engineapi.RuleStatusError:
+			status = engineapi.RuleStatusError
+		case engineapi.RuleStatusPass:
+			if status != engineapi.RuleStatusError {
+				status = engineapi.RuleStatusPass
+			}
+		}
+	}
+	return status
+}
+
+func policyKey(pol metav1.Object) string {
+	key, _ := cache.MetaNamespaceKeyFunc(pol)
+	return key
+}
@@ -0,0 +1,186 @@
+package coverage
+
+import (
+	"context"
+	"testing"
+
+	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	policiesv1beta1 "github.com/kyverno/kyverno/api/policies.kyverno.io/v1beta1"
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/policy"
+	celcompiler "github.com/kyverno/kyverno/pkg/cel/compiler"
+	"github.com/kyverno/kyverno/pkg/cel/libs"
+	dpolcompiler "github.com/kyverno/kyverno/pkg/cel/policies/dpol/compiler"
+	vpolcompiler "github.com/kyverno/kyverno/pkg/cel/policies/vpol/compiler"
+	engineapi "github.com/kyverno/kyverno/pkg/engine/api"
+	"github.com/stretchr/testify/assert"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newClusterPolicy(rules ...string) *kyvernov1.ClusterPolicy {
+	pol := &kyvernov1.ClusterPolicy{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "kyverno.io/v1", Kind: "ClusterPolicy"},
+		ObjectMeta: metav1.ObjectMeta{Name: "require-labels"},
+	}
+	for _, rule := range rules {
+		pol.Spec.Rules = append(pol.Spec.Rules, kyvernov1.Rule{Name: rule})
+	}
+	return pol
+}
+
+func newValidatingPolicy() *policiesv1beta1.ValidatingPolicy {
+	mode := policiesv1beta1.EvaluationModeJSON
+	return &policiesv1beta1.ValidatingPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "check-replicas"},
+		Spec: policiesv1beta1.ValidatingPolicySpec{
+			EvaluationConfiguration: &policiesv1beta1.EvaluationConfiguration{Mode: mode},
+			MatchConditions: []admissionregistrationv1.MatchCondition{{
+				Name:       "is-deployment",
+				Expression: "object.kind == 'Deployment'",
+			}},
+			Variables: []admissionregistrationv1.Variable{{
+				Name:       "replicas",
+				Expression: "object.replicas",
+			}, {
+				Name:       "unused",
+				Expression: "object.unused",
+			}},
+			Validations: []admissionregistrationv1.Validation{{
+				Expression: "variables.replicas > 1",
+			}, {
+				Expression: "variables.replicas < 10",
+			}},
+		},
+	}
+}
+
+func find(report Report, rule, expression string) *Entry {
+	for i := range report.Entries {
+		if report.Entries[i].Rule == rule && report.Entries[i].Expression == expression {
+			return &report.Entries[i]
+		}
+	}
+	return nil
+}
+
+func TestCollector_AddEngineResponses(t *testing.T) {
+	pol := newClusterPolicy("check-team", "check-app", "check-env")
+	collector := NewCollector()
+	collector.AddPolicies(&policy.LoaderResults{Policies: []kyvernov1.PolicyInterface{pol}})
+	// policies loaded by several tests are registered once
+	collector.AddPolicies(&policy.LoaderResults{Policies: []kyvernov1.PolicyInterface{pol}})
+	response := func(rules ...engineapi.RuleResponse) engineapi.EngineResponse {
+		return engineapi.NewEngineResponse(unstructured.Unstructured{}, engineapi.NewKyvernoPolicy(pol), nil).
+			WithPolicyResponse(engineapi.PolicyResponse{Rules: rules})
+	}
+	collector.AddEngineResponses(
+		response(
+			*engineapi.RulePass("check-team", engineapi.Validation, "", nil),
+			*engineapi.RuleFail("autogen-check-app", engineapi.Validation, "", nil),
+		),
+		response(
+			*engineapi.RuleSkip("autogen-cronjob-check-team", engineapi.Validation, "", nil),
+		),
+		// the policy didn't match
+		response(),
+	)
+	report := collector.Report()
+	assert.Equal(t, 4, report.Total)
+	assert.Equal(t, 3, report.Covered)
+	assert.Equal(t, 75.0, report.Percentage)
+	assert.Equal(t, Counts{Evaluated: 2, Fail: 1, Skip: 1}, find(report, "", "").Counts)
+	assert.Equal(t, Counts{Evaluated: 2, Pass: 1, Skip: 1}, find(report, "check-team", "").Counts)
+	assert.Equal(t, Counts{Evaluated: 1, Fail: 1}, find(report, "check-app", "").Counts)
+	assert.False(t, find(report, "check-env", "").Covered())
+}
+
+func TestCollector_Observer(t *testing.T) {
+	pol := newValidatingPolicy()
+	collector := NewCollector()
+	collector.AddPolicies(&policy.LoaderResults{ValidatingPolicies: []policiesv1beta1.ValidatingPolicy{*pol}})
+	compiled, errs := vpolcompiler.NewCompiler().Compile(pol, nil)
+	assert.Empty(t, errs)
+	ctx := celcompiler.WithObserver(context.TODO(), collector)
+	for _, object := range []map[string]any{
+		{"kind": "Deployment", "replicas": int64(1)},
+		{"kind": "Deployment", "replicas": int64(3)},
+		{"kind": "Service"},
+	} {
+		_, err := compiled.Evaluate(ctx, object, nil, nil, nil, nil)
+		assert.NoError(t, err)
+	}
+	report := collector.Report()
+	assert.Equal(t, 6, report.Total)
+	assert.Equal(t, Counts{Evaluated: 3, Pass: 2, Fail: 1}, find(report, "", "matchConditions[0]").Counts)
+	assert.Equal(t, Counts{Evaluated: 2, Pass: 2}, find(report, "", "variables.replicas").Counts)
+	assert.Equal(t, Counts{Evaluated: 2, Pass: 1, Fail: 1}, find(report, "", "validations[0]").Counts)
+	assert.Equal(t, Counts{Evaluated: 1, Pass: 1}, find(report, "", "validations[1]").Counts)
+	assert.False(t, find(report, "", "variables.unused").Covered())
+	// the policy entry is recorded from engine responses
+	assert.False(t, find(report, "", "").Covered())
+	assert.Equal(t, 4, report.Covered)
+}
+
+func TestCollector_Observer_deletingPolicy(t *testing.T) {
+	pol := &policiesv1beta1.DeletingPolicy{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "policies.kyverno.io/v1beta1", Kind: "DeletingPolicy"},
+		ObjectMeta: metav1.ObjectMeta{Name: "cleanup-pods"},
+		Spec: policiesv1beta1.DeletingPolicySpec{
+			Schedule: "*/5 * * * *",
+			Conditions: []admissionregistrationv1.MatchCondition{{
+				Name:       "is-expired",
+				Expression: "object.expired == true",
+			}},
+			Variables: []admissionregistrationv1.Variable{{
+				Name:       "unused",
+				Expression: "object.unused",
+			}},
+		},
+	}
+	collector := NewCollector()
+	collector.AddPolicies(&policy.LoaderResults{DeletingPolicies: []policiesv1beta1.DeletingPolicy{*pol}})
+	compiled, errs := dpolcompiler.NewCompiler().Compile(pol, nil)
+	assert.Empty(t, errs)
+	ctx := celcompiler.WithObserver(context.TODO(), collector)
+	for _, expired := range []bool{true, false} {
+		object := unstructured.Unstructured{Object: map[string]any{"expired": expired}}
+		_, err := compiled.Evaluate(ctx, object, &unstructured.Unstructured{}, &libs.FakeContextProvider{})
+		assert.NoError(t, err)
+	}
+	report := collector.Report()
+	assert.Equal(t, 3, report.Total)
+	assert.Equal(t, Counts{Evaluated: 2, Pass: 1, Fail: 1}, find(report, "", "conditions[0]").Counts)
+	assert.False(t, find(report, "", "variables.unused").Covered())
+}
+
+func TestCollector_Merge(t *testing.T) {
+	pol := newClusterPolicy("check-team", "check-app")
+	run := func(rules ...engineapi.RuleResponse) *Collector {
+		collector := NewCollector()
+		collector.AddPolicies(&policy.LoaderResults{Policies: []kyvernov1.PolicyInterface{pol}})
+		collector.AddEngineResponses(
+			engineapi.NewEngineResponse(unstructured.Unstructured{}, engineapi.NewKyvernoPolicy(pol), nil).
+				WithPolicyResponse(engineapi.PolicyResponse{Rules: rules}),
+		)
+		return collector
+	}
+	collector := NewCollector()
+	collector.Merge(run(*engineapi.RulePass("check-team", engineapi.Validation, "", nil)))
+	collector.Merge(run(*engineapi.RuleFail("check-team", engineapi.Validation, "", nil)))
+	// runs that weren't collected are ignored
+	collector.Merge(nil)
+	report := collector.Report()
+	assert.Equal(t, 3, report.Total)
+	assert.Equal(t, 2, report.Covered)
+	assert.Equal(t, Counts{Evaluated: 2, Pass: 1, Fail: 1}, find(report, "", "").Counts)
+	assert.Equal(t, Counts{Evaluated: 2, Pass: 1, Fail: 1}, find(report, "check-team", "").Counts)
+	assert.False(t, find(report, "check-app", "").Covered())
+}
+
+func TestCollector_Report(t *testing.T) {
+	report := NewCollector().Report()
+	assert.Equal(t, 0, report.Total)
+	assert.Equal(t, 100.0, report.Percentage)
+	assert.NotNil(t, report.Entries)
+}
@@ -0,0 +1,201 @@
+package sarif
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/go-git/go-billy/v5"
+	"github.com/go-git/go-billy/v5/util"
+	"gopkg.in/yaml.v3"
+)
+
+// ResourceIndex locates resources in the files they were loaded from.
+type ResourceIndex struct {
+	entries map[string][]indexEntry
+}
+
+type indexEntry struct {
+	path      string
+	namespace string
+	node      *yaml.Node
+}
+
+// NewResourceIndex indexes the yaml documents found in the given files and directories.
+// Files are read from fs or from the local file system when fs is nil, unreadable files are ignored.
+func NewResourceIndex(fs billy.Filesystem, paths ...string) *ResourceIndex {
+	index := &ResourceIndex{
+		entries: map[string][]indexEntry{},
+	}
+	for _, path := range paths {
+		if path == "" || path == "-" {
+			continue
+		}
+		for _, file := range listFiles(fs, path) {
+			data, err := readFile(fs, file)
+			if err != nil {
+				continue
+			}
+			index.Add(file, data)
+		}
+	}
+	return index
+}
+
+// Add indexes the yaml documents contained in data, read from the file at path.
+func (i *ResourceIndex) Add(path string, data []byte) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var document yaml.Node
+		// stop at the end of the file or at an invalid document, the following ones can't be located reliably
+		if err := decoder.Decode(&document); err != nil {
+			return
+		}
+		if len(document.Content) == 0 {
+			continue
+		}
+		node := document.Content[0]
+		kind := scalar(node, "kind")
+		metadata := field(node, "metadata")
+		name := scalar(metadata, "name")
+		if kind == "" || name == "" {
+			continue
+		}
+		key := kind + "/" + name
+		i.entries[key] = append(i.entries[key], indexEntry{
+			path:      filepath.ToSlash(path),
+			namespace: scalar(metadata, "namespace"),
+			node:      node,
+		})
+	}
+}
+
+// Lookup returns the location of a resource, the region points to the field at fieldPath when it exists
+// and to the beginning of the resource otherwise. It returns nil if the resource is not indexed.
+func (i *ResourceIndex) Lookup(kind, namespace, name string, fieldPath []string) *PhysicalLocation {
+	if i == nil {
+		return nil
+	}
+	entries := i.entries[kind+"/"+name]
+	if len(entries) == 0 {
+		return nil
+	}
+	// resources without a namespace in their file are defaulted when loaded
+	entry := entries[0]
+	for _, e := range entries {
+		if e.namespace == namespace {
+			entry = e
+			break
+		}
+		if e.namespace == "" {
+			entry = e
+		}
+	}
+	return &PhysicalLocation{
+		ArtifactLocation: ArtifactLocation{URI: entry.path},
+		Region:           &Region{StartLine: fieldLine(entry.node, fieldPath)},
+	}
+}
+
+var anchorRegex = regexp.MustCompile(`^[=X^+<]?\((.+)\)$`)
+
+// fieldLine returns the line of the deepest field of path found in node.
+func fieldLine(node *yaml.Node, path []string) int {
+	line := node.Line
+	for _, segment := range path {
+		if match := anchorRegex.FindStringSubmatch(segment); match != nil {
+			segment = match[1]
+		}
+		var next *yaml.Node
+		switch node.Kind {
+		case yaml.MappingNode:
+			for j := 0; j+1 < len(node.Content); j += 2 {
+				if node.Content[j].Value == segment {
+					line = node.Content[j].Line
+					next = node.Content[j+1]
+					break
+				}
+			}
+		case yaml.SequenceNode:
+			if index, ok := sequenceIndex(segment); ok && index < len(node.Content) {
+				next = node.Content[index]
+				line = next.Line
+			}
+		}
+		if next == nil {
+			break
+		}
+		node = next
+	}
+	return line
+}
+
+func field(node *yaml.Node, name string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for j := 0; j+1 < len(node.Content); j += 2 {
+		if node.Content[j].Value == name {
+			return node.Content[j+1]
+		}
+	}
+	return nil
+}
+
+func scalar(node *yaml.Node, name string) string {
+	value := field(node, name)
+	if value == nil || value.Kind != yaml.ScalarNode {
+		return ""
+	}
+	return value.Value
+}
+
+func isResourceFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml", ".json":
+		return true
+	}
+	return false
+}
+
+func listFiles(fs billy.Filesystem, path string) []string {
+	var files []string
+	walk := func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if !info.IsDir() && isResourceFile(path) {
+			files = append(files, path)
+		}
+		return nil
+	}
+	if fs != nil {
+		info, err := fs.Stat(path)
+		if err != nil {
+			return nil
+		}
+		if !info.IsDir() {
+			return []string{path}
+		}
+		_ = util.Walk(fs, path, walk)
+		return files
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil
+	}
+	if !info.IsDir() {
+		return []string{path}
+	}
+	_ = filepath.Walk(path, walk)
+	return files
+}
+
+func readFile(fs billy.Filesystem, path string) ([]byte, error) {
+	if fs != nil {
+		return util.ReadFile(fs, path)
+	}
+	return os.ReadFile(path)
+}
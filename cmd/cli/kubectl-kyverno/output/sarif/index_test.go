@@ -0,0 +1,125 @@
+package sarif
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const resources = `apiVersion: v1
+kind: Pod
+metadata:
+  name: nginx
+  namespace: prod
+spec:
+  containers:
+  - name: nginx
+    image: nginx:latest
+  - name: sidecar
+    image: busybox:1.36
+    securityContext:
+      privileged: true
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: nginx
+spec:
+  containers:
+  - name: nginx
+    image: nginx:1.27
+`
+
+func TestResourceIndex_Lookup(t *testing.T) {
+	index := NewResourceIndex(nil)
+	index.Add("resources.yaml", []byte(resources))
+	tests := []struct {
+		name      string
+		kind      string
+		namespace string
+		fieldPath []string
+		wantLine  int
+		wantNil   bool
+	}{{
+		name:      "resource",
+		kind:      "Pod",
+		namespace: "prod",
+		wantLine:  1,
+	}, {
+		name:      "field",
+		kind:      "Pod",
+		namespace: "prod",
+		fieldPath: []string{"spec", "containers", "0", "image"},
+		wantLine:  9,
+	}, {
+		name:      "anchored field",
+		kind:      "Pod",
+		namespace: "prod",
+		fieldPath: []string{"spec", "containers", "1", "=(securityContext)", "privileged"},
+		wantLine:  13,
+	}, {
+		name:      "missing field",
+		kind:      "Pod",
+		namespace: "prod",
+		fieldPath: []string{"spec", "containers", "0", "securityContext", "privileged"},
+		wantLine:  8,
+	}, {
+		name:      "defaulted namespace",
+		kind:      "Pod",
+		namespace: "default",
+		fieldPath: []string{"spec", "containers", "0", "image"},
+		wantLine:  22,
+	}, {
+		name:    "missing resource",
+		kind:    "Deployment",
+		wantNil: true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := index.Lookup(tt.kind, tt.namespace, "nginx", tt.fieldPath)
+			if tt.wantNil {
+				assert.Nil(t, got)
+				return
+			}
+			assert.NotNil(t, got)
+			assert.Equal(t, "resources.yaml", got.ArtifactLocation.URI)
+			assert.Equal(t, tt.wantLine, got.Region.StartLine)
+		})
+	}
+	var nilIndex *ResourceIndex
+	assert.Nil(t, nilIndex.Lookup("Pod", "prod", "nginx", nil))
+}
+
+func TestNewResourceIndex(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "nested"), 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "nested", "pod.yaml"), []byte(resources), 0o600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("kind: Pod"), 0o600))
+	index := NewResourceIndex(nil, dir, "-", filepath.Join(dir, "missing.yaml"))
+	got := index.Lookup("Pod", "prod", "nginx", nil)
+	assert.NotNil(t, got)
+	assert.Equal(t, filepath.ToSlash(filepath.Join(dir, "nested", "pod.yaml")), got.ArtifactLocation.URI)
+}
+
+func TestFieldPath(t *testing.T) {
+	tests := []struct {
+		message string
+		want    []string
+	}{{
+		message: "validation error: rule check-image failed at path /spec/containers/0/image/",
+		want:    []string{"spec", "containers", "0", "image"},
+	}, {
+		message: "resource value 'true' does not match 'false' at path /spec/hostNetwork/",
+		want:    []string{"spec", "hostNetwork"},
+	}, {
+		message: "privileged containers are not allowed",
+		want:    nil,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.message, func(t *testing.T) {
+			assert.Equal(t, tt.want, FieldPath(tt.message))
+		})
+	}
+}
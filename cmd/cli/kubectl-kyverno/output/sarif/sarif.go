@@ -0,0 +1,271 @@
+package sarif
+
+import (
+	"encoding/json"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/kyverno/kyverno/api/kyverno"
+	engineapi "github.com/kyverno/kyverno/pkg/engine/api"
+	"github.com/kyverno/kyverno/pkg/version"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	Version = "2.1.0"
+	Schema  = "https://json.schemastore.org/sarif-2.1.0.json"
+
+	annotationPolicyTitle       = "policies.kyverno.io/title"
+	annotationPolicyDescription = "policies.kyverno.io/description"
+)
+
+type Log struct {
+	Version string `json:"version"`
+	Schema  string `json:"$schema"`
+	Runs    []Run  `json:"runs"`
+}
+
+type Run struct {
+	Tool    Tool     `json:"tool"`
+	Results []Result `json:"results"`
+}
+
+type Tool struct {
+	Driver Driver `json:"driver"`
+}
+
+type Driver struct {
+	Name           string                `json:"name"`
+	Version        string                `json:"version,omitempty"`
+	InformationURI string                `json:"informationUri,omitempty"`
+	Rules          []ReportingDescriptor `json:"rules"`
+}
+
+type ReportingDescriptor struct {
+	ID               string         `json:"id"`
+	Name             string         `json:"name,omitempty"`
+	ShortDescription *Message       `json:"shortDescription,omitempty"`
+	FullDescription  *Message       `json:"fullDescription,omitempty"`
+	Properties       map[string]any `json:"properties,omitempty"`
+}
+
+type Message struct {
+	Text string `json:"text"`
+}
+
+type Result struct {
+	RuleID    string     `json:"ruleId"`
+	RuleIndex int        `json:"ruleIndex"`
+	Level     string     `json:"level"`
+	Message   Message    `json:"message"`
+	Locations []Location `json:"locations,omitempty"`
+}
+
+type Location struct {
+	PhysicalLocation *PhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []LogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type PhysicalLocation struct {
+	ArtifactLocation ArtifactLocation `json:"artifactLocation"`
+	Region           *Region          `json:"region,omitempty"`
+}
+
+type ArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type Region struct {
+	StartLine int `json:"startLine"`
+}
+
+type LogicalLocation struct {
+	Name               string `json:"name,omitempty"`
+	FullyQualifiedName string `json:"fullyQualifiedName,omitempty"`
+	Kind               string `json:"kind,omitempty"`
+}
+
+// Builder accumulates failed and warned rule responses into a SARIF log.
+type Builder struct {
+	rules   []ReportingDescriptor
+	ruleIDs map[string]int
+	results []Result
+}
+
+// NewBuilder returns an empty builder.
+func NewBuilder() *Builder {
+	return &Builder{
+		ruleIDs: map[string]int{},
+	}
+}
+
+// AddEngineResponses adds a result for every failed or warned rule response, resources are located with index which can be nil.
+// Failures of audit policies are reported as warnings when auditWarn is set.
+func (b *Builder) AddEngineResponses(index *ResourceIndex, auditWarn bool, responses ...engineapi.EngineResponse) {
+	for _, response := range responses {
+		policy := response.Policy()
+		if policy == nil {
+			continue
+		}
+		for _, rule := range response.PolicyResponse.Rules {
+			var level string
+			switch rule.Status() {
+			case engineapi.RuleStatusFail:
+				level = "error"
+				if auditWarn && response.GetValidationFailureAction().Audit() {
+					level = "warning"
+				}
+			case engineapi.RuleStatusWarn:
+				level = "warning"
+			default:
+				continue
+			}
+			ruleIndex := b.addRule(policy, rule.Name())
+			b.results = append(b.results, Result{
+				RuleID:    b.rules[ruleIndex].ID,
+				RuleIndex: ruleIndex,
+				Level:     level,
+				Message:   Message{Text: rule.Message()},
+				Locations: locations(index, response, rule.Message()),
+			})
+		}
+	}
+}
+
+// Log returns the SARIF log containing the results added so far.
+func (b *Builder) Log() *Log {
+	rules := b.rules
+	if rules == nil {
+		rules = []ReportingDescriptor{}
+	}
+	results := b.results
+	if results == nil {
+		results = []Result{}
+	}
+	return &Log{
+		Version: Version,
+		Schema:  Schema,
+		Runs: []Run{{
+			Tool: Tool{
+				Driver: Driver{
+					Name:           "kyverno",
+					Version:        version.Version(),
+					InformationURI: "https://kyverno.io",
+					Rules:          rules,
+				},
+			},
+			Results: results,
+		}},
+	}
+}
+
+// Write writes the SARIF log as indented json.
+func (b *Builder) Write(out io.Writer) error {
+	data, err := json.MarshalIndent(b.Log(), "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	_, err = out.Write(data)
+	return err
+}
+
+func (b *Builder) addRule(policy engineapi.GenericPolicy, rule string) int {
+	policyName, _ := cache.MetaNamespaceKeyFunc(policy)
+	id := policyName + "/" + rule
+	if index, ok := b.ruleIDs[id]; ok {
+		return index
+	}
+	annotations := policy.GetAnnotations()
+	descriptor := ReportingDescriptor{
+		ID:   id,
+		Name: rule,
+	}
+	if title := annotations[annotationPolicyTitle]; title != "" {
+		descriptor.ShortDescription = &Message{Text: title}
+	}
+	if description := strings.TrimSpace(annotations[annotationPolicyDescription]); description != "" {
+		descriptor.FullDescription = &Message{Text: description}
+	}
+	properties := map[string]any{}
+	if severity := annotations[kyverno.AnnotationPolicySeverity]; severity != "" {
+		properties["severity"] = severity
+		if score, ok := securitySeverity[severity]; ok {
+			properties["security-severity"] = score
+		}
+	}
+	if category := annotations[kyverno.AnnotationPolicyCategory]; category != "" {
+		properties["category"] = category
+		var tags []string
+		for _, tag := range strings.Split(category, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+		properties["tags"] = tags
+	}
+	if len(properties) != 0 {
+		descriptor.Properties = properties
+	}
+	b.ruleIDs[id] = len(b.rules)
+	b.rules = append(b.rules, descriptor)
+	return len(b.rules) - 1
+}
+
+// securitySeverity maps policy severities to the scores used by code scanning tools to rank results.
+var securitySeverity = map[string]string{
+	"critical": "9.0",
+	"high":     "7.0",
+	"medium":   "5.0",
+	"low":      "3.0",
+	"info":     "0.0",
+}
+
+func locations(index *ResourceIndex, response engineapi.EngineResponse, message string) []Location {
+	resource := response.Resource
+	if resource.Object == nil {
+		return nil
+	}
+	location := Location{
+		LogicalLocations: []LogicalLocation{{
+			Name:               resource.GetName(),
+			FullyQualifiedName: resourceKey(resource.GetKind(), resource.GetNamespace(), resource.GetName()),
+			Kind:               "resource",
+		}},
+	}
+	if physical := index.Lookup(resource.GetKind(), resource.GetNamespace(), resource.GetName(), FieldPath(message)); physical != nil {
+		location.PhysicalLocation = physical
+	}
+	return []Location{location}
+}
+
+var fieldPathRegex = regexp.MustCompile(`at path (/\S*)`)
+
+// FieldPath extracts the path of the offending field from a rule message, it returns nil if the message has no path.
+func FieldPath(message string) []string {
+	match := fieldPathRegex.FindStringSubmatch(message)
+	if match == nil {
+		return nil
+	}
+	var path []string
+	for _, segment := range strings.Split(strings.Trim(match[1], "/"), "/") {
+		if segment != "" {
+			path = append(path, segment)
+		}
+	}
+	return path
+}
+
+func resourceKey(kind, namespace, name string) string {
+	if namespace == "" {
+		return kind + "/" + name
+	}
+	return namespace + "/" + kind + "/" + name
+}
+
+func sequenceIndex(segment string) (int, bool) {
+	index, err := strconv.Atoi(segment)
+	return index, err == nil && index >= 0
+}
@@ -0,0 +1,125 @@
+package sarif
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	engineapi "github.com/kyverno/kyverno/pkg/engine/api"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newResponse(action kyvernov1.ValidationFailureAction, rules ...engineapi.RuleResponse) engineapi.EngineResponse {
+	policy := &kyvernov1.ClusterPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "disallow-latest-tag",
+			Annotations: map[string]string{
+				"policies.kyverno.io/title":       "Disallow Latest Tag",
+				"policies.kyverno.io/description": "The ':latest' tag is mutable.",
+				"policies.kyverno.io/severity":    "medium",
+				"policies.kyverno.io/category":    "Best Practices, EKS Best Practices",
+			},
+		},
+		Spec: kyvernov1.Spec{
+			ValidationFailureAction: action,
+		},
+	}
+	resource := unstructured.Unstructured{}
+	resource.SetAPIVersion("v1")
+	resource.SetKind("Pod")
+	resource.SetNamespace("prod")
+	resource.SetName("nginx")
+	return engineapi.NewEngineResponse(resource, engineapi.NewKyvernoPolicy(policy), nil).
+		WithPolicyResponse(engineapi.PolicyResponse{Rules: rules})
+}
+
+func TestBuilder_AddEngineResponses(t *testing.T) {
+	index := NewResourceIndex(nil)
+	index.Add("resources.yaml", []byte(resources))
+	tests := []struct {
+		name       string
+		action     kyvernov1.ValidationFailureAction
+		auditWarn  bool
+		rules      []engineapi.RuleResponse
+		wantLevels []string
+	}{{
+		name:   "enforce",
+		action: kyvernov1.Enforce,
+		rules: []engineapi.RuleResponse{
+			*engineapi.RuleFail("require-image-tag", engineapi.Validation, "validation error: rule require-image-tag failed at path /spec/containers/0/image/", nil),
+			*engineapi.RulePass("validate-image-tag", engineapi.Validation, "", nil),
+			*engineapi.RuleWarn("validate-image-tag", engineapi.Validation, "latest tag", nil),
+		},
+		wantLevels: []string{"error", "warning"},
+	}, {
+		name:      "audit warn",
+		action:    kyvernov1.Audit,
+		auditWarn: true,
+		rules: []engineapi.RuleResponse{
+			*engineapi.RuleFail("require-image-tag", engineapi.Validation, "validation error: rule require-image-tag failed at path /spec/containers/0/image/", nil),
+		},
+		wantLevels: []string{"warning"},
+	}, {
+		name:   "audit",
+		action: kyvernov1.Audit,
+		rules: []engineapi.RuleResponse{
+			*engineapi.RuleFail("require-image-tag", engineapi.Validation, "validation error: rule require-image-tag failed at path /spec/containers/0/image/", nil),
+		},
+		wantLevels: []string{"error"},
+	}, {
+		name:   "pass",
+		action: kyvernov1.Enforce,
+		rules: []engineapi.RuleResponse{
+			*engineapi.RulePass("require-image-tag", engineapi.Validation, "", nil),
+		},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := NewBuilder()
+			builder.AddEngineResponses(index, tt.auditWarn, newResponse(tt.action, tt.rules...))
+			log := builder.Log()
+			assert.Equal(t, Version, log.Version)
+			assert.Len(t, log.Runs, 1)
+			var levels []string
+			for _, result := range log.Runs[0].Results {
+				levels = append(levels, result.Level)
+				assert.Equal(t, log.Runs[0].Tool.Driver.Rules[result.RuleIndex].ID, result.RuleID)
+			}
+			assert.Equal(t, tt.wantLevels, levels)
+		})
+	}
+}
+
+func TestBuilder_Write(t *testing.T) {
+	index := NewResourceIndex(nil)
+	index.Add("resources.yaml", []byte(resources))
+	builder := NewBuilder()
+	builder.AddEngineResponses(index, false, newResponse(kyvernov1.Enforce,
+		*engineapi.RuleFail("require-image-tag", engineapi.Validation, "validation error: rule require-image-tag failed at path /spec/containers/0/image/", nil),
+	))
+	// results without a known resource file only have a logical location
+	builder.AddEngineResponses(nil, false, newResponse(kyvernov1.Enforce,
+		*engineapi.RuleFail("require-image-tag", engineapi.Validation, "latest tag is not allowed", nil),
+	))
+	var out bytes.Buffer
+	assert.NoError(t, builder.Write(&out))
+	var log Log
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &log))
+	rules := log.Runs[0].Tool.Driver.Rules
+	assert.Len(t, rules, 1)
+	assert.Equal(t, "disallow-latest-tag/require-image-tag", rules[0].ID)
+	assert.Equal(t, "Disallow Latest Tag", rules[0].ShortDescription.Text)
+	assert.Equal(t, "The ':latest' tag is mutable.", rules[0].FullDescription.Text)
+	assert.Equal(t, "medium", rules[0].Properties["severity"])
+	assert.Equal(t, "5.0", rules[0].Properties["security-severity"])
+	assert.Equal(t, []any{"Best Practices", "EKS Best Practices"}, rules[0].Properties["tags"])
+	results := log.Runs[0].Results
+	assert.Len(t, results, 2)
+	assert.Equal(t, "resources.yaml", results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, 9, results[0].Locations[0].PhysicalLocation.Region.StartLine)
+	assert.Equal(t, "prod/Pod/nginx", results[0].Locations[0].LogicalLocations[0].FullyQualifiedName)
+	assert.Nil(t, results[1].Locations[0].PhysicalLocation)
+}
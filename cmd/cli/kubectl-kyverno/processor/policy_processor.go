@@ -21,6 +21,7 @@ import (
 	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/utils/common"
 	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/variables"
 	"github.com/kyverno/kyverno/pkg/admissionpolicy"
+	celcompiler "github.com/kyverno/kyverno/pkg/cel/compiler"
 	celengine "github.com/kyverno/kyverno/pkg/cel/engine"
 	"github.com/kyverno/kyverno/pkg/cel/matching"
 	gpolcompiler "github.com/kyverno/kyverno/pkg/cel/policies/gpol/compiler"
@@ -34,6 +35,7 @@ import (
 	"github.com/kyverno/kyverno/pkg/engine"
 	"github.com/kyverno/kyverno/pkg/engine/adapters"
 	engineapi "github.com/kyverno/kyverno/pkg/engine/api"
+	"github.com/kyverno/kyverno/pkg/engine/explain"
 	"github.com/kyverno/kyverno/pkg/engine/factories"
 	"github.com/kyverno/kyverno/pkg/engine/jmespath"
 	"github.com/kyverno/kyverno/pkg/engine/mutate/patch"
@@ -92,9 +94,22 @@ type PolicyProcessor struct {
 	Subresources              []v1alpha1.Subresource
 	Out                       io.Writer
 	NamespaceCache            map[string]*unstructured.Unstructured
+	// Observer is notified of the expressions evaluated by CEL policies
+	Observer celcompiler.Observer
+	// Trace records the steps evaluated by the engines when explain mode is enabled
+	Trace *explain.Trace
+	// OldResource is the object updated by Resource, the resource is evaluated as an update when it is set
+	OldResource *unstructured.Unstructured
 }
 
 func (p *PolicyProcessor) ApplyPoliciesOnResource() ([]engineapi.EngineResponse, error) {
+	ctx := context.TODO()
+	if p.Trace != nil {
+		ctx, _ = explain.Begin(explain.WithTrace(ctx, p.Trace), explain.TypeResource, p.resourceKey())
+	}
+	if p.Observer != nil {
+		ctx = celcompiler.WithObserver(ctx, p.Observer)
+	}
 	cfg := config.NewDefaultConfiguration(false)
 	jp := jmespath.New(cfg)
 	resource := p.Resource
@@ -171,7 +186,7 @@ func (p *PolicyProcessor) ApplyPoliciesOnResource() ([]engineapi.EngineResponse,
 		if err != nil {
 			return responses, err
 		}
-		mutateResponse := eng.Mutate(context.Background(), policyContext)
+		mutateResponse := eng.Mutate(ctx, policyContext)
 		err = p.processMutateEngineResponse(mutateResponse, resPath)
 		if err != nil {
 			return responses, fmt.Errorf("failed to print mutated result (%w)", err)
@@ -188,7 +203,7 @@ func (p *PolicyProcessor) ApplyPoliciesOnResource() ([]engineapi.EngineResponse,
 		if err != nil {
 			return responses, err
 		}
-		verifyImageResponse, verifiedImageData := eng.VerifyAndPatchImages(context.TODO(), policyContext)
+		verifyImageResponse, verifiedImageData := eng.VerifyAndPatchImages(ctx, policyContext)
 		// update annotation to reflect verified images
 		var patches []jsonpatch.JsonPatchOperation
 		if !verifiedImageData.IsEmpty() {
@@ -230,7 +245,7 @@ func (p *PolicyProcessor) ApplyPoliciesOnResource() ([]engineapi.EngineResponse,
 		if err != nil {
 			return responses, err
 		}
-		validateResponse := eng.Validate(context.TODO(), policyContext)
+		validateResponse := eng.Validate(ctx, policyContext)
 		responses = append(responses, validateResponse)
 		resource = validateResponse.PatchedResource
 	}
@@ -309,6 +324,7 @@ func (p *PolicyProcessor) ApplyPoliciesOnResource() ([]engineapi.EngineResponse,
 			if p.UserInfo != nil {
 				user = p.UserInfo.AdmissionUserInfo
 			}
+			operation, oldObject := AdmissionOperation(p.OldResource)
 			// create engine request
 			request := celengine.Request(
 				contextProvider,
@@ -317,14 +333,14 @@ func (p *PolicyProcessor) ApplyPoliciesOnResource() ([]engineapi.EngineResponse,
 				"",
 				resource.GetName(),
 				resource.GetNamespace(),
-				admissionv1.Create,
+				operation,
 				user,
 				&resource,
-				nil,
+				oldObject,
 				false,
 				nil,
 			)
-			reps, err := eng.Handle(context.TODO(), request, nil)
+			reps, err := eng.Handle(ctx, request, nil)
 			if err != nil {
 				return nil, fmt.Errorf("failed to apply mutating policies on resource %s (%w)", resource.GetName(), err)
 			}
@@ -384,7 +400,6 @@ func (p *PolicyProcessor) ApplyPoliciesOnResource() ([]engineapi.EngineResponse,
 	}
 	// validating policies
 	if len(p.ValidatingPolicies) != 0 || len(p.NamespacedValidatingPolicies) != 0 {
-		ctx := context.TODO()
 		compiler := vpolcompiler.NewCompiler()
 		policies := make([]policiesv1beta1.ValidatingPolicyLike, 0, len(p.ValidatingPolicies))
 		for i := range p.ValidatingPolicies {
@@ -413,6 +428,7 @@ func (p *PolicyProcessor) ApplyPoliciesOnResource() ([]engineapi.EngineResponse,
 			if p.UserInfo != nil {
 				user = p.UserInfo.AdmissionUserInfo
 			}
+			operation, oldObject := AdmissionOperation(p.OldResource)
 			// create engine request
 			request := celengine.Request(
 				contextProvider,
@@ -422,11 +438,10 @@ func (p *PolicyProcessor) ApplyPoliciesOnResource() ([]engineapi.EngineResponse,
 				"",
 				resource.GetName(),
 				resource.GetNamespace(),
-				// TODO: how to manage other operations ?
-				admissionv1.Create,
+				operation,
 				user,
 				&resource,
-				nil,
+				oldObject,
 				false,
 				nil,
 			)
@@ -496,6 +511,7 @@ func (p *PolicyProcessor) ApplyPoliciesOnResource() ([]engineapi.EngineResponse,
 			if p.UserInfo != nil {
 				user = p.UserInfo.AdmissionUserInfo
 			}
+			operation, oldObject := AdmissionOperation(p.OldResource)
 			// create engine request
 			request := celengine.Request(
 				contextProvider,
@@ -504,15 +520,15 @@ func (p *PolicyProcessor) ApplyPoliciesOnResource() ([]engineapi.EngineResponse,
 				"",
 				resource.GetName(),
 				resource.GetNamespace(),
-				admissionv1.Create,
+				operation,
 				user,
 				&resource,
-				nil,
+				oldObject,
 				false,
 				nil,
 			)
 			for _, policy := range compiledPolicies {
-				engineResponse, err := engine.Handle(request, policy, false)
+				engineResponse, err := engine.Handle(ctx, request, policy, false)
 				if err != nil {
 					return nil, err
 				}
@@ -543,7 +559,7 @@ func (p *PolicyProcessor) ApplyPoliciesOnResource() ([]engineapi.EngineResponse,
 			if err != nil {
 				return responses, err
 			}
-			generateResponse := eng.ApplyBackgroundChecks(context.TODO(), policyContext)
+			generateResponse := eng.ApplyBackgroundChecks(ctx, policyContext)
 			if !generateResponse.IsEmpty() {
 				newRuleResponse, err := handleGeneratePolicy(p.Out, p.Store, &generateResponse, *policyContext, p.RuleToCloneSourceResource)
 				if err != nil {
@@ -564,6 +580,15 @@ func (p *PolicyProcessor) ApplyPoliciesOnResource() ([]engineapi.EngineResponse,
 	return responses, nil
 }
 
+// AdmissionOperation returns the operation and the old object of the admission request evaluating a resource,
+// the resource is created unless it updates oldResource.
+func AdmissionOperation(oldResource *unstructured.Unstructured) (admissionv1.Operation, runtime.Object) {
+	if oldResource == nil {
+		return admissionv1.Create, nil
+	}
+	return admissionv1.Update, oldResource
+}
+
 func (p *PolicyProcessor) makePolicyContext(
 	jp jmespath.Interface,
 	cfg config.Configuration,
@@ -574,6 +599,9 @@ func (p *PolicyProcessor) makePolicyContext(
 	subresource string,
 ) (*policycontext.PolicyContext, error) {
 	operation := kyvernov1.Create
+	if p.OldResource != nil {
+		operation = kyvernov1.Update
+	}
 	var resourceValues map[string]interface{}
 	if p.Variables != nil {
 		kindOnwhichPolicyIsApplied := common.GetKindsFromPolicy(p.Out, policy, p.Variables.Subresources(), p.Client)
@@ -607,6 +635,9 @@ func (p *PolicyProcessor) makePolicyContext(
 	}
 	if operation == kyvernov1.Update {
 		resource := resource.DeepCopy()
+		if p.OldResource != nil {
+			resource = p.OldResource.DeepCopy()
+		}
 		policyContext = policyContext.WithOldResource(*resource)
 		if err := policyContext.JSONContext().AddOldResource(resource.Object); err != nil {
 			return nil, fmt.Errorf("failed to update old resource in json context (%w)", err)
@@ -793,12 +824,34 @@ func (p *PolicyProcessor) printOutput(resource interface{}, response engineapi.E
 	return nil
 }
 
-func (p *PolicyProcessor) openAPI() openapi.Client {
-	clients := make([]openapi.Client, 0)
+// resourceKey identifies the resource or JSON payload in the explain trace.
+func (p *PolicyProcessor) resourceKey() string {
+	if p.JsonPayload.Object != nil {
+		return "JSON payload"
+	}
+	return ExplainKey(p.Resource)
+}
 
+// ExplainKey identifies a resource in the explain trace.
+func ExplainKey(resource unstructured.Unstructured) string {
+	key := resource.GetName()
+	if resource.GetNamespace() != "" {
+		key = resource.GetNamespace() + "/" + key
+	}
+	return resource.GetKind() + " " + key
+}
+
+func (p *PolicyProcessor) openAPI() openapi.Client {
 	if p.Cluster {
 		return p.Client.GetKubeClient().Discovery().OpenAPIV3()
 	}
+	return NewLocalOpenAPIClient()
+}
+
+// NewLocalOpenAPIClient returns a client serving the schemas of the builtin types and of the Kyverno CRDs,
+// it is used when there is no access to a cluster.
+func NewLocalOpenAPIClient() openapi.Client {
+	clients := make([]openapi.Client, 0)
 
 	clients = append(clients, openapiclient.NewHardcodedBuiltins("1.32"))
 
@@ -1,6 +1,7 @@
 package processor
 
 import (
+	"io"
 	"os"
 	"testing"
 
@@ -8,6 +9,7 @@ import (
 	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/store"
 	yamlutils "github.com/kyverno/kyverno/pkg/utils/yaml"
 	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
 var policyNamespaceSelector = []byte(`{
@@ -118,3 +120,58 @@ func Test_NamespaceSelector(t *testing.T) {
 		assert.Equal(t, int64(rc.Error), int64(tc.result.Error))
 	}
 }
+
+var policyImmutableTeam = []byte(`{
+	"apiVersion": "kyverno.io/v1",
+	"kind": "ClusterPolicy",
+	"metadata": {
+	  "name": "immutable-team"
+	},
+	"spec": {
+	  "rules": [
+		{
+		  "name": "check-team",
+		  "match": {
+			"any": [{"resources": {"kinds": ["Pod"], "operations": ["UPDATE"]}}]
+		  },
+		  "validate": {
+			"deny": {
+			  "conditions": {
+				"any": [
+				  {
+					"key": "{{ request.oldObject.metadata.labels.team }}",
+					"operator": "NotEquals",
+					"value": "{{ request.object.metadata.labels.team }}"
+				  }
+				]
+			  }
+			}
+		  }
+		}
+	  ]
+	}
+}`)
+
+func Test_OldResource(t *testing.T) {
+	policyArray, _, _, _, _, _, _, _ := yamlutils.GetPolicy(policyImmutableTeam)
+	resourceArray, _ := resource.GetUnstructuredResources([]byte(`{"apiVersion":"v1","kind":"Pod","metadata":{"name":"nginx","namespace":"default","labels":{"team":"b"}}}`))
+	oldArray, _ := resource.GetUnstructuredResources([]byte(`{"apiVersion":"v1","kind":"Pod","metadata":{"name":"nginx","namespace":"default","labels":{"team":"a"}}}`))
+	apply := func(old *unstructured.Unstructured) ResultCounts {
+		rc := &ResultCounts{}
+		processor := PolicyProcessor{
+			Store:       &store.Store{},
+			Policies:    policyArray,
+			Resource:    *resourceArray[0],
+			OldResource: old,
+			Rc:          rc,
+			Out:         io.Discard,
+		}
+		_, err := processor.ApplyPoliciesOnResource()
+		assert.NilError(t, err)
+		return *rc
+	}
+	// without an old resource the resource is created and the rule doesn't match
+	assert.Equal(t, apply(nil), ResultCounts{})
+	// the resource updates the old resource
+	assert.Equal(t, apply(oldArray[0]).Fail, 1)
+}
@@ -19,6 +19,8 @@ type Rule struct {
 	ForEachValues map[string][]interface{} `json:"foreachValues"`
 }
 
+// Store holds the state of a single evaluation, it is not safe for concurrent use and must not be shared
+// between evaluations running concurrently.
 type Store struct {
 	local          bool
 	registryClient registryclient.Client
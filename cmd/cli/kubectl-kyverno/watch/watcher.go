@@ -0,0 +1,151 @@
+package watch
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// settle is the time the watcher waits for more changes once a change was seen,
+// editors often write a file in several steps.
+const settle = 200 * time.Millisecond
+
+// Watcher notifies of the changes made to a set of files and directories.
+// Files are watched through their parent directory so that files replaced by editors are still watched,
+// directories are watched recursively.
+type Watcher struct {
+	watcher *fsnotify.Watcher
+	files   map[string]bool
+	dirs    map[string]bool
+	watched map[string]bool
+}
+
+func New() (*Watcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	return &Watcher{
+		watcher: watcher,
+		files:   map[string]bool{},
+		dirs:    map[string]bool{},
+		watched: map[string]bool{},
+	}, nil
+}
+
+// Watch replaces the files and directories being watched, paths that don't exist are ignored.
+func (w *Watcher) Watch(paths ...string) error {
+	files := map[string]bool{}
+	dirs := map[string]bool{}
+	watched := map[string]bool{}
+	for _, path := range paths {
+		path, err := filepath.Abs(path)
+		if err != nil {
+			return err
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return err
+		}
+		if !info.IsDir() {
+			files[path] = true
+			watched[filepath.Dir(path)] = true
+			continue
+		}
+		dirs[path] = true
+		err = filepath.WalkDir(path, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if entry.IsDir() {
+				watched[path] = true
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	for path := range w.watched {
+		if !watched[path] {
+			_ = w.watcher.Remove(path)
+		}
+	}
+	for path := range watched {
+		if !w.watched[path] {
+			if err := w.watcher.Add(path); err != nil {
+				return err
+			}
+		}
+	}
+	w.files, w.dirs, w.watched = files, dirs, watched
+	return nil
+}
+
+// Next blocks until watched files change and returns the sorted absolute paths of the files that changed.
+func (w *Watcher) Next(ctx context.Context) ([]string, error) {
+	changes := map[string]bool{}
+	var timeout <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case err := <-w.watcher.Errors:
+			return nil, err
+		case event := <-w.watcher.Events:
+			if event.Has(fsnotify.Chmod) || !w.Matches(event.Name) {
+				continue
+			}
+			// directories created in a watched directory are watched too
+			if event.Has(fsnotify.Create) && w.watched[filepath.Dir(event.Name)] {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() && w.watcher.Add(event.Name) == nil {
+					w.watched[event.Name] = true
+				}
+			}
+			changes[event.Name] = true
+			timeout = time.After(settle)
+		case <-timeout:
+			paths := make([]string, 0, len(changes))
+			for path := range changes {
+				paths = append(paths, path)
+			}
+			sort.Strings(paths)
+			return paths, nil
+		}
+	}
+}
+
+// Matches returns true if an absolute path is one of the watched files or is in a watched directory.
+func (w *Watcher) Matches(path string) bool {
+	if w.files[path] {
+		return true
+	}
+	for dir := range w.dirs {
+		if Contains(dir, path) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *Watcher) Close() error {
+	return w.watcher.Close()
+}
+
+// Contains returns true if path is parent or is in the parent directory.
+func Contains(parent, path string) bool {
+	rel, err := filepath.Rel(parent, path)
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
@@ -0,0 +1,94 @@
+package watch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContains(t *testing.T) {
+	tests := []struct {
+		name   string
+		parent string
+		path   string
+		want   bool
+	}{{
+		name:   "same path",
+		parent: "/policies",
+		path:   "/policies",
+		want:   true,
+	}, {
+		name:   "file in directory",
+		parent: "/policies",
+		path:   "/policies/require-labels/policy.yaml",
+		want:   true,
+	}, {
+		name:   "sibling directory",
+		parent: "/policies",
+		path:   "/policies-old/policy.yaml",
+		want:   false,
+	}, {
+		name:   "parent directory",
+		parent: "/policies/require-labels",
+		path:   "/policies",
+		want:   false,
+	}, {
+		name:   "file named like parent",
+		parent: "/policies",
+		path:   "/policies/..policy.yaml",
+		want:   true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Contains(tt.parent, tt.path))
+		})
+	}
+}
+
+func TestWatcher(t *testing.T) {
+	dir := t.TempDir()
+	policy := filepath.Join(dir, "policy.yaml")
+	resource := filepath.Join(dir, "resource.yaml")
+	other := filepath.Join(dir, "other.yaml")
+	resources := filepath.Join(dir, "resources")
+	for _, file := range []string{policy, resource, other} {
+		require.NoError(t, os.WriteFile(file, []byte("{}"), 0o600))
+	}
+	require.NoError(t, os.Mkdir(resources, 0o700))
+
+	watcher, err := New()
+	require.NoError(t, err)
+	defer watcher.Close()
+	require.NoError(t, watcher.Watch(policy, resources, filepath.Join(dir, "missing.yaml")))
+	assert.True(t, watcher.Matches(policy))
+	assert.True(t, watcher.Matches(filepath.Join(resources, "pod.yaml")))
+	assert.False(t, watcher.Matches(resource))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	// changes to files that are not watched are ignored
+	require.NoError(t, os.WriteFile(other, []byte("{}\n"), 0o600))
+	require.NoError(t, os.WriteFile(policy, []byte("{}\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(resources, "pod.yaml"), []byte("{}"), 0o600))
+	changes, err := watcher.Next(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{policy, filepath.Join(resources, "pod.yaml")}, changes)
+
+	// watched paths are replaced
+	require.NoError(t, watcher.Watch(resource))
+	assert.False(t, watcher.Matches(policy))
+	require.NoError(t, os.WriteFile(policy, []byte("{}"), 0o600))
+	require.NoError(t, os.WriteFile(resource, []byte("{}\n"), 0o600))
+	changes, err = watcher.Next(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{resource}, changes)
+
+	cancel()
+	_, err = watcher.Next(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+}
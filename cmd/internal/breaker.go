@@ -0,0 +1,22 @@
+package internal
+
+import (
+	"github.com/go-logr/logr"
+	"github.com/kyverno/kyverno/pkg/breaker"
+)
+
+func setupCircuitBreakers(logger logr.Logger) {
+	logger = logger.WithName("circuit-breaker").WithValues("enabled", circuitBreakerEnabled, "consecutiveFailures", circuitBreakerConsecutiveFailures, "failureRate", circuitBreakerFailureRate, "coolDown", circuitBreakerCoolDown)
+	logger.V(2).Info("setup circuit breakers...")
+	if !circuitBreakerEnabled {
+		return
+	}
+	breaker.ConfigureCircuits(breaker.CircuitConfig{
+		ConsecutiveFailures: circuitBreakerConsecutiveFailures,
+		FailureRate:         circuitBreakerFailureRate,
+		MinRequests:         circuitBreakerMinRequests,
+		Window:              circuitBreakerWindow,
+		CoolDown:            circuitBreakerCoolDown,
+		HalfOpenRequests:    circuitBreakerHalfOpenRequests,
+	})
+}
@@ -16,6 +16,8 @@ type Configuration interface {
 	UsesCosign() bool
 	UsesRegistryClient() bool
 	UsesImageVerifyCache() bool
+	UsesCircuitBreaker() bool
+	UsesServiceCalls() bool
 	UsesLeaderElection() bool
 	UsesKyvernoClient() bool
 	UsesDynamicClient() bool
@@ -101,6 +103,18 @@ func WithImageVerifyCache() ConfigurationOption {
 	}
 }
 
+func WithCircuitBreaker() ConfigurationOption {
+	return func(c *configuration) {
+		c.usesCircuitBreaker = true
+	}
+}
+
+func WithServiceCalls() ConfigurationOption {
+	return func(c *configuration) {
+		c.usesServiceCalls = true
+	}
+}
+
 func WithLeaderElection() ConfigurationOption {
 	return func(c *configuration) {
 		c.usesLeaderElection = true
@@ -180,6 +194,8 @@ type configuration struct {
 	usesCosign               bool
 	usesRegistryClient       bool
 	usesImageVerifyCache     bool
+	usesCircuitBreaker       bool
+	usesServiceCalls         bool
 	usesLeaderElection       bool
 	usesKyvernoClient        bool
 	usesDynamicClient        bool
@@ -237,6 +253,14 @@ func (c *configuration) UsesImageVerifyCache() bool {
 	return c.usesImageVerifyCache
 }
 
+func (c *configuration) UsesCircuitBreaker() bool {
+	return c.usesCircuitBreaker
+}
+
+func (c *configuration) UsesServiceCalls() bool {
+	return c.usesServiceCalls
+}
+
 func (c *configuration) UsesLeaderElection() bool {
 	return c.usesLeaderElection
 }
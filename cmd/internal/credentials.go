@@ -0,0 +1,42 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/kyverno/kyverno/pkg/config"
+	"github.com/kyverno/kyverno/pkg/utils/credentials"
+	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+)
+
+func setupServiceCallCredentials(ctx context.Context, logger logr.Logger, client kubernetes.Interface) credentials.Provider {
+	logger = logger.WithName("service-call-credentials").WithValues("secrets", serviceCallClientCertSecrets, "audiences", serviceCallTokenAudiences)
+	logger.V(2).Info("setup service call credentials...")
+	secrets := splitList(serviceCallClientCertSecrets)
+	audiences := splitList(serviceCallTokenAudiences)
+	var secretLister corev1listers.SecretNamespaceLister
+	// secrets are only watched if some can be used
+	if len(secrets) > 0 {
+		factory := kubeinformers.NewSharedInformerFactoryWithOptions(client, resyncPeriod, kubeinformers.WithNamespace(config.KyvernoNamespace()))
+		secretLister = factory.Core().V1().Secrets().Lister().Secrets(config.KyvernoNamespace())
+		// start informers and wait for cache sync
+		if !StartInformersAndWaitForCacheSync(ctx, logger, factory) {
+			checkError(logger, errors.New("failed to wait for cache sync"), "failed to wait for cache sync")
+		}
+	}
+	return credentials.NewProvider(secretLister, serviceCallTokenDir, secrets, audiences)
+}
+
+func splitList(in string) []string {
+	var out []string
+	for _, item := range strings.Split(in, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
@@ -5,9 +5,11 @@ import (
 	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/kyverno/kyverno/pkg/breaker"
 	"github.com/kyverno/kyverno/pkg/leaderelection"
 	"github.com/kyverno/kyverno/pkg/logging"
 	"github.com/kyverno/kyverno/pkg/toggle"
+	"github.com/kyverno/kyverno/pkg/utils/credentials"
 	"github.com/sigstore/sigstore/pkg/tuf"
 )
 
@@ -52,6 +54,11 @@ var (
 	imagePullSecrets          string
 	allowInsecureRegistry     bool
 	registryCredentialHelpers string
+	// service call credentials
+	serviceCallTokenDir          string
+	serviceCallClientCertSecrets string
+	serviceCallTokenAudiences    string
+	serviceCallCacheMaxSize      int
 	// leader election
 	leaderElectionRetryPeriod time.Duration
 	// cleanupServer port and host for listening address
@@ -61,6 +68,17 @@ var (
 	imageVerifyCacheEnabled     bool
 	imageVerifyCacheTTLDuration time.Duration
 	imageVerifyCacheMaxSize     int64
+	imageVerifyCacheBackend     string
+	imageVerifyCacheFile        string
+	imageVerifyCacheConfigMap   string
+	// circuit breaker
+	circuitBreakerEnabled             bool
+	circuitBreakerConsecutiveFailures int
+	circuitBreakerFailureRate         float64
+	circuitBreakerMinRequests         int
+	circuitBreakerWindow              time.Duration
+	circuitBreakerCoolDown            time.Duration
+	circuitBreakerHalfOpenRequests    int
 	// global context
 	enableGlobalContext bool
 	// reporting
@@ -139,10 +157,31 @@ func initRegistryClientFlags() {
 	flag.StringVar(&registryCredentialHelpers, "registryCredentialHelpers", "", "Credential helpers to enable (default,google,amazon,azure,github). No helpers are added when this flag is empty.")
 }
 
+func initServiceCallFlags() {
+	flag.StringVar(&serviceCallTokenDir, "serviceCallTokenDir", credentials.DefaultTokenDir, "Directory containing the projected service account tokens referenced by service calls, one file per audience.")
+	flag.StringVar(&serviceCallClientCertSecrets, "serviceCallClientCertSecrets", "", "Comma separated list of kubernetes.io/tls Secrets in the Kyverno namespace policies can use as client certificates in service calls. No secret can be used when this flag is empty. Requires permission to list and watch secrets in the Kyverno namespace.")
+	flag.StringVar(&serviceCallTokenAudiences, "serviceCallTokenAudiences", "", "Comma separated list of projected token audiences policies can send as bearer tokens in service calls. No audience can be used when this flag is empty.")
+	flag.IntVar(&serviceCallCacheMaxSize, "serviceCallCacheMaxSize", 0, "Maximum number of http CEL library responses cached across admission requests for the cacheTTL request option. 0 disables the cache.")
+}
+
 func initImageVerifyCacheFlags() {
 	flag.BoolVar(&imageVerifyCacheEnabled, "imageVerifyCacheEnabled", true, "Enable a TTL cache for verified images.")
 	flag.Int64Var(&imageVerifyCacheMaxSize, "imageVerifyCacheMaxSize", 1000, "Maximum number of keys that can be stored in the TTL cache. Keys are a combination of policy elements along with the image reference. Default is 1000. 0 sets the value to default.")
 	flag.DurationVar(&imageVerifyCacheTTLDuration, "imageVerifyCacheTTLDuration", 60*time.Minute, "Maximum TTL value for a cache expressed as duration. Default is 60m. 0 sets the value to default.")
+	flag.StringVar(&imageVerifyCacheBackend, "imageVerifyCacheBackend", "memory", "Backend persisting verified images (memory, file or configmap). The file and configmap backends survive restarts and can be shared between replicas, anyone able to write the file or ConfigMap can mark images as verified.")
+	flag.StringVar(&imageVerifyCacheFile, "imageVerifyCacheFile", "", "Path of the file used by the file image verify cache backend.")
+	flag.StringVar(&imageVerifyCacheConfigMap, "imageVerifyCacheConfigMap", "kyverno-image-verify-cache", "Name of the ConfigMap in the Kyverno namespace used by the configmap image verify cache backend.")
+}
+
+func initCircuitBreakerFlags() {
+	defaults := breaker.DefaultCircuitConfig()
+	flag.BoolVar(&circuitBreakerEnabled, "circuitBreakerEnabled", true, "Enable circuit breakers for calls to external services, image registries and global context entries.")
+	flag.IntVar(&circuitBreakerConsecutiveFailures, "circuitBreakerConsecutiveFailures", defaults.ConsecutiveFailures, "Number of consecutive failures opening a circuit. 0 disables the threshold.")
+	flag.Float64Var(&circuitBreakerFailureRate, "circuitBreakerFailureRate", defaults.FailureRate, "Ratio of failed calls in a window opening a circuit. 0 disables the threshold.")
+	flag.IntVar(&circuitBreakerMinRequests, "circuitBreakerMinRequests", defaults.MinRequests, "Minimum number of calls in a window before the failure rate is considered.")
+	flag.DurationVar(&circuitBreakerWindow, "circuitBreakerWindow", defaults.Window, "Duration of the window used to compute the failure rate.")
+	flag.DurationVar(&circuitBreakerCoolDown, "circuitBreakerCoolDown", defaults.CoolDown, "Duration a circuit stays open before trial calls are allowed.")
+	flag.IntVar(&circuitBreakerHalfOpenRequests, "circuitBreakerHalfOpenRequests", defaults.HalfOpenRequests, "Number of successful trial calls required to close a circuit.")
 }
 
 func initLeaderElectionFlags() {
@@ -249,6 +288,13 @@ func initFlags(config Configuration, opts ...Option) {
 	if config.UsesImageVerifyCache() {
 		initImageVerifyCacheFlags()
 	}
+	// circuit breaker
+	if config.UsesCircuitBreaker() {
+		initCircuitBreakerFlags()
+	}
+	if config.UsesServiceCalls() {
+		initServiceCallFlags()
+	}
 	// leader election
 	if config.UsesLeaderElection() {
 		initLeaderElectionFlags()
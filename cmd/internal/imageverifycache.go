@@ -1,12 +1,21 @@
 package internal
 
 import (
+	"context"
+	"errors"
+	"fmt"
+
 	"github.com/go-logr/logr"
+	"github.com/kyverno/kyverno/pkg/config"
 	"github.com/kyverno/kyverno/pkg/imageverifycache"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
 )
 
-func setupImageVerifyCache(logger logr.Logger) imageverifycache.Client {
-	logger = logger.WithName("image-verify-cache").WithValues("enabled", imageVerifyCacheEnabled, "maxsize", imageVerifyCacheMaxSize, "ttl", imageVerifyCacheTTLDuration)
+func setupImageVerifyCache(ctx context.Context, logger logr.Logger, client kubernetes.Interface) imageverifycache.Client {
+	logger = logger.WithName("image-verify-cache").WithValues("enabled", imageVerifyCacheEnabled, "maxsize", imageVerifyCacheMaxSize, "ttl", imageVerifyCacheTTLDuration, "backend", imageVerifyCacheBackend)
 	logger.V(2).Info("setup image verify cache...")
 	opts := []imageverifycache.Option{
 		imageverifycache.WithLogger(logger),
@@ -14,6 +23,35 @@ func setupImageVerifyCache(logger logr.Logger) imageverifycache.Client {
 		imageverifycache.WithMaxSize(imageVerifyCacheMaxSize),
 		imageverifycache.WithTTLDuration(imageVerifyCacheTTLDuration),
 	}
+	switch imageVerifyCacheBackend {
+	case "", "memory":
+	case "file":
+		if imageVerifyCacheFile == "" {
+			checkError(logger, errors.New("imageVerifyCacheFile must be set"), "failed to create image verify cache store")
+		}
+		store, err := imageverifycache.NewFileStore(imageVerifyCacheFile, imageVerifyCacheMaxSize)
+		checkError(logger, err, "failed to create image verify cache store")
+		opts = append(opts, imageverifycache.WithStore(store))
+	case "configmap":
+		// only the cache ConfigMap is watched, RBAC can be restricted to its name
+		factory := kubeinformers.NewSharedInformerFactoryWithOptions(
+			client,
+			resyncPeriod,
+			kubeinformers.WithNamespace(config.KyvernoNamespace()),
+			kubeinformers.WithTweakListOptions(func(opts *metav1.ListOptions) {
+				opts.FieldSelector = fields.OneTermEqualSelector("metadata.name", imageVerifyCacheConfigMap).String()
+			}),
+		)
+		lister := factory.Core().V1().ConfigMaps().Lister().ConfigMaps(config.KyvernoNamespace())
+		// start informers and wait for cache sync
+		if !StartInformersAndWaitForCacheSync(ctx, logger, factory) {
+			checkError(logger, errors.New("failed to wait for cache sync"), "failed to wait for cache sync")
+		}
+		store := imageverifycache.NewConfigMapStore(client.CoreV1().ConfigMaps(config.KyvernoNamespace()), lister, imageVerifyCacheConfigMap, imageVerifyCacheMaxSize)
+		opts = append(opts, imageverifycache.WithStore(store))
+	default:
+		checkError(logger, fmt.Errorf("unknown backend %s", imageVerifyCacheBackend), "failed to create image verify cache store")
+	}
 	imageVerifyCache, err := imageverifycache.New(opts...)
 	checkError(logger, err, "failed to create image verify cache client")
 	return imageVerifyCache
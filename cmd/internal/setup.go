@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/go-logr/logr"
+	httplib "github.com/kyverno/kyverno/pkg/cel/libs/http"
 	apiserverclient "github.com/kyverno/kyverno/pkg/clients/apiserver"
 	"github.com/kyverno/kyverno/pkg/clients/dclient"
 	dynamicclient "github.com/kyverno/kyverno/pkg/clients/dynamic"
@@ -16,6 +17,7 @@ import (
 	"github.com/kyverno/kyverno/pkg/imageverifycache"
 	"github.com/kyverno/kyverno/pkg/metrics"
 	"github.com/kyverno/kyverno/pkg/registryclient"
+	"github.com/kyverno/kyverno/pkg/utils/credentials"
 	reportutils "github.com/kyverno/kyverno/pkg/utils/report"
 	openreportsclient "github.com/openreports/reports-api/pkg/client/clientset/versioned/typed/openreports.io/v1alpha1"
 	eventsv1 "k8s.io/client-go/kubernetes/typed/events/v1"
@@ -46,6 +48,8 @@ type SetupResult struct {
 	RegistryClient         registryclient.Client
 	ImageVerifyCacheClient imageverifycache.Client
 	RegistrySecretLister   corev1listers.SecretNamespaceLister
+	ServiceCallCredentials credentials.Provider
+	HTTPConfig             *httplib.Config
 	KyvernoClient          kyvernoclient.UpstreamInterface
 	DynamicClient          dynamicclient.UpstreamInterface
 	ApiServerClient        apiserverclient.UpstreamInterface
@@ -77,9 +81,21 @@ func Setup(config Configuration, name string, skipResourceFilters bool) (context
 	if config.UsesRegistryClient() {
 		registryClient, registrySecretLister = setupRegistryClient(ctx, logger, client)
 	}
+	var serviceCallCredentials credentials.Provider
+	if config.UsesServiceCalls() {
+		serviceCallCredentials = setupServiceCallCredentials(ctx, logger, client)
+	}
+	httpConfig := &httplib.Config{
+		Credentials:   serviceCallCredentials,
+		Configuration: configuration,
+		CacheSize:     serviceCallCacheMaxSize,
+	}
 	var imageVerifyCache imageverifycache.Client
 	if config.UsesImageVerifyCache() {
-		imageVerifyCache = setupImageVerifyCache(logger)
+		imageVerifyCache = setupImageVerifyCache(ctx, logger, client)
+	}
+	if config.UsesCircuitBreaker() {
+		setupCircuitBreakers(logger)
 	}
 	if config.UsesCosign() {
 		setupSigstoreTUF(ctx, logger)
@@ -144,6 +160,8 @@ func Setup(config Configuration, name string, skipResourceFilters bool) (context
 			RegistryClient:         registryClient,
 			ImageVerifyCacheClient: imageVerifyCache,
 			RegistrySecretLister:   registrySecretLister,
+			ServiceCallCredentials: serviceCallCredentials,
+			HTTPConfig:             httpConfig,
 			OpenreportsClient:      orClient,
 			KyvernoClient:          kyvernoClient,
 			DynamicClient:          dynamicClient,
@@ -34,6 +34,7 @@ import (
 	globalcontextcontroller "github.com/kyverno/kyverno/pkg/controllers/globalcontext"
 	policymetricscontroller "github.com/kyverno/kyverno/pkg/controllers/metrics/policy"
 	policycachecontroller "github.com/kyverno/kyverno/pkg/controllers/policycache"
+	policysourcecontroller "github.com/kyverno/kyverno/pkg/controllers/policysource"
 	policystatuscontroller "github.com/kyverno/kyverno/pkg/controllers/policystatus"
 	webhookcontroller "github.com/kyverno/kyverno/pkg/controllers/webhook"
 	"github.com/kyverno/kyverno/pkg/engine/apicall"
@@ -44,6 +45,7 @@ import (
 	"github.com/kyverno/kyverno/pkg/logging"
 	"github.com/kyverno/kyverno/pkg/metrics"
 	"github.com/kyverno/kyverno/pkg/policycache"
+	"github.com/kyverno/kyverno/pkg/registryclient"
 	"github.com/kyverno/kyverno/pkg/tls"
 	"github.com/kyverno/kyverno/pkg/toggle"
 	"github.com/kyverno/kyverno/pkg/utils/generator"
@@ -73,6 +75,7 @@ import (
 	appsv1informers "k8s.io/client-go/informers/apps/v1"
 	corev1informers "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/kubernetes"
+	corev1listers "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/restmapper"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/server"
@@ -150,6 +153,9 @@ func createrLeaderControllers(
 	configuration config.Configuration,
 	eventGenerator event.Interface,
 	stateRecorder webhookcontroller.StateRecorder,
+	enablePolicySources bool,
+	registryClient registryclient.Client,
+	registrySecretLister corev1listers.SecretNamespaceLister,
 ) ([]internal.Controller, func(context.Context) error, error) {
 	var leaderControllers []internal.Controller
 	certManager := certmanager.NewController(
@@ -307,6 +313,16 @@ func createrLeaderControllers(
 	leaderControllers = append(leaderControllers, internal.NewController(celExceptionWebhookControllerName, celExceptionWebhookController, 1))
 	leaderControllers = append(leaderControllers, internal.NewController(gctxWebhookControllerName, gctxWebhookController, 1))
 	leaderControllers = append(leaderControllers, internal.NewController(policystatuscontroller.ControllerName, policyStatusController, policystatuscontroller.Workers))
+	if enablePolicySources {
+		policySourceController := policysourcecontroller.NewController(
+			dynamicClient,
+			kyvernoClient,
+			kyvernoInformer.Kyverno().V2alpha1().PolicySources(),
+			registryClient,
+			registrySecretLister,
+		)
+		leaderControllers = append(leaderControllers, internal.NewController(policysourcecontroller.ControllerName, policySourceController, policysourcecontroller.Workers))
+	}
 
 	generateVAPs := toggle.FromContext(context.TODO()).GenerateValidatingAdmissionPolicy()
 	generateMAPs := toggle.FromContext(context.TODO()).GenerateMutatingAdmissionPolicy()
@@ -374,6 +390,10 @@ func main() {
 		maxAuditCapacity                int
 		maxAdmissionReports             int
 		controllerRuntimeMetricsAddress string
+		globalContextSnapshotDir        string
+		globalContextSnapshotInterval   time.Duration
+		apiCallCacheSize                int64
+		enablePolicySources             bool
 	)
 	flagset := flag.NewFlagSet("kyverno", flag.ExitOnError)
 	flagset.BoolVar(&dumpPayload, "dumpPayload", false, "Set this flag to activate/deactivate debug mode.")
@@ -403,6 +423,10 @@ func main() {
 	flagset.IntVar(&maxAuditCapacity, "maxAuditCapacity", 1000, "Maximum capacity of the audit policy task queue")
 	flagset.IntVar(&maxAdmissionReports, "maxAdmissionReports", 10000, "Maximum number of admission reports before we stop creating new ones")
 	flagset.StringVar(&controllerRuntimeMetricsAddress, "controllerRuntimeMetricsAddress", "", `Bind address for controller-runtime metrics server. It will be defaulted to ":8080" if unspecified. Set this to "0" to disable the metrics server.`)
+	flagset.StringVar(&globalContextSnapshotDir, "globalContextSnapshotDir", "", "Directory where global context entries are persisted and warm-started from. Persistence is disabled if not set.")
+	flagset.DurationVar(&globalContextSnapshotInterval, "globalContextSnapshotInterval", time.Minute, "Interval at which global context entries are persisted.")
+	flagset.Int64Var(&apiCallCacheSize, "apiCallCacheSize", 0, "Maximum size in bytes of the API call response cache shared across admission requests. The cache is disabled if set to 0.")
+	flagset.BoolVar(&enablePolicySources, "enablePolicySources", false, "Enable syncing policies from the OCI artifacts and Git repositories declared in policy sources.")
 	// config
 	appConfig := internal.NewConfiguration(
 		internal.WithProfiling(),
@@ -415,6 +439,8 @@ func main() {
 		internal.WithCosign(),
 		internal.WithRegistryClient(),
 		internal.WithImageVerifyCache(),
+		internal.WithCircuitBreaker(),
+		internal.WithServiceCalls(),
 		internal.WithLeaderElection(),
 		internal.WithKyvernoClient(),
 		internal.WithDynamicClient(),
@@ -495,7 +521,29 @@ func main() {
 			setup.Configuration,
 			strings.Split(omitEvents, ",")...,
 		)
+		// global context entries are refreshed periodically and don't use the response cache
+		gceAPICallConfig := apicall.NewAPICallConfiguration(maxAPICallResponseLength).WithCredentials(setup.ServiceCallCredentials)
+		apiCallConfig := gceAPICallConfig
+		if apiCallCacheSize > 0 {
+			apiCallConfig = apiCallConfig.WithResponseCache(apicall.NewResponseCache(apiCallCacheSize))
+		}
 		gcstore := store.New()
+		if globalContextSnapshotDir != "" {
+			backend, err := store.NewFileBackend(globalContextSnapshotDir)
+			if err != nil {
+				setup.Logger.Error(err, "failed to create global context snapshot backend")
+				os.Exit(1)
+			}
+			persistentStore, err := store.NewPersistent(backend)
+			if err != nil {
+				setup.Logger.Error(err, "failed to load global context snapshots")
+				os.Exit(1)
+			}
+			wg.StartWithContext(signalCtx, func(ctx context.Context) {
+				persistentStore.Run(ctx, setup.Logger.WithName("global-context-store"), globalContextSnapshotInterval)
+			})
+			gcstore = persistentStore
+		}
 		restMapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(setup.KubeClient.Discovery()))
 
 		gceController := internal.NewController(
@@ -507,7 +555,7 @@ func main() {
 				setup.KyvernoClient,
 				gcstore,
 				eventGenerator,
-				maxAPICallResponseLength,
+				gceAPICallConfig,
 				true,
 				setup.Jp,
 			),
@@ -556,7 +604,7 @@ func main() {
 			setup.KubeClient,
 			setup.KyvernoClient,
 			setup.RegistrySecretLister,
-			apicall.NewAPICallConfiguration(maxAPICallResponseLength),
+			apiCallConfig,
 			polexCache,
 			gcstore,
 		)
@@ -613,6 +661,9 @@ func main() {
 					setup.Configuration,
 					eventGenerator,
 					stateRecorder,
+					enablePolicySources,
+					setup.RegistryClient,
+					setup.RegistrySecretLister,
 				)
 				if err != nil {
 					logger.Error(err, "failed to create leader controllers")
@@ -654,6 +705,7 @@ func main() {
 			setup.KyvernoDynamicClient,
 			backgroundServiceAccountName,
 			reportsServiceAccountName,
+			setup.HTTPConfig,
 		)
 
 		contextProvider, err := libs.NewContextProvider(
@@ -696,7 +748,7 @@ func main() {
 				os.Exit(1)
 			}
 			// create compiler
-			compiler := vpolcompiler.NewCompiler()
+			compiler := vpolcompiler.NewCompiler(vpolcompiler.WithHTTPConfig(setup.HTTPConfig))
 			// create vpolProvider
 			vpolProvider, err := vpolengine.NewKubeProvider(
 				compiler,
@@ -713,7 +765,7 @@ func main() {
 				setup.Logger.Error(err, "failed to create ivpol provider")
 				os.Exit(1)
 			}
-			mpolcompiler := mpolcompiler.NewCompiler()
+			mpolcompiler := mpolcompiler.NewCompiler(mpolcompiler.WithHTTPConfig(setup.HTTPConfig))
 			mpolProvider, typeConverter, err := mpolengine.NewKubeProvider(signalCtx, mpolcompiler, mgr, setup.KubeClient.Discovery().OpenAPIV3(), kyvernoInformer.Policies().V1alpha1().PolicyExceptions().Lister(), internal.PolicyExceptionEnabled())
 			if err != nil {
 				setup.Logger.Error(err, "failed to create mpol provider")
@@ -759,6 +811,8 @@ func main() {
 				matching.NewMatcher(),
 				setup.KubeClient.CoreV1().Secrets(""),
 				nil,
+				setup.HTTPConfig,
+				setup.ImageVerifyCacheClient,
 			), metrics.AdmissionRequest)
 			mpolEngine = mpolengine.NewMetricWrapper(mpolengine.NewEngine(
 				mpolProvider,
@@ -11,6 +11,7 @@ import (
 	"github.com/kyverno/kyverno/cmd/internal"
 	"github.com/kyverno/kyverno/pkg/admissionpolicy"
 	"github.com/kyverno/kyverno/pkg/breaker"
+	httplib "github.com/kyverno/kyverno/pkg/cel/libs/http"
 	"github.com/kyverno/kyverno/pkg/client/clientset/versioned"
 	kyvernoinformer "github.com/kyverno/kyverno/pkg/client/informers/externalversions"
 	"github.com/kyverno/kyverno/pkg/clients/dclient"
@@ -25,6 +26,7 @@ import (
 	"github.com/kyverno/kyverno/pkg/engine/jmespath"
 	"github.com/kyverno/kyverno/pkg/event"
 	"github.com/kyverno/kyverno/pkg/globalcontext/store"
+	"github.com/kyverno/kyverno/pkg/imageverifycache"
 	"github.com/kyverno/kyverno/pkg/leaderelection"
 	"github.com/kyverno/kyverno/pkg/logging"
 	kubeutils "github.com/kyverno/kyverno/pkg/utils/kube"
@@ -85,6 +87,8 @@ func createReportControllers(
 	reportsConfig reportutils.ReportingConfiguration,
 	gcstore store.Store,
 	typeConverter patch.TypeConverterManager,
+	httpConfig *httplib.Config,
+	imageVerifyCache imageverifycache.Client,
 ) ([]internal.Controller, func(context.Context) error) {
 	var ctrls []internal.Controller
 	var warmups []func(context.Context) error
@@ -191,6 +195,8 @@ func createReportControllers(
 				gcstore,
 				restMapper,
 				typeConverter,
+				httpConfig,
+				imageVerifyCache,
 			)
 			ctrls = append(ctrls, internal.NewController(
 				backgroundscancontroller.ControllerName,
@@ -233,6 +239,8 @@ func createrLeaderControllers(
 	backgroundScanInterval time.Duration,
 	gcstore store.Store,
 	typeConverter patch.TypeConverterManager,
+	httpConfig *httplib.Config,
+	imageVerifyCache imageverifycache.Client,
 ) ([]internal.Controller, func(context.Context) error, error) {
 	reportControllers, warmup := createReportControllers(
 		eng,
@@ -258,6 +266,8 @@ func createrLeaderControllers(
 		reportsConfig,
 		gcstore,
 		typeConverter,
+		httpConfig,
+		imageVerifyCache,
 	)
 	return reportControllers, warmup, nil
 }
@@ -308,6 +318,8 @@ func main() {
 		internal.WithCosign(),
 		internal.WithRegistryClient(),
 		internal.WithImageVerifyCache(),
+		internal.WithCircuitBreaker(),
+		internal.WithServiceCalls(),
 		internal.WithLeaderElection(),
 		internal.WithKyvernoClient(),
 		internal.WithDynamicClient(),
@@ -363,6 +375,7 @@ func main() {
 			eventGenerator,
 			event.Workers,
 		)
+		apiCallConfig := apicall.NewAPICallConfiguration(maxAPICallResponseLength).WithCredentials(setup.ServiceCallCredentials)
 		gcstore := store.New()
 		gceController := internal.NewController(
 			globalcontextcontroller.ControllerName,
@@ -373,7 +386,7 @@ func main() {
 				setup.KyvernoClient,
 				gcstore,
 				eventGenerator,
-				maxAPICallResponseLength,
+				apiCallConfig,
 				false,
 				setup.Jp,
 			),
@@ -391,7 +404,7 @@ func main() {
 			setup.KubeClient,
 			setup.KyvernoClient,
 			setup.RegistrySecretLister,
-			apicall.NewAPICallConfiguration(maxAPICallResponseLength),
+			apiCallConfig,
 			polexCache,
 			gcstore,
 		)
@@ -475,6 +488,8 @@ func main() {
 					backgroundScanInterval,
 					gcstore,
 					typeConverter,
+					setup.HTTPConfig,
+					setup.ImageVerifyCacheClient,
 				)
 				if err != nil {
 					logger.Error(err, "failed to create leader controllers")
@@ -0,0 +1,117 @@
+package archive
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// ReasonPolicyMatched is the reason recorded for resources deleted by a cleanup or deleting policy.
+	ReasonPolicyMatched = "PolicyMatched"
+	// ReasonTTLExpired is the reason recorded for resources deleted because their ttl label expired.
+	ReasonTTLExpired = "TTLExpired"
+)
+
+const (
+	SinkDirectory = "directory"
+	SinkConfigMap = "configmap"
+	SinkSecret    = "secret"
+	SinkS3        = "s3"
+)
+
+// Record holds the full manifest of a deleted resource, along with why and when it was deleted.
+type Record struct {
+	// PolicyKind is the kind of the policy that deleted the resource, empty for ttl deletions.
+	PolicyKind string `json:"policyKind,omitempty"`
+	// Policy is the key of the policy that deleted the resource, empty for ttl deletions.
+	Policy string `json:"policy,omitempty"`
+	// Reason is the reason the resource was deleted.
+	Reason string `json:"reason"`
+	// DeletedAt is the time the resource was deleted.
+	DeletedAt metav1.Time `json:"deletedAt"`
+	// Object is the manifest of the deleted resource.
+	Object *unstructured.Unstructured `json:"object"`
+}
+
+func NewRecord(object *unstructured.Unstructured, policyKind, policy, reason string) Record {
+	return Record{
+		PolicyKind: policyKind,
+		Policy:     policy,
+		Reason:     reason,
+		DeletedAt:  metav1.Now(),
+		Object:     object,
+	}
+}
+
+// Key returns a unique path identifying the record, made of the namespace, kind, name and deletion time of the resource.
+func (r Record) Key() string {
+	namespace := r.Object.GetNamespace()
+	if namespace == "" {
+		namespace = "_cluster"
+	}
+	gvk := r.Object.GroupVersionKind()
+	kind := strings.ToLower(gvk.Kind)
+	if gvk.Group != "" {
+		kind += "." + gvk.Group
+	}
+	return path.Join(namespace, kind, r.Object.GetName(), r.DeletedAt.UTC().Format("20060102T150405.000000000Z")+".json")
+}
+
+// Sink stores the records of deleted resources.
+type Sink interface {
+	Archive(context.Context, Record) error
+}
+
+// Config configures the sink used to archive deleted resources.
+type Config struct {
+	// Sink is the kind of sink, an empty sink disables archiving.
+	Sink string
+	// Path is the directory where records are written by the directory sink.
+	Path string
+	// Namespace is the namespace where configmaps or secrets are created by the configmap and secret sinks.
+	Namespace string
+	// S3 configures the s3 sink.
+	S3 S3Config
+}
+
+// New creates the sink described by the config, it returns a nil sink when archiving is disabled.
+func New(config Config, client kubernetes.Interface) (Sink, error) {
+	switch config.Sink {
+	case "":
+		return nil, nil
+	case SinkDirectory:
+		if config.Path == "" {
+			return nil, fmt.Errorf("a path is required by the %s archive sink", config.Sink)
+		}
+		return NewDirectorySink(config.Path), nil
+	case SinkConfigMap, SinkSecret:
+		if config.Namespace == "" {
+			return nil, fmt.Errorf("a namespace is required by the %s archive sink", config.Sink)
+		}
+		if config.Sink == SinkSecret {
+			return NewSecretSink(client.CoreV1(), config.Namespace), nil
+		}
+		return NewConfigMapSink(client.CoreV1(), config.Namespace), nil
+	case SinkS3:
+		return NewS3Sink(config.S3)
+	default:
+		return nil, fmt.Errorf("unknown archive sink %q", config.Sink)
+	}
+}
+
+// Archive stores a record of the object in the sink, a nil sink archives nothing.
+func Archive(ctx context.Context, sink Sink, object *unstructured.Unstructured, policyKind, policy, reason string) error {
+	if sink == nil {
+		return nil
+	}
+	if err := sink.Archive(ctx, NewRecord(object, policyKind, policy, reason)); err != nil {
+		return fmt.Errorf("failed to archive resource: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package archive
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/kyverno/kyverno/api/kyverno"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newRecord(apiVersion, kind, namespace, name string) Record {
+	object := &unstructured.Unstructured{}
+	object.SetAPIVersion(apiVersion)
+	object.SetKind(kind)
+	object.SetNamespace(namespace)
+	object.SetName(name)
+	record := NewRecord(object, "ClusterCleanupPolicy", "cleanup-pods", ReasonPolicyMatched)
+	record.DeletedAt = metav1.NewTime(time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC))
+	return record
+}
+
+func TestRecord_Key(t *testing.T) {
+	assert.Equal(t, "default/pod/nginx/20240501T103000.000000000Z.json", newRecord("v1", "Pod", "default", "nginx").Key())
+	assert.Equal(t, "default/deployment.apps/nginx/20240501T103000.000000000Z.json", newRecord("apps/v1", "Deployment", "default", "nginx").Key())
+	assert.Equal(t, "_cluster/namespace/team-a/20240501T103000.000000000Z.json", newRecord("v1", "Namespace", "", "team-a").Key())
+}
+
+func TestDirectorySink(t *testing.T) {
+	dir := t.TempDir()
+	record := newRecord("v1", "Pod", "default", "nginx")
+	assert.NoError(t, NewDirectorySink(dir).Archive(context.Background(), record))
+	data, err := os.ReadFile(filepath.Join(dir, "default", "pod", "nginx", "20240501T103000.000000000Z.json"))
+	assert.NoError(t, err)
+	var archived Record
+	assert.NoError(t, json.Unmarshal(data, &archived))
+	assert.Equal(t, "cleanup-pods", archived.Policy)
+	assert.Equal(t, "ClusterCleanupPolicy", archived.PolicyKind)
+	assert.Equal(t, ReasonPolicyMatched, archived.Reason)
+	assert.Equal(t, record.Object, archived.Object)
+}
+
+func TestConfigMapSink(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	record := newRecord("v1", "Pod", "default", "nginx")
+	assert.NoError(t, NewConfigMapSink(client.CoreV1(), "archive").Archive(context.Background(), record))
+	list, err := client.CoreV1().ConfigMaps("archive").List(context.Background(), metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, list.Items, 1)
+	assert.Equal(t, "true", list.Items[0].Labels[kyverno.LabelCleanupArchive])
+	assert.Equal(t, record.Key(), list.Items[0].Annotations[annotationKey])
+	var archived Record
+	assert.NoError(t, json.Unmarshal([]byte(list.Items[0].Data[recordKey]), &archived))
+	assert.Equal(t, record.Object, archived.Object)
+}
+
+func TestSecretSink(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	record := newRecord("v1", "Secret", "default", "token")
+	assert.NoError(t, NewSecretSink(client.CoreV1(), "archive").Archive(context.Background(), record))
+	list, err := client.CoreV1().Secrets("archive").List(context.Background(), metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, list.Items, 1)
+	var archived Record
+	assert.NoError(t, json.Unmarshal(list.Items[0].Data[recordKey], &archived))
+	assert.Equal(t, record.Object, archived.Object)
+}
+
+func TestS3Sink(t *testing.T) {
+	var path, authorization string
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		authorization = r.Header.Get("Authorization")
+		body, _ = io.ReadAll(r.Body)
+		if strings.Contains(path, "forbidden") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	endpoint, err := url.Parse(server.URL)
+	assert.NoError(t, err)
+	credentials := aws.NewCredentialsCache(aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+		return aws.Credentials{AccessKeyID: "access", SecretAccessKey: "secret"}, nil
+	}))
+
+	record := newRecord("v1", "Pod", "default", "nginx")
+	assert.NoError(t, newS3Sink(endpoint, "backups", "kyverno", "eu-west-1", credentials).Archive(context.Background(), record))
+	assert.Equal(t, "/backups/kyverno/default/pod/nginx/20240501T103000.000000000Z.json", path)
+	assert.True(t, strings.HasPrefix(authorization, "AWS4-HMAC-SHA256 Credential=access/"))
+	assert.Contains(t, authorization, "/eu-west-1/s3/aws4_request")
+	var archived Record
+	assert.NoError(t, json.Unmarshal(body, &archived))
+	assert.Equal(t, record.Object, archived.Object)
+
+	err = newS3Sink(endpoint, "forbidden", "", "eu-west-1", credentials).Archive(context.Background(), record)
+	assert.ErrorContains(t, err, "403 Forbidden")
+}
+
+func TestNew(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	tests := []struct {
+		name    string
+		config  Config
+		wantNil bool
+		wantErr string
+	}{{
+		name:    "disabled",
+		wantNil: true,
+	}, {
+		name:   "directory",
+		config: Config{Sink: SinkDirectory, Path: "/archive"},
+	}, {
+		name:    "directory without path",
+		config:  Config{Sink: SinkDirectory},
+		wantErr: "a path is required by the directory archive sink",
+	}, {
+		name:   "configmap",
+		config: Config{Sink: SinkConfigMap, Namespace: "archive"},
+	}, {
+		name:    "secret without namespace",
+		config:  Config{Sink: SinkSecret},
+		wantErr: "a namespace is required by the secret archive sink",
+	}, {
+		name:    "s3 without bucket",
+		config:  Config{Sink: SinkS3, S3: S3Config{Endpoint: "https://s3.example.com"}},
+		wantErr: "an endpoint and a bucket are required by the s3 archive sink",
+	}, {
+		name:    "s3 with invalid scheme",
+		config:  Config{Sink: SinkS3, S3: S3Config{Endpoint: "ftp://s3.example.com", Bucket: "backups"}},
+		wantErr: `invalid s3 endpoint scheme: "ftp"`,
+	}, {
+		name:    "unknown",
+		config:  Config{Sink: "tape"},
+		wantErr: `unknown archive sink "tape"`,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink, err := New(tt.config, client)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantNil, sink == nil)
+		})
+	}
+}
+
+func TestArchive_NilSink(t *testing.T) {
+	assert.NoError(t, Archive(context.Background(), nil, &unstructured.Unstructured{}, "", "", ReasonTTLExpired))
+}
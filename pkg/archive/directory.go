@@ -0,0 +1,34 @@
+package archive
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+type directorySink struct {
+	path string
+}
+
+// NewDirectorySink returns a sink writing records as json files in a local directory, typically backed by a persistent volume.
+func NewDirectorySink(path string) Sink {
+	return &directorySink{path: path}
+}
+
+func (s *directorySink) Archive(_ context.Context, record Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	file := filepath.Join(s.path, filepath.FromSlash(record.Key()))
+	if err := os.MkdirAll(filepath.Dir(file), 0o750); err != nil {
+		return err
+	}
+	// write to a temporary file first so that a partially written record is never left behind
+	tmp := file + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, file)
+}
@@ -0,0 +1,81 @@
+package archive
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/kyverno/kyverno/api/kyverno"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+const (
+	// recordKey is the data key holding the record in configmaps and secrets.
+	recordKey = "record.json"
+	// annotationKey is the annotation holding the key of the record in configmaps and secrets.
+	annotationKey = "cleanup.kyverno.io/archive-key"
+)
+
+type configMapSink struct {
+	client    corev1client.ConfigMapsGetter
+	namespace string
+}
+
+// NewConfigMapSink returns a sink creating a configmap for every record in a dedicated namespace.
+func NewConfigMapSink(client corev1client.ConfigMapsGetter, namespace string) Sink {
+	return &configMapSink{client: client, namespace: namespace}
+}
+
+func (s *configMapSink) Archive(ctx context.Context, record Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	_, err = s.client.ConfigMaps(s.namespace).Create(ctx, &corev1.ConfigMap{
+		ObjectMeta: objectMeta(record),
+		Data: map[string]string{
+			recordKey: string(data),
+		},
+	}, metav1.CreateOptions{})
+	return err
+}
+
+type secretSink struct {
+	client    corev1client.SecretsGetter
+	namespace string
+}
+
+// NewSecretSink returns a sink creating a secret for every record in a dedicated namespace,
+// it should be preferred when archived resources can hold sensitive data.
+func NewSecretSink(client corev1client.SecretsGetter, namespace string) Sink {
+	return &secretSink{client: client, namespace: namespace}
+}
+
+func (s *secretSink) Archive(ctx context.Context, record Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	_, err = s.client.Secrets(s.namespace).Create(ctx, &corev1.Secret{
+		ObjectMeta: objectMeta(record),
+		Type:       corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			recordKey: data,
+		},
+	}, metav1.CreateOptions{})
+	return err
+}
+
+func objectMeta(record Record) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		GenerateName: "kyverno-archive-",
+		Labels: map[string]string{
+			kyverno.LabelAppManagedBy:   kyverno.ValueKyvernoApp,
+			kyverno.LabelCleanupArchive: "true",
+		},
+		Annotations: map[string]string{
+			annotationKey: record.Key(),
+		},
+	}
+}
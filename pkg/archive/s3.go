@@ -0,0 +1,113 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+)
+
+// S3Config configures the s3 sink.
+type S3Config struct {
+	// Endpoint is the url of the s3 compatible endpoint, objects are addressed using the path style.
+	Endpoint string
+	// Bucket is the bucket where records are written.
+	Bucket string
+	// Prefix is prepended to the key of the records.
+	Prefix string
+	// Region is the region used to sign requests.
+	Region string
+}
+
+type s3Sink struct {
+	endpoint    *url.URL
+	bucket      string
+	prefix      string
+	region      string
+	credentials aws.CredentialsProvider
+	signer      *v4.Signer
+	client      *http.Client
+}
+
+// NewS3Sink returns a sink writing records as json objects in a bucket of an s3 compatible endpoint.
+// Credentials are resolved using the default aws chain (environment variables, shared files, web identity, etc).
+func NewS3Sink(config S3Config) (Sink, error) {
+	if config.Endpoint == "" || config.Bucket == "" {
+		return nil, fmt.Errorf("an endpoint and a bucket are required by the %s archive sink", SinkS3)
+	}
+	endpoint, err := url.Parse(config.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid s3 endpoint: %w", err)
+	}
+	if endpoint.Scheme != "http" && endpoint.Scheme != "https" {
+		return nil, fmt.Errorf("invalid s3 endpoint scheme: %q", endpoint.Scheme)
+	}
+	region := config.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+	awsConfig, err := awsconfig.LoadDefaultConfig(context.Background(), awsconfig.WithRegion(region))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load s3 credentials: %w", err)
+	}
+	return newS3Sink(endpoint, config.Bucket, config.Prefix, region, awsConfig.Credentials), nil
+}
+
+func newS3Sink(endpoint *url.URL, bucket, prefix, region string, credentials aws.CredentialsProvider) *s3Sink {
+	return &s3Sink{
+		endpoint:    endpoint,
+		bucket:      bucket,
+		prefix:      prefix,
+		region:      region,
+		credentials: credentials,
+		signer:      v4.NewSigner(),
+		client:      &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (s *s3Sink) Archive(ctx context.Context, record Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	target := *s.endpoint
+	target.Path = path.Join("/", s.endpoint.Path, s.bucket, s.prefix, record.Key())
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, target.String(), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	hash := sha256.Sum256(data)
+	payloadHash := hex.EncodeToString(hash[:])
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if s.credentials != nil {
+		credentials, err := s.credentials.Retrieve(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve s3 credentials: %w", err)
+		}
+		if err := s.signer.SignHTTP(ctx, credentials, req, payloadHash, "s3", s.region, time.Now()); err != nil {
+			return fmt.Errorf("failed to sign s3 request: %w", err)
+		}
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("failed to write s3 object %s: %s: %s", target.Path, resp.Status, bytes.TrimSpace(body))
+	}
+	return nil
+}
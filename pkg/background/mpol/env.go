@@ -17,7 +17,7 @@ import (
 
 var targetConstraintsEnvironmentVersion = version.MajorMinor(1, 0)
 
-func buildMpolTargetEvalEnv(namespace string) (*cel.Env, error) {
+func buildMpolTargetEvalEnv(namespace string, httpConfig *http.Config) (*cel.Env, error) {
 	baseOpts := compiler.DefaultEnvOptions()
 	baseOpts = append(baseOpts,
 		cel.Variable(compiler.ObjectKey, cel.DynType),
@@ -59,6 +59,7 @@ func buildMpolTargetEvalEnv(namespace string) (*cel.Env, error) {
 					globalcontext.Latest(),
 				),
 				http.Lib(
+					httpConfig,
 					http.Latest(),
 				),
 				image.Lib(
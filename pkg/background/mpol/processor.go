@@ -52,6 +52,7 @@ type processor struct {
 
 	reportsConfig reportutils.ReportingConfiguration
 	eventGen      event.Interface
+	httpConfig    *http.Config
 }
 
 func NewProcessor(client dclient.Interface,
@@ -62,6 +63,7 @@ func NewProcessor(client dclient.Interface,
 	reportsConfig reportutils.ReportingConfiguration,
 	statusControl common.StatusControlInterface,
 	eventGen event.Interface,
+	httpConfig *http.Config,
 ) *processor {
 	return &processor{
 		client:        client,
@@ -72,6 +74,7 @@ func NewProcessor(client dclient.Interface,
 		statusControl: statusControl,
 		reportsConfig: reportsConfig,
 		eventGen:      eventGen,
+		httpConfig:    httpConfig,
 	}
 }
 
@@ -313,7 +316,7 @@ func (p *processor) getTargetsFromExpression(ctx context.Context, ur *kyvernov2.
 		compiler.ObjectKey:        originalObj.Object,
 		compiler.ResourceKey:      resource.Context{ContextInterface: p.context},
 		compiler.GlobalContextKey: globalcontext.Context{ContextInterface: p.context},
-		compiler.HttpKey:          http.Context{ContextInterface: http.NewHTTP(nil)},
+		compiler.HttpKey:          http.Context{ContextInterface: http.NewHTTPWithContext(ctx, nil)},
 		compiler.ImageDataKey:     imagedata.Context{ContextInterface: p.context},
 	}
 	vars := lazy.NewMapValue(compiler.VariablesType)
@@ -355,7 +358,7 @@ func (p *processor) getTargetsFromExpression(ctx context.Context, ur *kyvernov2.
 }
 
 func (p *processor) getResourcesFromExpression(ctx context.Context, expr, policyNs string, data map[string]interface{}) (map[string]interface{}, error) {
-	e, err := buildMpolTargetEvalEnv(policyNs)
+	e, err := buildMpolTargetEvalEnv(policyNs, p.httpConfig)
 	if err != nil {
 		return nil, err
 	}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
 	kyvernov2 "github.com/kyverno/kyverno/api/kyverno/v2"
@@ -33,6 +34,9 @@ type fakeContext struct{}
 
 func (f *fakeContext) GenerateResources(string, []map[string]any) error        { return nil }
 func (f *fakeContext) GetGlobalReference(name, projection string) (any, error) { return name, nil }
+func (f *fakeContext) GetGlobalReferenceWithMaxAge(name, projection string, _ time.Duration) (any, error) {
+	return name, nil
+}
 func (f *fakeContext) GetImageData(image string) (map[string]any, error) {
 	return map[string]any{"test": image}, nil
 }
@@ -116,7 +120,8 @@ func TestProcess_NoPolicyFound(t *testing.T) {
 		&libs.FakeContextProvider{},
 		reportutils.NewReportingConfig(),
 		&fakeStatusControl{},
-		event.NewFake())
+		event.NewFake(),
+		nil)
 
 	ur := &kyvernov2.UpdateRequest{
 		ObjectMeta: metav1.ObjectMeta{
@@ -167,6 +172,7 @@ func TestProcess_EngineEvaluateError(t *testing.T) {
 		reportutils.NewReportingConfig(),
 		&fakeStatusControl{},
 		event.NewFake(),
+		nil,
 	)
 
 	ur := &kyvernov2.UpdateRequest{
@@ -13,6 +13,7 @@ import (
 	"github.com/kyverno/kyverno/pkg/background/mpol"
 	"github.com/kyverno/kyverno/pkg/background/mutate"
 	"github.com/kyverno/kyverno/pkg/cel/libs"
+	"github.com/kyverno/kyverno/pkg/cel/libs/http"
 	gpolengine "github.com/kyverno/kyverno/pkg/cel/policies/gpol/engine"
 	mpolengine "github.com/kyverno/kyverno/pkg/cel/policies/mpol/engine"
 	"github.com/kyverno/kyverno/pkg/client/clientset/versioned"
@@ -75,6 +76,7 @@ type controller struct {
 	configuration config.Configuration
 	jp            jmespath.Interface
 	reportsConfig reportutils.ReportingConfiguration
+	httpConfig    *http.Config
 }
 
 // NewController returns an instance of the Generate-Request Controller
@@ -96,6 +98,7 @@ func NewController(
 	configuration config.Configuration,
 	jp jmespath.Interface,
 	reportsConfig reportutils.ReportingConfiguration,
+	httpConfig *http.Config,
 ) Controller {
 	urLister := urInformer.Lister().UpdateRequests(config.KyvernoNamespace())
 	c := controller{
@@ -120,6 +123,7 @@ func NewController(
 		configuration: configuration,
 		jp:            jp,
 		reportsConfig: reportsConfig,
+		httpConfig:    httpConfig,
 	}
 	_, _ = urInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc:    c.addUR,
@@ -261,7 +265,7 @@ func (c *controller) processUR(ur *kyvernov2.UpdateRequest) error {
 		ctrl := gpol.NewCELGenerateController(c.client, c.kyvernoClient, c.context, c.gpolEngine, c.gpolProvider, c.watchManager, statusControl, c.reportsConfig, c.eventGen, logger)
 		return ctrl.ProcessUR(ur)
 	case kyvernov2.CELMutate:
-		processor := mpol.NewProcessor(c.client, c.kyvernoClient, c.mpolEngine, c.restMapper, c.context, c.reportsConfig, statusControl, c.eventGen)
+		processor := mpol.NewProcessor(c.client, c.kyvernoClient, c.mpolEngine, c.restMapper, c.context, c.reportsConfig, statusControl, c.eventGen, c.httpConfig)
 		return processor.Process(ur)
 	}
 	return nil
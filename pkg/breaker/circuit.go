@@ -0,0 +1,259 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/kyverno/kyverno/pkg/metrics"
+)
+
+// ErrCircuitOpen is returned when a call is rejected because the circuit is open.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// State is the state of a circuit breaker.
+type State int64
+
+const (
+	// StateClosed lets all calls through.
+	StateClosed State = iota
+	// StateHalfOpen lets a limited number of trial calls through to probe the dependency.
+	StateHalfOpen
+	// StateOpen rejects all calls until the cool-down elapsed.
+	StateOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateHalfOpen:
+		return "half-open"
+	case StateOpen:
+		return "open"
+	}
+	return "unknown"
+}
+
+// CircuitConfig configures when a circuit breaker opens and how it recovers.
+type CircuitConfig struct {
+	// ConsecutiveFailures opens the circuit after this number of consecutive failures, 0 disables the threshold.
+	ConsecutiveFailures int
+	// FailureRate opens the circuit when the ratio of failed calls in the current window reaches it, 0 disables the threshold.
+	FailureRate float64
+	// MinRequests is the minimum number of calls in the current window before FailureRate is considered.
+	MinRequests int
+	// Window is the duration over which calls are counted for FailureRate.
+	Window time.Duration
+	// CoolDown is the duration the circuit stays open before trial calls are allowed.
+	CoolDown time.Duration
+	// HalfOpenRequests is the number of successful trial calls required to close the circuit.
+	HalfOpenRequests int
+}
+
+// DefaultCircuitConfig returns the default circuit breaker configuration.
+func DefaultCircuitConfig() CircuitConfig {
+	return CircuitConfig{
+		ConsecutiveFailures: 5,
+		FailureRate:         0.5,
+		MinRequests:         20,
+		Window:              time.Minute,
+		CoolDown:            30 * time.Second,
+		HalfOpenRequests:    1,
+	}
+}
+
+// CircuitBreaker protects the calls to an external dependency, it rejects them while the dependency is failing.
+// All the methods of a nil CircuitBreaker let the calls through.
+type CircuitBreaker struct {
+	name   string
+	config CircuitConfig
+	now    func() time.Time
+
+	lock        sync.Mutex
+	state       State
+	consecutive int
+	requests    int
+	failures    int
+	windowStart time.Time
+	openedAt    time.Time
+	trials      int
+	successes   int
+}
+
+// NewCircuitBreaker creates a circuit breaker, a nil circuit breaker lets all calls through.
+func NewCircuitBreaker(name string, config CircuitConfig) *CircuitBreaker {
+	if config.HalfOpenRequests <= 0 {
+		config.HalfOpenRequests = 1
+	}
+	b := &CircuitBreaker{
+		name:   name,
+		config: config,
+		now:    time.Now,
+	}
+	b.windowStart = b.now()
+	return b
+}
+
+// State returns the current state of the circuit.
+func (b *CircuitBreaker) State() State {
+	if b == nil {
+		return StateClosed
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.expire(b.now())
+	return b.state
+}
+
+// Allow reports whether a call can proceed, the returned function must be called with the outcome of the call.
+func (b *CircuitBreaker) Allow(ctx context.Context) (func(error), error) {
+	if b == nil {
+		return func(error) {}, nil
+	}
+	metrics := metrics.GetBreakerMetrics()
+	if metrics != nil {
+		metrics.RecordTotalIncrease(ctx, b.name)
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.expire(b.now())
+	switch b.state {
+	case StateOpen:
+		if metrics != nil {
+			metrics.RecordDrop(ctx, b.name)
+		}
+		return nil, ErrCircuitOpen
+	case StateHalfOpen:
+		if b.trials >= b.config.HalfOpenRequests {
+			if metrics != nil {
+				metrics.RecordDrop(ctx, b.name)
+			}
+			return nil, ErrCircuitOpen
+		}
+		b.trials++
+	}
+	state := b.state
+	var once sync.Once
+	return func(err error) {
+		once.Do(func() { b.done(ctx, state, err) })
+	}, nil
+}
+
+// Do calls inner unless the circuit is open, errors returned by inner count as failures.
+func (b *CircuitBreaker) Do(ctx context.Context, inner func(context.Context) error) error {
+	done, err := b.Allow(ctx)
+	if err != nil {
+		return err
+	}
+	if inner == nil {
+		done(nil)
+		return nil
+	}
+	err = inner(ctx)
+	done(err)
+	return err
+}
+
+// expire moves an open circuit to half-open once the cool-down elapsed and starts a new window if needed.
+func (b *CircuitBreaker) expire(now time.Time) {
+	if b.state == StateOpen && !now.Before(b.openedAt.Add(b.config.CoolDown)) {
+		b.state = StateHalfOpen
+		b.trials = 0
+		b.successes = 0
+		b.recordState()
+	}
+	if b.config.Window > 0 && !now.Before(b.windowStart.Add(b.config.Window)) {
+		b.windowStart = now
+		b.requests = 0
+		b.failures = 0
+	}
+}
+
+func (b *CircuitBreaker) done(ctx context.Context, state State, err error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	now := b.now()
+	b.expire(now)
+	// outcomes of calls started in another state are ignored
+	if b.state != state {
+		return
+	}
+	// calls cancelled by the caller say nothing about the dependency
+	if errors.Is(err, context.Canceled) && ctx.Err() != nil {
+		if b.state == StateHalfOpen {
+			b.trials--
+		}
+		return
+	}
+	failed := err != nil
+	switch b.state {
+	case StateHalfOpen:
+		if failed {
+			b.open(now)
+			return
+		}
+		b.successes++
+		if b.successes >= b.config.HalfOpenRequests {
+			b.close(now)
+		}
+	case StateClosed:
+		b.requests++
+		if !failed {
+			b.consecutive = 0
+			return
+		}
+		b.failures++
+		b.consecutive++
+		if b.config.ConsecutiveFailures > 0 && b.consecutive >= b.config.ConsecutiveFailures {
+			b.open(now)
+			return
+		}
+		if b.config.FailureRate > 0 && b.requests >= b.config.MinRequests && float64(b.failures)/float64(b.requests) >= b.config.FailureRate {
+			b.open(now)
+		}
+	}
+}
+
+func (b *CircuitBreaker) open(now time.Time) {
+	b.state = StateOpen
+	b.openedAt = now
+	b.recordState()
+}
+
+func (b *CircuitBreaker) close(now time.Time) {
+	b.state = StateClosed
+	b.consecutive = 0
+	b.requests = 0
+	b.failures = 0
+	b.windowStart = now
+	b.recordState()
+}
+
+func (b *CircuitBreaker) recordState() {
+	if metrics := metrics.GetBreakerMetrics(); metrics != nil {
+		metrics.RecordState(context.Background(), b.name, int64(b.state))
+	}
+}
+
+// HTTPClient sends HTTP requests.
+type HTTPClient interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+// DoRequest sends a request through the circuit breaker, transport errors and server errors count as failures.
+func (b *CircuitBreaker) DoRequest(client HTTPClient, req *http.Request) (*http.Response, error) {
+	done, err := b.Allow(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err == nil && resp.StatusCode >= http.StatusInternalServerError {
+		done(errors.New(resp.Status))
+	} else {
+		done(err)
+	}
+	return resp, err
+}
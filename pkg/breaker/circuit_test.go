@@ -0,0 +1,178 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var errFailure = errors.New("failure")
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func newTestCircuitBreaker(config CircuitConfig) (*CircuitBreaker, *fakeClock) {
+	clock := &fakeClock{now: time.Now()}
+	b := NewCircuitBreaker("test", config)
+	b.now = clock.Now
+	b.windowStart = clock.now
+	return b, clock
+}
+
+func call(b *CircuitBreaker, err error) error {
+	return b.Do(context.TODO(), func(context.Context) error {
+		return err
+	})
+}
+
+func Test_CircuitBreaker_consecutiveFailures(t *testing.T) {
+	b, clock := newTestCircuitBreaker(CircuitConfig{
+		ConsecutiveFailures: 3,
+		CoolDown:            time.Minute,
+	})
+	assert.ErrorIs(t, call(b, errFailure), errFailure)
+	assert.ErrorIs(t, call(b, errFailure), errFailure)
+	// a success resets the consecutive failures
+	assert.NoError(t, call(b, nil))
+	assert.ErrorIs(t, call(b, errFailure), errFailure)
+	assert.ErrorIs(t, call(b, errFailure), errFailure)
+	assert.Equal(t, StateClosed, b.State())
+	assert.ErrorIs(t, call(b, errFailure), errFailure)
+	assert.Equal(t, StateOpen, b.State())
+	// calls are rejected without calling inner
+	called := false
+	err := b.Do(context.TODO(), func(context.Context) error {
+		called = true
+		return nil
+	})
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.False(t, called)
+	// the circuit becomes half-open after the cool-down
+	clock.now = clock.now.Add(time.Minute)
+	assert.Equal(t, StateHalfOpen, b.State())
+	assert.NoError(t, call(b, nil))
+	assert.Equal(t, StateClosed, b.State())
+}
+
+func Test_CircuitBreaker_failureRate(t *testing.T) {
+	b, clock := newTestCircuitBreaker(CircuitConfig{
+		FailureRate: 0.5,
+		MinRequests: 4,
+		Window:      time.Minute,
+		CoolDown:    time.Minute,
+	})
+	assert.NoError(t, call(b, nil))
+	assert.Error(t, call(b, errFailure))
+	assert.NoError(t, call(b, nil))
+	assert.Equal(t, StateClosed, b.State())
+	// calls in a previous window are not counted
+	clock.now = clock.now.Add(time.Minute)
+	assert.Error(t, call(b, errFailure))
+	assert.NoError(t, call(b, nil))
+	assert.NoError(t, call(b, nil))
+	assert.Equal(t, StateClosed, b.State())
+	assert.Error(t, call(b, errFailure))
+	assert.Equal(t, StateOpen, b.State())
+}
+
+func Test_CircuitBreaker_halfOpen(t *testing.T) {
+	b, clock := newTestCircuitBreaker(CircuitConfig{
+		ConsecutiveFailures: 1,
+		CoolDown:            time.Minute,
+		HalfOpenRequests:    2,
+	})
+	assert.Error(t, call(b, errFailure))
+	assert.Equal(t, StateOpen, b.State())
+	clock.now = clock.now.Add(time.Minute)
+	// a failed trial call opens the circuit again
+	assert.ErrorIs(t, call(b, errFailure), errFailure)
+	assert.Equal(t, StateOpen, b.State())
+	clock.now = clock.now.Add(time.Minute)
+	// only the configured number of trial calls are let through
+	first, err := b.Allow(context.TODO())
+	assert.NoError(t, err)
+	second, err := b.Allow(context.TODO())
+	assert.NoError(t, err)
+	_, err = b.Allow(context.TODO())
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	first(nil)
+	assert.Equal(t, StateHalfOpen, b.State())
+	second(nil)
+	assert.Equal(t, StateClosed, b.State())
+}
+
+func Test_CircuitBreaker_cancelled(t *testing.T) {
+	b, _ := newTestCircuitBreaker(CircuitConfig{
+		ConsecutiveFailures: 1,
+		CoolDown:            time.Minute,
+	})
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+	err := b.Do(ctx, func(ctx context.Context) error {
+		return ctx.Err()
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, StateClosed, b.State())
+}
+
+func Test_CircuitBreaker_nil(t *testing.T) {
+	var b *CircuitBreaker
+	assert.ErrorIs(t, call(b, errFailure), errFailure)
+	assert.NoError(t, call(b, nil))
+	assert.Equal(t, StateClosed, b.State())
+}
+
+func TestTransport(t *testing.T) {
+	status := http.StatusInternalServerError
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+	ConfigureCircuits(CircuitConfig{
+		ConsecutiveFailures: 2,
+		CoolDown:            time.Hour,
+	})
+	defer func() {
+		circuits.config = nil
+		circuits.breakers = nil
+	}()
+	client := &http.Client{Transport: Transport("test", nil)}
+	for range 2 {
+		resp, err := client.Get(server.URL)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		resp.Body.Close()
+	}
+	status = http.StatusOK
+	_, err := client.Get(server.URL)
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, StateOpen, GetCircuitBreaker("test/"+server.Listener.Addr().String()).State())
+	// other hosts are not affected
+	assert.Equal(t, StateClosed, GetCircuitBreaker("test/example.com").State())
+}
+
+func TestGetCircuitBreaker_bounded(t *testing.T) {
+	ConfigureCircuits(DefaultCircuitConfig())
+	defer func() {
+		circuits.config = nil
+		circuits.breakers = nil
+	}()
+	first := GetCircuitBreaker("test/0")
+	for i := range maxCircuits {
+		GetCircuitBreaker(fmt.Sprintf("test/%d", i+1))
+	}
+	assert.Equal(t, maxCircuits, circuits.breakers.Len())
+	// the least recently used breaker was dropped
+	assert.NotSame(t, first, GetCircuitBreaker("test/0"))
+}
@@ -0,0 +1,75 @@
+package breaker
+
+import (
+	"net/http"
+	"sync"
+
+	"k8s.io/utils/lru"
+)
+
+// maxCircuits bounds the number of circuit breakers, the least recently used ones are dropped first.
+const maxCircuits = 1000
+
+// circuits holds the circuit breakers protecting calls to external dependencies, one per dependency.
+var circuits struct {
+	lock     sync.Mutex
+	config   *CircuitConfig
+	breakers *lru.Cache
+}
+
+// ConfigureCircuits enables circuit breakers for external dependencies with the given configuration.
+// Until it is called, GetCircuitBreaker returns nil and all calls go through.
+func ConfigureCircuits(config CircuitConfig) {
+	circuits.lock.Lock()
+	defer circuits.lock.Unlock()
+	circuits.config = &config
+	circuits.breakers = lru.New(maxCircuits)
+}
+
+// GetCircuitBreaker returns the circuit breaker for the named dependency, creating it if needed.
+func GetCircuitBreaker(name string) *CircuitBreaker {
+	circuits.lock.Lock()
+	defer circuits.lock.Unlock()
+	if circuits.config == nil {
+		return nil
+	}
+	if b, ok := circuits.breakers.Get(name); ok {
+		return b.(*CircuitBreaker)
+	}
+	b := NewCircuitBreaker(name, *circuits.config)
+	circuits.breakers.Add(name, b)
+	return b
+}
+
+// HostCircuitBreaker returns the circuit breaker for a host called by the given component.
+func HostCircuitBreaker(component string, host string) *CircuitBreaker {
+	return GetCircuitBreaker(component + "/" + host)
+}
+
+// Transport wraps inner so that requests go through the circuit breaker of their host.
+func Transport(component string, inner http.RoundTripper) http.RoundTripper {
+	if inner == nil {
+		inner = http.DefaultTransport
+	}
+	return &transport{
+		component: component,
+		inner:     inner,
+	}
+}
+
+type transport struct {
+	component string
+	inner     http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return HostCircuitBreaker(t.component, req.URL.Host).DoRequest(roundTripper{t.inner}, req)
+}
+
+type roundTripper struct {
+	http.RoundTripper
+}
+
+func (r roundTripper) Do(req *http.Request) (*http.Response, error) {
+	return r.RoundTrip(req)
+}
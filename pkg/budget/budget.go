@@ -0,0 +1,176 @@
+package budget
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/utils/clock"
+)
+
+// Limits bounds the resources deleted by cleanup runs, zero means no limit.
+type Limits struct {
+	// MaxPerRun is the maximum number of resources deleted by a single run.
+	MaxPerRun int
+	// MaxPerMinute is the maximum number of resources deleted within a minute.
+	MaxPerMinute int
+	// MaxPercentage is the maximum percentage of the resources of a kind deleted by a single run.
+	MaxPercentage int
+}
+
+// ExceededError is returned when a deletion would exceed a deletion budget.
+type ExceededError struct {
+	Message string
+}
+
+func (e *ExceededError) Error() string {
+	return "deletion budget exceeded: " + e.Message
+}
+
+// IsExceeded returns true if the error, or one of the errors it wraps, is an ExceededError.
+func IsExceeded(err error) bool {
+	var exceeded *ExceededError
+	return errors.As(err, &exceeded)
+}
+
+// Budget enforces the global deletion limits and keeps track of the deletions of the last minute,
+// for all the policies together and for each policy.
+type Budget struct {
+	global   Limits
+	clock    clock.PassiveClock
+	lock     sync.Mutex
+	deleted  []time.Time
+	policies map[string][]time.Time
+}
+
+func New(global Limits) *Budget {
+	return &Budget{
+		global:   global,
+		clock:    clock.RealClock{},
+		policies: map[string][]time.Time{},
+	}
+}
+
+// Run starts a run of a policy, the run enforces both the global limits and the limits of the policy.
+// A nil budget only enforces the limits of the policy.
+func (b *Budget) Run(policy string, limits Limits) *Run {
+	if b == nil {
+		b = New(Limits{})
+	}
+	return &Run{
+		budget:        b,
+		policy:        policy,
+		limits:        limits,
+		maxPerRun:     stricter(b.global.MaxPerRun, limits.MaxPerRun),
+		maxPercentage: stricter(b.global.MaxPercentage, limits.MaxPercentage),
+	}
+}
+
+// prune drops the deletions older than a minute, it must be called with the lock held.
+func (b *Budget) prune(now time.Time) {
+	b.deleted = recent(b.deleted, now)
+	for policy, deleted := range b.policies {
+		if deleted = recent(deleted, now); len(deleted) == 0 {
+			delete(b.policies, policy)
+		} else {
+			b.policies[policy] = deleted
+		}
+	}
+}
+
+// Run tracks the deletions of a single run of a policy.
+type Run struct {
+	budget        *Budget
+	policy        string
+	limits        Limits
+	maxPerRun     int
+	maxPercentage int
+	deleted       int
+	reserved      []time.Time
+}
+
+// CheckPercentage returns an ExceededError when deleting matched resources out of the total resources of a kind
+// would exceed the maximum percentage.
+func (r *Run) CheckPercentage(kind string, matched, total int) error {
+	if r.maxPercentage == 0 || total == 0 {
+		return nil
+	}
+	if matched*100 > r.maxPercentage*total {
+		return &ExceededError{
+			Message: fmt.Sprintf("deleting %d of %d %s resources exceeds the maximum of %d%%", matched, total, kind, r.maxPercentage),
+		}
+	}
+	return nil
+}
+
+// Reserve accounts for a deletion. It returns an ExceededError, without accounting for the deletion,
+// when the deletion would exceed one of the limits.
+func (r *Run) Reserve() error {
+	b := r.budget
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	now := b.clock.Now()
+	b.prune(now)
+	if r.maxPerRun != 0 && r.deleted >= r.maxPerRun {
+		return &ExceededError{Message: fmt.Sprintf("the run reached the maximum of %d deletions", r.maxPerRun)}
+	}
+	if r.limits.MaxPerMinute != 0 && len(b.policies[r.policy]) >= r.limits.MaxPerMinute {
+		return &ExceededError{Message: fmt.Sprintf("the policy reached the maximum of %d deletions per minute", r.limits.MaxPerMinute)}
+	}
+	if b.global.MaxPerMinute != 0 && len(b.deleted) >= b.global.MaxPerMinute {
+		return &ExceededError{Message: fmt.Sprintf("the global maximum of %d deletions per minute was reached", b.global.MaxPerMinute)}
+	}
+	b.deleted = append(b.deleted, now)
+	b.policies[r.policy] = append(b.policies[r.policy], now)
+	r.reserved = append(r.reserved, now)
+	r.deleted++
+	return nil
+}
+
+// Release gives back the last deletion reserved by the run, it is used when the deletion was not performed.
+func (r *Run) Release() {
+	if len(r.reserved) == 0 {
+		return
+	}
+	b := r.budget
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	reserved := r.reserved[len(r.reserved)-1]
+	r.reserved = r.reserved[:len(r.reserved)-1]
+	r.deleted--
+	b.deleted = remove(b.deleted, reserved)
+	if deleted := remove(b.policies[r.policy], reserved); len(deleted) == 0 {
+		delete(b.policies, r.policy)
+	} else {
+		b.policies[r.policy] = deleted
+	}
+}
+
+// stricter returns the lowest of two limits, zero means no limit.
+func stricter(a, b int) int {
+	if a == 0 || (b != 0 && b < a) {
+		return b
+	}
+	return a
+}
+
+// recent returns the deletions of the last minute, deletions are sorted from the oldest to the most recent.
+func recent(deleted []time.Time, now time.Time) []time.Time {
+	for i, t := range deleted {
+		if now.Sub(t) < time.Minute {
+			return deleted[i:]
+		}
+	}
+	return nil
+}
+
+// remove removes the most recent occurrence of t.
+func remove(deleted []time.Time, t time.Time) []time.Time {
+	for i := len(deleted) - 1; i >= 0; i-- {
+		if deleted[i].Equal(t) {
+			return append(deleted[:i], deleted[i+1:]...)
+		}
+	}
+	return deleted
+}
@@ -0,0 +1,103 @@
+package budget
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	clocktesting "k8s.io/utils/clock/testing"
+)
+
+func newFakeBudget(global Limits) (*Budget, *clocktesting.FakePassiveClock) {
+	clock := clocktesting.NewFakePassiveClock(time.Now())
+	budget := New(global)
+	budget.clock = clock
+	return budget, clock
+}
+
+func reserve(run *Run, count int) error {
+	for i := 0; i < count; i++ {
+		if err := run.Reserve(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestRun_MaxPerRun(t *testing.T) {
+	tests := []struct {
+		name    string
+		global  Limits
+		limits  Limits
+		allowed int
+	}{{
+		name:    "no limit",
+		allowed: 100,
+	}, {
+		name:    "policy limit",
+		limits:  Limits{MaxPerRun: 5},
+		allowed: 5,
+	}, {
+		name:    "global limit",
+		global:  Limits{MaxPerRun: 3},
+		limits:  Limits{MaxPerRun: 5},
+		allowed: 3,
+	}, {
+		name:    "stricter policy limit",
+		global:  Limits{MaxPerRun: 10},
+		limits:  Limits{MaxPerRun: 2},
+		allowed: 2,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			budget, _ := newFakeBudget(tt.global)
+			run := budget.Run("policy", tt.limits)
+			assert.NoError(t, reserve(run, tt.allowed))
+			if tt.allowed < 100 {
+				err := run.Reserve()
+				assert.True(t, IsExceeded(err))
+				assert.Equal(t, fmt.Sprintf("deletion budget exceeded: the run reached the maximum of %d deletions", tt.allowed), err.Error())
+			}
+			// a new run starts with a fresh budget
+			assert.NoError(t, budget.Run("policy", tt.limits).Reserve())
+		})
+	}
+}
+
+func TestRun_MaxPerMinute(t *testing.T) {
+	budget, clock := newFakeBudget(Limits{MaxPerMinute: 5})
+	run := budget.Run("a", Limits{MaxPerMinute: 3})
+	assert.NoError(t, reserve(run, 3))
+	assert.EqualError(t, run.Reserve(), "deletion budget exceeded: the policy reached the maximum of 3 deletions per minute")
+	// the limit of a policy doesn't apply to other policies, the global limit applies to all of them
+	other := budget.Run("b", Limits{})
+	assert.NoError(t, reserve(other, 2))
+	assert.EqualError(t, other.Reserve(), "deletion budget exceeded: the global maximum of 5 deletions per minute was reached")
+	// released deletions are not accounted for
+	other.Release()
+	assert.NoError(t, other.Reserve())
+	// deletions older than a minute are not accounted for
+	clock.SetTime(clock.Now().Add(time.Minute))
+	assert.NoError(t, reserve(budget.Run("a", Limits{MaxPerMinute: 3}), 3))
+}
+
+func TestRun_CheckPercentage(t *testing.T) {
+	budget, _ := newFakeBudget(Limits{MaxPercentage: 50})
+	run := budget.Run("policy", Limits{MaxPercentage: 10})
+	assert.NoError(t, run.CheckPercentage("Pod", 1, 10))
+	assert.NoError(t, run.CheckPercentage("Pod", 0, 0))
+	assert.EqualError(t, run.CheckPercentage("Pod", 2, 10), "deletion budget exceeded: deleting 2 of 10 Pod resources exceeds the maximum of 10%")
+	run = budget.Run("policy", Limits{})
+	assert.NoError(t, run.CheckPercentage("Pod", 5, 10))
+	assert.Error(t, run.CheckPercentage("Pod", 6, 10))
+}
+
+func TestRun_NilBudget(t *testing.T) {
+	var budget *Budget
+	run := budget.Run("policy", Limits{MaxPerRun: 1})
+	assert.NoError(t, run.Reserve())
+	assert.True(t, IsExceeded(fmt.Errorf("wrapped: %w", run.Reserve())))
+	assert.False(t, IsExceeded(errors.New("other")))
+}
@@ -0,0 +1,137 @@
+package compiler
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/kyverno/kyverno/pkg/engine/explain"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Observer is notified of the expressions evaluated by CEL policies, it is used to compute policy coverage.
+// Policies are identified by their kind and namespace/name key, expressions by their path in the policy spec,
+// e.g. matchConditions[0], variables.name, validations[1] or mutations[0].
+type Observer interface {
+	// Expression is called after the expression is evaluated, passed is false when a condition evaluated to false.
+	Expression(kind, policy, expression string, passed bool, err error)
+}
+
+// TimingObserver is optionally implemented by observers also notified of the time spent evaluating expressions,
+// it is used to benchmark policies.
+type TimingObserver interface {
+	// Elapsed is called after the expression is evaluated.
+	Elapsed(kind, policy, expression string, elapsed time.Duration)
+}
+
+type observerKey struct{}
+
+// WithObserver returns a context notifying observer of the expressions evaluated by policies.
+func WithObserver(ctx context.Context, observer Observer) context.Context {
+	return context.WithValue(ctx, observerKey{}, observer)
+}
+
+// ObserverFrom returns the observer of the context, if any.
+func ObserverFrom(ctx context.Context) Observer {
+	observer, _ := ctx.Value(observerKey{}).(Observer)
+	return observer
+}
+
+// Tracer notifies the observer of a context of the expressions evaluated by a policy and records them in the
+// explain trace of the context. A nil tracer is valid and records nothing.
+type Tracer struct {
+	ctx       context.Context //nolint:containedctx
+	observer  Observer
+	timing    TimingObserver
+	explained bool
+	kind      string
+	policy    string
+}
+
+// NewTracer returns a tracer for the policy, it returns nil when the context has no observer and no explain trace.
+func NewTracer(ctx context.Context, kind, policy string) *Tracer {
+	observer := ObserverFrom(ctx)
+	explained := explain.Enabled(ctx)
+	if observer == nil && !explained {
+		return nil
+	}
+	timing, _ := observer.(TimingObserver)
+	return &Tracer{
+		ctx:       ctx,
+		observer:  observer,
+		timing:    timing,
+		explained: explained,
+		kind:      kind,
+		policy:    policy,
+	}
+}
+
+// Start returns the time the evaluation of an expression starts, the clock is only read when timings are observed.
+func (t *Tracer) Start() time.Time {
+	if t == nil || t.timing == nil {
+		return time.Time{}
+	}
+	return time.Now()
+}
+
+// Done records the outcome of an expression whose evaluation started at start.
+func (t *Tracer) Done(expression string, start time.Time, passed bool, err error) {
+	if t == nil {
+		return
+	}
+	if t.timing != nil {
+		t.timing.Elapsed(t.kind, t.policy, expression, time.Since(start))
+	}
+	t.record(explain.TypeExpression, expression, expression, explain.Bool(passed), passed, err, nil)
+}
+
+// Elapsed records the outcome of an expression evaluated by a third party that measured its duration.
+func (t *Tracer) Elapsed(expression string, elapsed time.Duration, passed bool, err error) {
+	if t == nil {
+		return
+	}
+	if t.timing != nil {
+		t.timing.Elapsed(t.kind, t.policy, expression, elapsed)
+	}
+	t.record(explain.TypeExpression, expression, expression, explain.Bool(passed), passed, err, nil)
+}
+
+// Variable records the value of a variable whose evaluation started at start.
+func (t *Tracer) Variable(name string, start time.Time, value ref.Val, err error) {
+	if t == nil {
+		return
+	}
+	expression := "variables." + name
+	if t.timing != nil {
+		t.timing.Elapsed(t.kind, t.policy, expression, time.Since(start))
+	}
+	t.record(explain.TypeVariable, name, expression, "evaluated", true, err, value)
+}
+
+func (t *Tracer) record(stepType, name, expression, result string, passed bool, err error, value ref.Val) {
+	if t.observer != nil {
+		t.observer.Expression(t.kind, t.policy, expression, passed, err)
+	}
+	if !t.explained {
+		return
+	}
+	var message string
+	if err != nil {
+		result, message = "error", err.Error()
+	}
+	var values map[string]any
+	if value != nil && err == nil {
+		values = map[string]any{"value": explainValue(value)}
+	}
+	explain.Record(t.ctx, stepType, name, result, message, values)
+}
+
+// explainValue converts a CEL value to a JSON compatible value.
+func explainValue(value ref.Val) any {
+	if native, err := value.ConvertToNative(reflect.TypeOf(&structpb.Value{})); err == nil {
+		return native.(*structpb.Value).AsInterface()
+	}
+	return fmt.Sprint(value.Value())
+}
@@ -0,0 +1,91 @@
+package compiler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/cel-go/common/types"
+	"github.com/kyverno/kyverno/pkg/engine/explain"
+	"github.com/stretchr/testify/assert"
+)
+
+type expression struct {
+	kind, policy, expression string
+	passed                   bool
+	err                      error
+}
+
+type recorder struct {
+	expressions []expression
+	elapsed     []string
+}
+
+func (r *recorder) Expression(kind, policy, name string, passed bool, err error) {
+	r.expressions = append(r.expressions, expression{kind, policy, name, passed, err})
+}
+
+type timingRecorder struct {
+	recorder
+}
+
+func (r *timingRecorder) Elapsed(kind, policy, expression string, elapsed time.Duration) {
+	r.elapsed = append(r.elapsed, expression)
+}
+
+func TestNewTracer(t *testing.T) {
+	assert.Nil(t, NewTracer(context.TODO(), "ValidatingPolicy", "policy"))
+	assert.NotNil(t, NewTracer(WithObserver(context.TODO(), &recorder{}), "ValidatingPolicy", "policy"))
+	assert.NotNil(t, NewTracer(explain.WithTrace(context.TODO(), explain.NewTrace()), "ValidatingPolicy", "policy"))
+	// a nil tracer records nothing
+	var tracer *Tracer
+	assert.True(t, tracer.Start().IsZero())
+	tracer.Done("validations[0]", time.Time{}, true, nil)
+	tracer.Variable("name", time.Time{}, types.String("value"), nil)
+}
+
+func TestTracer_observer(t *testing.T) {
+	observer := &recorder{}
+	tracer := NewTracer(WithObserver(context.TODO(), observer), "MutatingPolicy", "default/policy")
+	// the clock is only read when timings are observed
+	assert.True(t, tracer.Start().IsZero())
+	err := errors.New("no such key")
+	tracer.Done("matchConditions[0]", time.Time{}, true, nil)
+	tracer.Variable("name", time.Time{}, nil, err)
+	tracer.Elapsed("mutations[0]", time.Second, false, nil)
+	assert.Equal(t, []expression{
+		{"MutatingPolicy", "default/policy", "matchConditions[0]", true, nil},
+		{"MutatingPolicy", "default/policy", "variables.name", true, err},
+		{"MutatingPolicy", "default/policy", "mutations[0]", false, nil},
+	}, observer.expressions)
+	assert.Empty(t, observer.elapsed)
+}
+
+func TestTracer_timing(t *testing.T) {
+	observer := &timingRecorder{}
+	tracer := NewTracer(WithObserver(context.TODO(), observer), "GeneratingPolicy", "policy")
+	start := tracer.Start()
+	assert.False(t, start.IsZero())
+	tracer.Done("generate[0]", start, true, nil)
+	tracer.Variable("name", start, types.String("value"), nil)
+	assert.Equal(t, []string{"generate[0]", "variables.name"}, observer.elapsed)
+	assert.Len(t, observer.expressions, 2)
+}
+
+func TestTracer_explain(t *testing.T) {
+	trace := explain.NewTrace()
+	tracer := NewTracer(explain.WithTrace(context.TODO(), trace), "DeletingPolicy", "policy")
+	tracer.Done("conditions[0]", time.Time{}, false, nil)
+	tracer.Variable("name", time.Time{}, types.String("value"), nil)
+	tracer.Done("conditions[1]", time.Time{}, false, errors.New("no such key"))
+	steps := trace.Steps()
+	assert.Len(t, steps, 3)
+	assert.Equal(t, &explain.Step{Type: explain.TypeExpression, Name: "conditions[0]", Result: "false"}, stripTrace(steps[0]))
+	assert.Equal(t, &explain.Step{Type: explain.TypeVariable, Name: "name", Result: "evaluated", Values: map[string]any{"value": "value"}}, stripTrace(steps[1]))
+	assert.Equal(t, &explain.Step{Type: explain.TypeExpression, Name: "conditions[1]", Result: "error", Message: "no such key"}, stripTrace(steps[2]))
+}
+
+func stripTrace(step *explain.Step) *explain.Step {
+	return &explain.Step{Type: step.Type, Name: step.Name, Result: step.Result, Message: step.Message, Values: step.Values}
+}
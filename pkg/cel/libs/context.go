@@ -3,6 +3,7 @@ package libs
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/kyverno/kyverno/api/kyverno"
 	"github.com/kyverno/kyverno/pkg/background/common"
@@ -81,13 +82,17 @@ func NewContextProvider(
 }
 
 func (cp *contextProvider) GetGlobalReference(name, projection string) (any, error) {
+	return cp.GetGlobalReferenceWithMaxAge(name, projection, 0)
+}
+
+func (cp *contextProvider) GetGlobalReferenceWithMaxAge(name, projection string, maxAge time.Duration) (any, error) {
 	ent, ok := cp.gctxStore.Get(name)
 	if !ok {
 		logger := logging.GlobalLogger()
 		logger.V(2).Info("global context entry not found, returning nil", "entry", name, "projection", projection)
 		return nil, nil
 	}
-	data, err := ent.Get(projection)
+	data, err := gctxstore.GetWithMaxAge(ent, projection, maxAge)
 	if err != nil {
 		return nil, err
 	}
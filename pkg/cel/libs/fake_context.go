@@ -2,6 +2,7 @@ package libs
 
 import (
 	"fmt"
+	"time"
 
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -58,6 +59,10 @@ func (cp *FakeContextProvider) GetGlobalReference(string, string) (any, error) {
 	panic("not implemented")
 }
 
+func (cp *FakeContextProvider) GetGlobalReferenceWithMaxAge(string, string, time.Duration) (any, error) {
+	panic("not implemented")
+}
+
 func (cp *FakeContextProvider) GetImageData(image string) (map[string]any, error) {
 	if cp.images == nil {
 		return nil, fmt.Errorf("image data not found in the context")
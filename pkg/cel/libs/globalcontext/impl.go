@@ -1,6 +1,8 @@
 package globalcontext
 
 import (
+	"time"
+
 	"github.com/google/cel-go/common/types"
 	"github.com/google/cel-go/common/types/ref"
 	"github.com/kyverno/kyverno/pkg/cel/utils"
@@ -44,3 +46,43 @@ func (c *impl) get_string_string(args ...ref.Val) ref.Val {
 		return c.NativeToValue(globalRef)
 	}
 }
+
+func (c *impl) get_string_duration(args ...ref.Val) ref.Val {
+	if len(args) != 3 {
+		return types.NewErr("expected 3 arguments, got %d", len(args))
+	}
+	if self, err := utils.ConvertToNative[Context](args[0]); err != nil {
+		return types.WrapErr(err)
+	} else if name, err := utils.ConvertToNative[string](args[1]); err != nil {
+		return types.WrapErr(err)
+	} else if maxAge, err := utils.ConvertToNative[time.Duration](args[2]); err != nil {
+		return types.WrapErr(err)
+	} else {
+		globalRef, err := self.GetGlobalReferenceWithMaxAge(name, "", maxAge)
+		if err != nil {
+			return types.NewErr("failed to get global reference: %v", err)
+		}
+		return c.NativeToValue(globalRef)
+	}
+}
+
+func (c *impl) get_string_string_duration(args ...ref.Val) ref.Val {
+	if len(args) != 4 {
+		return types.NewErr("expected 4 arguments, got %d", len(args))
+	}
+	if self, err := utils.ConvertToNative[Context](args[0]); err != nil {
+		return types.WrapErr(err)
+	} else if name, err := utils.ConvertToNative[string](args[1]); err != nil {
+		return types.WrapErr(err)
+	} else if projection, err := utils.ConvertToNative[string](args[2]); err != nil {
+		return types.WrapErr(err)
+	} else if maxAge, err := utils.ConvertToNative[time.Duration](args[3]); err != nil {
+		return types.WrapErr(err)
+	} else {
+		globalRef, err := self.GetGlobalReferenceWithMaxAge(name, projection, maxAge)
+		if err != nil {
+			return types.NewErr("failed to get global reference: %v", err)
+		}
+		return c.NativeToValue(globalRef)
+	}
+}
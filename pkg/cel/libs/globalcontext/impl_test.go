@@ -3,6 +3,7 @@ package globalcontext
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/google/cel-go/cel"
 	"github.com/google/cel-go/common/types"
@@ -178,6 +179,79 @@ func Test_impl_get_string_string(t *testing.T) {
 	}
 }
 
+func Test_impl_get_string_string_duration(t *testing.T) {
+	base, err := compiler.NewBaseEnv()
+	assert.NoError(t, err)
+	assert.NotNil(t, base)
+	options := []cel.EnvOption{
+		cel.Variable("globalContext", ContextType),
+		Lib(nil),
+	}
+	env, err := base.Extend(options...)
+	assert.NoError(t, err)
+	assert.NotNil(t, env)
+	ast, issues := env.Compile(`globalContext.Get("foo", "bar", duration("1m"))`)
+	assert.Nil(t, issues)
+	assert.NotNil(t, ast)
+	prog, err := env.Program(ast)
+	assert.NoError(t, err)
+	assert.NotNil(t, prog)
+	tests := []struct {
+		name          string
+		gctxStoreData map[string]store.Entry
+		expectedValue any
+		expectedError string
+	}{{
+		name:          "global context entry not found",
+		gctxStoreData: map[string]store.Entry{},
+		expectedValue: structpb.NullValue(0),
+	}, {
+		name: "global context entry is fresh",
+		gctxStoreData: map[string]store.Entry{
+			"foo": &MockEntry{Data: "stringValue", LastRefresh: time.Now()},
+		},
+		expectedValue: "stringValue",
+	}, {
+		name: "global context entry is stale",
+		gctxStoreData: map[string]store.Entry{
+			"foo": &MockEntry{Data: "stringValue", LastRefresh: time.Now().Add(-time.Hour)},
+		},
+		expectedError: "global context entry data is stale",
+	}, {
+		name: "global context entry was never refreshed",
+		gctxStoreData: map[string]store.Entry{
+			"foo": &MockEntry{Data: "stringValue"},
+		},
+		expectedError: "entry was never refreshed",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStore := &MockGctxStore{Data: tt.gctxStoreData}
+			data := map[string]any{
+				"globalContext": Context{&ContextMock{
+					GetGlobalReferenceWithMaxAgeFunc: func(name string, path string, maxAge time.Duration) (any, error) {
+						assert.Equal(t, "bar", path)
+						assert.Equal(t, time.Minute, maxAge)
+						ent, ok := mockStore.Get(name)
+						if !ok {
+							return nil, nil
+						}
+						return store.GetWithMaxAge(ent, path, maxAge)
+					},
+				}},
+			}
+			out, _, err := prog.Eval(data)
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedValue, out.Value())
+			}
+		})
+	}
+}
+
 func Test_impl_get_string_error(t *testing.T) {
 	base, err := compiler.NewBaseEnv()
 	assert.NoError(t, err)
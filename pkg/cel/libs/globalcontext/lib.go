@@ -60,6 +60,18 @@ func (c *lib) extendEnv(env *cel.Env) (*cel.Env, error) {
 				types.DynType,
 				cel.FunctionBinding(impl.get_string_string),
 			),
+			cel.MemberOverload(
+				"globalcontext_get_string_duration",
+				[]*cel.Type{ContextType, types.StringType, types.DurationType},
+				types.DynType,
+				cel.FunctionBinding(impl.get_string_duration),
+			),
+			cel.MemberOverload(
+				"globalcontext_get_string_string_duration",
+				[]*cel.Type{ContextType, types.StringType, types.StringType, types.DurationType},
+				types.DynType,
+				cel.FunctionBinding(impl.get_string_string_duration),
+			),
 		},
 	}
 	// create env options corresponding to our function overloads
@@ -1,17 +1,24 @@
 package globalcontext
 
 import (
+	"time"
+
 	"github.com/kyverno/kyverno/pkg/globalcontext/store"
 )
 
 type ContextMock struct {
-	GetGlobalReferenceFunc func(string, string) (any, error)
+	GetGlobalReferenceFunc           func(string, string) (any, error)
+	GetGlobalReferenceWithMaxAgeFunc func(string, string, time.Duration) (any, error)
 }
 
 func (mock *ContextMock) GetGlobalReference(n, p string) (any, error) {
 	return mock.GetGlobalReferenceFunc(n, p)
 }
 
+func (mock *ContextMock) GetGlobalReferenceWithMaxAge(n, p string, maxAge time.Duration) (any, error) {
+	return mock.GetGlobalReferenceWithMaxAgeFunc(n, p, maxAge)
+}
+
 type MockGctxStore struct {
 	Data map[string]store.Entry
 }
@@ -29,12 +36,17 @@ func (m *MockGctxStore) Set(name string, data store.Entry) {
 }
 
 type MockEntry struct {
-	Data any
-	Err  error
+	Data        any
+	Err         error
+	LastRefresh time.Time
 }
 
 func (m *MockEntry) Get(_ string) (any, error) {
 	return m.Data, m.Err
 }
 
+func (m *MockEntry) LastRefreshTime() time.Time {
+	return m.LastRefresh
+}
+
 func (m *MockEntry) Stop() {}
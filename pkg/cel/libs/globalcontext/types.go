@@ -1,6 +1,8 @@
 package globalcontext
 
 import (
+	"time"
+
 	"github.com/google/cel-go/common/types"
 )
 
@@ -8,6 +10,7 @@ var ContextType = types.NewOpaqueType("globalcontext.Context")
 
 type ContextInterface interface {
 	GetGlobalReference(string, string) (any, error)
+	GetGlobalReferenceWithMaxAge(string, string, time.Duration) (any, error)
 }
 
 type Context struct {
@@ -0,0 +1,114 @@
+package http
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kyverno/kyverno/pkg/metrics"
+	"golang.org/x/sync/singleflight"
+	utilcache "k8s.io/apimachinery/pkg/util/cache"
+)
+
+const (
+	// CacheScopeRequest caches a response for the rest of the admission request.
+	CacheScopeRequest = "request"
+	// requestCacheSize is the maximum number of responses cached for a single admission request.
+	requestCacheSize = 100
+	// requestCacheTTL bounds the lifetime of responses cached for an admission request, it matches the maximum webhook timeout.
+	requestCacheTTL = 30 * time.Second
+)
+
+type requestCacheKey struct{}
+
+// WithRequestCache returns a context carrying a cache shared by the policies evaluated for a single admission request,
+// responses of requests sent with the request cache scope are only cached when such a cache exists.
+func WithRequestCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, requestCacheKey{}, newResponseCache(requestCacheSize))
+}
+
+func requestCacheFrom(ctx context.Context) *responseCache {
+	if ctx == nil {
+		return nil
+	}
+	cache, _ := ctx.Value(requestCacheKey{}).(*responseCache)
+	return cache
+}
+
+// responseCache caches decoded responses and coalesces concurrent identical requests.
+type responseCache struct {
+	cache *utilcache.LRUExpireCache
+	group singleflight.Group
+}
+
+func newResponseCache(size int) *responseCache {
+	if size <= 0 {
+		return nil
+	}
+	return &responseCache{
+		cache: utilcache.NewLRUExpireCache(size),
+	}
+}
+
+// get returns the cached response for the given request or calls fetch, concurrent callers of the same request share the result.
+func (c *responseCache) get(req *http.Request, identity string, ttl time.Duration, fetch func() (any, error)) (any, error) {
+	if c == nil || ttl <= 0 {
+		return fetch()
+	}
+	key := cacheKey(req, identity)
+	if value, ok := c.cache.Get(key); ok {
+		recordCache(req.Context(), true)
+		return value, nil
+	}
+	recordCache(req.Context(), false)
+	value, err, _ := c.group.Do(key, func() (any, error) {
+		value, err := fetch()
+		if err == nil {
+			c.cache.Add(key, value, ttl)
+		}
+		return value, err
+	})
+	return value, err
+}
+
+// cacheKey identifies a request by client identity, method, url and headers, so that responses are never shared between different credentials.
+func cacheKey(req *http.Request, identity string) string {
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var key strings.Builder
+	key.WriteString(identity)
+	key.WriteString("\n")
+	key.WriteString(req.Method)
+	key.WriteString(" ")
+	key.WriteString(req.URL.String())
+	for _, name := range names {
+		key.WriteString("\n")
+		key.WriteString(name)
+		key.WriteString(": ")
+		key.WriteString(strings.Join(req.Header.Values(name), ","))
+	}
+	return key.String()
+}
+
+// clientIdentity identifies the TLS and authentication settings of a client.
+func clientIdentity(options ClientOptions) string {
+	caBundle := sha256.Sum256([]byte(options.CABundle))
+	return strings.Join([]string{
+		hex.EncodeToString(caBundle[:]),
+		options.ClientCertSecret,
+		options.TokenAudience,
+	}, "/")
+}
+
+func recordCache(ctx context.Context, hit bool) {
+	if m := metrics.GetCELHTTPMetrics(); m != nil {
+		m.RecordCache(ctx, hit)
+	}
+}
@@ -0,0 +1,183 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/cel-go/cel"
+	"github.com/kyverno/kyverno/pkg/cel/compiler"
+	"github.com/stretchr/testify/assert"
+)
+
+func countingClient(calls *atomic.Int64, wait <-chan struct{}) testClient {
+	return testClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			calls.Add(1)
+			if wait != nil {
+				<-wait
+			}
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(`{"body": "ok"}`))}, nil
+		},
+	}
+}
+
+func Test_impl_get_request_with_options(t *testing.T) {
+	base, err := compiler.NewBaseEnv()
+	assert.NoError(t, err)
+	env, err := base.Extend(
+		cel.Variable("http", ContextType),
+		Lib(nil, nil),
+	)
+	assert.NoError(t, err)
+	tests := []struct {
+		name      string
+		expr      string
+		wantCalls int64
+		wantErr   string
+	}{{
+		name:      "duration ttl",
+		expr:      `http.Get("http://localhost:8080", {}, {"cacheTTL": duration("1m")})`,
+		wantCalls: 1,
+	}, {
+		name:      "string ttl",
+		expr:      `http.Get("http://localhost:8080", {}, {"cacheTTL": "1m"})`,
+		wantCalls: 1,
+	}, {
+		name:      "no ttl",
+		expr:      `http.Get("http://localhost:8080", {}, {})`,
+		wantCalls: 2,
+	}, {
+		name:      "request scope outside of an admission request",
+		expr:      `http.Get("http://localhost:8080", {}, {"cacheScope": "request"})`,
+		wantCalls: 2,
+	}, {
+		name:    "invalid scope",
+		expr:    `http.Get("http://localhost:8080", {}, {"cacheScope": "cluster"})`,
+		wantErr: "cacheScope must be",
+	}, {
+		name:    "invalid ttl",
+		expr:    `http.Get("http://localhost:8080", {}, {"cacheTTL": 1})`,
+		wantErr: "cacheTTL must be a duration",
+	}, {
+		name:    "unknown option",
+		expr:    `http.Get("http://localhost:8080", {}, {"foo": "bar"})`,
+		wantErr: "unknown request option: foo",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ast, issues := env.Compile(tt.expr)
+			assert.Nil(t, issues)
+			prog, err := env.Program(ast)
+			assert.NoError(t, err)
+			var calls atomic.Int64
+			cache := newResponseCache(10)
+			// two evaluations sharing the same cache
+			for range 2 {
+				out, _, err := prog.Eval(map[string]any{
+					"http": Context{&contextImpl{client: countingClient(&calls, nil), cache: cache}},
+				})
+				if tt.wantErr != "" {
+					assert.ErrorContains(t, err, tt.wantErr)
+					return
+				}
+				assert.NoError(t, err)
+				assert.Equal(t, "ok", out.Value().(map[string]any)["body"])
+			}
+			assert.Equal(t, tt.wantCalls, calls.Load())
+		})
+	}
+}
+
+func Test_responseCache(t *testing.T) {
+	var calls atomic.Int64
+	cache := newResponseCache(10)
+	client := &contextImpl{client: countingClient(&calls, nil), cache: cache}
+	options := RequestOptions{CacheTTL: time.Minute}
+	_, err := client.GetWithOptions("http://localhost:8080", nil, options)
+	assert.NoError(t, err)
+	_, err = client.GetWithOptions("http://localhost:8080", nil, options)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), calls.Load())
+	// requests with different headers are cached separately
+	_, err = client.GetWithOptions("http://localhost:8080", map[string]string{"Authorization": "Bearer token"}, options)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), calls.Load())
+	// clients with different credentials are cached separately
+	derived, err := client.ClientWithOptions(ClientOptions{TokenAudience: "audience"})
+	assert.NoError(t, err)
+	_, err = derived.GetWithOptions("http://localhost:8080", map[string]string{"Authorization": "Bearer token"}, options)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), calls.Load())
+	// a disabled cache always sends requests
+	client.cache = newResponseCache(0)
+	_, err = client.GetWithOptions("http://localhost:8080", nil, options)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), calls.Load())
+}
+
+func Test_responseCache_shared(t *testing.T) {
+	var calls atomic.Int64
+	options := RequestOptions{CacheTTL: time.Minute}
+	// the shared cache is disabled by default
+	for range 2 {
+		_, err := configured(NewHTTP(countingClient(&calls, nil)), &Config{}).GetWithOptions("http://localhost:8080", nil, options)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int64(2), calls.Load())
+	config := &Config{CacheSize: 10}
+	for range 2 {
+		_, err := configured(NewHTTP(countingClient(&calls, nil)), config).GetWithOptions("http://localhost:8080", nil, options)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int64(3), calls.Load())
+}
+
+func Test_responseCache_request(t *testing.T) {
+	var calls atomic.Int64
+	options := RequestOptions{CacheScope: CacheScopeRequest}
+	// contexts created for the same admission request share responses
+	ctx := WithRequestCache(context.Background())
+	for range 2 {
+		_, err := NewHTTPWithContext(ctx, countingClient(&calls, nil)).GetWithOptions("http://localhost:8080", nil, options)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int64(1), calls.Load())
+	// a new admission request sends the request again
+	_, err := NewHTTPWithContext(WithRequestCache(context.Background()), countingClient(&calls, nil)).GetWithOptions("http://localhost:8080", nil, options)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), calls.Load())
+	// responses are not cached outside of an admission request
+	for range 2 {
+		_, err := NewHTTPWithContext(context.Background(), countingClient(&calls, nil)).GetWithOptions("http://localhost:8080", nil, options)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int64(4), calls.Load())
+}
+
+func Test_responseCache_coalescing(t *testing.T) {
+	var calls atomic.Int64
+	wait := make(chan struct{})
+	client := &contextImpl{client: countingClient(&calls, wait), cache: newResponseCache(10)}
+	var group sync.WaitGroup
+	for range 5 {
+		group.Add(1)
+		go func() {
+			defer group.Done()
+			data, err := client.GetWithOptions("http://localhost:8080", nil, RequestOptions{CacheTTL: time.Minute})
+			assert.NoError(t, err)
+			assert.Equal(t, "ok", data.(map[string]any)["body"])
+		}()
+	}
+	// let the concurrent requests join the in flight request
+	assert.Eventually(t, func() bool { return calls.Load() == 1 }, time.Second, time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	close(wait)
+	group.Wait()
+	assert.Equal(t, int64(1), calls.Load())
+}
@@ -0,0 +1,41 @@
+package http
+
+import (
+	"sync"
+
+	"github.com/kyverno/kyverno/pkg/config"
+	"github.com/kyverno/kyverno/pkg/utils/credentials"
+)
+
+// Config configures the requests sent by the library, it is passed to Lib and applied to the
+// contexts created with NewHTTP when they are first used by an expression.
+// A nil Config sends unauthenticated and unrestricted requests.
+type Config struct {
+	// Credentials resolves the client certificates and tokens referenced by clients.
+	Credentials credentials.Provider
+	// Configuration provides the egress restrictions enforced on requests.
+	Configuration config.Configuration
+	// CacheSize is the maximum number of responses cached across admission requests, 0 disables the cache.
+	CacheSize int
+
+	cacheOnce sync.Once
+	cache     *responseCache
+}
+
+// sharedCache returns the cache shared by all the contexts using the configuration.
+func (c *Config) sharedCache() *responseCache {
+	if c == nil {
+		return nil
+	}
+	c.cacheOnce.Do(func() {
+		c.cache = newResponseCache(c.CacheSize)
+	})
+	return c.cache
+}
+
+func (c *Config) egress() *config.HTTPEgressConfig {
+	if c == nil || c.Configuration == nil {
+		return nil
+	}
+	return c.Configuration.GetHTTPEgress()
+}
@@ -0,0 +1,185 @@
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/ast"
+	"github.com/google/cel-go/common/types"
+	"github.com/kyverno/kyverno/pkg/config"
+	"github.com/kyverno/kyverno/pkg/metrics"
+	"github.com/kyverno/kyverno/pkg/tracing"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// maxRedirects is the number of redirects followed by clients enforcing the egress configuration, like http.Client does by default.
+const maxRedirects = 10
+
+// egress enforces the egress configuration on the requests sent during a policy evaluation.
+type egress struct {
+	config   *config.HTTPEgressConfig
+	requests atomic.Int64
+}
+
+func newEgress(config *config.HTTPEgressConfig) *egress {
+	return &egress{
+		config: config,
+	}
+}
+
+func (e *egress) checkRequest(req *http.Request) error {
+	if e == nil || e.config == nil {
+		return nil
+	}
+	if err := e.config.CheckRequest(req.Method, req.URL); err != nil {
+		return e.deny(req.Context(), err)
+	}
+	// only allowed requests count against the budget
+	if max := e.config.MaxRequestsPerPolicy; max > 0 && e.requests.Add(1) > max {
+		return e.deny(req.Context(), &config.HTTPEgressError{
+			Reason:  "limit",
+			Message: fmt.Sprintf("policy exceeded the maximum of %d requests", max),
+		})
+	}
+	return nil
+}
+
+// checkRedirect runs the egress checks on every redirect followed by a client.
+func (e *egress) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxRedirects)
+	}
+	return e.checkRequest(req)
+}
+
+// dialContext checks the addresses host names resolve to when connecting, so that a host
+// can't resolve to an allowed address when checked and to a denied one when connecting.
+func (e *egress) dialContext(dialer *net.Dialer) func(context.Context, string, string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+		dialer := *dialer
+		dialer.Control = func(_, address string, _ syscall.RawConn) error {
+			ip, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if err := e.config.CheckAddress(host, net.ParseIP(ip)); err != nil {
+				return e.deny(ctx, err)
+			}
+			return nil
+		}
+		return dialer.DialContext(ctx, network, addr)
+	}
+}
+
+// newClient creates a client using tlsConfig, connections and redirects are checked against the egress configuration.
+// Requests are not sent through proxies as the addresses the proxy connects to can't be checked.
+func (e *egress) newClient(tlsConfig *tls.Config) ClientInterface {
+	if e == nil || e.config == nil {
+		if tlsConfig == nil {
+			return http.DefaultClient
+		}
+		transport := &http.Transport{
+			TLSClientConfig: tlsConfig,
+		}
+		return &http.Client{
+			Transport: tracing.Transport(transport, otelhttp.WithFilter(tracing.RequestFilterIsInSpan)),
+		}
+	}
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+	transport := &http.Transport{
+		TLSClientConfig:       tlsConfig,
+		DialContext:           e.dialContext(dialer),
+		ForceAttemptHTTP2:     true,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+	return &http.Client{
+		Transport:     tracing.Transport(transport, otelhttp.WithFilter(tracing.RequestFilterIsInSpan)),
+		CheckRedirect: e.checkRedirect,
+	}
+}
+
+func (e *egress) maxResponseBytes() int64 {
+	if e == nil || e.config == nil {
+		return 0
+	}
+	return e.config.MaxResponseBytes
+}
+
+func (e *egress) deny(ctx context.Context, err error) error {
+	var egressErr *config.HTTPEgressError
+	if errors.As(err, &egressErr) {
+		if m := metrics.GetCELHTTPMetrics(); m != nil {
+			m.RecordBlocked(ctx, egressErr.Reason)
+		}
+	}
+	return err
+}
+
+// egressValidator rejects expressions sending requests to constant urls denied by the egress configuration.
+type egressValidator struct {
+	config *Config
+}
+
+func (egressValidator) Name() string {
+	return "kyverno.http.egress"
+}
+
+func (v egressValidator) Validate(_ *cel.Env, _ cel.ValidatorConfig, a *ast.AST, issues *cel.Issues) {
+	egress := v.config.egress()
+	if egress == nil {
+		return
+	}
+	root := ast.NavigateAST(a)
+	for _, name := range []string{"Get", "Post"} {
+		for _, call := range ast.MatchDescendants(root, ast.FunctionMatcher(name)) {
+			method := requestMethod(a.GetOverloadIDs(call.ID()))
+			args := call.AsCall().Args()
+			if method == "" || len(args) == 0 || args[0].Kind() != ast.LiteralKind {
+				continue
+			}
+			raw, ok := args[0].AsLiteral().(types.String)
+			if !ok {
+				continue
+			}
+			target, err := url.Parse(string(raw))
+			if err != nil {
+				continue
+			}
+			// host names are resolved at request time
+			if err := egress.CheckRequest(method, target); err != nil {
+				issues.ReportErrorAtID(args[0].ID(), "%v", err)
+			}
+		}
+	}
+}
+
+func requestMethod(overloads []string) string {
+	for _, overload := range overloads {
+		switch {
+		case strings.HasPrefix(overload, "http_get_"):
+			return http.MethodGet
+		case strings.HasPrefix(overload, "http_post_"):
+			return http.MethodPost
+		}
+	}
+	return ""
+}
@@ -0,0 +1,149 @@
+package http
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/google/cel-go/cel"
+	"github.com/kyverno/kyverno/pkg/cel/compiler"
+	"github.com/kyverno/kyverno/pkg/config"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func withEgress(t *testing.T, egress string) *Config {
+	c := config.NewDefaultConfiguration(false)
+	c.Load(&corev1.ConfigMap{Data: map[string]string{"httpEgress": egress}})
+	assert.NotNil(t, c.GetHTTPEgress())
+	return &Config{Configuration: c}
+}
+
+func configured(client ContextInterface, config *Config) ContextInterface {
+	client.(*contextImpl).configure(config)
+	return client
+}
+
+func Test_egress_request(t *testing.T) {
+	config := withEgress(t, `{
+		"allowedHosts": ["*.example.com"],
+		"allowedSchemes": ["https"],
+		"allowedMethods": ["GET"],
+		"maxRequestsPerPolicy": 2,
+		"maxResponseBytes": 16
+	}`)
+	response := `{"body": "ok"}`
+	client := configured(NewHTTP(testClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(response))}, nil
+		},
+	}), config)
+	_, err := client.Post("https://api.example.com", nil, nil)
+	assert.ErrorContains(t, err, "method POST is not allowed")
+	_, err = client.Get("http://api.example.com", nil)
+	assert.ErrorContains(t, err, "scheme http is not allowed")
+	_, err = client.Get("https://10.0.0.1", nil)
+	assert.ErrorContains(t, err, "host 10.0.0.1 is not allowed")
+	data, err := client.Get("https://api.example.com", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"body": "ok"}, data)
+	// the response size is limited
+	response = `{"body": "too large"}`
+	_, err = client.Get("https://api.example.com", nil)
+	assert.ErrorContains(t, err, "response exceeded the maximum size of 16 bytes")
+	// the request count is shared with derived clients
+	derived, err := client.Client("")
+	assert.NoError(t, err)
+	_, err = derived.Get("https://api.example.com", nil)
+	assert.ErrorContains(t, err, "policy exceeded the maximum of 2 requests")
+	// a new evaluation gets a new budget
+	response = `{"body": "ok"}`
+	_, err = configured(NewHTTP(client.(*contextImpl).client), config).Get("https://api.example.com", nil)
+	assert.NoError(t, err)
+}
+
+func Test_egress_connect(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"body": "ok"}`))
+	}))
+	defer server.Close()
+	target, err := url.Parse(server.URL)
+	assert.NoError(t, err)
+	// localhost is not an allowed host, the address it resolves to is checked when connecting
+	_, err = configured(NewHTTP(nil), withEgress(t, `{"allowedCIDRs": ["10.0.0.0/8"]}`)).Get("http://localhost:"+target.Port(), nil)
+	assert.ErrorContains(t, err, "of host localhost is not allowed")
+	data, err := configured(NewHTTP(nil), withEgress(t, `{"allowedCIDRs": ["127.0.0.0/8", "::1/128"]}`)).Get("http://localhost:"+target.Port(), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"body": "ok"}, data)
+}
+
+func Test_egress_redirect(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, "http://"+r.Host+"/data", http.StatusFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"body": "ok"}`))
+	}))
+	defer server.Close()
+	target, err := url.Parse(server.URL)
+	assert.NoError(t, err)
+	// the redirect to 127.0.0.1 is denied, only localhost is allowed
+	client := configured(NewHTTP(nil), withEgress(t, `{"allowedHosts": ["localhost"]}`))
+	_, err = client.Get(server.URL+"/data", nil)
+	assert.ErrorContains(t, err, "host 127.0.0.1 is not allowed")
+	_, err = client.Get("http://localhost:"+target.Port()+"/redirect", nil)
+	assert.NoError(t, err)
+	redirect := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, server.URL+"/data", http.StatusFound)
+	}))
+	defer redirect.Close()
+	redirectTarget, err := url.Parse(redirect.URL)
+	assert.NoError(t, err)
+	_, err = client.Get("http://localhost:"+redirectTarget.Port(), nil)
+	assert.ErrorContains(t, err, "host 127.0.0.1 is not allowed")
+}
+
+func Test_egress_validator(t *testing.T) {
+	config := withEgress(t, `{"allowedHosts": ["*.example.com"], "allowedMethods": ["GET"]}`)
+	base, err := compiler.NewBaseEnv()
+	assert.NoError(t, err)
+	env, err := base.Extend(
+		cel.Variable("http", ContextType),
+		cel.Variable("url", cel.StringType),
+		Lib(config, nil),
+	)
+	assert.NoError(t, err)
+	tests := []struct {
+		name    string
+		expr    string
+		wantErr string
+	}{{
+		name: "allowed",
+		expr: `http.Get("https://api.example.com/data")`,
+	}, {
+		name: "dynamic url",
+		expr: `http.Get(url)`,
+	}, {
+		name:    "denied host",
+		expr:    `http.Get("https://evil.com/data")`,
+		wantErr: "host evil.com is not allowed",
+	}, {
+		name:    "denied method",
+		expr:    `http.Client("").Post("https://api.example.com/data", {"key": "value"})`,
+		wantErr: "method POST is not allowed",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, issues := env.Compile(tt.expr)
+			if tt.wantErr == "" {
+				assert.NoError(t, issues.Err())
+			} else {
+				assert.ErrorContains(t, issues.Err(), tt.wantErr)
+			}
+		})
+	}
+}
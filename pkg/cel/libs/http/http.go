@@ -3,15 +3,15 @@ package http
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
-	"crypto/x509"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"sync"
 
-	"github.com/kyverno/kyverno/pkg/tracing"
-	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"github.com/kyverno/kyverno/pkg/breaker"
+	"github.com/kyverno/kyverno/pkg/config"
+	"github.com/kyverno/kyverno/pkg/utils/credentials"
 )
 
 type ClientInterface interface {
@@ -19,46 +19,115 @@ type ClientInterface interface {
 }
 
 type contextImpl struct {
-	client ClientInterface
+	ctx           context.Context
+	client        ClientInterface
+	credentials   credentials.Provider
+	tokenAudience string
+	identity      string
+	egress        *egress
+	cache         *responseCache
+	requestCache  *responseCache
+	// configured is set once the library configuration was applied
+	configured bool
+	lock       sync.Mutex
 }
 
+// NewHTTP creates a context sending requests with client, if client is nil a client enforcing
+// the egress configuration is created when the context is configured.
 func NewHTTP(client ClientInterface) ContextInterface {
-	if client == nil {
-		client = http.DefaultClient
-	}
+	return NewHTTPWithContext(context.TODO(), client)
+}
+
+// NewHTTPWithContext creates a context sending requests with client in the scope of ctx,
+// the admission request cache carried by ctx is used by requests with the request cache scope.
+func NewHTTPWithContext(ctx context.Context, client ClientInterface) ContextInterface {
 	return &contextImpl{
-		client: client,
+		ctx:          ctx,
+		client:       client,
+		requestCache: requestCacheFrom(ctx),
+	}
+}
+
+// configure applies the library configuration, settings already present on the context are kept.
+// Contexts used outside of the library are configured with a nil Config on first use.
+func (r *contextImpl) configure(config *Config) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.configured {
+		return
+	}
+	r.configured = true
+	if r.credentials == nil && config != nil {
+		r.credentials = config.Credentials
+	}
+	if r.cache == nil {
+		r.cache = config.sharedCache()
+	}
+	r.egress = newEgress(config.egress())
+	if r.client == nil {
+		r.client = r.egress.newClient(nil)
 	}
 }
 
 func (r *contextImpl) Get(url string, headers map[string]string) (any, error) {
-	req, err := http.NewRequestWithContext(context.TODO(), "GET", url, nil)
+	return r.GetWithOptions(url, headers, RequestOptions{})
+}
+
+func (r *contextImpl) GetWithOptions(url string, headers map[string]string, options RequestOptions) (any, error) {
+	r.configure(nil)
+	req, err := http.NewRequestWithContext(r.context(), "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %v", err)
 	}
 	for h, v := range headers {
 		req.Header.Add(h, v)
 	}
-	return r.executeRequest(r.client, req)
+	if err := r.addToken(req); err != nil {
+		return nil, err
+	}
+	if err := r.egress.checkRequest(req); err != nil {
+		return nil, err
+	}
+	cache, ttl := r.cache, options.CacheTTL
+	if options.CacheScope == CacheScopeRequest {
+		cache, ttl = r.requestCache, requestCacheTTL
+	}
+	return cache.get(req, r.identity, ttl, func() (any, error) {
+		return r.executeRequest(r.client, req)
+	})
+}
+
+func (r *contextImpl) context() context.Context {
+	if r.ctx == nil {
+		return context.TODO()
+	}
+	return r.ctx
 }
 
 func (r *contextImpl) Post(url string, data any, headers map[string]string) (any, error) {
+	r.configure(nil)
 	body, err := buildRequestData(data)
 	if err != nil {
 		return nil, fmt.Errorf("failed to encode request data: %v", err)
 	}
-	req, err := http.NewRequestWithContext(context.TODO(), "POST", url, body)
+	req, err := http.NewRequestWithContext(r.context(), "POST", url, body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %v", err)
 	}
 	for h, v := range headers {
 		req.Header.Add(h, v)
 	}
+	if err := r.addToken(req); err != nil {
+		return nil, err
+	}
+	if err := r.egress.checkRequest(req); err != nil {
+		return nil, err
+	}
 	return r.executeRequest(r.client, req)
 }
 
 func (r *contextImpl) executeRequest(client ClientInterface, req *http.Request) (any, error) {
-	resp, err := client.Do(req)
+	resp, err := breaker.HostCircuitBreaker("http", req.URL.Host).DoRequest(client, req)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %v", err)
 	}
@@ -66,31 +135,70 @@ func (r *contextImpl) executeRequest(client ClientInterface, req *http.Request)
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		return nil, fmt.Errorf("HTTP %s", resp.Status)
 	}
+	reader := io.Reader(resp.Body)
+	maxBytes := r.egress.maxResponseBytes()
+	if maxBytes > 0 {
+		data, err := io.ReadAll(io.LimitReader(resp.Body, maxBytes+1))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response body: %v", err)
+		}
+		if int64(len(data)) > maxBytes {
+			return nil, r.egress.deny(req.Context(), &config.HTTPEgressError{
+				Reason:  "size",
+				Message: fmt.Sprintf("response exceeded the maximum size of %d bytes", maxBytes),
+			})
+		}
+		reader = bytes.NewReader(data)
+	}
 	var body any
-	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+	if err := json.NewDecoder(reader).Decode(&body); err != nil {
 		return nil, fmt.Errorf("Unable to decode JSON body %v", err)
 	}
 	return body, nil
 }
 
+func (r *contextImpl) addToken(req *http.Request) error {
+	if r.tokenAudience == "" || req.Header.Get("Authorization") != "" {
+		return nil
+	}
+	if r.credentials == nil {
+		return fmt.Errorf("failed to get token for audience %s: %w", r.tokenAudience, credentials.ErrNoProvider)
+	}
+	token, err := r.credentials.Token(r.tokenAudience)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
 func (r *contextImpl) Client(caBundle string) (ContextInterface, error) {
-	if caBundle == "" {
+	return r.ClientWithOptions(ClientOptions{CABundle: caBundle})
+}
+
+func (r *contextImpl) ClientWithOptions(options ClientOptions) (ContextInterface, error) {
+	if options.CABundle == "" && options.ClientCertSecret == "" && options.TokenAudience == "" {
 		return r, nil
 	}
-	caCertPool := x509.NewCertPool()
-	if ok := caCertPool.AppendCertsFromPEM([]byte(caBundle)); !ok {
-		return nil, fmt.Errorf("failed to parse PEM CA bundle for APICall")
-	}
-	transport := &http.Transport{
-		TLSClientConfig: &tls.Config{
-			RootCAs:    caCertPool,
-			MinVersion: tls.VersionTLS12,
-		},
+	r.configure(nil)
+	client := r.client
+	if options.CABundle != "" || options.ClientCertSecret != "" {
+		tlsConfig, err := credentials.TLSConfig(r.credentials, options.CABundle, options.ClientCertSecret)
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure TLS: %w", err)
+		}
+		client = r.egress.newClient(tlsConfig)
 	}
 	return &contextImpl{
-		client: &http.Client{
-			Transport: tracing.Transport(transport, otelhttp.WithFilter(tracing.RequestFilterIsInSpan)),
-		},
+		ctx:           r.ctx,
+		client:        client,
+		credentials:   r.credentials,
+		tokenAudience: options.TokenAudience,
+		identity:      clientIdentity(options),
+		egress:        r.egress,
+		cache:         r.cache,
+		requestCache:  r.requestCache,
+		configured:    true,
 	}, nil
 }
 
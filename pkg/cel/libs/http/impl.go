@@ -1,14 +1,27 @@
 package http
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/google/cel-go/common/types"
 	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/common/types/traits"
 	"github.com/kyverno/kyverno/pkg/cel/utils"
 	"google.golang.org/protobuf/types/known/structpb"
 )
 
 type impl struct {
 	types.Adapter
+	config *Config
+}
+
+// context returns the request context with the library configuration applied.
+func (c *impl) context(request Context) ContextInterface {
+	if r, ok := request.ContextInterface.(*contextImpl); ok {
+		r.configure(c.config)
+	}
+	return request.ContextInterface
 }
 
 func (c *impl) get_request_with_client_string(args ...ref.Val) ref.Val {
@@ -22,7 +35,7 @@ func (c *impl) get_request_with_client_string(args ...ref.Val) ref.Val {
 	} else if header, err := utils.GetArg[map[string]string](args, 2); err != nil {
 		return err
 	} else {
-		data, err := request.Get(url, header)
+		data, err := c.context(request).Get(url, header)
 		if err != nil {
 			return types.NewErr("request failed: %v", err)
 		}
@@ -30,6 +43,62 @@ func (c *impl) get_request_with_client_string(args ...ref.Val) ref.Val {
 	}
 }
 
+func (c *impl) get_request_with_options(args ...ref.Val) ref.Val {
+	if len(args) != 4 {
+		return types.NewErr("expected 4 arguments, got %d", len(args))
+	}
+	if request, err := utils.GetArg[Context](args, 0); err != nil {
+		return err
+	} else if url, err := utils.GetArg[string](args, 1); err != nil {
+		return err
+	} else if header, err := utils.GetArg[map[string]string](args, 2); err != nil {
+		return err
+	} else if options, err := requestOptions(args[3]); err != nil {
+		return types.NewErr("invalid arg %d: %v", 3, err)
+	} else {
+		data, err := c.context(request).GetWithOptions(url, header, options)
+		if err != nil {
+			return types.NewErr("request failed: %v", err)
+		}
+		return c.NativeToValue(data)
+	}
+}
+
+func requestOptions(value ref.Val) (RequestOptions, error) {
+	var options RequestOptions
+	mapper, ok := value.(traits.Mapper)
+	if !ok {
+		return options, fmt.Errorf("expected a map, got %s", value.Type().TypeName())
+	}
+	for it := mapper.Iterator(); it.HasNext() == types.True; {
+		key := it.Next()
+		switch key {
+		case types.String("cacheTTL"):
+			switch ttl := mapper.Get(key).(type) {
+			case types.Duration:
+				options.CacheTTL = ttl.Duration
+			case types.String:
+				duration, err := time.ParseDuration(string(ttl))
+				if err != nil {
+					return options, fmt.Errorf("invalid cacheTTL: %w", err)
+				}
+				options.CacheTTL = duration
+			default:
+				return options, fmt.Errorf("cacheTTL must be a duration, got %s", ttl.Type().TypeName())
+			}
+		case types.String("cacheScope"):
+			scope, ok := mapper.Get(key).(types.String)
+			if !ok || scope != CacheScopeRequest {
+				return options, fmt.Errorf("cacheScope must be %q", CacheScopeRequest)
+			}
+			options.CacheScope = string(scope)
+		default:
+			return options, fmt.Errorf("unknown request option: %v", key)
+		}
+	}
+	return options, nil
+}
+
 func (c *impl) get_request_string(request, url ref.Val) ref.Val {
 	return c.get_request_with_client_string(request, url, c.NativeToValue(make(map[string]string, 0)))
 }
@@ -51,7 +120,7 @@ func (c *impl) post_request_string_with_client(args ...ref.Val) ref.Val {
 	} else if header, err := utils.GetArg[map[string]string](args, 3); err != nil {
 		return err
 	} else {
-		data, err := request.Post(url, data, header)
+		data, err := c.context(request).Post(url, data, header)
 		if err != nil {
 			return types.NewErr("request failed: %v", err)
 		}
@@ -73,10 +142,37 @@ func (c *impl) http_client_string(request, caBundle ref.Val) ref.Val {
 	} else if caBundle, err := utils.ConvertToNative[string](caBundle); err != nil {
 		return types.NewErr("invalid arg %d: %v", 1, err)
 	} else {
-		caRequest, err := request.Client(caBundle)
+		caRequest, err := c.context(request).Client(caBundle)
+		if err != nil {
+			return types.NewErr("request failed: %v", err)
+		}
+		return c.NativeToValue(Context{caRequest})
+	}
+}
+
+func (c *impl) http_client_options(request, options ref.Val) ref.Val {
+	if request, err := utils.ConvertToNative[Context](request); err != nil {
+		return types.NewErr("invalid arg %d: %v", 0, err)
+	} else if options, err := utils.ConvertToNative[map[string]string](options); err != nil {
+		return types.NewErr("invalid arg %d: %v", 1, err)
+	} else {
+		var clientOptions ClientOptions
+		for key, value := range options {
+			switch key {
+			case "caBundle":
+				clientOptions.CABundle = value
+			case "clientCertSecret":
+				clientOptions.ClientCertSecret = value
+			case "tokenAudience":
+				clientOptions.TokenAudience = value
+			default:
+				return types.NewErr("invalid client option: %s", key)
+			}
+		}
+		client, err := c.context(request).ClientWithOptions(clientOptions)
 		if err != nil {
 			return types.NewErr("request failed: %v", err)
 		}
-		return c.NativeToValue(caRequest)
+		return c.NativeToValue(Context{client})
 	}
 }
@@ -1,6 +1,7 @@
 package http
 
 import (
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -44,7 +45,7 @@ func Test_impl_get_request(t *testing.T) {
 	assert.NotNil(t, base)
 	options := []cel.EnvOption{
 		cel.Variable("http", ContextType),
-		Lib(nil),
+		Lib(nil, nil),
 	}
 	env, err := base.Extend(options...)
 	assert.NoError(t, err)
@@ -79,7 +80,7 @@ func Test_impl_get_request_with_headers(t *testing.T) {
 	assert.NotNil(t, base)
 	options := []cel.EnvOption{
 		cel.Variable("http", ContextType),
-		Lib(nil),
+		Lib(nil, nil),
 	}
 	env, err := base.Extend(options...)
 	assert.NoError(t, err)
@@ -115,7 +116,7 @@ func Test_impl_get_request_with_client_string_error(t *testing.T) {
 	assert.NotNil(t, base)
 	env, err := base.Extend(
 		cel.Variable("http", ContextType),
-		Lib(nil),
+		Lib(nil, nil),
 	)
 	assert.NoError(t, err)
 	assert.NotNil(t, env)
@@ -155,7 +156,7 @@ func Test_impl_post_request(t *testing.T) {
 	assert.NotNil(t, base)
 	options := []cel.EnvOption{
 		cel.Variable("http", ContextType),
-		Lib(nil),
+		Lib(nil, nil),
 	}
 	env, err := base.Extend(options...)
 	assert.NoError(t, err)
@@ -196,7 +197,7 @@ func Test_impl_post_request_with_headers(t *testing.T) {
 	assert.NotNil(t, base)
 	options := []cel.EnvOption{
 		cel.Variable("http", ContextType),
-		Lib(nil),
+		Lib(nil, nil),
 	}
 	env, err := base.Extend(options...)
 	assert.NoError(t, err)
@@ -237,7 +238,7 @@ func Test_impl_post_request_string_with_client_error(t *testing.T) {
 	assert.NotNil(t, base)
 	env, err := base.Extend(
 		cel.Variable("http", ContextType),
-		Lib(nil),
+		Lib(nil, nil),
 	)
 	assert.NoError(t, err)
 	assert.NotNil(t, env)
@@ -282,7 +283,7 @@ func Test_impl_http_client_string(t *testing.T) {
 	options := []cel.EnvOption{
 		cel.Variable("pem", types.StringType),
 		cel.Variable("http", ContextType),
-		Lib(nil),
+		Lib(nil, nil),
 	}
 	env, err := base.Extend(options...)
 	assert.NoError(t, err)
@@ -299,7 +300,7 @@ func Test_impl_http_client_string(t *testing.T) {
 		"http": Context{&contextImpl{}},
 	})
 	assert.NoError(t, err)
-	reqProvider := out.Value().(*contextImpl)
+	reqProvider := out.Value().(Context).ContextInterface.(*contextImpl)
 	assert.NotNil(t, reqProvider)
 }
 
@@ -309,7 +310,7 @@ func Test_impl_http_client_string_error(t *testing.T) {
 	assert.NotNil(t, base)
 	env, err := base.Extend(
 		cel.Variable("http", ContextType),
-		Lib(nil),
+		Lib(nil, nil),
 	)
 	assert.NoError(t, err)
 	assert.NotNil(t, env)
@@ -334,3 +335,103 @@ func Test_impl_http_client_string_error(t *testing.T) {
 		})
 	}
 }
+
+type testCredentials struct {
+	tokens map[string]string
+}
+
+func (c testCredentials) ClientCertificate(string) (*tls.Certificate, error) {
+	return nil, fmt.Errorf("not found")
+}
+
+func (c testCredentials) Token(audience string) (string, error) {
+	return c.tokens[audience], nil
+}
+
+func Test_impl_http_client_options(t *testing.T) {
+	base, err := compiler.NewBaseEnv()
+	assert.NoError(t, err)
+	assert.NotNil(t, base)
+	env, err := base.Extend(
+		cel.Variable("http", ContextType),
+		Lib(nil, nil),
+	)
+	assert.NoError(t, err)
+	assert.NotNil(t, env)
+	tests := []struct {
+		name    string
+		expr    string
+		headers map[string]string
+		wantErr string
+	}{{
+		name:    "token audience",
+		expr:    `http.Client({"tokenAudience": "example"}).Get("http://localhost:8080")`,
+		headers: map[string]string{"Authorization": "Bearer audience-token"},
+	}, {
+		name:    "explicit authorization header",
+		expr:    `http.Client({"tokenAudience": "example"}).Get("http://localhost:8080", {"Authorization": "Bearer token"})`,
+		headers: map[string]string{"Authorization": "Bearer token"},
+	}, {
+		name:    "unknown option",
+		expr:    `http.Client({"foo": "bar"}).Get("http://localhost:8080")`,
+		wantErr: "invalid client option: foo",
+	}, {
+		name:    "client certificate not found",
+		expr:    `http.Client({"clientCertSecret": "client-cert"}).Get("http://localhost:8080")`,
+		wantErr: "request failed: failed to configure TLS: not found",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ast, issues := env.Compile(tt.expr)
+			assert.Nil(t, issues)
+			prog, err := env.Program(ast)
+			assert.NoError(t, err)
+			out, _, err := prog.Eval(map[string]any{
+				"http": Context{&contextImpl{
+					client: testClient{
+						doFunc: func(req *http.Request) (*http.Response, error) {
+							for k, v := range tt.headers {
+								assert.Equal(t, v, req.Header.Get(k))
+							}
+							return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(`{"body": "ok"}`))}, nil
+						},
+					},
+					credentials: testCredentials{tokens: map[string]string{"example": "audience-token"}},
+				}},
+			})
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			body := out.Value().(map[string]any)
+			assert.Equal(t, "ok", body["body"])
+		})
+	}
+}
+
+func Test_impl_http_client_lib_credentials(t *testing.T) {
+	base, err := compiler.NewBaseEnv()
+	assert.NoError(t, err)
+	env, err := base.Extend(
+		cel.Variable("http", ContextType),
+		Lib(&Config{Credentials: testCredentials{tokens: map[string]string{"example": "audience-token"}}}, nil),
+	)
+	assert.NoError(t, err)
+	ast, issues := env.Compile(`http.Client({"tokenAudience": "example"}).Get("http://localhost:8080")`)
+	assert.Nil(t, issues)
+	prog, err := env.Program(ast)
+	assert.NoError(t, err)
+	// contexts created without credentials use the ones configured in the library
+	out, _, err := prog.Eval(map[string]any{
+		"http": Context{NewHTTP(testClient{
+			doFunc: func(req *http.Request) (*http.Response, error) {
+				assert.Equal(t, "Bearer audience-token", req.Header.Get("Authorization"))
+				return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(`{"body": "ok"}`))}, nil
+			},
+		})},
+	})
+	assert.NoError(t, err)
+	body := out.Value().(map[string]any)
+	assert.Equal(t, "ok", body["body"])
+}
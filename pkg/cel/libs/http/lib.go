@@ -13,6 +13,7 @@ import (
 const libraryName = "kyverno.http"
 
 type lib struct {
+	config  *Config
 	version *version.Version
 }
 
@@ -20,9 +21,9 @@ func Latest() *version.Version {
 	return versions.HttpVersion
 }
 
-func Lib(v *version.Version) cel.EnvOption {
+func Lib(config *Config, v *version.Version) cel.EnvOption {
 	// create the cel lib env option
-	return cel.Lib(&lib{version: v})
+	return cel.Lib(&lib{config: config, version: v})
 }
 
 func (*lib) LibraryName() string {
@@ -33,6 +34,7 @@ func (c *lib) CompileOptions() []cel.EnvOption {
 	return []cel.EnvOption{
 		ext.NativeTypes(reflect.TypeFor[struct{}]()), // register an empty struct type since the library depends on the ext native types provider to resolve the context type
 		c.extendEnv,
+		cel.ASTValidators(egressValidator{config: c.config}),
 	}
 }
 
@@ -44,6 +46,7 @@ func (c *lib) extendEnv(env *cel.Env) (*cel.Env, error) {
 	// create implementation, recording the envoy types aware adapter
 	impl := impl{
 		Adapter: env.CELTypeAdapter(),
+		config:  c.config,
 	}
 	// build our function overloads
 	libraryDecls := map[string][]cel.FunctionOpt{
@@ -60,6 +63,12 @@ func (c *lib) extendEnv(env *cel.Env) (*cel.Env, error) {
 				types.AnyType,
 				cel.FunctionBinding(impl.get_request_with_headers_string),
 			),
+			cel.MemberOverload(
+				"http_get_string_headers_options",
+				[]*cel.Type{ContextType, types.StringType, types.NewMapType(types.StringType, types.StringType), types.NewMapType(types.StringType, types.DynType)},
+				types.AnyType,
+				cel.FunctionBinding(impl.get_request_with_options),
+			),
 		},
 		"Post": {
 			cel.MemberOverload(
@@ -82,6 +91,12 @@ func (c *lib) extendEnv(env *cel.Env) (*cel.Env, error) {
 				ContextType,
 				cel.BinaryBinding(impl.http_client_string),
 			),
+			cel.MemberOverload(
+				"http_client_options",
+				[]*cel.Type{ContextType, types.NewMapType(types.StringType, types.StringType)},
+				ContextType,
+				cel.BinaryBinding(impl.http_client_options),
+			),
 		},
 	}
 	// create env options corresponding to our function overloads
@@ -1,6 +1,8 @@
 package http
 
 import (
+	"time"
+
 	"github.com/google/cel-go/common/types"
 )
 
@@ -8,8 +10,28 @@ var ContextType = types.NewOpaqueType("http.Context")
 
 type ContextInterface interface {
 	Get(url string, headers map[string]string) (any, error)
+	GetWithOptions(url string, headers map[string]string, options RequestOptions) (any, error)
 	Post(url string, data any, headers map[string]string) (any, error)
 	Client(caBundle string) (ContextInterface, error)
+	ClientWithOptions(options ClientOptions) (ContextInterface, error)
+}
+
+// RequestOptions configure how a single request is sent.
+type RequestOptions struct {
+	// CacheTTL is the duration the response is cached and reused by identical requests across admission requests, 0 disables caching.
+	CacheTTL time.Duration
+	// CacheScope set to CacheScopeRequest caches the response for the rest of the admission request instead.
+	CacheScope string
+}
+
+// ClientOptions configure the TLS and authentication settings of an http client.
+type ClientOptions struct {
+	// CABundle is a PEM encoded CA bundle used to validate the server certificate.
+	CABundle string
+	// ClientCertSecret is the name of a kubernetes.io/tls Secret in the Kyverno namespace used for mutual TLS.
+	ClientCertSecret string
+	// TokenAudience is the audience of the projected service account token sent as a bearer token.
+	TokenAudience string
 }
 
 type Context struct {
@@ -0,0 +1,71 @@
+package imageverify
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/kyverno/kyverno/api/policies.kyverno.io/v1beta1"
+	"github.com/kyverno/kyverno/pkg/imageverification/imagedataloader"
+	"github.com/kyverno/kyverno/pkg/imageverifycache"
+)
+
+// signatureCache caches the image signature verifications of a policy.
+// Attestation verifications are not cached because they load the verified payloads into the image data.
+type signatureCache struct {
+	logger   logr.Logger
+	client   imageverifycache.Client
+	policy   v1beta1.ImageValidatingPolicyLike
+	ttl      time.Duration
+	disabled bool
+}
+
+// A nil client disables the cache.
+func newSignatureCache(logger logr.Logger, client imageverifycache.Client, ivpol v1beta1.ImageValidatingPolicyLike) *signatureCache {
+	c := &signatureCache{
+		logger: logger,
+		client: client,
+		policy: ivpol,
+	}
+	if ttl := ivpol.GetSpec().ValidationConfigurations.CacheTTL; ttl != nil {
+		c.ttl = ttl.Duration
+		c.disabled = ttl.Duration <= 0
+	}
+	return c
+}
+
+func (c *signatureCache) key(img *imagedataloader.ImageData, attestor *v1beta1.Attestor) imageverifycache.VerificationKey {
+	return imageverifycache.VerificationKey{
+		PolicyUID:  c.policy.GetUID(),
+		Generation: c.policy.GetGeneration(),
+		Attestor:   attestor.Name,
+		Digest:     img.Digest,
+	}
+}
+
+func (c *signatureCache) enabled(img *imagedataloader.ImageData) bool {
+	return c != nil && c.client != nil && !c.disabled && img.Digest != ""
+}
+
+// verified returns true if the image signature was already verified by the attestor.
+func (c *signatureCache) verified(ctx context.Context, img *imagedataloader.ImageData, attestor *v1beta1.Attestor) bool {
+	if !c.enabled(img) {
+		return false
+	}
+	found, err := c.client.GetVerification(ctx, c.key(img, attestor))
+	if err != nil {
+		c.logger.Error(err, "failed to get image verification from cache", "image", img.Image)
+		return false
+	}
+	return found
+}
+
+// add records a successful image signature verification by the attestor.
+func (c *signatureCache) add(ctx context.Context, img *imagedataloader.ImageData, attestor *v1beta1.Attestor) {
+	if !c.enabled(img) {
+		return
+	}
+	if _, err := c.client.SetVerification(ctx, c.key(img, attestor), c.ttl); err != nil {
+		c.logger.Error(err, "failed to add image verification to cache", "image", img.Image)
+	}
+}
@@ -0,0 +1,63 @@
+package imageverify
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/kyverno/kyverno/api/policies.kyverno.io/v1beta1"
+	"github.com/kyverno/kyverno/pkg/imageverification/imagedataloader"
+	"github.com/kyverno/kyverno/pkg/imageverifycache"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newCachePolicy(generation int64, ttl *metav1.Duration) *v1beta1.ImageValidatingPolicy {
+	return &v1beta1.ImageValidatingPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "verify", UID: "uid", Generation: generation},
+		Spec: v1beta1.ImageValidatingPolicySpec{
+			ValidationConfigurations: v1beta1.ValidationConfiguration{
+				CacheTTL: ttl,
+			},
+		},
+	}
+}
+
+func Test_signatureCache(t *testing.T) {
+	client, err := imageverifycache.New(
+		imageverifycache.WithCacheEnableFlag(true),
+		imageverifycache.WithMaxSize(100),
+		imageverifycache.WithTTLDuration(time.Hour),
+	)
+	assert.NoError(t, err)
+	ctx := context.TODO()
+	img := &imagedataloader.ImageData{
+		ImageDescriptor: imagedataloader.ImageDescriptor{
+			ImageReference: imagedataloader.ImageReference{
+				Image:  "ghcr.io/kyverno/app:v1",
+				Digest: "sha256:4f5c8e0f1c3d",
+			},
+		},
+	}
+	attestor := &v1beta1.Attestor{Name: "notary"}
+	cache := newSignatureCache(logr.Discard(), client, newCachePolicy(1, nil))
+	assert.False(t, cache.verified(ctx, img, attestor))
+	cache.add(ctx, img, attestor)
+	assert.True(t, cache.verified(ctx, img, attestor))
+	// other attestors are verified separately
+	assert.False(t, cache.verified(ctx, img, &v1beta1.Attestor{Name: "cosign"}))
+	// a new generation of the policy invalidates the entries
+	assert.False(t, newSignatureCache(logr.Discard(), client, newCachePolicy(2, nil)).verified(ctx, img, attestor))
+	// a zero ttl disables the cache
+	disabled := newSignatureCache(logr.Discard(), client, newCachePolicy(3, &metav1.Duration{}))
+	disabled.add(ctx, img, attestor)
+	assert.False(t, disabled.verified(ctx, img, attestor))
+	// a nil client disables the cache
+	uncached := newSignatureCache(logr.Discard(), nil, newCachePolicy(1, nil))
+	uncached.add(ctx, img, attestor)
+	assert.False(t, uncached.verified(ctx, img, attestor))
+	// images without a digest are never cached
+	cache.add(ctx, &imagedataloader.ImageData{}, attestor)
+	assert.False(t, cache.verified(ctx, &imagedataloader.ImageData{}, attestor))
+}
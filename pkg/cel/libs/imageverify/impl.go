@@ -14,6 +14,7 @@ import (
 	"github.com/kyverno/kyverno/pkg/imageverification/imagedataloader"
 	"github.com/kyverno/kyverno/pkg/imageverification/imageverifiers/cosign"
 	"github.com/kyverno/kyverno/pkg/imageverification/imageverifiers/notary"
+	"github.com/kyverno/kyverno/pkg/imageverifycache"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	k8scorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 )
@@ -28,6 +29,7 @@ type ivfuncs struct {
 	attestationList map[string]v1beta1.Attestation
 	cosignVerifier  *cosign.Verifier
 	notaryVerifier  *notary.Verifier
+	cache           *signatureCache
 }
 
 func ImageVerifyCELFuncs(
@@ -35,6 +37,7 @@ func ImageVerifyCELFuncs(
 	imgCtx imagedataloader.ImageContext,
 	ivpol v1beta1.ImageValidatingPolicyLike,
 	lister k8scorev1.SecretInterface,
+	cache imageverifycache.Client,
 	adapter types.Adapter,
 ) (*ivfuncs, error) {
 	if ivpol == nil {
@@ -57,6 +60,7 @@ func ImageVerifyCELFuncs(
 		attestationList: attestationMap(ivpol),
 		cosignVerifier:  cosign.NewVerifier(lister, logger),
 		notaryVerifier:  notary.NewVerifier(logger),
+		cache:           newSignatureCache(logger, cache, ivpol),
 	}, nil
 }
 
@@ -80,11 +84,16 @@ func (f *ivfuncs) verify_image_signature_string_stringarray(image ref.Val, attes
 				return types.NewErr("failed to get imagedata: %v", err)
 			}
 
+			if f.cache.verified(ctx, img, &attestor) {
+				count += 1
+				continue
+			}
 			if attestor.IsCosign() {
 				if err := f.cosignVerifier.VerifyImageSignature(ctx, img, &attestor); err != nil {
 					f.logger.Info("failed to verify image cosign: %v", err)
 				} else {
 					count += 1
+					f.cache.add(ctx, img, &attestor)
 				}
 			} else if attestor.IsNotary() {
 				var certs, tsaCerts string
@@ -98,6 +107,7 @@ func (f *ivfuncs) verify_image_signature_string_stringarray(image ref.Val, attes
 					f.logger.Info("failed to verify image notary: %v", err)
 				} else {
 					count += 1
+					f.cache.add(ctx, img, &attestor)
 				}
 			}
 		}
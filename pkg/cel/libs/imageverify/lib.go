@@ -7,6 +7,7 @@ import (
 	policiesv1beta1 "github.com/kyverno/kyverno/api/policies.kyverno.io/v1beta1"
 	"github.com/kyverno/kyverno/pkg/cel/libs/versions"
 	"github.com/kyverno/kyverno/pkg/imageverification/imagedataloader"
+	"github.com/kyverno/kyverno/pkg/imageverifycache"
 	"k8s.io/apimachinery/pkg/util/version"
 	apiservercel "k8s.io/apiserver/pkg/cel"
 	k8scorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
@@ -20,19 +21,22 @@ type lib struct {
 	imgCtx  imagedataloader.ImageContext
 	ivpol   policiesv1beta1.ImageValidatingPolicyLike
 	lister  k8scorev1.SecretInterface
+	cache   imageverifycache.Client
 }
 
 func Latest() *version.Version {
 	return versions.ImageVerifyVersion
 }
 
-func Lib(v *version.Version, imgCtx imagedataloader.ImageContext, ivpol policiesv1beta1.ImageValidatingPolicyLike, lister k8scorev1.SecretInterface) cel.EnvOption {
+// Lib returns the image verification library, successful signature verifications are recorded in cache unless it is nil.
+func Lib(v *version.Version, imgCtx imagedataloader.ImageContext, ivpol policiesv1beta1.ImageValidatingPolicyLike, lister k8scorev1.SecretInterface, cache imageverifycache.Client) cel.EnvOption {
 	// create the cel lib env option
 	return cel.Lib(&lib{
 		version: v,
 		imgCtx:  imgCtx,
 		ivpol:   ivpol,
 		lister:  lister,
+		cache:   cache,
 	})
 }
 
@@ -56,7 +60,7 @@ func (*lib) ProgramOptions() []cel.ProgramOption {
 
 func (c *lib) extendEnv(env *cel.Env) (*cel.Env, error) {
 	// create implementation, recording the envoy types aware adapter
-	impl, err := ImageVerifyCELFuncs(c.logger, c.imgCtx, c.ivpol, c.lister, env.CELTypeAdapter())
+	impl, err := ImageVerifyCELFuncs(c.logger, c.imgCtx, c.ivpol, c.lister, c.cache, env.CELTypeAdapter())
 	if err != nil {
 		return nil, err
 	}
@@ -17,6 +17,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/version"
 	apiservercel "k8s.io/apiserver/pkg/cel"
 	"k8s.io/apiserver/pkg/cel/environment"
+	"k8s.io/client-go/tools/cache"
 )
 
 var (
@@ -28,11 +29,27 @@ type Compiler interface {
 	Compile(policy policiesv1beta1.DeletingPolicyLike, exceptions []*policiesv1alpha1.PolicyException) (*Policy, field.ErrorList)
 }
 
-func NewCompiler() Compiler {
-	return &compilerImpl{}
+// Option configures a Compiler.
+type Option func(*compilerImpl)
+
+// WithHTTPConfig configures the requests sent by the http library.
+func WithHTTPConfig(config *http.Config) Option {
+	return func(c *compilerImpl) {
+		c.httpConfig = config
+	}
+}
+
+func NewCompiler(opts ...Option) Compiler {
+	c := &compilerImpl{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
-type compilerImpl struct{}
+type compilerImpl struct {
+	httpConfig *http.Config
+}
 
 func (c *compilerImpl) Compile(policy policiesv1beta1.DeletingPolicyLike, exceptions []*policiesv1alpha1.PolicyException) (*Policy, field.ErrorList) {
 	if policy == nil {
@@ -81,7 +98,10 @@ func (c *compilerImpl) Compile(policy policiesv1beta1.DeletingPolicyLike, except
 			MatchConditions: polexMatchConditions,
 		})
 	}
+	key, _ := cache.MetaNamespaceKeyFunc(policy)
 	return &Policy{
+		kind:                      policy.GetKind(),
+		name:                      key,
 		deletionPropagationPolicy: spec.DeletionPropagationPolicy,
 		schedule:                  spec.Schedule,
 		conditions:                conditions,
@@ -137,6 +157,7 @@ func (c *compilerImpl) createBaseDpolEnv(namespace string) (*environment.EnvSet,
 					globalcontext.Latest(),
 				),
 				http.Lib(
+					c.httpConfig,
 					http.Latest(),
 				),
 				image.Lib(
@@ -2,6 +2,7 @@ package compiler
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/google/cel-go/cel"
 	"github.com/google/cel-go/common/types"
@@ -22,6 +23,8 @@ import (
 )
 
 type Policy struct {
+	kind                      string
+	name                      string
 	deletionPropagationPolicy *metav1.DeletionPropagation
 	schedule                  string
 	conditions                []cel.Program
@@ -29,7 +32,13 @@ type Policy struct {
 	exceptions                []compiler.Exception
 }
 
+// Exceptions returns the exceptions compiled with the policy.
+func (p *Policy) Exceptions() []compiler.Exception {
+	return p.exceptions
+}
+
 func (p *Policy) Evaluate(ctx context.Context, object unstructured.Unstructured, namespace runtime.Object, context libs.Context) (*EvaluationResult, error) {
+	tracer := compiler.NewTracer(ctx, p.kind, p.name)
 	vars := lazy.NewMapValue(compiler.VariablesType)
 	namespaceVal, err := utils.ObjectToResolveVal(namespace)
 	if err != nil {
@@ -38,7 +47,7 @@ func (p *Policy) Evaluate(ctx context.Context, object unstructured.Unstructured,
 	dataNew := map[string]any{
 		compiler.NamespaceObjectKey: namespaceVal,
 		compiler.GlobalContextKey:   globalcontext.Context{ContextInterface: context},
-		compiler.HttpKey:            http.Context{ContextInterface: http.NewHTTP(nil)},
+		compiler.HttpKey:            http.Context{ContextInterface: http.NewHTTPWithContext(ctx, nil)},
 		compiler.ImageDataKey:       imagedata.Context{ContextInterface: context},
 		compiler.ObjectKey:          object.UnstructuredContent(),
 		compiler.ResourceKey:        resource.Context{ContextInterface: context},
@@ -46,7 +55,9 @@ func (p *Policy) Evaluate(ctx context.Context, object unstructured.Unstructured,
 	}
 	for name, variable := range p.variables {
 		vars.Append(name, func(*lazy.MapValue) ref.Val {
+			start := tracer.Start()
 			out, _, err := variable.ContextEval(ctx, dataNew)
+			tracer.Variable(name, start, out, err)
 			if out != nil {
 				return out
 			}
@@ -61,7 +72,7 @@ func (p *Policy) Evaluate(ctx context.Context, object unstructured.Unstructured,
 	if len(p.exceptions) > 0 {
 		matchedExceptions := make([]*policiesv1alpha1.PolicyException, 0)
 		for _, polex := range p.exceptions {
-			match, err := p.match(ctx, dataNew, polex.MatchConditions)
+			match, err := p.match(ctx, dataNew, polex.MatchConditions, nil)
 			if err != nil {
 				return nil, err
 			}
@@ -73,7 +84,7 @@ func (p *Policy) Evaluate(ctx context.Context, object unstructured.Unstructured,
 			return &EvaluationResult{Exceptions: matchedExceptions}, nil
 		}
 	}
-	match, err := p.match(ctx, dataNew, p.conditions)
+	match, err := p.match(ctx, dataNew, p.conditions, tracer)
 	if err != nil {
 		return nil, err
 	}
@@ -81,18 +92,21 @@ func (p *Policy) Evaluate(ctx context.Context, object unstructured.Unstructured,
 	return &EvaluationResult{Result: match}, nil
 }
 
-func (p *Policy) match(ctx context.Context, data map[string]any, conditions []cel.Program) (bool, error) {
+func (p *Policy) match(ctx context.Context, data map[string]any, conditions []cel.Program, tracer *compiler.Tracer) (bool, error) {
 	var errs []error
-	for _, condition := range conditions {
+	for index, condition := range conditions {
 		// evaluate the condition
+		start := tracer.Start()
 		out, _, err := condition.ContextEval(ctx, data)
 		// check error
 		if err != nil {
+			tracer.Done(fmt.Sprintf("conditions[%d]", index), start, false, err)
 			errs = append(errs, err)
 			continue
 		}
 		// try to convert to a bool
 		result, err := utils.ConvertToNative[bool](out)
+		tracer.Done(fmt.Sprintf("conditions[%d]", index), start, err == nil && result, err)
 		// check error
 		if err != nil {
 			errs = append(errs, err)
@@ -7,6 +7,7 @@ import (
 	"github.com/kyverno/kyverno/pkg/cel/engine"
 	"github.com/kyverno/kyverno/pkg/cel/libs"
 	"github.com/kyverno/kyverno/pkg/cel/matching"
+	"github.com/kyverno/kyverno/pkg/engine/explain"
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -35,44 +36,31 @@ func NewEngine(nsResolver engine.NamespaceResolver, mapper meta.RESTMapper, cont
 	}
 }
 
-func (e *Engine) Handle(ctx context.Context, policy Policy, resource unstructured.Unstructured) (EngineResponse, error) {
+func (e *Engine) Handle(ctx context.Context, policy Policy, resource unstructured.Unstructured) (response EngineResponse, err error) {
+	ctx, step := explain.BeginPolicy(ctx, policy.Policy)
+	defer func() {
+		if err != nil {
+			step.End("error", err.Error())
+		} else {
+			step.End(explain.Bool(response.Match), "")
+		}
+	}()
 	var ns runtime.Object
 	if resource.GetAPIVersion() != "" && resource.GetKind() != "" {
-		namespace := resource.GetNamespace()
-
-		spec := policy.Policy.GetDeletingPolicySpec()
-		if spec == nil {
-			return EngineResponse{}, fmt.Errorf("deleting policy %s has no spec", policy.Policy.GetName())
-		}
-
-		mapping, err := e.mapper.RESTMapping(resource.GroupVersionKind().GroupKind(), resource.GroupVersionKind().Version)
+		matches, err := e.Match(policy, resource)
 		if err != nil {
 			return EngineResponse{}, err
 		}
-
-		// create admission attributes
-		attr := admission.NewAttributesRecord(
-			&resource,
-			nil,
-			resource.GroupVersionKind(),
-			namespace,
-			resource.GetName(),
-			mapping.Resource,
-			"",
-			"",
-			nil,
-			false,
-			nil,
-		)
-
-		if namespace != "" {
-			ns = e.nsResolver(namespace)
+		var rules []admissionregistrationv1.NamedRuleWithOperations
+		if constraints := policy.Policy.GetDeletingPolicySpec().MatchConstraints; constraints != nil {
+			rules = constraints.ResourceRules
 		}
-
-		if matches, err := e.matchPolicy(spec.MatchConstraints, attr, ns); err != nil {
-			return EngineResponse{}, err
-		} else if !matches {
-			return EngineResponse{Match: false}, err
+		explain.RecordMatch(ctx, rules, matches)
+		if !matches {
+			return EngineResponse{Match: false}, nil
+		}
+		if namespace := resource.GetNamespace(); namespace != "" {
+			ns = e.nsResolver(namespace)
 		}
 	}
 
@@ -84,6 +72,44 @@ func (e *Engine) Handle(ctx context.Context, policy Policy, resource unstructure
 	return EngineResponse{Match: result.Result}, nil
 }
 
+// Match returns true if the resource matches the match constraints of the policy, it only depends on the metadata
+// of the resource and can be used to filter resources before fetching their full object.
+func (e *Engine) Match(policy Policy, resource unstructured.Unstructured) (bool, error) {
+	namespace := resource.GetNamespace()
+
+	spec := policy.Policy.GetDeletingPolicySpec()
+	if spec == nil {
+		return false, fmt.Errorf("deleting policy %s has no spec", policy.Policy.GetName())
+	}
+
+	mapping, err := e.mapper.RESTMapping(resource.GroupVersionKind().GroupKind(), resource.GroupVersionKind().Version)
+	if err != nil {
+		return false, err
+	}
+
+	// create admission attributes
+	attr := admission.NewAttributesRecord(
+		&resource,
+		nil,
+		resource.GroupVersionKind(),
+		namespace,
+		resource.GetName(),
+		mapping.Resource,
+		"",
+		"",
+		nil,
+		false,
+		nil,
+	)
+
+	var ns runtime.Object
+	if namespace != "" {
+		ns = e.nsResolver(namespace)
+	}
+
+	return e.matchPolicy(spec.MatchConstraints, attr, ns)
+}
+
 func (e *Engine) matchPolicy(constraints *admissionregistrationv1.MatchResources, attr admission.Attributes, namespace runtime.Object) (bool, error) {
 	if constraints == nil {
 		return false, nil
@@ -9,6 +9,7 @@ import (
 	"github.com/kyverno/kyverno/pkg/cel/libs"
 	"github.com/kyverno/kyverno/pkg/cel/matching"
 	"github.com/kyverno/kyverno/pkg/cel/policies/dpol/compiler"
+	"github.com/kyverno/kyverno/pkg/engine/explain"
 	"github.com/stretchr/testify/assert"
 	v1 "k8s.io/api/admissionregistration/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -107,6 +108,44 @@ func TestHandleValidPolicy(t *testing.T) {
 	assert.True(t, resp.Match)
 }
 
+func TestHandleExplain(t *testing.T) {
+	resource.SetAPIVersion("apps/v1")
+	resource.SetKind("Deployment")
+	resource.SetName("nginx")
+	resource.SetNamespace("default")
+
+	mapper.Add(schema.GroupVersionKind{
+		Group:   "apps",
+		Version: "v1",
+		Kind:    "Deployment",
+	}, meta.RESTScopeNamespace)
+
+	compiledDpol, _ := comp.Compile(dpol, nil)
+
+	pol := Policy{
+		Policy:         dpol,
+		CompiledPolicy: compiledDpol,
+	}
+
+	trace := explain.NewTrace()
+	engine := NewEngine(nsResolver, mapper, &libs.FakeContextProvider{}, matcher)
+	resp, err := engine.Handle(explain.WithTrace(ctx, trace), pol, resource)
+
+	assert.NoError(t, err)
+	assert.True(t, resp.Match)
+	steps := trace.Steps()
+	assert.Len(t, steps, 1)
+	assert.Equal(t, explain.TypePolicy, steps[0].Type)
+	assert.Equal(t, "valid-policy", steps[0].Name)
+	assert.Equal(t, "true", steps[0].Result)
+	assert.Len(t, steps[0].Steps, 2)
+	assert.Equal(t, explain.TypeMatch, steps[0].Steps[0].Type)
+	assert.Equal(t, "true", steps[0].Steps[0].Result)
+	assert.Equal(t, explain.TypeExpression, steps[0].Steps[1].Type)
+	assert.Equal(t, "conditions[0]", steps[0].Steps[1].Name)
+	assert.Equal(t, "true", steps[0].Steps[1].Result)
+}
+
 func TestHandleWithPolex(t *testing.T) {
 	resource.SetAPIVersion("apps/v1")
 	resource.SetKind("Deployment")
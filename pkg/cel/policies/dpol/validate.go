@@ -6,11 +6,11 @@ import (
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
-func Validate(dpol v1beta1.DeletingPolicyLike) ([]string, error) {
+func Validate(dpol v1beta1.DeletingPolicyLike, opts ...compiler.Option) ([]string, error) {
 	warnings := make([]string, 0)
 	err := make(field.ErrorList, 0)
 
-	compiler := compiler.NewCompiler()
+	compiler := compiler.NewCompiler(opts...)
 	_, errList := compiler.Compile(dpol, nil)
 	if errList != nil {
 		err = errList
@@ -21,6 +21,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/version"
 	apiservercel "k8s.io/apiserver/pkg/cel"
 	"k8s.io/apiserver/pkg/cel/environment"
+	"k8s.io/client-go/tools/cache"
 )
 
 var (
@@ -32,13 +33,29 @@ type Compiler interface {
 	Compile(policy policiesv1beta1.GeneratingPolicyLike, exceptions []*policiesv1alpha1.PolicyException) (*Policy, field.ErrorList)
 }
 
-func NewCompiler() Compiler {
-	return &compilerImpl{}
+// Option configures a Compiler.
+type Option func(*compilerImpl)
+
+// WithHTTPConfig configures the requests sent by the http library.
+func WithHTTPConfig(config *http.Config) Option {
+	return func(c *compilerImpl) {
+		c.httpConfig = config
+	}
+}
+
+func NewCompiler(opts ...Option) Compiler {
+	c := &compilerImpl{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
-type compilerImpl struct{}
+type compilerImpl struct {
+	httpConfig *http.Config
+}
 
-func createBaseGpolEnv(namespace string) (*environment.EnvSet, *compiler.VariablesProvider, error) {
+func (c *compilerImpl) createBaseGpolEnv(namespace string) (*environment.EnvSet, *compiler.VariablesProvider, error) {
 	baseOpts := compiler.DefaultEnvOptions()
 	baseOpts = append(baseOpts,
 		cel.Variable(compiler.NamespaceObjectKey, compiler.NamespaceType.CelType()),
@@ -90,6 +107,7 @@ func createBaseGpolEnv(namespace string) (*environment.EnvSet, *compiler.Variabl
 					globalcontext.Latest(),
 				),
 				http.Lib(
+					c.httpConfig,
 					http.Latest(),
 				),
 				resource.Lib(
@@ -113,7 +131,7 @@ func createBaseGpolEnv(namespace string) (*environment.EnvSet, *compiler.Variabl
 
 func (c *compilerImpl) Compile(policy policiesv1beta1.GeneratingPolicyLike, exceptions []*policiesv1alpha1.PolicyException) (*Policy, field.ErrorList) {
 	var allErrs field.ErrorList
-	gpolEnvSet, variablesProvider, err := createBaseGpolEnv(policy.GetNamespace())
+	gpolEnvSet, variablesProvider, err := c.createBaseGpolEnv(policy.GetNamespace())
 	if err != nil {
 		return nil, append(allErrs, field.InternalError(nil, fmt.Errorf(compileError, err)))
 	}
@@ -164,7 +182,10 @@ func (c *compilerImpl) Compile(policy policiesv1beta1.GeneratingPolicyLike, exce
 			MatchConditions: polexMatchConditions,
 		})
 	}
+	key, _ := cache.MetaNamespaceKeyFunc(policy)
 	return &Policy{
+		kind:            policy.GetKind(),
+		name:            key,
 		matchConditions: matchConditions,
 		variables:       variables,
 		generations:     generations,
@@ -2,6 +2,7 @@ package compiler
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/google/cel-go/cel"
 	"github.com/google/cel-go/common/types"
@@ -25,6 +26,8 @@ import (
 )
 
 type Policy struct {
+	kind            string
+	name            string
 	matchConditions []cel.Program
 	variables       map[string]cel.Program
 	generations     []cel.Program
@@ -46,7 +49,7 @@ func (p *Policy) Evaluate(
 	allowedValues := make([]string, 0)
 	dataNew := map[string]any{
 		compiler.GlobalContextKey:   globalcontext.Context{ContextInterface: data.Context},
-		compiler.HttpKey:            http.Context{ContextInterface: http.NewHTTP(nil)},
+		compiler.HttpKey:            http.Context{ContextInterface: http.NewHTTPWithContext(ctx, nil)},
 		compiler.NamespaceObjectKey: data.Namespace,
 		compiler.ObjectKey:          data.Object,
 		compiler.OldObjectKey:       data.OldObject,
@@ -58,7 +61,7 @@ func (p *Policy) Evaluate(
 	if len(p.exceptions) > 0 {
 		matchedExceptions := make([]*policiesv1alpha1.PolicyException, 0)
 		for _, polex := range p.exceptions {
-			match, err := p.match(ctx, dataNew, polex.MatchConditions)
+			match, err := p.match(ctx, dataNew, polex.MatchConditions, nil)
 			if err != nil {
 				return nil, nil, err
 			}
@@ -76,7 +79,8 @@ func (p *Policy) Evaluate(
 		AllowedImages: allowedImages,
 		AllowedValues: allowedValues,
 	}
-	match, err := p.match(ctx, dataNew, p.matchConditions)
+	tracer := compiler.NewTracer(ctx, p.kind, p.name)
+	match, err := p.match(ctx, dataNew, p.matchConditions, tracer)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -88,7 +92,9 @@ func (p *Policy) Evaluate(
 	dataNew[compiler.GeneratorKey] = generator.Context{ContextInterface: data.Context}
 	for name, variable := range p.variables {
 		vars.Append(name, func(*lazy.MapValue) ref.Val {
+			start := tracer.Start()
 			out, _, err := variable.ContextEval(ctx, dataNew)
+			tracer.Variable(name, start, out, err)
 			if out != nil {
 				return out
 			}
@@ -98,8 +104,10 @@ func (p *Policy) Evaluate(
 			return nil
 		})
 	}
-	for _, generation := range p.generations {
+	for index, generation := range p.generations {
+		start := tracer.Start()
 		_, _, err := generation.ContextEval(ctx, dataNew)
+		tracer.Done(fmt.Sprintf("generate[%d]", index), start, err == nil, err)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -114,18 +122,22 @@ func (p *Policy) match(
 	ctx context.Context,
 	data map[string]any,
 	matchConditions []cel.Program,
+	tracer *compiler.Tracer,
 ) (bool, error) {
 	var errs []error
-	for _, matchCondition := range matchConditions {
+	for index, matchCondition := range matchConditions {
 		// evaluate the condition
+		start := tracer.Start()
 		out, _, err := matchCondition.ContextEval(ctx, data)
 		// check error
 		if err != nil {
+			tracer.Done(fmt.Sprintf("matchConditions[%d]", index), start, false, err)
 			errs = append(errs, err)
 			continue
 		}
 		// try to convert to a bool
 		result, err := utils.ConvertToNative[bool](out)
+		tracer.Done(fmt.Sprintf("matchConditions[%d]", index), start, err == nil && result, err)
 		// check error
 		if err != nil {
 			errs = append(errs, err)
@@ -11,6 +11,7 @@ import (
 	"github.com/kyverno/kyverno/pkg/cel/libs"
 	"github.com/kyverno/kyverno/pkg/cel/matching"
 	engineapi "github.com/kyverno/kyverno/pkg/engine/api"
+	"github.com/kyverno/kyverno/pkg/engine/explain"
 	admissionutils "github.com/kyverno/kyverno/pkg/utils/admission"
 	reportutils "github.com/kyverno/kyverno/pkg/utils/report"
 	admissionv1 "k8s.io/api/admission/v1"
@@ -27,7 +28,7 @@ var (
 )
 
 type Engine interface {
-	Handle(request engine.EngineRequest, policy Policy, cacheRestore bool) (EngineResponse, error)
+	Handle(ctx context.Context, request engine.EngineRequest, policy Policy, cacheRestore bool) (EngineResponse, error)
 }
 
 type engineImpl struct {
@@ -43,7 +44,7 @@ func NewEngine(nsResolver engine.NamespaceResolver, matcher matching.Matcher) En
 }
 
 // Handle evaluates a generating policy against the trigger in the provided request.
-func (e *engineImpl) Handle(request engine.EngineRequest, policy Policy, cacheRestore bool) (EngineResponse, error) {
+func (e *engineImpl) Handle(ctx context.Context, request engine.EngineRequest, policy Policy, cacheRestore bool) (EngineResponse, error) {
 	var response EngineResponse
 	// load objects
 	object, oldObject, err := admissionutils.ExtractResources(nil, request.Request)
@@ -81,7 +82,7 @@ func (e *engineImpl) Handle(request engine.EngineRequest, policy Policy, cacheRe
 	response.Policies = append(
 		response.Policies,
 		e.generate(
-			context.TODO(),
+			ctx,
 			policy,
 			attr,
 			&request.Request,
@@ -111,13 +112,23 @@ func (e *engineImpl) generate(
 		response.Result = engineapi.RuleError("", engineapi.Generation, "policy is not provided", errNilPolicy, nil)
 		return response
 	}
+	ctx, step := explain.BeginPolicy(ctx, policy.Policy)
+	defer func() {
+		if response.Result != nil {
+			step.EndWithResponses(*response.Result)
+		} else {
+			step.EndWithResponses()
+		}
+	}()
 	spec := policy.Policy.GetSpec()
 	if e.matcher != nil {
 		matches, err := e.matchPolicy(spec.MatchConstraints, attr, namespace)
 		if err != nil {
 			response.Result = engineapi.RuleError(policy.Policy.GetName(), engineapi.Generation, "failed to execute matching", err, nil)
 			return response
-		} else if !matches {
+		}
+		explain.RecordMatch(ctx, policy.Policy.GetMatchConstraints().ResourceRules, matches)
+		if !matches {
 			return response
 		}
 	}
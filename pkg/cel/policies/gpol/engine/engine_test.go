@@ -1,6 +1,7 @@
 package engine
 
 import (
+	"context"
 	"testing"
 
 	"github.com/kyverno/kyverno/api/policies.kyverno.io/v1alpha1"
@@ -82,7 +83,7 @@ func TestHandle(t *testing.T) {
 			},
 		}
 
-		resp, err := eng.Handle(req, pol, false)
+		resp, err := eng.Handle(context.TODO(), req, pol, false)
 		assert.NoError(t, err)
 		assert.NotNil(t, resp)
 	})
@@ -92,7 +93,7 @@ func TestHandle(t *testing.T) {
 			Request: admissionv1.AdmissionRequest{},
 		}
 
-		resp, err := eng.Handle(req, Policy{}, false)
+		resp, err := eng.Handle(context.TODO(), req, Policy{}, false)
 		assert.Nil(t, err)
 		assert.NotNil(t, resp.Policies)
 	})
@@ -135,7 +136,7 @@ func TestHandle(t *testing.T) {
 			},
 		}
 
-		resp, err := eng.Handle(req, pol, false)
+		resp, err := eng.Handle(context.TODO(), req, pol, false)
 		assert.NoError(t, err)
 		assert.NotNil(t, resp)
 	})
@@ -154,7 +155,7 @@ func TestHandle(t *testing.T) {
 			},
 		}
 
-		resp, err := eng.Handle(req, pol, false)
+		resp, err := eng.Handle(context.TODO(), req, pol, false)
 		assert.NoError(t, err)
 		assert.NotNil(t, resp)
 	})
@@ -176,7 +177,7 @@ func TestHandle(t *testing.T) {
 			},
 		}
 
-		resp, err := eng.Handle(req, pol, false)
+		resp, err := eng.Handle(context.TODO(), req, pol, false)
 		assert.NoError(t, err)
 		assert.NotNil(t, resp)
 	})
@@ -201,7 +202,7 @@ func TestHandle(t *testing.T) {
 			CompiledPolicy: compiledGpol,
 		}
 		eng := NewEngine(nsResolver, nil)
-		resp, err := eng.Handle(req, pol, false)
+		resp, err := eng.Handle(context.TODO(), req, pol, false)
 		assert.NoError(t, err)
 		assert.NotNil(t, resp)
 	})
@@ -252,7 +253,7 @@ func TestHandle(t *testing.T) {
 			CompiledPolicy: compiledGpol,
 		}
 		eng := NewEngine(nsResolver, nil)
-		resp, err := eng.Handle(req, pol, false)
+		resp, err := eng.Handle(context.TODO(), req, pol, false)
 		assert.NoError(t, err)
 		assert.NotNil(t, resp)
 	})
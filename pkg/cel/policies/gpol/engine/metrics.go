@@ -19,14 +19,14 @@ func NewMetricsEngine(engine Engine) *metricWrapper {
 	}
 }
 
-func (w *metricWrapper) Handle(request engine.EngineRequest, policy Policy, cacheRestore bool) (EngineResponse, error) {
-	response, err := w.inner.Handle(request, policy, cacheRestore)
+func (w *metricWrapper) Handle(ctx context.Context, request engine.EngineRequest, policy Policy, cacheRestore bool) (EngineResponse, error) {
+	response, err := w.inner.Handle(ctx, request, policy, cacheRestore)
 	if err != nil {
 		return response, err
 	}
 
 	for _, policy := range response.Policies {
-		w.metrics.RecordDuration(context.TODO(), policy.Result.Stats().ProcessingTime().Seconds(), string(policy.Result.Status()), policy.Policy, response.Trigger, string(request.Request.Operation))
+		w.metrics.RecordDuration(ctx, policy.Result.Stats().ProcessingTime().Seconds(), string(policy.Result.Status()), policy.Policy, response.Trigger, string(request.Request.Operation))
 	}
 
 	return response, nil
@@ -6,7 +6,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
-func Validate(gpol v1beta1.GeneratingPolicyLike) ([]string, error) {
+func Validate(gpol v1beta1.GeneratingPolicyLike, opts ...compiler.Option) ([]string, error) {
 	warnings := make([]string, 0)
 	err := make(field.ErrorList, 0)
 
@@ -19,7 +19,7 @@ func Validate(gpol v1beta1.GeneratingPolicyLike) ([]string, error) {
 		return warnings, err.ToAggregate()
 	}
 
-	compiler := compiler.NewCompiler()
+	compiler := compiler.NewCompiler(opts...)
 	_, errList := compiler.Compile(gpol, nil)
 	if errList != nil {
 		err = errList
@@ -12,10 +12,13 @@ import (
 	policiesv1beta1 "github.com/kyverno/kyverno/api/policies.kyverno.io/v1beta1"
 	"github.com/kyverno/kyverno/pkg/cel/engine"
 	"github.com/kyverno/kyverno/pkg/cel/libs"
+	"github.com/kyverno/kyverno/pkg/cel/libs/http"
 	"github.com/kyverno/kyverno/pkg/cel/matching"
 	engineapi "github.com/kyverno/kyverno/pkg/engine/api"
+	"github.com/kyverno/kyverno/pkg/engine/explain"
 	eval "github.com/kyverno/kyverno/pkg/imageverification/evaluator"
 	"github.com/kyverno/kyverno/pkg/imageverification/imagedataloader"
+	"github.com/kyverno/kyverno/pkg/imageverifycache"
 	admissionutils "github.com/kyverno/kyverno/pkg/utils/admission"
 	"golang.org/x/exp/maps"
 	"gomodules.xyz/jsonpatch/v2"
@@ -48,6 +51,8 @@ type engineImpl struct {
 	matcher      matching.Matcher
 	lister       k8scorev1.SecretInterface
 	registryOpts []imagedataloader.Option
+	httpConfig   *http.Config
+	cache        imageverifycache.Client
 }
 
 func NewEngine(
@@ -56,6 +61,8 @@ func NewEngine(
 	matcher matching.Matcher,
 	lister k8scorev1.SecretInterface,
 	registryOpts []imagedataloader.Option,
+	httpConfig *http.Config,
+	verificationCache imageverifycache.Client,
 ) Engine {
 	return &engineImpl{
 		provider:     provider,
@@ -63,6 +70,8 @@ func NewEngine(
 		matcher:      matcher,
 		lister:       lister,
 		registryOpts: registryOpts,
+		httpConfig:   httpConfig,
+		cache:        verificationCache,
 	}
 }
 
@@ -208,6 +217,13 @@ func (e *engineImpl) matchPolicy(policy Policy, attr admission.Attributes, names
 	return false, nil
 }
 
+// matchedPolicy is a policy matching the resource, it is evaluated in the context of its explain step.
+type matchedPolicy struct {
+	policy Policy
+	ctx    context.Context //nolint:containedctx
+	step   *explain.Step
+}
+
 func (e *engineImpl) handleMutation(
 	ctx context.Context,
 	policies []Policy,
@@ -217,9 +233,10 @@ func (e *engineImpl) handleMutation(
 	context libs.Context,
 ) ([]eval.ImageVerifyPolicyResponse, []jsonpatch.JsonPatchOperation, error) {
 	results := make(map[string]eval.ImageVerifyPolicyResponse, len(policies))
-	filteredPolicies := make([]Policy, 0)
+	filteredPolicies := make([]matchedPolicy, 0)
 	if e.matcher != nil {
 		for _, pol := range policies {
+			ctx, step := explain.BeginPolicy(ctx, pol.Policy)
 			matches, err := e.matchPolicy(pol, attr, namespace)
 			response := eval.ImageVerifyPolicyResponse{
 				Policy:     pol.Policy,
@@ -229,8 +246,14 @@ func (e *engineImpl) handleMutation(
 			if err != nil {
 				response.Result = *engineapi.RuleError("match", engineapi.ImageVerify, "failed to execute matching", err, nil)
 				results[pol.Policy.GetName()] = response
-			} else if matches {
-				filteredPolicies = append(filteredPolicies, pol)
+				step.EndWithResponses(response.Result)
+				continue
+			}
+			explain.RecordMatch(ctx, pol.Policy.GetMatchConstraints().ResourceRules, matches)
+			if matches {
+				filteredPolicies = append(filteredPolicies, matchedPolicy{policy: pol, ctx: ctx, step: step})
+			} else {
+				step.EndWithResponses()
 			}
 		}
 	}
@@ -238,8 +261,9 @@ func (e *engineImpl) handleMutation(
 	if err != nil {
 		return nil, nil, err
 	}
-	c := eval.NewCompiler(ictx, e.lister, request.RequestResource)
-	for _, ivpol := range filteredPolicies {
+	c := eval.NewCompiler(ictx, e.lister, request.RequestResource, eval.WithHTTPConfig(e.httpConfig), eval.WithVerificationCache(e.cache))
+	for _, matched := range filteredPolicies {
+		ivpol := matched.policy
 		response := eval.ImageVerifyPolicyResponse{
 			Policy:     ivpol.Policy,
 			Actions:    ivpol.Actions,
@@ -249,7 +273,7 @@ func (e *engineImpl) handleMutation(
 		if p, errList := c.Compile(ivpol.Policy, ivpol.Exceptions); errList != nil {
 			response.Result = *engineapi.RuleError("evaluation", engineapi.ImageVerify, "failed to compile policy", errList.ToAggregate(), nil)
 		} else {
-			result, err := p.Evaluate(ctx, ictx, attr, request, namespace, true, context)
+			result, err := p.Evaluate(matched.ctx, ictx, attr, request, namespace, true, context)
 			if err != nil {
 				response.Result = *engineapi.RuleError("evaluation", engineapi.ImageVerify, "failed to evaluate policy", err, nil)
 				results[ivpol.Policy.GetName()] = response
@@ -281,6 +305,12 @@ func (e *engineImpl) handleMutation(
 			}
 		}
 		response.Result = response.Result.WithStats(engineapi.NewExecutionStats(startTime, time.Now()))
+		// the policy is skipped when its match conditions are not met
+		if response.Result.Status() != "" {
+			matched.step.EndWithResponses(response.Result)
+		} else {
+			matched.step.EndWithResponses()
+		}
 	}
 	ann, err := objectAnnotations(attr)
 	if err != nil {
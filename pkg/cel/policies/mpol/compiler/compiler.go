@@ -21,6 +21,7 @@ import (
 	patch "k8s.io/apiserver/pkg/admission/plugin/policy/mutating/patch"
 	matchconditions "k8s.io/apiserver/pkg/admission/plugin/webhook/matchconditions"
 	environment "k8s.io/apiserver/pkg/cel/environment"
+	"k8s.io/client-go/tools/cache"
 )
 
 var (
@@ -32,14 +33,31 @@ type Compiler interface {
 	Compile(policy policiesv1beta1.MutatingPolicyLike, exceptions []*policiesv1alpha1.PolicyException) (*Policy, field.ErrorList)
 }
 
-func NewCompiler() Compiler {
-	return &compilerImpl{}
+// Option configures a Compiler.
+type Option func(*compilerImpl)
+
+// WithHTTPConfig configures the requests sent by the http library.
+func WithHTTPConfig(config *http.Config) Option {
+	return func(c *compilerImpl) {
+		c.httpConfig = config
+	}
+}
+
+func NewCompiler(opts ...Option) Compiler {
+	c := &compilerImpl{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
-type compilerImpl struct{}
+type compilerImpl struct {
+	httpConfig *http.Config
+}
 
 func (c *compilerImpl) Compile(policy policiesv1beta1.MutatingPolicyLike, exceptions []*policiesv1alpha1.PolicyException) (*Policy, field.ErrorList) {
 	var allErrs field.ErrorList
+	key, _ := cache.MetaNamespaceKeyFunc(policy)
 
 	baseEnvSet := environment.MustBaseEnvSet(environment.DefaultCompatibilityVersion(), false)
 	extendedEnvSet, err := baseEnvSet.Extend(
@@ -58,7 +76,7 @@ func (c *compilerImpl) Compile(policy policiesv1beta1.MutatingPolicyLike, except
 				cel.Types(compiler.NamespaceType.CelType()),
 				cel.Types(compiler.RequestType.CelType()),
 				globalcontext.Lib(image.Latest()),
-				http.Lib(image.Latest()),
+				http.Lib(c.httpConfig, http.Latest()),
 				image.Lib(image.Latest()),
 				imagedata.Lib(imagedata.Latest()),
 				resource.Lib(policy.GetNamespace(), resource.Latest()),
@@ -106,7 +124,7 @@ func (c *compilerImpl) Compile(policy policiesv1beta1.MutatingPolicyLike, except
 
 		failurePolicy := policy.GetFailurePolicy()
 		matcher = matchconditions.NewMatcher(
-			evaluator,
+			&observedConditions{ConditionEvaluator: evaluator, kind: policy.GetKind(), policy: key},
 			&failurePolicy,
 			"policy", "validate", policy.GetName())
 	}
@@ -159,6 +177,8 @@ func (c *compilerImpl) Compile(policy policiesv1beta1.MutatingPolicyLike, except
 		}
 	}
 	return &Policy{
+		kind:       policy.GetKind(),
+		name:       key,
 		evaluator:  mutating.PolicyEvaluator{Matcher: matcher, Mutators: patchers, CompositionEnv: compositedCompiler.CompositionEnv},
 		exceptions: compiledExceptions,
 	}, allErrs
@@ -31,6 +31,8 @@ import (
 )
 
 type Policy struct {
+	kind       string
+	name       string
 	evaluator  mutating.PolicyEvaluator
 	exceptions []compiler.Exception
 }
@@ -39,6 +41,7 @@ type compositionContext struct {
 	ctx             context.Context //nolint:containedctx
 	evaluator       *mutating.PolicyEvaluator
 	contextProvider libs.Context
+	tracer          *compiler.Tracer
 	accumulatedCost int64
 }
 
@@ -62,7 +65,7 @@ func (c *compositionContext) Variables(activation any) ref.Val {
 	// Set up context data for variable evaluation
 	ctxData := map[string]interface{}{
 		compiler.GlobalContextKey: globalcontext.Context{ContextInterface: c.contextProvider},
-		compiler.HttpKey:          http.Context{ContextInterface: http.NewHTTP(nil)},
+		compiler.HttpKey:          http.Context{ContextInterface: http.NewHTTPWithContext(c.ctx, nil)},
 		compiler.ImageDataKey:     imagedata.Context{ContextInterface: c.contextProvider},
 		compiler.ResourceKey:      resource.Context{ContextInterface: c.contextProvider},
 		compiler.VariablesKey:     lazyMap,
@@ -72,7 +75,9 @@ func (c *compositionContext) Variables(activation any) ref.Val {
 
 	for name, result := range c.evaluator.CompositionEnv.CompiledVariables {
 		lazyMap.Append(name, func(*lazy.MapValue) ref.Val {
+			start := c.tracer.Start()
 			out, _, err := result.Program.ContextEval(c.ctx, ctxData)
+			c.tracer.Variable(name, start, out, err)
 			if out != nil {
 				return out
 			}
@@ -159,14 +164,16 @@ func (p *Policy) Evaluate(
 		}
 	}
 
+	tracer := compiler.NewTracer(ctx, p.kind, p.name)
 	compositionCtx := &compositionContext{
 		ctx:             ctx,
 		evaluator:       &p.evaluator,
 		contextProvider: contextProvider,
+		tracer:          tracer,
 	}
 
 	o := admission.NewObjectInterfacesFromScheme(runtime.NewScheme())
-	for _, patcher := range p.evaluator.Mutators {
+	for index, patcher := range p.evaluator.Mutators {
 		patchRequest := patch.Request{
 			MatchedResource:     attr.GetResource(),
 			VersionedAttributes: versionedAttributes,
@@ -176,7 +183,9 @@ func (p *Policy) Evaluate(
 			TypeConverter:       tcm.GetTypeConverter(versionedAttributes.VersionedKind),
 		}
 
+		start := tracer.Start()
 		newVersionedObject, err := patcher.Patch(compositionCtx, patchRequest, celconfig.RuntimeCELCostBudget)
+		tracer.Done(fmt.Sprintf("mutations[%d]", index), start, err == nil, err)
 		if err != nil {
 			return &EvaluationResult{Error: err}
 		}
@@ -254,3 +263,27 @@ func (p *Policy) GetCompiledVariables() map[string]cel.Program {
 	}
 	return varsMap
 }
+
+// observedConditions notifies the observer of the context of the match conditions evaluated by the matcher.
+type observedConditions struct {
+	plugincel.ConditionEvaluator
+	kind   string
+	policy string
+}
+
+func (c *observedConditions) ForInput(
+	ctx context.Context,
+	versionedAttr *admission.VersionedAttributes,
+	request *admissionv1.AdmissionRequest,
+	optionalVars plugincel.OptionalVariableBindings,
+	namespace *corev1.Namespace,
+	runtimeCELCostBudget int64,
+) ([]plugincel.EvaluationResult, int64, error) {
+	results, remaining, err := c.ConditionEvaluator.ForInput(ctx, versionedAttr, request, optionalVars, namespace, runtimeCELCostBudget)
+	if tracer := compiler.NewTracer(ctx, c.kind, c.policy); tracer != nil {
+		for index, result := range results {
+			tracer.Elapsed(fmt.Sprintf("matchConditions[%d]", index), result.Elapsed, result.Error == nil && result.EvalResult == types.True, result.Error)
+		}
+	}
+	return results, remaining, err
+}
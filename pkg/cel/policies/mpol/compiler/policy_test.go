@@ -42,6 +42,9 @@ type fakeContext struct{}
 
 func (f *fakeContext) GenerateResources(string, []map[string]any) error        { return nil }
 func (f *fakeContext) GetGlobalReference(name, projection string) (any, error) { return name, nil }
+func (f *fakeContext) GetGlobalReferenceWithMaxAge(name, projection string, _ time.Duration) (any, error) {
+	return name, nil
+}
 func (f *fakeContext) GetImageData(image string) (map[string]any, error) {
 	return map[string]any{"test": image}, nil
 }
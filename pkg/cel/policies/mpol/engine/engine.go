@@ -13,6 +13,7 @@ import (
 	"github.com/kyverno/kyverno/pkg/cel/matching"
 	"github.com/kyverno/kyverno/pkg/cel/policies/mpol/compiler"
 	engineapi "github.com/kyverno/kyverno/pkg/engine/api"
+	"github.com/kyverno/kyverno/pkg/engine/explain"
 	"github.com/kyverno/kyverno/pkg/engine/handlers"
 	admissionutils "github.com/kyverno/kyverno/pkg/utils/admission"
 	reportutils "github.com/kyverno/kyverno/pkg/utils/report"
@@ -155,6 +156,10 @@ func (e *engineImpl) handlePolicy(ctx context.Context, mpol Policy, attr admissi
 		Rules:  []engineapi.RuleResponse{},
 	}
 
+	ctx, step := explain.BeginPolicy(ctx, mpol.Policy)
+	defer func() {
+		step.EndWithResponses(ruleResponse.Rules...)
+	}()
 	startTime := time.Now()
 	if e.matcher != nil {
 		constraints := mpol.Policy.GetMatchConstraints()
@@ -162,7 +167,9 @@ func (e *engineImpl) handlePolicy(ctx context.Context, mpol Policy, attr admissi
 		if err != nil {
 			ruleResponse.Rules = append(ruleResponse.Rules, engineapi.RuleError("match", engineapi.Validation, "failed to execute matching", err, nil).WithStats(engineapi.NewExecutionStats(startTime, time.Now())))
 			return ruleResponse, nil
-		} else if !matches {
+		}
+		explain.RecordMatch(ctx, constraints.ResourceRules, matches)
+		if !matches {
 			return ruleResponse, nil
 		}
 	}
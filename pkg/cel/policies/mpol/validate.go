@@ -6,7 +6,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
-func Validate(mpol v1beta1.MutatingPolicyLike) ([]string, error) {
+func Validate(mpol v1beta1.MutatingPolicyLike, opts ...compiler.Option) ([]string, error) {
 	warnings := make([]string, 0)
 	err := make(field.ErrorList, 0)
 
@@ -21,7 +21,7 @@ func Validate(mpol v1beta1.MutatingPolicyLike) ([]string, error) {
 		return warnings, err.ToAggregate()
 	}
 
-	compiler := compiler.NewCompiler()
+	compiler := compiler.NewCompiler(opts...)
 	_, errList := compiler.Compile(mpol, nil)
 	if errList != nil {
 		err = errList
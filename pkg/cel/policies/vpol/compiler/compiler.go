@@ -21,6 +21,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/version"
 	apiservercel "k8s.io/apiserver/pkg/cel"
 	"k8s.io/apiserver/pkg/cel/environment"
+	"k8s.io/client-go/tools/cache"
 )
 
 var (
@@ -37,19 +38,42 @@ type Compiler interface {
 	Compile(policy policiesv1beta1.ValidatingPolicyLike, exceptions []*policiesv1alpha1.PolicyException) (*Policy, field.ErrorList)
 }
 
-func NewCompiler() Compiler {
-	return &compilerImpl{}
+// Option configures a Compiler.
+type Option func(*compilerImpl)
+
+// WithHTTPConfig configures the requests sent by the http library.
+func WithHTTPConfig(config *http.Config) Option {
+	return func(c *compilerImpl) {
+		c.httpConfig = config
+	}
 }
 
-type compilerImpl struct{}
+func NewCompiler(opts ...Option) Compiler {
+	c := &compilerImpl{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+type compilerImpl struct {
+	httpConfig *http.Config
+}
 
 func (c *compilerImpl) Compile(policy policiesv1beta1.ValidatingPolicyLike, exceptions []*policiesv1alpha1.PolicyException) (*Policy, field.ErrorList) {
+	var compiled *Policy
+	var errs field.ErrorList
 	switch policy.GetValidatingPolicySpec().EvaluationMode() {
 	case policiesv1beta1.EvaluationModeJSON:
-		return c.compileForJSON(policy)
+		compiled, errs = c.compileForJSON(policy)
 	default:
-		return c.compileForKubernetes(policy, exceptions)
+		compiled, errs = c.compileForKubernetes(policy, exceptions)
+	}
+	if compiled != nil {
+		compiled.kind = policy.GetKind()
+		compiled.name, _ = cache.MetaNamespaceKeyFunc(policy)
 	}
+	return compiled, errs
 }
 
 func (c *compilerImpl) compileForKubernetes(policy policiesv1beta1.ValidatingPolicyLike, exceptions []*policiesv1alpha1.PolicyException) (*Policy, field.ErrorList) {
@@ -142,7 +166,7 @@ func (c *compilerImpl) compileForJSON(policy policiesv1beta1.ValidatingPolicyLik
 	}
 
 	options = append(options, declOptions...)
-	options = append(options, http.Lib(http.Latest()), image.Lib(image.Latest()), resource.Lib(policy.GetNamespace(), resource.Latest()))
+	options = append(options, http.Lib(c.httpConfig, http.Latest()), image.Lib(image.Latest()), resource.Lib(policy.GetNamespace(), resource.Latest()))
 	env, err := base.Extend(options...)
 	if err != nil {
 		return nil, append(allErrs, field.InternalError(nil, err))
@@ -243,6 +267,7 @@ func (c *compilerImpl) createBaseVpolEnv(namespace string) (*environment.EnvSet,
 					globalcontext.Latest(),
 				),
 				http.Lib(
+					c.httpConfig,
 					http.Latest(),
 				),
 				image.Lib(
@@ -26,6 +26,8 @@ import (
 )
 
 type Policy struct {
+	kind             string
+	name             string
 	mode             policiesv1beta1.EvaluationMode
 	failurePolicy    admissionregistrationv1.FailurePolicyType
 	matchConditions  []cel.Program
@@ -84,7 +86,7 @@ func (p *Policy) evaluateWithData(
 	allowedValues := make([]string, 0)
 	dataNew := map[string]any{
 		compiler.GlobalContextKey:   globalcontext.Context{ContextInterface: data.Context},
-		compiler.HttpKey:            http.Context{ContextInterface: http.NewHTTP(nil)},
+		compiler.HttpKey:            http.Context{ContextInterface: http.NewHTTPWithContext(ctx, nil)},
 		compiler.ImageDataKey:       imagedata.Context{ContextInterface: data.Context},
 		compiler.NamespaceObjectKey: data.Namespace,
 		compiler.ObjectKey:          data.Object,
@@ -96,7 +98,7 @@ func (p *Policy) evaluateWithData(
 	if len(p.exceptions) > 0 {
 		matchedExceptions := make([]*policiesv1alpha1.PolicyException, 0)
 		for _, polex := range p.exceptions {
-			match, err := p.match(ctx, dataNew, polex.MatchConditions)
+			match, err := p.match(ctx, dataNew, polex.MatchConditions, nil)
 			if err != nil {
 				return nil, err
 			}
@@ -116,7 +118,8 @@ func (p *Policy) evaluateWithData(
 		AllowedImages: allowedImages,
 		AllowedValues: allowedValues,
 	}
-	match, err := p.match(ctx, dataNew, p.matchConditions)
+	tracer := compiler.NewTracer(ctx, p.kind, p.name)
+	match, err := p.match(ctx, dataNew, p.matchConditions, tracer)
 	if err != nil {
 		return nil, err
 	}
@@ -127,7 +130,9 @@ func (p *Policy) evaluateWithData(
 	dataNew[compiler.VariablesKey] = vars
 	for name, variable := range p.variables {
 		vars.Append(name, func(*lazy.MapValue) ref.Val {
+			start := tracer.Start()
 			out, _, err := variable.ContextEval(ctx, dataNew)
+			tracer.Variable(name, start, out, err)
 			if out != nil {
 				return out
 			}
@@ -138,12 +143,16 @@ func (p *Policy) evaluateWithData(
 		})
 	}
 	for index, validation := range p.validations {
+		start := tracer.Start()
 		out, _, err := validation.Program.ContextEval(ctx, dataNew)
 		if err != nil {
+			tracer.Done(fmt.Sprintf("validations[%d]", index), start, false, err)
 			return nil, err
 		}
+		outcome, err := utils.ConvertToNative[bool](out)
+		tracer.Done(fmt.Sprintf("validations[%d]", index), start, err == nil && outcome, err)
 		// evaluate only when rule fails
-		if outcome, err := utils.ConvertToNative[bool](out); err == nil && !outcome {
+		if err == nil && !outcome {
 			message := validation.Message
 			if validation.MessageExpression != nil {
 				if out, _, err := validation.MessageExpression.ContextEval(ctx, dataNew); err != nil {
@@ -186,18 +195,22 @@ func (p *Policy) match(
 	ctx context.Context,
 	data map[string]any,
 	matchConditions []cel.Program,
+	tracer *compiler.Tracer,
 ) (bool, error) {
 	var errs []error
-	for _, matchCondition := range matchConditions {
+	for index, matchCondition := range matchConditions {
 		// evaluate the condition
+		start := tracer.Start()
 		out, _, err := matchCondition.ContextEval(ctx, data)
 		// check error
 		if err != nil {
+			tracer.Done(fmt.Sprintf("matchConditions[%d]", index), start, false, err)
 			errs = append(errs, err)
 			continue
 		}
 		// try to convert to a bool
 		result, err := utils.ConvertToNative[bool](out)
+		tracer.Done(fmt.Sprintf("matchConditions[%d]", index), start, err == nil && result, err)
 		// check error
 		if err != nil {
 			errs = append(errs, err)
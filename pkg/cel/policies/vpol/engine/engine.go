@@ -13,6 +13,7 @@ import (
 	"github.com/kyverno/kyverno/pkg/cel/matching"
 	"github.com/kyverno/kyverno/pkg/cel/policies/vpol/compiler"
 	engineapi "github.com/kyverno/kyverno/pkg/engine/api"
+	"github.com/kyverno/kyverno/pkg/engine/explain"
 	"github.com/kyverno/kyverno/pkg/engine/handlers"
 	admissionutils "github.com/kyverno/kyverno/pkg/utils/admission"
 	reportutils "github.com/kyverno/kyverno/pkg/utils/report"
@@ -116,13 +117,19 @@ func (e *engineImpl) handlePolicy(ctx context.Context, policy Policy, jsonPayloa
 		Actions: policy.Actions,
 		Policy:  policy.Policy,
 	}
+	ctx, step := explain.BeginPolicy(ctx, policy.Policy)
+	defer func() {
+		step.EndWithResponses(response.Rules...)
+	}()
 	spec := policy.Policy.GetValidatingPolicySpec()
 	if e.matcher != nil {
 		matches, err := e.matchPolicy(spec.MatchConstraints, attr, namespace)
 		if err != nil {
 			response.Rules = handlers.WithResponses(engineapi.RuleError("match", engineapi.Validation, "failed to execute matching", err, nil))
 			return response
-		} else if !matches {
+		}
+		explain.RecordMatch(ctx, policy.Policy.GetMatchConstraints().ResourceRules, matches)
+		if !matches {
 			return response
 		}
 	}
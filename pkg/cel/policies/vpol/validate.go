@@ -6,7 +6,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
-func Validate(vpol v1beta1.ValidatingPolicyLike) ([]string, error) {
+func Validate(vpol v1beta1.ValidatingPolicyLike, opts ...compiler.Option) ([]string, error) {
 	warnings := make([]string, 0)
 	err := make(field.ErrorList, 0)
 
@@ -21,7 +21,7 @@ func Validate(vpol v1beta1.ValidatingPolicyLike) ([]string, error) {
 		return warnings, err.ToAggregate()
 	}
 
-	compiler := compiler.NewCompiler()
+	compiler := compiler.NewCompiler(opts...)
 	_, errList := compiler.Compile(vpol, nil)
 	if errList != nil {
 		err = errList
@@ -26,6 +26,8 @@ import (
 type Interface interface {
 	// GlobalContextEntries returns a GlobalContextEntryInformer.
 	GlobalContextEntries() GlobalContextEntryInformer
+	// PolicySources returns a PolicySourceInformer.
+	PolicySources() PolicySourceInformer
 }
 
 type version struct {
@@ -43,3 +45,8 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 func (v *version) GlobalContextEntries() GlobalContextEntryInformer {
 	return &globalContextEntryInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
 }
+
+// PolicySources returns a PolicySourceInformer.
+func (v *version) PolicySources() PolicySourceInformer {
+	return &policySourceInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
@@ -21,3 +21,7 @@ package v2alpha1
 // GlobalContextEntryListerExpansion allows custom methods to be added to
 // GlobalContextEntryLister.
 type GlobalContextEntryListerExpansion interface{}
+
+// PolicySourceListerExpansion allows custom methods to be added to
+// PolicySourceLister.
+type PolicySourceListerExpansion interface{}
@@ -113,6 +113,7 @@ const (
 	webhookLabels                 = "webhookLabels"
 	matchConditions               = "matchConditions"
 	updateRequestThreshold        = "updateRequestThreshold"
+	httpEgress                    = "httpEgress"
 )
 
 const UpdateRequestThreshold = 1000
@@ -204,6 +205,8 @@ type Configuration interface {
 	OnChanged(func())
 	// GetUpdateRequestThreshold gets the threshold limit for the total number of updaterequests
 	GetUpdateRequestThreshold() int64
+	// GetHTTPEgress returns the restrictions on outbound requests sent by policies, nil if not configured
+	GetHTTPEgress() *HTTPEgressConfig
 }
 
 // configuration stores the configuration
@@ -222,6 +225,7 @@ type configuration struct {
 	mux                           sync.RWMutex
 	callbacks                     []func()
 	updateRequestThreshold        int64
+	httpEgress                    *HTTPEgressConfig
 }
 
 type match struct {
@@ -356,6 +360,12 @@ func (cd *configuration) GetUpdateRequestThreshold() int64 {
 	return cd.updateRequestThreshold
 }
 
+func (cd *configuration) GetHTTPEgress() *HTTPEgressConfig {
+	cd.mux.RLock()
+	defer cd.mux.RUnlock()
+	return cd.httpEgress
+}
+
 func (cd *configuration) Load(cm *corev1.ConfigMap) {
 	if cm != nil {
 		cd.load(cm)
@@ -384,6 +394,7 @@ func (cd *configuration) load(cm *corev1.ConfigMap) {
 	cd.webhookAnnotations = nil
 	cd.webhookLabels = nil
 	cd.matchConditions = nil
+	cd.httpEgress = nil
 	// load filters
 	cd.filters = parseKinds(data[resourceFilters])
 	cd.updateRequestThreshold = UpdateRequestThreshold
@@ -530,6 +541,20 @@ func (cd *configuration) load(cm *corev1.ConfigMap) {
 			logger.V(2).Info("enableDefaultRegistryMutation configured")
 		}
 	}
+	// load http egress
+	httpEgress, ok := data[httpEgress]
+	if !ok {
+		logger.V(2).Info("httpEgress not set")
+	} else {
+		logger := logger.WithValues("httpEgress", httpEgress)
+		httpEgress, err := parseHTTPEgress(httpEgress)
+		if err != nil {
+			logger.Error(err, "failed to parse http egress")
+		} else {
+			cd.httpEgress = httpEgress
+			logger.V(2).Info("httpEgress configured")
+		}
+	}
 }
 
 func (cd *configuration) unload() {
@@ -545,6 +570,7 @@ func (cd *configuration) unload() {
 	cd.webhook = WebhookConfig{}
 	cd.webhookAnnotations = nil
 	cd.webhookLabels = nil
+	cd.httpEgress = nil
 	logger.V(2).Info("configuration unloaded")
 }
 
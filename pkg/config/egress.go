@@ -0,0 +1,130 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/kyverno/kyverno/ext/wildcard"
+)
+
+// HTTPEgressConfig restricts the outbound requests policies can send with the http CEL library.
+// Empty lists don't restrict the corresponding request attribute.
+type HTTPEgressConfig struct {
+	// AllowedHosts are the host name patterns (wildcards supported) requests can be sent to.
+	AllowedHosts []string `json:"allowedHosts,omitempty"`
+	// AllowedCIDRs are the networks requests can be sent to.
+	// Host names not matching AllowedHosts are allowed if all their addresses belong to these networks.
+	AllowedCIDRs []string `json:"allowedCIDRs,omitempty"`
+	// AllowedSchemes are the URL schemes requests can use.
+	AllowedSchemes []string `json:"allowedSchemes,omitempty"`
+	// AllowedMethods are the HTTP methods requests can use.
+	AllowedMethods []string `json:"allowedMethods,omitempty"`
+	// MaxRequestsPerPolicy is the maximum number of requests a policy can send in a single evaluation, 0 means no limit.
+	MaxRequestsPerPolicy int64 `json:"maxRequestsPerPolicy,omitempty"`
+	// MaxResponseBytes is the maximum size of a response body, 0 means no limit.
+	MaxResponseBytes int64 `json:"maxResponseBytes,omitempty"`
+
+	networks []*net.IPNet
+}
+
+// HTTPEgressError is returned when a request is denied by the egress configuration.
+type HTTPEgressError struct {
+	// Reason is the request attribute that was denied (scheme, method, host or limit).
+	Reason string
+	// Message describes why the request was denied.
+	Message string
+}
+
+func (e *HTTPEgressError) Error() string {
+	return fmt.Sprintf("request denied by egress configuration: %s", e.Message)
+}
+
+func parseHTTPEgress(in string) (*HTTPEgressConfig, error) {
+	var out HTTPEgressConfig
+	if err := json.Unmarshal([]byte(in), &out); err != nil {
+		return nil, err
+	}
+	for _, cidr := range out.AllowedCIDRs {
+		_, network, err := net.ParseCIDR(strings.TrimSpace(cidr))
+		if err != nil {
+			return nil, err
+		}
+		out.networks = append(out.networks, network)
+	}
+	if out.MaxRequestsPerPolicy < 0 {
+		return nil, fmt.Errorf("maxRequestsPerPolicy must not be negative")
+	}
+	if out.MaxResponseBytes < 0 {
+		return nil, fmt.Errorf("maxResponseBytes must not be negative")
+	}
+	return &out, nil
+}
+
+// CheckRequest returns an HTTPEgressError if a request with the given method and target is not allowed.
+// Host names not matching AllowedHosts are allowed when AllowedCIDRs is not empty, the addresses
+// they resolve to must then be checked with CheckAddress when connecting.
+func (c *HTTPEgressConfig) CheckRequest(method string, target *url.URL) error {
+	if len(c.AllowedMethods) != 0 && !containsFold(c.AllowedMethods, method) {
+		return &HTTPEgressError{Reason: "method", Message: fmt.Sprintf("method %s is not allowed", method)}
+	}
+	if len(c.AllowedSchemes) != 0 && !containsFold(c.AllowedSchemes, target.Scheme) {
+		return &HTTPEgressError{Reason: "scheme", Message: fmt.Sprintf("scheme %s is not allowed", target.Scheme)}
+	}
+	host := strings.ToLower(target.Hostname())
+	if !c.restrictsHosts() || c.matchesHost(host) {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return c.CheckAddress(host, ip)
+	}
+	if len(c.networks) == 0 {
+		return &HTTPEgressError{Reason: "host", Message: fmt.Sprintf("host %s is not allowed", host)}
+	}
+	return nil
+}
+
+// CheckAddress returns an HTTPEgressError if connecting to ip, an address host resolved to, is not allowed.
+func (c *HTTPEgressConfig) CheckAddress(host string, ip net.IP) error {
+	host = strings.ToLower(host)
+	if !c.restrictsHosts() || c.matchesHost(host) || c.containsIP(ip) {
+		return nil
+	}
+	if ip.String() == host {
+		return &HTTPEgressError{Reason: "host", Message: fmt.Sprintf("host %s is not allowed", host)}
+	}
+	return &HTTPEgressError{Reason: "host", Message: fmt.Sprintf("address %s of host %s is not allowed", ip, host)}
+}
+
+func (c *HTTPEgressConfig) restrictsHosts() bool {
+	return len(c.AllowedHosts) != 0 || len(c.networks) != 0
+}
+
+func (c *HTTPEgressConfig) matchesHost(host string) bool {
+	for _, pattern := range c.AllowedHosts {
+		if wildcard.Match(strings.ToLower(pattern), host) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *HTTPEgressConfig) containsIP(ip net.IP) bool {
+	for _, network := range c.networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(strings.TrimSpace(v), value) {
+			return true
+		}
+	}
+	return false
+}
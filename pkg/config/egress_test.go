@@ -0,0 +1,148 @@
+package config
+
+import (
+	"net"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseHTTPEgress(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		wantErr bool
+	}{{
+		name: "valid",
+		in:   `{"allowedHosts":["*.example.com"],"allowedCIDRs":["10.0.0.0/8"],"allowedSchemes":["https"],"maxRequestsPerPolicy":5}`,
+	}, {
+		name:    "invalid json",
+		in:      `{`,
+		wantErr: true,
+	}, {
+		name:    "invalid cidr",
+		in:      `{"allowedCIDRs":["10.0.0.0"]}`,
+		wantErr: true,
+	}, {
+		name:    "negative limit",
+		in:      `{"maxResponseBytes":-1}`,
+		wantErr: true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseHTTPEgress(tt.in)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestHTTPEgressConfig_CheckRequest(t *testing.T) {
+	config, err := parseHTTPEgress(`{
+		"allowedHosts": ["*.example.com"],
+		"allowedCIDRs": ["10.0.0.0/8"],
+		"allowedSchemes": ["https"],
+		"allowedMethods": ["GET"]
+	}`)
+	assert.NoError(t, err)
+	tests := []struct {
+		name   string
+		method string
+		url    string
+		reason string
+	}{{
+		name:   "allowed host",
+		method: "GET",
+		url:    "https://api.example.com/data",
+	}, {
+		name:   "allowed host with port",
+		method: "GET",
+		url:    "https://API.example.com:8443/data",
+	}, {
+		name:   "denied method",
+		method: "POST",
+		url:    "https://api.example.com/data",
+		reason: "method",
+	}, {
+		name:   "denied scheme",
+		method: "GET",
+		url:    "http://api.example.com/data",
+		reason: "scheme",
+	}, {
+		name:   "allowed ip",
+		method: "GET",
+		url:    "https://10.0.0.1/data",
+	}, {
+		name:   "denied ip",
+		method: "GET",
+		url:    "https://169.254.169.254/data",
+		reason: "host",
+	}, {
+		name:   "host checked on connect",
+		method: "GET",
+		url:    "https://internal.svc/data",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target, err := url.Parse(tt.url)
+			assert.NoError(t, err)
+			err = config.CheckRequest(tt.method, target)
+			if tt.reason == "" {
+				assert.NoError(t, err)
+			} else {
+				var egressErr *HTTPEgressError
+				assert.ErrorAs(t, err, &egressErr)
+				assert.Equal(t, tt.reason, egressErr.Reason)
+			}
+		})
+	}
+	// without networks, host names not matching the allowed hosts are denied
+	hostsOnly, err := parseHTTPEgress(`{"allowedHosts": ["*.example.com"]}`)
+	assert.NoError(t, err)
+	target, err := url.Parse("https://evil.com/data")
+	assert.NoError(t, err)
+	assert.Error(t, hostsOnly.CheckRequest("GET", target))
+}
+
+func TestHTTPEgressConfig_CheckAddress(t *testing.T) {
+	config, err := parseHTTPEgress(`{
+		"allowedHosts": ["*.example.com"],
+		"allowedCIDRs": ["10.0.0.0/8"]
+	}`)
+	assert.NoError(t, err)
+	tests := []struct {
+		name    string
+		host    string
+		ip      string
+		wantErr bool
+	}{{
+		name: "allowed host",
+		host: "api.example.com",
+		ip:   "192.168.0.1",
+	}, {
+		name: "address in allowed network",
+		host: "internal.svc",
+		ip:   "10.1.2.3",
+	}, {
+		name:    "address outside allowed network",
+		host:    "internal.svc",
+		ip:      "169.254.169.254",
+		wantErr: true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := config.CheckAddress(tt.host, net.ParseIP(tt.ip))
+			if tt.wantErr {
+				var egressErr *HTTPEgressError
+				assert.ErrorAs(t, err, &egressErr)
+				assert.Equal(t, "host", egressErr.Reason)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -79,6 +79,20 @@ func (mr *MockConfigurationMockRecorder) GetGenerateSuccessEvents() *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGenerateSuccessEvents", reflect.TypeOf((*MockConfiguration)(nil).GetGenerateSuccessEvents))
 }
 
+// GetHTTPEgress mocks base method.
+func (m *MockConfiguration) GetHTTPEgress() *config.HTTPEgressConfig {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHTTPEgress")
+	ret0, _ := ret[0].(*config.HTTPEgressConfig)
+	return ret0
+}
+
+// GetHTTPEgress indicates an expected call of GetHTTPEgress.
+func (mr *MockConfigurationMockRecorder) GetHTTPEgress() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHTTPEgress", reflect.TypeOf((*MockConfiguration)(nil).GetHTTPEgress))
+}
+
 // GetMatchConditions mocks base method.
 func (m *MockConfiguration) GetMatchConditions() []v1.MatchCondition {
 	m.ctrl.T.Helper()
@@ -2,11 +2,16 @@ package cleanup
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"regexp"
 	"time"
 
 	"github.com/go-logr/logr"
 	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
 	kyvernov2 "github.com/kyverno/kyverno/api/kyverno/v2"
+	"github.com/kyverno/kyverno/pkg/archive"
+	"github.com/kyverno/kyverno/pkg/budget"
 	"github.com/kyverno/kyverno/pkg/client/clientset/versioned"
 	kyvernov2informers "github.com/kyverno/kyverno/pkg/client/informers/externalversions/kyverno/v2"
 	kyvernov2listers "github.com/kyverno/kyverno/pkg/client/listers/kyverno/v2"
@@ -14,6 +19,7 @@ import (
 	"github.com/kyverno/kyverno/pkg/config"
 	"github.com/kyverno/kyverno/pkg/controllers"
 	engineapi "github.com/kyverno/kyverno/pkg/engine/api"
+	"github.com/kyverno/kyverno/pkg/engine/apicall"
 	enginecontext "github.com/kyverno/kyverno/pkg/engine/context"
 	"github.com/kyverno/kyverno/pkg/engine/context/loaders"
 	"github.com/kyverno/kyverno/pkg/engine/factories"
@@ -24,20 +30,29 @@ import (
 	"github.com/kyverno/kyverno/pkg/toggle"
 	"github.com/kyverno/kyverno/pkg/utils/conditions"
 	controllerutils "github.com/kyverno/kyverno/pkg/utils/controller"
+	kubeutils "github.com/kyverno/kyverno/pkg/utils/kube"
 	"github.com/kyverno/kyverno/pkg/utils/match"
+	reportutils "github.com/kyverno/kyverno/pkg/utils/report"
 	"go.uber.org/multierr"
-	"k8s.io/apimachinery/pkg/api/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/dynamic"
 	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 )
 
 type controller struct {
 	// clients
-	client        dclient.Interface
-	kyvernoClient versioned.Interface
+	client         dclient.Interface
+	kyvernoClient  versioned.Interface
+	metadataClient metadata.Interface
 
 	// listers
 	cpolLister kyvernov2listers.ClusterCleanupPolicyLister
@@ -54,6 +69,9 @@ type controller struct {
 	eventGen      event.Interface
 	jp            jmespath.Interface
 	gctxStore     loaders.Store
+	apiCallConfig apicall.APICallConfiguration
+	budget        *budget.Budget
+	archive       archive.Sink
 }
 
 const (
@@ -66,6 +84,7 @@ const (
 func NewController(
 	client dclient.Interface,
 	kyvernoClient versioned.Interface,
+	metadataClient metadata.Interface,
 	cpolInformer kyvernov2informers.ClusterCleanupPolicyInformer,
 	polInformer kyvernov2informers.CleanupPolicyInformer,
 	nsLister corev1listers.NamespaceLister,
@@ -74,6 +93,9 @@ func NewController(
 	jp jmespath.Interface,
 	eventGen event.Interface,
 	gctxStore loaders.Store,
+	apiCallConfig apicall.APICallConfiguration,
+	budget *budget.Budget,
+	archive archive.Sink,
 ) controllers.Controller {
 	queue := workqueue.NewTypedRateLimitingQueueWithConfig(
 		workqueue.DefaultTypedControllerRateLimiter[any](),
@@ -97,18 +119,22 @@ func NewController(
 		}
 	}
 	c := &controller{
-		client:        client,
-		kyvernoClient: kyvernoClient,
-		cpolLister:    cpolInformer.Lister(),
-		polLister:     polInformer.Lister(),
-		nsLister:      nsLister,
-		queue:         queue,
-		enqueue:       baseEnqueueFunc,
-		configuration: configuration,
-		cmResolver:    cmResolver,
-		eventGen:      eventGen,
-		jp:            jp,
-		gctxStore:     gctxStore,
+		client:         client,
+		kyvernoClient:  kyvernoClient,
+		metadataClient: metadataClient,
+		cpolLister:     cpolInformer.Lister(),
+		polLister:      polInformer.Lister(),
+		nsLister:       nsLister,
+		queue:          queue,
+		enqueue:        baseEnqueueFunc,
+		configuration:  configuration,
+		cmResolver:     cmResolver,
+		eventGen:       eventGen,
+		jp:             jp,
+		gctxStore:      gctxStore,
+		apiCallConfig:  apiCallConfig,
+		budget:         budget,
+		archive:        archive,
 	}
 	if _, err := controllerutils.AddEventHandlersT(
 		cpolInformer.Informer(),
@@ -159,51 +185,33 @@ func (c *controller) getPolicy(namespace, name string) (kyvernov2.CleanupPolicyI
 	}
 }
 
-func (c *controller) cleanup(ctx context.Context, logger logr.Logger, policy kyvernov2.CleanupPolicyInterface) error {
+func (c *controller) cleanup(ctx context.Context, logger logr.Logger, policy kyvernov2.CleanupPolicyInterface) ([]unstructured.Unstructured, error) {
 	metrics := metrics.GetCleanupMetrics()
 
 	spec := policy.GetSpec()
 	kinds := sets.New(spec.MatchResources.GetKinds()...)
 	debug := logger.V(4)
 	var errs []error
+	var candidates []unstructured.Unstructured
 	deleteOptions := metav1.DeleteOptions{
 		PropagationPolicy: spec.DeletionPropagationPolicy,
 	}
+	policyKey, _ := cache.MetaNamespaceKeyFunc(policy)
+	run := c.budget.Run(policy.GetKind()+"/"+policyKey, budgetLimits(spec.DeletionBudget))
 	enginectx := enginecontext.NewContext(c.jp)
-	ctxFactory := factories.DefaultContextLoaderFactory(c.cmResolver, factories.WithGlobalContextStore(c.gctxStore))
+	ctxFactory := factories.DefaultContextLoaderFactory(c.cmResolver, factories.WithGlobalContextStore(c.gctxStore), factories.WithAPICallConfig(c.apiCallConfig))
 	loader := ctxFactory(nil, kyvernov1.Rule{})
-	if err := loader.Load(
-		ctx,
-		c.jp,
-		c.client,
-		nil,
-		spec.Context,
-		enginectx,
-	); err != nil {
-		return err
-	}
+	// resources are listed with their metadata only when possible, the full objects are fetched for the resources
+	// passing the match and exclude clauses when the conditions reference their content
+	metadataOnly := c.metadataClient != nil
+	fetchContent := metadataOnly && needsContent(spec)
 	for kind := range kinds {
 		debug := debug.WithValues("kind", kind)
 		debug.Info("processing...")
-		list, err := c.client.ListResource(ctx, "", kind, policy.GetNamespace(), nil)
-		if err != nil {
-			debug.Error(err, "failed to list resources")
-			if metrics != nil {
-				metrics.RecordCleanupFailure(ctx, kind, policy.GetNamespace(), policy, deleteOptions.PropagationPolicy)
-			}
-			// Check if this is a recoverable error (permission denied, resource not found, etc.)
-			if dclient.IsRecoverableError(err) {
-				logger.V(2).Info("skipping resource kind due to access restrictions", "kind", kind, "error", err.Error())
-			} else {
-				// For non-recoverable errors (connectivity issues, etc.), add to errors slice
-				errs = append(errs, err)
-			}
-
-			continue
-		}
-
-		for i := range list.Items {
-			resource := list.Items[i]
+		var selected []unstructured.Unstructured
+		total := 0
+		err := c.list(ctx, kind, policy.GetNamespace(), func(resource unstructured.Unstructured) error {
+			total++
 			namespace := resource.GetNamespace()
 			name := resource.GetName()
 			debug := debug.WithValues("name", name, "namespace", namespace)
@@ -211,11 +219,11 @@ func (c *controller) cleanup(ctx context.Context, logger logr.Logger, policy kyv
 			// Skip if resource matches resourceFilters from config
 			if c.configuration.ToFilter(gvk, resource.GetKind(), namespace, name) {
 				debug.Info("skipping resource due to resourceFilters in ConfigMap")
-				continue
+				return nil
 			}
 			// check if the resource is owned by Kyverno
 			if controllerutils.IsManagedByKyverno(&resource) && toggle.FromContext(ctx).ProtectManagedResources() {
-				continue
+				return nil
 			}
 
 			var nsLabels map[string]string
@@ -230,7 +238,7 @@ func (c *controller) cleanup(ctx context.Context, logger logr.Logger, policy kyv
 			// match namespaces
 			if err := match.CheckNamespace(policy.GetNamespace(), resource); err != nil {
 				debug.Info("resource namespace didn't match policy namespace", "result", err)
-				continue
+				return nil
 			}
 			// match resource with match/exclude clause
 			matched := match.CheckMatchesResources(
@@ -245,7 +253,7 @@ func (c *controller) cleanup(ctx context.Context, logger logr.Logger, policy kyv
 			)
 			if matched != nil {
 				debug.Info("resource/match didn't match", "result", matched)
-				continue
+				return nil
 			}
 			if spec.ExcludeResources != nil {
 				excluded := match.CheckMatchesResources(
@@ -260,43 +268,116 @@ func (c *controller) cleanup(ctx context.Context, logger logr.Logger, policy kyv
 				)
 				if excluded == nil {
 					debug.Info("resource/exclude matched")
-					continue
+					return nil
 				} else {
 					debug.Info("resource/exclude didn't match", "result", excluded)
 				}
 			}
 			// check conditions
 			if spec.Conditions != nil {
-				enginectx.Reset()
+				if fetchContent {
+					object, err := c.client.GetResource(ctx, resource.GetAPIVersion(), resource.GetKind(), namespace, name)
+					if err != nil {
+						if apierrors.IsNotFound(err) {
+							debug.Info("resource not found")
+							return nil
+						}
+						debug.Error(err, "failed to get resource")
+						errs = append(errs, err)
+						return nil
+					}
+					resource = *object
+				}
+				// the context entries are loaded for every resource, they can depend on the target resource
+				enginectx.Checkpoint()
+				defer enginectx.Restore()
 				if err := enginectx.SetTargetResource(resource.Object); err != nil {
 					debug.Error(err, "failed to add resource in context")
 					errs = append(errs, err)
-					continue
+					return nil
 				}
 				if err := enginectx.AddNamespace(resource.GetNamespace()); err != nil {
 					debug.Error(err, "failed to add namespace in context")
 					errs = append(errs, err)
-					continue
+					return nil
 				}
 				if err := enginectx.AddImageInfos(&resource, c.configuration); err != nil {
 					debug.Error(err, "failed to add image infos in context")
 					errs = append(errs, err)
-					continue
+					return nil
+				}
+				if err := loader.Load(ctx, c.jp, c.client, nil, spec.Context, enginectx); err != nil {
+					debug.Error(err, "failed to load context")
+					errs = append(errs, err)
+					return nil
 				}
 				passed, err := conditions.CheckAnyAllConditions(logger, enginectx, *spec.Conditions)
 				if err != nil {
 					debug.Error(err, "failed to check condition")
 					errs = append(errs, err)
-					continue
+					return nil
 				}
 				if !passed {
 					debug.Info("conditions did not pass")
+					return nil
+				}
+			}
+			selected = append(selected, resource)
+			return nil
+		})
+		if err != nil {
+			debug.Error(err, "failed to list resources")
+			if metrics != nil {
+				metrics.RecordCleanupFailure(ctx, kind, policy.GetNamespace(), policy, deleteOptions.PropagationPolicy)
+			}
+			// Check if this is a recoverable error (permission denied, resource not found, etc.)
+			if dclient.IsRecoverableError(err) {
+				logger.V(2).Info("skipping resource kind due to access restrictions", "kind", kind, "error", err.Error())
+			} else {
+				// For non-recoverable errors (connectivity issues, etc.), add to errors slice
+				errs = append(errs, err)
+			}
+
+			continue
+		}
+		if spec.DryRun {
+			for _, resource := range selected {
+				logger.WithValues("name", resource.GetName(), "namespace", resource.GetNamespace()).Info("resource matched, it would be deleted (dry run)")
+				candidates = append(candidates, resource)
+			}
+			continue
+		}
+		if err := run.CheckPercentage(kind, len(selected), total); err != nil {
+			return nil, multierr.Combine(append(errs, err)...)
+		}
+		for _, resource := range selected {
+			namespace := resource.GetNamespace()
+			name := resource.GetName()
+			debug := debug.WithValues("name", name, "namespace", namespace)
+			if err := run.Reserve(); err != nil {
+				return nil, multierr.Combine(append(errs, err)...)
+			}
+			uid, err := c.archiveResource(ctx, policy, resource, metadataOnly && !fetchContent)
+			if err != nil {
+				run.Release()
+				if apierrors.IsNotFound(err) {
+					debug.Info("resource not found")
 					continue
 				}
+				debug.Error(err, "failed to archive resource, it will not be deleted")
+				errs = append(errs, err)
+				c.eventGen.Add(event.NewCleanupPolicyEvent(policy, resource, err))
+				continue
 			}
 			logger.WithValues("name", name, "namespace", namespace).Info("resource matched, it will be deleted...")
-			if err := c.client.DeleteResource(ctx, resource.GetAPIVersion(), resource.GetKind(), namespace, name, false, deleteOptions); err != nil {
-				if errors.IsNotFound(err) {
+			options := deleteOptions
+			if uid != "" {
+				// make sure the archived object is the one being deleted
+				options.Preconditions = metav1.NewUIDPreconditions(string(uid))
+			}
+			if err := c.client.DeleteResource(ctx, resource.GetAPIVersion(), resource.GetKind(), namespace, name, false, options); err != nil {
+				run.Release()
+				if apierrors.IsNotFound(err) {
 					debug.Info("resource not found")
 					continue
 				}
@@ -317,7 +398,79 @@ func (c *controller) cleanup(ctx context.Context, logger logr.Logger, policy kyv
 			}
 		}
 	}
-	return multierr.Combine(errs...)
+	return candidates, multierr.Combine(errs...)
+}
+
+// list calls fn for every resource of a kind, page by page. When the metadata client is available, only the metadata
+// of the resources is listed.
+func (c *controller) list(ctx context.Context, kind, namespace string, fn func(unstructured.Unstructured) error) error {
+	apiVersion, kind := kubeutils.GetKindFromGVK(kind)
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return err
+	}
+	gvr, err := c.client.Discovery().GetGVRFromGVK(gv.WithKind(kind))
+	if err != nil {
+		return err
+	}
+	if c.metadataClient == nil {
+		var client dynamic.ResourceInterface = c.client.GetDynamicInterface().Resource(gvr)
+		if namespace != "" {
+			client = c.client.GetDynamicInterface().Resource(gvr).Namespace(namespace)
+		}
+		return kubeutils.EachResource(ctx, client, metav1.ListOptions{}, fn)
+	}
+	var client metadata.ResourceInterface = c.metadataClient.Resource(gvr)
+	if namespace != "" {
+		client = c.metadataClient.Resource(gvr).Namespace(namespace)
+	}
+	return kubeutils.EachMetadata(ctx, client, gvr.GroupVersion().WithKind(kind), metav1.ListOptions{}, fn)
+}
+
+// archiveResource archives a resource before it is deleted, its full object is fetched first when only its metadata was listed.
+// It returns the uid of the archived object, or an empty uid when archiving is disabled.
+func (c *controller) archiveResource(ctx context.Context, policy kyvernov2.CleanupPolicyInterface, resource unstructured.Unstructured, partial bool) (types.UID, error) {
+	if c.archive == nil {
+		return "", nil
+	}
+	object := &resource
+	if partial {
+		var err error
+		object, err = c.client.GetResource(ctx, resource.GetAPIVersion(), resource.GetKind(), resource.GetNamespace(), resource.GetName())
+		if err != nil {
+			return "", err
+		}
+	}
+	policyKey, _ := cache.MetaNamespaceKeyFunc(policy)
+	if err := archive.Archive(ctx, c.archive, object, policy.GetKind(), policyKey, archive.ReasonPolicyMatched); err != nil {
+		return "", err
+	}
+	return object.GetUID(), nil
+}
+
+// contentReference matches the references to the target resource and its images in context entries and conditions,
+// the second group is set when the reference is limited to the metadata of the target resource.
+var contentReference = regexp.MustCompile(`\b(target|images)\b(\.metadata\b)?`)
+
+// needsContent returns true if the conditions of the policy, or the context entries they can use, reference more
+// than the metadata of the target resource.
+func needsContent(spec *kyvernov2.CleanupPolicySpec) bool {
+	if spec.Conditions == nil {
+		return false
+	}
+	data, err := json.Marshal(struct {
+		Context    []kyvernov1.ContextEntry    `json:"context,omitempty"`
+		Conditions *kyvernov2.AnyAllConditions `json:"conditions"`
+	}{spec.Context, spec.Conditions})
+	if err != nil {
+		return true
+	}
+	for _, match := range contentReference.FindAllSubmatch(data, -1) {
+		if string(match[1]) == "images" || len(match[2]) == 0 {
+			return true
+		}
+	}
+	return false
 }
 
 func (c *controller) reconcile(ctx context.Context, logger logr.Logger, key, namespace, name string) error {
@@ -338,11 +491,21 @@ func (c *controller) reconcile(ctx context.Context, logger logr.Logger, key, nam
 	}
 	// In case it is the time to do the cleanup process
 	if time.Now().After(*executionTime) {
-		err := c.cleanup(ctx, logger, policy)
+		candidates, err := c.cleanup(ctx, logger, policy)
+		var exceeded *budget.ExceededError
+		if errors.As(err, &exceeded) {
+			// the run is aborted, the next run happens on schedule
+			logger.Error(err, "cleanup run aborted")
+			c.eventGen.Add(event.NewCleanupPolicyBudgetExceededEvent(policy, exceeded))
+		} else if err != nil {
+			return err
+		}
+		dryRun, err := c.recordDryRun(ctx, policy, candidates)
 		if err != nil {
+			logger.Error(err, "failed to record the dry run report")
 			return err
 		}
-		if err := c.updateCleanupPolicyStatus(ctx, policy, namespace, time.Now()); err != nil {
+		if err := c.updateCleanupPolicyStatus(ctx, policy, namespace, time.Now(), dryRun, exceeded); err != nil {
 			logger.Error(err, "failed to update the cleanup policy status")
 			return err
 		}
@@ -366,11 +529,69 @@ func (c *controller) reconcile(ctx context.Context, logger logr.Logger, key, nam
 	return nil
 }
 
-func (c *controller) updateCleanupPolicyStatus(ctx context.Context, policy kyvernov2.CleanupPolicyInterface, namespace string, time time.Time) error {
+// recordDryRun writes the resources selected by a dry run execution in the dry run report of the policy and returns
+// the dry run status of the policy. A single event summarizes the execution. The report of a previous dry run is
+// deleted when the policy is not in dry run mode.
+func (c *controller) recordDryRun(ctx context.Context, policy kyvernov2.CleanupPolicyInterface, candidates []unstructured.Unstructured) (*kyvernov2.DryRunStatus, error) {
+	reportutils.SortDryRunResources(candidates)
+	report := reportutils.BuildDryRunReport(policy, kyvernov2.SchemeGroupVersion.String(), policy.GetKind(), candidates)
+	if !policy.GetSpec().DryRun {
+		if policy.GetStatus().DryRun != nil {
+			if err := reportutils.DeleteReport(ctx, report, c.kyvernoClient, nil); err != nil && !apierrors.IsNotFound(err) {
+				return nil, err
+			}
+		}
+		return nil, nil
+	}
+	if _, err := reportutils.ApplyDryRunReport(ctx, report, c.kyvernoClient); err != nil {
+		return nil, err
+	}
+	c.eventGen.Add(event.NewCleanupPolicyDryRunEvent(policy, len(candidates)))
+	status := &kyvernov2.DryRunStatus{
+		Count: len(candidates),
+	}
+	for _, candidate := range candidates {
+		if len(status.Resources) == reportutils.MaxDryRunResources {
+			break
+		}
+		status.Resources = append(status.Resources, kyvernov2.DryRunResource{
+			APIVersion: candidate.GetAPIVersion(),
+			Kind:       candidate.GetKind(),
+			Namespace:  candidate.GetNamespace(),
+			Name:       candidate.GetName(),
+		})
+	}
+	return status, nil
+}
+
+func budgetLimits(deletionBudget *kyvernov2.DeletionBudget) budget.Limits {
+	if deletionBudget == nil {
+		return budget.Limits{}
+	}
+	return budget.Limits{
+		MaxPerRun:     int(deletionBudget.MaxDeletionsPerRun),
+		MaxPerMinute:  int(deletionBudget.MaxDeletionsPerMinute),
+		MaxPercentage: int(deletionBudget.MaxPercentage),
+	}
+}
+
+// setBudgetExceeded reports in the status whether the run exceeded the deletion budget,
+// the condition is only added to the status once a run exceeded the budget.
+func setBudgetExceeded(status *kyvernov2.CleanupPolicyStatus, exceeded *budget.ExceededError) {
+	if exceeded != nil {
+		status.SetBudgetExceeded(true, exceeded.Error())
+	} else if meta.FindStatusCondition(status.Conditions, kyvernov2.CleanupPolicyConditionBudgetExceeded) != nil {
+		status.SetBudgetExceeded(false, "the last run completed within the deletion budget")
+	}
+}
+
+func (c *controller) updateCleanupPolicyStatus(ctx context.Context, policy kyvernov2.CleanupPolicyInterface, namespace string, time time.Time, dryRun *kyvernov2.DryRunStatus, exceeded *budget.ExceededError) error {
 	switch obj := policy.(type) {
 	case *kyvernov2.ClusterCleanupPolicy:
 		latest := obj.DeepCopy()
 		latest.Status.LastExecutionTime = metav1.NewTime(time)
+		latest.Status.DryRun = dryRun
+		setBudgetExceeded(&latest.Status, exceeded)
 
 		new, err := c.kyvernoClient.KyvernoV2().ClusterCleanupPolicies().UpdateStatus(ctx, latest, metav1.UpdateOptions{})
 		if err != nil {
@@ -380,6 +601,8 @@ func (c *controller) updateCleanupPolicyStatus(ctx context.Context, policy kyver
 	case *kyvernov2.CleanupPolicy:
 		latest := obj.DeepCopy()
 		latest.Status.LastExecutionTime = metav1.NewTime(time)
+		latest.Status.DryRun = dryRun
+		setBudgetExceeded(&latest.Status, exceeded)
 
 		new, err := c.kyvernoClient.KyvernoV2().CleanupPolicies(namespace).UpdateStatus(ctx, latest, metav1.UpdateOptions{})
 		if err != nil {
@@ -2,25 +2,37 @@ package cleanup
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/golang/mock/gomock"
+	"github.com/kyverno/kyverno/api/kyverno"
 	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
 	kyvernov2 "github.com/kyverno/kyverno/api/kyverno/v2"
+	"github.com/kyverno/kyverno/pkg/archive"
+	"github.com/kyverno/kyverno/pkg/budget"
 	versionedfake "github.com/kyverno/kyverno/pkg/client/clientset/versioned/fake"
 	v2listers "github.com/kyverno/kyverno/pkg/client/listers/kyverno/v2"
 	"github.com/kyverno/kyverno/pkg/clients/dclient"
 	configpkg "github.com/kyverno/kyverno/pkg/config"
 	"github.com/kyverno/kyverno/pkg/config/mocks"
 	"github.com/kyverno/kyverno/pkg/engine/jmespath"
+	"github.com/kyverno/kyverno/pkg/event"
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
 	corev1listers "k8s.io/client-go/listers/core/v1"
+	metadatafake "k8s.io/client-go/metadata/fake"
+	clienttesting "k8s.io/client-go/testing"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 )
@@ -29,6 +41,7 @@ type mockDClient struct {
 	dclient.Interface
 	listResource   func(ctx context.Context, apiVersion string, kind string, namespace string, lselector *metav1.LabelSelector) (*unstructured.UnstructuredList, error)
 	deleteResource func(ctx context.Context, apiVersion string, kind string, namespace string, name string, dryRun bool, options metav1.DeleteOptions) error
+	getResource    func(ctx context.Context, apiVersion string, kind string, namespace string, name string, subresources ...string) (*unstructured.Unstructured, error)
 }
 
 func (m *mockDClient) ListResource(ctx context.Context, apiVersion string, kind string, namespace string, lselector *metav1.LabelSelector) (*unstructured.UnstructuredList, error) {
@@ -38,6 +51,22 @@ func (m *mockDClient) ListResource(ctx context.Context, apiVersion string, kind
 	return m.Interface.ListResource(ctx, apiVersion, kind, namespace, lselector)
 }
 
+// GetDynamicInterface returns a fake dynamic client listing config maps with listResource when it is set.
+func (m *mockDClient) GetDynamicInterface() dynamic.Interface {
+	if m.listResource == nil {
+		return m.Interface.GetDynamicInterface()
+	}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		{Version: "v1", Resource: "configmaps"}: "ConfigMapList",
+	})
+	client.PrependReactor("list", "*", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		kind := action.(clienttesting.ListActionImpl).GetKind()
+		list, err := m.listResource(context.TODO(), kind.GroupVersion().String(), kind.Kind, action.GetNamespace(), nil)
+		return true, list, err
+	})
+	return client
+}
+
 func (m *mockDClient) DeleteResource(ctx context.Context, apiVersion string, kind string, namespace string, name string, dryRun bool, options metav1.DeleteOptions) error {
 	if m.deleteResource != nil {
 		return m.deleteResource(ctx, apiVersion, kind, namespace, name, dryRun, options)
@@ -45,6 +74,13 @@ func (m *mockDClient) DeleteResource(ctx context.Context, apiVersion string, kin
 	return m.Interface.DeleteResource(ctx, apiVersion, kind, namespace, name, dryRun, options)
 }
 
+func (m *mockDClient) GetResource(ctx context.Context, apiVersion string, kind string, namespace string, name string, subresources ...string) (*unstructured.Unstructured, error) {
+	if m.getResource != nil {
+		return m.getResource(ctx, apiVersion, kind, namespace, name, subresources...)
+	}
+	return m.Interface.GetResource(ctx, apiVersion, kind, namespace, name, subresources...)
+}
+
 func Test_Cleanup_SkipResourceNamespaceMismatch(t *testing.T) {
 	// Define a CleanupPolicy in ns1
 	policy := &kyvernov2.CleanupPolicy{
@@ -124,12 +160,486 @@ func Test_Cleanup_SkipResourceNamespaceMismatch(t *testing.T) {
 	}
 
 	ctx := context.Background()
-	err := c.cleanup(ctx, logr.Discard(), policy)
+	_, err := c.cleanup(ctx, logr.Discard(), policy)
 	if err != nil {
 		t.Fatalf("cleanup failed: %v", err)
 	}
 }
 
+func Test_Cleanup_DryRun(t *testing.T) {
+	policy := &kyvernov2.CleanupPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-policy",
+			Namespace: "ns1",
+		},
+		Spec: kyvernov2.CleanupPolicySpec{
+			MatchResources: kyvernov2.MatchResources{
+				Any: []kyvernov1.ResourceFilter{
+					{
+						ResourceDescription: kyvernov1.ResourceDescription{
+							Kinds: []string{"ConfigMap"},
+						},
+					},
+				},
+			},
+			DryRun: true,
+		},
+	}
+
+	var resources []unstructured.Unstructured
+	for _, name := range []string{"cm-b", "cm-a"} {
+		resource := unstructured.Unstructured{}
+		resource.SetAPIVersion("v1")
+		resource.SetKind("ConfigMap")
+		resource.SetName(name)
+		resource.SetNamespace("ns1")
+		resources = append(resources, resource)
+	}
+
+	mockClient := &mockDClient{
+		Interface: dclient.NewEmptyFakeClient(),
+		listResource: func(ctx context.Context, apiVersion string, kind string, namespace string, lselector *metav1.LabelSelector) (*unstructured.UnstructuredList, error) {
+			return &unstructured.UnstructuredList{Items: resources}, nil
+		},
+		deleteResource: func(ctx context.Context, apiVersion string, kind string, namespace string, name string, dryRun bool, options metav1.DeleteOptions) error {
+			t.Fatalf("DeleteResource should not be called in dry run mode")
+			return nil
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockConfig := mocks.NewMockConfiguration(ctrl)
+	mockConfig.EXPECT().
+		ToFilter(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(false).
+		AnyTimes()
+
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	if err := indexer.Add(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns1"}}); err != nil {
+		t.Fatalf("failed to add namespace: %v", err)
+	}
+
+	fakeClient := versionedfake.NewSimpleClientset(policy.DeepCopy())
+	c := &controller{
+		client:        mockClient,
+		kyvernoClient: fakeClient,
+		configuration: mockConfig,
+		nsLister:      corev1listers.NewNamespaceLister(indexer),
+		eventGen:      event.NewFake(),
+		jp:            jmespath.New(configpkg.NewDefaultConfiguration(false)),
+	}
+
+	ctx := context.Background()
+	candidates, err := c.cleanup(ctx, logr.Discard(), policy)
+	assert.NoError(t, err)
+	assert.Len(t, candidates, 2)
+
+	dryRun, err := c.recordDryRun(ctx, policy, candidates)
+	assert.NoError(t, err)
+	assert.Equal(t, &kyvernov2.DryRunStatus{
+		Count: 2,
+		Resources: []kyvernov2.DryRunResource{
+			{APIVersion: "v1", Kind: "ConfigMap", Namespace: "ns1", Name: "cm-a"},
+			{APIVersion: "v1", Kind: "ConfigMap", Namespace: "ns1", Name: "cm-b"},
+		},
+	}, dryRun)
+
+	report, err := fakeClient.Wgpolicyk8sV1alpha2().PolicyReports("ns1").Get(ctx, "dryrun-cleanuppolicy-test-policy", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, report.Results, 2)
+	assert.Equal(t, "ns1/test-policy", report.Results[0].Policy)
+	assert.Equal(t, "CleanupPolicy", report.OwnerReferences[0].Kind)
+
+	assert.NoError(t, c.updateCleanupPolicyStatus(ctx, policy, "ns1", time.Now(), dryRun, nil))
+	updated, err := fakeClient.KyvernoV2().CleanupPolicies("ns1").Get(ctx, "test-policy", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, dryRun, updated.Status.DryRun)
+
+	// disabling the dry run mode deletes the report of the previous dry run
+	updated.Spec.DryRun = false
+	dryRun, err = c.recordDryRun(ctx, updated, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, dryRun)
+	_, err = fakeClient.Wgpolicyk8sV1alpha2().PolicyReports("ns1").Get(ctx, "dryrun-cleanuppolicy-test-policy", metav1.GetOptions{})
+	assert.True(t, apierrors.IsNotFound(err))
+}
+
+func Test_Cleanup_DeletionBudget(t *testing.T) {
+	newPolicy := func(deletionBudget *kyvernov2.DeletionBudget) *kyvernov2.CleanupPolicy {
+		return &kyvernov2.CleanupPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-policy",
+				Namespace: "ns1",
+			},
+			Spec: kyvernov2.CleanupPolicySpec{
+				MatchResources: kyvernov2.MatchResources{
+					Any: []kyvernov1.ResourceFilter{
+						{
+							ResourceDescription: kyvernov1.ResourceDescription{
+								Kinds: []string{"ConfigMap"},
+								Names: []string{"cm-*"},
+							},
+						},
+					},
+				},
+				DeletionBudget: deletionBudget,
+			},
+		}
+	}
+	var resources []unstructured.Unstructured
+	for _, name := range []string{"cm-a", "cm-b", "cm-c", "other"} {
+		resource := unstructured.Unstructured{}
+		resource.SetAPIVersion("v1")
+		resource.SetKind("ConfigMap")
+		resource.SetName(name)
+		resource.SetNamespace("ns1")
+		resources = append(resources, resource)
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockConfig := mocks.NewMockConfiguration(ctrl)
+	mockConfig.EXPECT().
+		ToFilter(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(false).
+		AnyTimes()
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	if err := indexer.Add(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns1"}}); err != nil {
+		t.Fatalf("failed to add namespace: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		global         budget.Limits
+		deletionBudget *kyvernov2.DeletionBudget
+		deleted        int
+		exceeded       string
+	}{{
+		name:    "no budget",
+		deleted: 3,
+	}, {
+		name:           "max deletions per run",
+		deletionBudget: &kyvernov2.DeletionBudget{MaxDeletionsPerRun: 2},
+		deleted:        2,
+		exceeded:       "deletion budget exceeded: the run reached the maximum of 2 deletions",
+	}, {
+		name:     "global max deletions per minute",
+		global:   budget.Limits{MaxPerMinute: 1},
+		deleted:  1,
+		exceeded: "deletion budget exceeded: the global maximum of 1 deletions per minute was reached",
+	}, {
+		name:           "max percentage",
+		deletionBudget: &kyvernov2.DeletionBudget{MaxPercentage: 50},
+		deleted:        0,
+		exceeded:       "deletion budget exceeded: deleting 3 of 4 ConfigMap resources exceeds the maximum of 50%",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deleted := 0
+			policy := newPolicy(tt.deletionBudget)
+			fakeClient := versionedfake.NewSimpleClientset(policy.DeepCopy())
+			c := &controller{
+				client: &mockDClient{
+					Interface: dclient.NewEmptyFakeClient(),
+					listResource: func(ctx context.Context, apiVersion string, kind string, namespace string, lselector *metav1.LabelSelector) (*unstructured.UnstructuredList, error) {
+						return &unstructured.UnstructuredList{Items: resources}, nil
+					},
+					deleteResource: func(ctx context.Context, apiVersion string, kind string, namespace string, name string, dryRun bool, options metav1.DeleteOptions) error {
+						deleted++
+						return nil
+					},
+				},
+				kyvernoClient: fakeClient,
+				configuration: mockConfig,
+				nsLister:      corev1listers.NewNamespaceLister(indexer),
+				eventGen:      event.NewFake(),
+				jp:            jmespath.New(configpkg.NewDefaultConfiguration(false)),
+				budget:        budget.New(tt.global),
+			}
+			ctx := context.Background()
+			_, err := c.cleanup(ctx, logr.Discard(), policy)
+			assert.Equal(t, tt.deleted, deleted)
+			var exceeded *budget.ExceededError
+			if tt.exceeded == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorAs(t, err, &exceeded)
+				assert.EqualError(t, exceeded, tt.exceeded)
+			}
+
+			assert.NoError(t, c.updateCleanupPolicyStatus(ctx, policy, "ns1", time.Now(), nil, exceeded))
+			updated, err := fakeClient.KyvernoV2().CleanupPolicies("ns1").Get(ctx, "test-policy", metav1.GetOptions{})
+			assert.NoError(t, err)
+			condition := meta.FindStatusCondition(updated.Status.Conditions, kyvernov2.CleanupPolicyConditionBudgetExceeded)
+			if tt.exceeded == "" {
+				assert.Nil(t, condition)
+			} else {
+				assert.Equal(t, metav1.ConditionTrue, condition.Status)
+				assert.Equal(t, tt.exceeded, condition.Message)
+				// the condition is reset by the next run completing within the budget
+				assert.NoError(t, c.updateCleanupPolicyStatus(ctx, updated, "ns1", time.Now(), nil, nil))
+				updated, err = fakeClient.KyvernoV2().CleanupPolicies("ns1").Get(ctx, "test-policy", metav1.GetOptions{})
+				assert.NoError(t, err)
+				condition = meta.FindStatusCondition(updated.Status.Conditions, kyvernov2.CleanupPolicyConditionBudgetExceeded)
+				assert.Equal(t, metav1.ConditionFalse, condition.Status)
+			}
+		})
+	}
+}
+
+type fakeSink struct {
+	records []archive.Record
+	err     error
+}
+
+func (s *fakeSink) Archive(_ context.Context, record archive.Record) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.records = append(s.records, record)
+	return nil
+}
+
+func Test_Cleanup_Archive(t *testing.T) {
+	policy := &kyvernov2.ClusterCleanupPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-policy",
+		},
+		Spec: kyvernov2.CleanupPolicySpec{
+			MatchResources: kyvernov2.MatchResources{
+				Any: []kyvernov1.ResourceFilter{
+					{
+						ResourceDescription: kyvernov1.ResourceDescription{
+							Kinds: []string{"ConfigMap"},
+						},
+					},
+				},
+			},
+		},
+	}
+	resource := unstructured.Unstructured{}
+	resource.SetAPIVersion("v1")
+	resource.SetKind("ConfigMap")
+	resource.SetName("cm")
+	resource.SetNamespace("ns1")
+	resource.SetUID("cm-uid")
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockConfig := mocks.NewMockConfiguration(ctrl)
+	mockConfig.EXPECT().
+		ToFilter(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(false).
+		AnyTimes()
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	if err := indexer.Add(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns1"}}); err != nil {
+		t.Fatalf("failed to add namespace: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		sink    *fakeSink
+		deleted int
+		wantErr string
+	}{{
+		name:    "archived",
+		sink:    &fakeSink{},
+		deleted: 1,
+	}, {
+		name:    "archive failure",
+		sink:    &fakeSink{err: errors.New("disk full")},
+		deleted: 0,
+		wantErr: "failed to archive resource: disk full",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deleted := 0
+			var preconditions *metav1.Preconditions
+			c := &controller{
+				client: &mockDClient{
+					Interface: dclient.NewEmptyFakeClient(),
+					listResource: func(ctx context.Context, apiVersion string, kind string, namespace string, lselector *metav1.LabelSelector) (*unstructured.UnstructuredList, error) {
+						return &unstructured.UnstructuredList{Items: []unstructured.Unstructured{resource}}, nil
+					},
+					deleteResource: func(ctx context.Context, apiVersion string, kind string, namespace string, name string, dryRun bool, options metav1.DeleteOptions) error {
+						deleted++
+						preconditions = options.Preconditions
+						return nil
+					},
+				},
+				configuration: mockConfig,
+				nsLister:      corev1listers.NewNamespaceLister(indexer),
+				eventGen:      event.NewFake(),
+				jp:            jmespath.New(configpkg.NewDefaultConfiguration(false)),
+				archive:       tt.sink,
+			}
+			_, err := c.cleanup(context.Background(), logr.Discard(), policy)
+			assert.Equal(t, tt.deleted, deleted)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				assert.Empty(t, tt.sink.records)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, tt.sink.records, 1)
+			assert.Equal(t, "ClusterCleanupPolicy", tt.sink.records[0].PolicyKind)
+			assert.Equal(t, "test-policy", tt.sink.records[0].Policy)
+			assert.Equal(t, archive.ReasonPolicyMatched, tt.sink.records[0].Reason)
+			assert.Equal(t, "cm", tt.sink.records[0].Object.GetName())
+			assert.Equal(t, metav1.NewUIDPreconditions("cm-uid"), preconditions)
+		})
+	}
+}
+
+func Test_Cleanup_MetadataOnly(t *testing.T) {
+	newPolicy := func(key string, context ...kyvernov1.ContextEntry) *kyvernov2.CleanupPolicy {
+		return &kyvernov2.CleanupPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-policy",
+				Namespace: "ns1",
+			},
+			Spec: kyvernov2.CleanupPolicySpec{
+				Context: context,
+				MatchResources: kyvernov2.MatchResources{
+					Any: []kyvernov1.ResourceFilter{
+						{
+							ResourceDescription: kyvernov1.ResourceDescription{
+								Kinds: []string{"ConfigMap"},
+							},
+						},
+					},
+				},
+				Conditions: &kyvernov2.AnyAllConditions{
+					AllConditions: []kyvernov2.Condition{
+						{
+							RawKey:   kyverno.ToAny("{{ " + key + " }}"),
+							Operator: kyvernov2.ConditionOperators["Equals"],
+							RawValue: kyverno.ToAny("cm-a"),
+						},
+					},
+				},
+			},
+		}
+	}
+	scheme := runtime.NewScheme()
+	assert.NoError(t, metav1.AddMetaToScheme(scheme))
+	var objects []runtime.Object
+	for _, name := range []string{"cm-a", "cm-b"} {
+		objects = append(objects, &metav1.PartialObjectMetadata{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns1"},
+		})
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockConfig := mocks.NewMockConfiguration(ctrl)
+	mockConfig.EXPECT().
+		ToFilter(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(false).
+		AnyTimes()
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	if err := indexer.Add(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns1"}}); err != nil {
+		t.Fatalf("failed to add namespace: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		key     string
+		context []kyvernov1.ContextEntry
+		fetched []string
+	}{{
+		name: "conditions on metadata",
+		key:  "target.metadata.name",
+	}, {
+		name:    "conditions on content",
+		key:     "target.data.name",
+		fetched: []string{"cm-a", "cm-b"},
+	}, {
+		name: "conditions on a context variable derived from content",
+		key:  "name",
+		context: []kyvernov1.ContextEntry{{
+			Name:     "name",
+			Variable: &kyvernov1.Variable{JMESPath: "target.data.name"},
+		}},
+		fetched: []string{"cm-a", "cm-b"},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fetched, deleted []string
+			c := &controller{
+				client: &mockDClient{
+					Interface: dclient.NewEmptyFakeClient(),
+					listResource: func(ctx context.Context, apiVersion string, kind string, namespace string, lselector *metav1.LabelSelector) (*unstructured.UnstructuredList, error) {
+						t.Fatalf("resources should not be listed by the dynamic client when the metadata client is available")
+						return nil, nil
+					},
+					getResource: func(ctx context.Context, apiVersion string, kind string, namespace string, name string, subresources ...string) (*unstructured.Unstructured, error) {
+						fetched = append(fetched, name)
+						resource := &unstructured.Unstructured{}
+						resource.SetAPIVersion(apiVersion)
+						resource.SetKind(kind)
+						resource.SetNamespace(namespace)
+						resource.SetName(name)
+						assert.NoError(t, unstructured.SetNestedField(resource.Object, name, "data", "name"))
+						return resource, nil
+					},
+					deleteResource: func(ctx context.Context, apiVersion string, kind string, namespace string, name string, dryRun bool, options metav1.DeleteOptions) error {
+						assert.Equal(t, "v1", apiVersion)
+						assert.Equal(t, "ConfigMap", kind)
+						deleted = append(deleted, name)
+						return nil
+					},
+				},
+				metadataClient: metadatafake.NewSimpleMetadataClient(scheme, objects...),
+				configuration:  mockConfig,
+				nsLister:       corev1listers.NewNamespaceLister(indexer),
+				eventGen:       event.NewFake(),
+				jp:             jmespath.New(configpkg.NewDefaultConfiguration(false)),
+			}
+			_, err := c.cleanup(context.Background(), logr.Discard(), newPolicy(tt.key, tt.context...))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.fetched, fetched)
+			assert.Equal(t, []string{"cm-a"}, deleted)
+		})
+	}
+}
+
+func Test_NeedsContent(t *testing.T) {
+	newSpec := func(key string, context ...kyvernov1.ContextEntry) *kyvernov2.CleanupPolicySpec {
+		return &kyvernov2.CleanupPolicySpec{
+			Context: context,
+			Conditions: &kyvernov2.AnyAllConditions{
+				AnyConditions: []kyvernov2.Condition{
+					{
+						RawKey:   kyverno.ToAny(key),
+						Operator: kyvernov2.ConditionOperators["Equals"],
+						RawValue: kyverno.ToAny("value"),
+					},
+				},
+			},
+		}
+	}
+	variable := func(jmesPath string) kyvernov1.ContextEntry {
+		return kyvernov1.ContextEntry{Name: "value", Variable: &kyvernov1.Variable{JMESPath: jmesPath}}
+	}
+	apiCall := kyvernov1.ContextEntry{Name: "value", APICall: &kyvernov1.ContextAPICall{
+		APICall: kyvernov1.APICall{URLPath: "/api/v1/namespaces/{{ target.spec.namespace }}"},
+	}}
+	assert.False(t, needsContent(&kyvernov2.CleanupPolicySpec{}))
+	assert.False(t, needsContent(&kyvernov2.CleanupPolicySpec{Context: []kyvernov1.ContextEntry{variable("target.spec")}}))
+	assert.False(t, needsContent(newSpec("{{ time_since('', '{{ target.metadata.creationTimestamp }}', '') }}")))
+	assert.False(t, needsContent(newSpec("{{ target.metadata.labels.app }}")))
+	assert.False(t, needsContent(newSpec("{{ request.namespace }}")))
+	assert.False(t, needsContent(newSpec("{{ value }}", variable("target.metadata.name"))))
+	assert.True(t, needsContent(newSpec("{{ target.spec.replicas }}")))
+	assert.True(t, needsContent(newSpec("{{ target }}")))
+	assert.True(t, needsContent(newSpec("{{ images.containers.*.tag }}")))
+	assert.True(t, needsContent(newSpec("{{ value }}", variable("target.spec.replicas"))))
+	assert.True(t, needsContent(newSpec("{{ value }}", apiCall)))
+}
+
 func Test_SkipResourceDueToFilter(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -4,11 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"regexp"
 	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/kyverno/kyverno/api/policies.kyverno.io/v1beta1"
 	"github.com/kyverno/kyverno/pkg/admissionpolicy"
+	"github.com/kyverno/kyverno/pkg/archive"
+	"github.com/kyverno/kyverno/pkg/budget"
 	"github.com/kyverno/kyverno/pkg/cel/policies/dpol/engine"
 	"github.com/kyverno/kyverno/pkg/client/clientset/versioned"
 	kyvernov1beta1informers "github.com/kyverno/kyverno/pkg/client/informers/externalversions/policies.kyverno.io/v1beta1"
@@ -22,23 +25,30 @@ import (
 	"github.com/kyverno/kyverno/pkg/toggle"
 	controllerutils "github.com/kyverno/kyverno/pkg/utils/controller"
 	datautils "github.com/kyverno/kyverno/pkg/utils/data"
+	kubeutils "github.com/kyverno/kyverno/pkg/utils/kube"
+	reportutils "github.com/kyverno/kyverno/pkg/utils/report"
 	"github.com/kyverno/kyverno/pkg/utils/restmapper"
 	"go.uber.org/multierr"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/dynamic"
 	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 )
 
 type controller struct {
 	// clients
-	client        dclient.Interface
-	kyvernoClient versioned.Interface
-	provider      engine.Provider
-	engine        *engine.Engine
+	client         dclient.Interface
+	kyvernoClient  versioned.Interface
+	metadataClient metadata.Interface
+	provider       engine.Provider
+	engine         *engine.Engine
 
 	// listers
 	nsLister corev1listers.NamespaceLister
@@ -52,6 +62,8 @@ type controller struct {
 	cmResolver    engineapi.ConfigmapResolver
 	eventGen      event.Interface
 	metrics       pkgmetrics.DeletingMetrics
+	budget        *budget.Budget
+	archive       archive.Sink
 }
 
 const (
@@ -64,6 +76,7 @@ const (
 func NewController(
 	client dclient.Interface,
 	kyvernoClient versioned.Interface,
+	metadataClient metadata.Interface,
 	polInformer kyvernov1beta1informers.DeletingPolicyInformer,
 	ndpolInformer kyvernov1beta1informers.NamespacedDeletingPolicyInformer,
 	provider engine.Provider,
@@ -72,6 +85,8 @@ func NewController(
 	configuration config.Configuration,
 	cmResolver engineapi.ConfigmapResolver,
 	eventGen event.Interface,
+	budget *budget.Budget,
+	archive archive.Sink,
 ) controllers.Controller {
 	queue := workqueue.NewTypedRateLimitingQueueWithConfig(
 		workqueue.DefaultTypedControllerRateLimiter[any](),
@@ -95,17 +110,20 @@ func NewController(
 		}
 	}
 	c := &controller{
-		client:        client,
-		kyvernoClient: kyvernoClient,
-		nsLister:      nsLister,
-		queue:         queue,
-		enqueue:       baseEnqueueFunc,
-		configuration: configuration,
-		cmResolver:    cmResolver,
-		eventGen:      eventGen,
-		metrics:       pkgmetrics.GetDeletingMetrics(),
-		provider:      provider,
-		engine:        engine,
+		client:         client,
+		kyvernoClient:  kyvernoClient,
+		metadataClient: metadataClient,
+		nsLister:       nsLister,
+		queue:          queue,
+		enqueue:        baseEnqueueFunc,
+		configuration:  configuration,
+		cmResolver:     cmResolver,
+		eventGen:       eventGen,
+		metrics:        pkgmetrics.GetDeletingMetrics(),
+		provider:       provider,
+		engine:         engine,
+		budget:         budget,
+		archive:        archive,
 	}
 	if _, err := controllerutils.AddEventHandlersT(
 		polInformer.Informer(),
@@ -140,9 +158,9 @@ func (c *controller) Run(ctx context.Context, workers int) {
 	controllerutils.Run(ctx, logger.V(3), ControllerName, time.Second, c.queue, workers, maxRetries, c.reconcile)
 }
 
-func (c *controller) deleting(ctx context.Context, logger logr.Logger, ePolicy engine.Policy) error {
+func (c *controller) deleting(ctx context.Context, logger logr.Logger, ePolicy engine.Policy) ([]unstructured.Unstructured, error) {
 	if c.client == nil {
-		return nil
+		return nil, nil
 	}
 
 	spec := ePolicy.Policy.GetDeletingPolicySpec()
@@ -151,30 +169,35 @@ func (c *controller) deleting(ctx context.Context, logger logr.Logger, ePolicy e
 
 	debug := logger.V(4)
 	var errs []error
+	var candidates []unstructured.Unstructured
 	deleteOptions := metav1.DeleteOptions{
 		PropagationPolicy: spec.DeletionPropagationPolicy,
 	}
+	policyKey, _ := cache.MetaNamespaceKeyFunc(policy)
+	run := c.budget.Run(policy.GetKind()+"/"+policyKey, budgetLimits(spec.DeletionBudget))
 
 	if spec.MatchConstraints == nil {
-		return errors.New("matchConstraints is required")
+		return nil, errors.New("matchConstraints is required")
 	}
 
 	selector, err := metav1.LabelSelectorAsSelector(spec.MatchConstraints.ObjectSelector)
 	if err != nil {
 		debug.Error(err, "failed to parse label selector")
-		return err
+		return nil, err
 	}
 
 	restMapper, err := restmapper.GetRESTMapper(c.client, false)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	gvrList := admissionpolicy.GetGVRs(spec.MatchConstraints, restMapper)
 
+	// resources are listed with their metadata only when possible, the full objects are fetched for the resources
+	// matching the policy when its expressions reference their content
+	metadataOnly := c.metadataClient != nil
+	fetchContent := metadataOnly && needsContent(ePolicy)
 	for _, gvr := range gvrList {
-		var client dynamic.ResourceInterface
-
 		debug := debug.WithValues("gvr", gvr)
 		debug.Info("processing...")
 		if policyNamespace != "" && !isNamespaced(gvr, restMapper) {
@@ -182,31 +205,10 @@ func (c *controller) deleting(ctx context.Context, logger logr.Logger, ePolicy e
 			continue
 		}
 
-		client = c.client.GetDynamicInterface().Resource(gvr)
-		if policyNamespace != "" {
-			client = client.(dynamic.NamespaceableResourceInterface).Namespace(policyNamespace)
-		}
-
-		list, err := client.List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
-		if err != nil {
-			debug.Error(err, "failed to list resources")
-			// record failure metric
-			if c.metrics != nil {
-				c.metrics.RecordDeletingFailure(ctx, gvr.Resource, "", policy, deleteOptions.PropagationPolicy)
-			}
-			// Check if this is a recoverable error (permission denied, resource not found, etc.)
-			if dclient.IsRecoverableError(err) {
-				logger.V(2).Info("skipping resource due to access restrictions", "resource", gvr.Resource, "error", err.Error())
-			} else {
-				// For non-recoverable errors (connectivity issues, etc.), add to errors slice
-				errs = append(errs, err)
-			}
-
-			continue
-		}
-
-		for i := range list.Items {
-			resource := list.Items[i]
+		var selected []unstructured.Unstructured
+		total := 0
+		err := c.list(ctx, gvr, policyNamespace, restMapper, metav1.ListOptions{LabelSelector: selector.String()}, func(resource unstructured.Unstructured) error {
+			total++
 
 			namespace := resource.GetNamespace()
 			name := resource.GetName()
@@ -215,28 +217,107 @@ func (c *controller) deleting(ctx context.Context, logger logr.Logger, ePolicy e
 			// Skip if resource matches resourceFilters from config
 			if c.configuration.ToFilter(gvk, resource.GetKind(), namespace, name) {
 				debug.Info("skipping resource due to resourceFilters in ConfigMap")
-				continue
+				return nil
 			}
 			// check if the resource is owned by Kyverno
 			if controllerutils.IsManagedByKyverno(&resource) && toggle.FromContext(ctx).ProtectManagedResources() {
-				continue
+				return nil
+			}
+
+			if fetchContent {
+				// match constraints only depend on the metadata, the full object is fetched for matching resources only
+				matches, err := c.engine.Match(ePolicy, resource)
+				if err != nil {
+					debug.Error(err, "failed to process resource")
+					errs = append(errs, err)
+					return nil
+				}
+				if !matches {
+					debug.Info("policy did not match")
+					return nil
+				}
+				object, err := c.client.GetDynamicInterface().Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+				if err != nil {
+					if apierrors.IsNotFound(err) {
+						debug.Info("resource not found")
+						return nil
+					}
+					debug.Error(err, "failed to get resource")
+					errs = append(errs, err)
+					return nil
+				}
+				resource = *object
 			}
 
 			engineResult, err := c.engine.Handle(ctx, ePolicy, resource)
 			if err != nil {
 				debug.Error(err, "failed to process resource")
 				errs = append(errs, err)
-				continue
+				return nil
 			}
 
 			if !engineResult.Match {
 				debug.Error(err, "policy did not match match")
 				errs = append(errs, err)
-				continue
+				return nil
 			}
 
+			selected = append(selected, resource)
+			return nil
+		})
+		if err != nil {
+			debug.Error(err, "failed to list resources")
+			// record failure metric
+			if c.metrics != nil {
+				c.metrics.RecordDeletingFailure(ctx, gvr.Resource, "", policy, deleteOptions.PropagationPolicy)
+			}
+			// Check if this is a recoverable error (permission denied, resource not found, etc.)
+			if dclient.IsRecoverableError(err) {
+				logger.V(2).Info("skipping resource due to access restrictions", "resource", gvr.Resource, "error", err.Error())
+			} else {
+				// For non-recoverable errors (connectivity issues, etc.), add to errors slice
+				errs = append(errs, err)
+			}
+
+			continue
+		}
+		if spec.DryRun {
+			for _, resource := range selected {
+				logger.WithValues("name", resource.GetName(), "namespace", resource.GetNamespace()).Info("resource matched, it would be deleted (dry run)")
+				candidates = append(candidates, resource)
+			}
+			continue
+		}
+		if err := run.CheckPercentage(gvr.Resource, len(selected), total); err != nil {
+			return nil, multierr.Combine(append(errs, err)...)
+		}
+		for _, resource := range selected {
+			namespace := resource.GetNamespace()
+			name := resource.GetName()
+			debug := debug.WithValues("name", name, "namespace", namespace)
+			if err := run.Reserve(); err != nil {
+				return nil, multierr.Combine(append(errs, err)...)
+			}
+			uid, err := c.archiveResource(ctx, policy, gvr, resource, metadataOnly && !fetchContent)
+			if err != nil {
+				run.Release()
+				if apierrors.IsNotFound(err) {
+					debug.Info("resource not found")
+					continue
+				}
+				debug.Error(err, "failed to archive resource, it will not be deleted")
+				errs = append(errs, err)
+				c.eventGen.Add(event.NewDeletingPolicyEvent(ePolicy.Policy, resource, err))
+				continue
+			}
 			logger.WithValues("name", name, "namespace", namespace).Info("resource matched, it will be deleted...")
-			if err := c.client.DeleteResource(ctx, resource.GetAPIVersion(), resource.GetKind(), namespace, name, false, deleteOptions); err != nil {
+			options := deleteOptions
+			if uid != "" {
+				// make sure the archived object is the one being deleted
+				options.Preconditions = metav1.NewUIDPreconditions(string(uid))
+			}
+			if err := c.client.DeleteResource(ctx, resource.GetAPIVersion(), resource.GetKind(), namespace, name, false, options); err != nil {
+				run.Release()
 				if apierrors.IsNotFound(err) {
 					debug.Info("resource not found")
 					continue
@@ -258,7 +339,84 @@ func (c *controller) deleting(ctx context.Context, logger logr.Logger, ePolicy e
 			}
 		}
 	}
-	return multierr.Combine(errs...)
+	return candidates, multierr.Combine(errs...)
+}
+
+// list calls fn for every resource of gvr, page by page. When the metadata client is available, only the metadata of the
+// resources is listed.
+func (c *controller) list(ctx context.Context, gvr schema.GroupVersionResource, namespace string, restMapper apimeta.RESTMapper, options metav1.ListOptions, fn func(unstructured.Unstructured) error) error {
+	if c.metadataClient == nil {
+		var client dynamic.ResourceInterface = c.client.GetDynamicInterface().Resource(gvr)
+		if namespace != "" {
+			client = c.client.GetDynamicInterface().Resource(gvr).Namespace(namespace)
+		}
+		return kubeutils.EachResource(ctx, client, options, fn)
+	}
+	gvk, err := restMapper.KindFor(gvr)
+	if err != nil {
+		return err
+	}
+	var client metadata.ResourceInterface = c.metadataClient.Resource(gvr)
+	if namespace != "" {
+		client = c.metadataClient.Resource(gvr).Namespace(namespace)
+	}
+	return kubeutils.EachMetadata(ctx, client, gvk, options, fn)
+}
+
+// archiveResource archives a resource before it is deleted, its full object is fetched first when only its metadata was listed.
+// It returns the uid of the archived object, or an empty uid when archiving is disabled.
+func (c *controller) archiveResource(ctx context.Context, policy v1beta1.DeletingPolicyLike, gvr schema.GroupVersionResource, resource unstructured.Unstructured, partial bool) (types.UID, error) {
+	if c.archive == nil {
+		return "", nil
+	}
+	object := &resource
+	if partial {
+		var err error
+		object, err = c.client.GetDynamicInterface().Resource(gvr).Namespace(resource.GetNamespace()).Get(ctx, resource.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+	}
+	policyKey, _ := cache.MetaNamespaceKeyFunc(policy)
+	if err := archive.Archive(ctx, c.archive, object, policy.GetKind(), policyKey, archive.ReasonPolicyMatched); err != nil {
+		return "", err
+	}
+	return object.GetUID(), nil
+}
+
+// objectReference matches the references to the object in CEL expressions, the second group is set when the reference
+// is limited to the metadata of the object.
+var objectReference = regexp.MustCompile(`\bobject\b(\s*\.\s*metadata\b)?`)
+
+// needsContent returns true if the conditions, variables or exceptions of the policy reference more than the metadata of the object.
+func needsContent(policy engine.Policy) bool {
+	var expressions []string
+	if spec := policy.Policy.GetDeletingPolicySpec(); spec != nil {
+		for _, condition := range spec.Conditions {
+			expressions = append(expressions, condition.Expression)
+		}
+		for _, variable := range spec.Variables {
+			expressions = append(expressions, variable.Expression)
+		}
+	}
+	if policy.CompiledPolicy != nil {
+		for _, exception := range policy.CompiledPolicy.Exceptions() {
+			if exception.Exception == nil {
+				continue
+			}
+			for _, condition := range exception.Exception.Spec.MatchConditions {
+				expressions = append(expressions, condition.Expression)
+			}
+		}
+	}
+	for _, expression := range expressions {
+		for _, match := range objectReference.FindAllStringSubmatch(expression, -1) {
+			if match[1] == "" {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 func (c *controller) reconcile(ctx context.Context, logger logr.Logger, key, namespace, name string) error {
@@ -280,11 +438,21 @@ func (c *controller) reconcile(ctx context.Context, logger logr.Logger, key, nam
 
 	// In case it is the time to do the cleanup process
 	if time.Now().After(*executionTime) {
-		err := c.deleting(ctx, logger, policy)
+		candidates, err := c.deleting(ctx, logger, policy)
+		var exceeded *budget.ExceededError
+		if errors.As(err, &exceeded) {
+			// the run is aborted, the next run happens on schedule
+			logger.Error(err, "deleting run aborted")
+			c.eventGen.Add(event.NewDeletingPolicyBudgetExceededEvent(policy.Policy, exceeded))
+		} else if err != nil {
+			return err
+		}
+		dryRun, err := c.recordDryRun(ctx, policy.Policy, candidates)
 		if err != nil {
+			logger.Error(err, "failed to record the dry run report")
 			return err
 		}
-		if err := c.updateDeletingPolicyStatus(ctx, policy.Policy, time.Now()); err != nil {
+		if err := c.updateDeletingPolicyStatus(ctx, policy.Policy, time.Now(), dryRun, exceeded); err != nil {
 			logger.Error(err, "failed to update the deleting policy status")
 			return err
 		}
@@ -324,13 +492,69 @@ func isNamespaced(gvr schema.GroupVersionResource, mapper apimeta.RESTMapper) bo
 	return mapping.Scope.Name() == apimeta.RESTScopeNameNamespace
 }
 
-func (c *controller) updateDeletingPolicyStatus(ctx context.Context, policy v1beta1.DeletingPolicyLike, time time.Time) error {
+// recordDryRun writes the resources selected by a dry run execution in the dry run report of the policy and returns
+// the dry run status of the policy. A single event summarizes the execution. The report of a previous dry run is
+// deleted when the policy is not in dry run mode.
+func (c *controller) recordDryRun(ctx context.Context, policy v1beta1.DeletingPolicyLike, candidates []unstructured.Unstructured) (*v1beta1.DryRunStatus, error) {
+	reportutils.SortDryRunResources(candidates)
+	report := reportutils.BuildDryRunReport(policy, v1beta1.SchemeGroupVersion.String(), policy.GetKind(), candidates)
+	if !policy.GetDeletingPolicySpec().DryRun {
+		if policy.GetDeletingPolicyStatus().DryRun != nil {
+			if err := reportutils.DeleteReport(ctx, report, c.kyvernoClient, nil); err != nil && !apierrors.IsNotFound(err) {
+				return nil, err
+			}
+		}
+		return nil, nil
+	}
+	if _, err := reportutils.ApplyDryRunReport(ctx, report, c.kyvernoClient); err != nil {
+		return nil, err
+	}
+	c.eventGen.Add(event.NewDeletingPolicyDryRunEvent(policy, len(candidates)))
+	status := &v1beta1.DryRunStatus{
+		Count: len(candidates),
+	}
+	for _, candidate := range candidates {
+		if len(status.Resources) == reportutils.MaxDryRunResources {
+			break
+		}
+		status.Resources = append(status.Resources, v1beta1.DryRunResource{
+			APIVersion: candidate.GetAPIVersion(),
+			Kind:       candidate.GetKind(),
+			Namespace:  candidate.GetNamespace(),
+			Name:       candidate.GetName(),
+		})
+	}
+	return status, nil
+}
+
+func budgetLimits(deletionBudget *v1beta1.DeletionBudget) budget.Limits {
+	if deletionBudget == nil {
+		return budget.Limits{}
+	}
+	return budget.Limits{
+		MaxPerRun:     int(deletionBudget.MaxDeletionsPerRun),
+		MaxPerMinute:  int(deletionBudget.MaxDeletionsPerMinute),
+		MaxPercentage: int(deletionBudget.MaxPercentage),
+	}
+}
+
+// setBudgetExceeded reports in the status whether the run exceeded the deletion budget,
+// the condition is only added to the status once a run exceeded the budget.
+func setBudgetExceeded(status *v1beta1.DeletingPolicyStatus, exceeded *budget.ExceededError) {
+	if exceeded != nil {
+		status.SetBudgetExceeded(true, exceeded.Error())
+	} else if apimeta.FindStatusCondition(status.ConditionStatus.Conditions, string(v1beta1.PolicyConditionTypeBudgetExceeded)) != nil {
+		status.SetBudgetExceeded(false, "the last run completed within the deletion budget")
+	}
+}
+
+func (c *controller) updateDeletingPolicyStatus(ctx context.Context, policy v1beta1.DeletingPolicyLike, time time.Time, dryRun *v1beta1.DryRunStatus, exceeded *budget.ExceededError) error {
 	switch p := policy.(type) {
 	case *v1beta1.DeletingPolicy:
 		err := controllerutils.UpdateStatus(ctx, p, c.kyvernoClient.PoliciesV1beta1().DeletingPolicies(), func(p *v1beta1.DeletingPolicy) error {
-			p.Status = v1beta1.DeletingPolicyStatus{
-				LastExecutionTime: metav1.NewTime(time),
-			}
+			p.Status.LastExecutionTime = metav1.NewTime(time)
+			p.Status.DryRun = dryRun
+			setBudgetExceeded(&p.Status, exceeded)
 			return nil
 		}, func(current, expect *v1beta1.DeletingPolicy) bool {
 			return datautils.DeepEqual(current.Status, expect.Status)
@@ -341,9 +565,9 @@ func (c *controller) updateDeletingPolicyStatus(ctx context.Context, policy v1be
 		logging.Info("updated deleting policy status", "name", p.GetName(), "namespace", p.GetNamespace(), "status", p.Status)
 	case *v1beta1.NamespacedDeletingPolicy:
 		err := controllerutils.UpdateStatus(ctx, p, c.kyvernoClient.PoliciesV1beta1().NamespacedDeletingPolicies(p.GetNamespace()), func(p *v1beta1.NamespacedDeletingPolicy) error {
-			p.Status = v1beta1.DeletingPolicyStatus{
-				LastExecutionTime: metav1.NewTime(time),
-			}
+			p.Status.LastExecutionTime = metav1.NewTime(time)
+			p.Status.DryRun = dryRun
+			setBudgetExceeded(&p.Status, exceeded)
 			return nil
 		}, func(current, expect *v1beta1.NamespacedDeletingPolicy) bool {
 			return datautils.DeepEqual(current.Status, expect.Status)
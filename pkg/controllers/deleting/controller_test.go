@@ -9,12 +9,15 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/golang/mock/gomock"
 	policiesv1beta1 "github.com/kyverno/kyverno/api/policies.kyverno.io/v1beta1"
+	"github.com/kyverno/kyverno/pkg/budget"
 	enginecompiler "github.com/kyverno/kyverno/pkg/cel/policies/dpol/compiler"
 	dpolengine "github.com/kyverno/kyverno/pkg/cel/policies/dpol/engine"
 	versionedfake "github.com/kyverno/kyverno/pkg/client/clientset/versioned/fake"
 	"github.com/kyverno/kyverno/pkg/config/mocks"
+	"github.com/kyverno/kyverno/pkg/event"
 	"github.com/stretchr/testify/assert"
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -129,6 +132,110 @@ func TestReconcile_ClampPastNextExecution(t *testing.T) {
 	}
 }
 
+type recordingEventGenerator struct {
+	infos []event.Info
+}
+
+func (r *recordingEventGenerator) Add(infos ...event.Info) {
+	r.infos = append(r.infos, infos...)
+}
+
+func TestRecordDryRun(t *testing.T) {
+	pol := &policiesv1beta1.NamespacedDeletingPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns1",
+			Name:      "ndpol",
+		},
+		Spec: policiesv1beta1.DeletingPolicySpec{
+			Schedule: "* * * * *",
+			DryRun:   true,
+		},
+	}
+	fakeClient := versionedfake.NewSimpleClientset(pol.DeepCopy())
+	eventGen := &recordingEventGenerator{}
+	ctrl := &controller{
+		kyvernoClient: fakeClient,
+		eventGen:      eventGen,
+	}
+
+	var candidates []unstructured.Unstructured
+	for i := 0; i < 120; i++ {
+		resource := unstructured.Unstructured{}
+		resource.SetAPIVersion("v1")
+		resource.SetKind("Pod")
+		resource.SetNamespace("ns1")
+		resource.SetName(fmt.Sprintf("pod-%03d", 119-i))
+		candidates = append(candidates, resource)
+	}
+
+	ctx := context.Background()
+	dryRun, err := ctrl.recordDryRun(ctx, pol, candidates)
+	assert.NoError(t, err)
+	assert.Equal(t, 120, dryRun.Count)
+	assert.Len(t, dryRun.Resources, 100)
+	assert.Equal(t, policiesv1beta1.DryRunResource{APIVersion: "v1", Kind: "Pod", Namespace: "ns1", Name: "pod-000"}, dryRun.Resources[0])
+
+	report, err := fakeClient.Wgpolicyk8sV1alpha2().PolicyReports("ns1").Get(ctx, "dryrun-namespaceddeletingpolicy-ndpol", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, report.Results, 100)
+	assert.Equal(t, 100, report.Summary.Warn)
+	assert.Len(t, eventGen.infos, 1)
+	assert.Equal(t, "dry run: 120 target resource(s) would have been deleted", eventGen.infos[0].Message)
+
+	// a second execution replaces the results of the report
+	_, err = ctrl.recordDryRun(ctx, pol, candidates[:1])
+	assert.NoError(t, err)
+	report, err = fakeClient.Wgpolicyk8sV1alpha2().PolicyReports("ns1").Get(ctx, "dryrun-namespaceddeletingpolicy-ndpol", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, report.Results, 1)
+
+	assert.NoError(t, ctrl.updateDeletingPolicyStatus(ctx, pol, time.Now(), dryRun, nil))
+	updated, err := fakeClient.PoliciesV1beta1().NamespacedDeletingPolicies("ns1").Get(ctx, "ndpol", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, dryRun, updated.Status.DryRun)
+}
+
+func TestUpdateDeletingPolicyStatus_BudgetExceeded(t *testing.T) {
+	pol := &policiesv1beta1.DeletingPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "dpol",
+		},
+		Spec: policiesv1beta1.DeletingPolicySpec{
+			Schedule:       "* * * * *",
+			DeletionBudget: &policiesv1beta1.DeletionBudget{MaxDeletionsPerRun: 1},
+		},
+	}
+	fakeClient := versionedfake.NewSimpleClientset(pol.DeepCopy())
+	ctrl := &controller{
+		kyvernoClient: fakeClient,
+	}
+	ctx := context.Background()
+
+	// a policy that never exceeded its budget has no condition
+	assert.NoError(t, ctrl.updateDeletingPolicyStatus(ctx, pol, time.Now(), nil, nil))
+	updated, err := fakeClient.PoliciesV1beta1().DeletingPolicies().Get(ctx, "dpol", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Nil(t, apimeta.FindStatusCondition(updated.Status.ConditionStatus.Conditions, string(policiesv1beta1.PolicyConditionTypeBudgetExceeded)))
+
+	run := budget.New(budget.Limits{}).Run("dpol", budgetLimits(pol.Spec.DeletionBudget))
+	assert.NoError(t, run.Reserve())
+	var exceeded *budget.ExceededError
+	assert.ErrorAs(t, run.Reserve(), &exceeded)
+	assert.NoError(t, ctrl.updateDeletingPolicyStatus(ctx, updated, time.Now(), nil, exceeded))
+	updated, err = fakeClient.PoliciesV1beta1().DeletingPolicies().Get(ctx, "dpol", metav1.GetOptions{})
+	assert.NoError(t, err)
+	condition := apimeta.FindStatusCondition(updated.Status.ConditionStatus.Conditions, string(policiesv1beta1.PolicyConditionTypeBudgetExceeded))
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, "deletion budget exceeded: the run reached the maximum of 1 deletions", condition.Message)
+
+	// the next run completing within the budget resets the condition
+	assert.NoError(t, ctrl.updateDeletingPolicyStatus(ctx, updated, time.Now(), nil, nil))
+	updated, err = fakeClient.PoliciesV1beta1().DeletingPolicies().Get(ctx, "dpol", metav1.GetOptions{})
+	assert.NoError(t, err)
+	condition = apimeta.FindStatusCondition(updated.Status.ConditionStatus.Conditions, string(policiesv1beta1.PolicyConditionTypeBudgetExceeded))
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+}
+
 type providerAdapter struct {
 	fetch dpolengine.ProviderFunc
 	name  string
@@ -146,3 +253,20 @@ func (p providerAdapter) Get(ctx context.Context, namespace, name string) (dpole
 	}
 	return dpolengine.Policy{}, fmt.Errorf("not found")
 }
+
+func TestNeedsContent(t *testing.T) {
+	newPolicy := func(conditions []string, variables []string) dpolengine.Policy {
+		policy := &policiesv1beta1.DeletingPolicy{}
+		for i, expression := range conditions {
+			policy.Spec.Conditions = append(policy.Spec.Conditions, admissionregistrationv1.MatchCondition{Name: fmt.Sprintf("c%d", i), Expression: expression})
+		}
+		for i, expression := range variables {
+			policy.Spec.Variables = append(policy.Spec.Variables, admissionregistrationv1.Variable{Name: fmt.Sprintf("v%d", i), Expression: expression})
+		}
+		return dpolengine.Policy{Policy: policy}
+	}
+	assert.False(t, needsContent(newPolicy(nil, nil)))
+	assert.False(t, needsContent(newPolicy([]string{"object.metadata.name == 'x'", "has(object.metadata.labels)"}, nil)))
+	assert.True(t, needsContent(newPolicy([]string{"object.spec.replicas > 1"}, nil)))
+	assert.True(t, needsContent(newPolicy(nil, []string{"object"})))
+}
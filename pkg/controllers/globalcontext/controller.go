@@ -12,6 +12,7 @@ import (
 	"github.com/kyverno/kyverno/pkg/clients/dclient"
 	"github.com/kyverno/kyverno/pkg/controllers"
 	"github.com/kyverno/kyverno/pkg/engine/adapters"
+	"github.com/kyverno/kyverno/pkg/engine/apicall"
 	"github.com/kyverno/kyverno/pkg/engine/jmespath"
 	"github.com/kyverno/kyverno/pkg/event"
 	"github.com/kyverno/kyverno/pkg/globalcontext/externalapi"
@@ -20,7 +21,9 @@ import (
 	controllerutils "github.com/kyverno/kyverno/pkg/utils/controller"
 	datautils "github.com/kyverno/kyverno/pkg/utils/data"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
@@ -46,7 +49,7 @@ type controller struct {
 	kyvernoClient      versioned.Interface
 	store              store.Store
 	eventGen           event.Interface
-	maxResponseLength  int64
+	apiCallConfig      apicall.APICallConfiguration
 	shouldUpdateStatus bool
 	jp                 jmespath.Interface
 }
@@ -58,7 +61,7 @@ func NewController(
 	kyvernoClient versioned.Interface,
 	storage store.Store,
 	eventGen event.Interface,
-	maxResponseLength int64,
+	apiCallConfig apicall.APICallConfiguration,
 	shouldUpdateStatus bool,
 	jp jmespath.Interface,
 ) controllers.Controller {
@@ -74,7 +77,7 @@ func NewController(
 		kyvernoClient:      kyvernoClient,
 		store:              storage,
 		eventGen:           eventGen,
-		maxResponseLength:  maxResponseLength,
+		apiCallConfig:      apiCallConfig,
 		shouldUpdateStatus: shouldUpdateStatus,
 		jp:                 jp,
 	}
@@ -114,9 +117,31 @@ func (c *controller) enqueueGCTXEntry(gctxentry *kyvernov2alpha1.GlobalContextEn
 }
 
 func (c *controller) Run(ctx context.Context, workers int) {
+	c.pruneSnapshots()
 	controllerutils.Run(ctx, logger, ControllerName, time.Second, c.queue, workers, maxRetries, c.reconcile)
 }
 
+// pruneSnapshots removes the persisted data of entries deleted while the controller was not running,
+// entries deleted afterwards are removed when reconciled.
+func (c *controller) pruneSnapshots() {
+	pruner, ok := c.store.(store.Pruner)
+	if !ok {
+		return
+	}
+	gces, err := c.gceLister.List(labels.Everything())
+	if err != nil {
+		logger.Error(err, "failed to list global context entries")
+		return
+	}
+	names := sets.New[string]()
+	for _, gce := range gces {
+		names.Insert(gce.Name)
+	}
+	if err := pruner.Prune(names.Has); err != nil {
+		logger.Error(err, "failed to prune global context snapshots")
+	}
+}
+
 func (c *controller) reconcile(ctx context.Context, logger logr.Logger, key, _, name string) error {
 	gce, err := c.getEntry(name)
 	if err != nil {
@@ -169,7 +194,7 @@ func (c *controller) makeStoreEntry(ctx context.Context, gce *kyvernov2alpha1.Gl
 		adapters.Client(c.dclient),
 		gce.Spec.APICall.APICall,
 		gce.Spec.APICall.RefreshInterval.Duration,
-		c.maxResponseLength,
+		c.apiCallConfig,
 		c.shouldUpdateStatus,
 		c.jp,
 	)
@@ -0,0 +1,133 @@
+package policysource
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	kyvernov2alpha1 "github.com/kyverno/kyverno/api/kyverno/v2alpha1"
+	"github.com/kyverno/kyverno/pkg/client/clientset/versioned"
+	kyvernov2alpha1informers "github.com/kyverno/kyverno/pkg/client/informers/externalversions/kyverno/v2alpha1"
+	kyvernov2alpha1listers "github.com/kyverno/kyverno/pkg/client/listers/kyverno/v2alpha1"
+	"github.com/kyverno/kyverno/pkg/clients/dclient"
+	"github.com/kyverno/kyverno/pkg/controllers"
+	"github.com/kyverno/kyverno/pkg/registryclient"
+	controllerutils "github.com/kyverno/kyverno/pkg/utils/controller"
+	datautils "github.com/kyverno/kyverno/pkg/utils/data"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+)
+
+const (
+	// Workers is the number of workers for this controller
+	Workers        = 1
+	ControllerName = "policy-source-controller"
+	maxRetries     = 10
+)
+
+type controller struct {
+	// clients
+	client        dclient.Interface
+	kyvernoClient versioned.Interface
+	rclient       registryclient.Client
+
+	// listers
+	sourceLister kyvernov2alpha1listers.PolicySourceLister
+	secretLister corev1listers.SecretNamespaceLister
+
+	// queue
+	queue workqueue.TypedRateLimitingInterface[any]
+}
+
+func NewController(
+	client dclient.Interface,
+	kyvernoClient versioned.Interface,
+	sourceInformer kyvernov2alpha1informers.PolicySourceInformer,
+	rclient registryclient.Client,
+	secretLister corev1listers.SecretNamespaceLister,
+) controllers.Controller {
+	queue := workqueue.NewTypedRateLimitingQueueWithConfig(
+		workqueue.DefaultTypedControllerRateLimiter[any](),
+		workqueue.TypedRateLimitingQueueConfig[any]{Name: ControllerName},
+	)
+	c := &controller{
+		client:        client,
+		kyvernoClient: kyvernoClient,
+		rclient:       rclient,
+		sourceLister:  sourceInformer.Lister(),
+		secretLister:  secretLister,
+		queue:         queue,
+	}
+	if _, err := controllerutils.AddEventHandlersT(sourceInformer.Informer(), c.addSource, c.updateSource, c.deleteSource); err != nil {
+		logger.Error(err, "failed to register event handlers")
+	}
+	return c
+}
+
+func (c *controller) addSource(obj *kyvernov2alpha1.PolicySource) {
+	logger.V(4).Info("policysource created", "uid", obj.GetUID(), "name", obj.GetName())
+	c.enqueueSource(obj)
+}
+
+func (c *controller) updateSource(old, obj *kyvernov2alpha1.PolicySource) {
+	// status updates don't change the generation, skip them
+	if old.GetGeneration() == obj.GetGeneration() {
+		return
+	}
+	logger.V(4).Info("policysource updated", "uid", obj.GetUID(), "name", obj.GetName())
+	c.enqueueSource(obj)
+}
+
+func (c *controller) deleteSource(obj *kyvernov2alpha1.PolicySource) {
+	// synced policies are left in the cluster, they can be deleted with the label selector
+	logger.V(4).Info("policysource deleted", "uid", obj.GetUID(), "name", obj.GetName())
+}
+
+func (c *controller) enqueueSource(source *kyvernov2alpha1.PolicySource) {
+	key, err := cache.MetaNamespaceKeyFunc(source)
+	if err != nil {
+		logger.Error(err, "failed to enqueue policy source")
+		return
+	}
+	c.queue.Add(key)
+}
+
+func (c *controller) Run(ctx context.Context, workers int) {
+	controllerutils.Run(ctx, logger, ControllerName, time.Second, c.queue, workers, maxRetries, c.reconcile)
+}
+
+func (c *controller) reconcile(ctx context.Context, logger logr.Logger, key, _, name string) error {
+	source, err := c.sourceLister.Get(name)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	status := source.Status.DeepCopy()
+	if source.Spec.Suspend {
+		// the source is enqueued again when its spec changes
+		status.SetReady(false, kyvernov2alpha1.PolicySourceReasonSuspended, "policy source is suspended")
+		return c.updateStatus(ctx, source, status)
+	}
+	if err := c.sync(ctx, logger, source, status); err != nil {
+		logger.Error(err, "failed to sync policy source")
+		status.SetReady(false, kyvernov2alpha1.PolicySourceReasonFailed, err.Error())
+	}
+	if err := c.updateStatus(ctx, source, status); err != nil {
+		return err
+	}
+	c.queue.AddAfter(key, source.Spec.GetInterval())
+	return nil
+}
+
+func (c *controller) updateStatus(ctx context.Context, source *kyvernov2alpha1.PolicySource, status *kyvernov2alpha1.PolicySourceStatus) error {
+	return controllerutils.UpdateStatus(ctx, source, c.kyvernoClient.KyvernoV2alpha1().PolicySources(), func(source *kyvernov2alpha1.PolicySource) error {
+		source.Status = *status
+		return nil
+	}, func(current, expect *kyvernov2alpha1.PolicySource) bool {
+		return datautils.DeepEqual(current.Status, expect.Status)
+	})
+}
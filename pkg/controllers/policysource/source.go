@@ -0,0 +1,176 @@
+package policysource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"time"
+
+	"github.com/go-git/go-billy/v5"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	kyvernov2alpha1 "github.com/kyverno/kyverno/api/kyverno/v2alpha1"
+	extyaml "github.com/kyverno/kyverno/ext/yaml"
+	"github.com/kyverno/kyverno/pkg/cosign"
+	"github.com/kyverno/kyverno/pkg/images"
+	"github.com/kyverno/kyverno/pkg/registryclient"
+	gitutils "github.com/kyverno/kyverno/pkg/utils/git"
+	ociutils "github.com/kyverno/kyverno/pkg/utils/oci"
+)
+
+// fetchTimeout bounds the time spent pulling an artifact or cloning a repository, so that an unresponsive
+// registry or Git server doesn't block the controller worker.
+const fetchTimeout = 5 * time.Minute
+
+// fetch returns the revision of a source and the documents it contains.
+func (c *controller) fetch(ctx context.Context, source *kyvernov2alpha1.PolicySource) (string, [][]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, fetchTimeout)
+	defer cancel()
+	if source.Spec.IsOCI() {
+		return c.fetchOCI(ctx, source.Spec.OCI)
+	}
+	if source.Spec.IsGit() {
+		return c.fetchGit(ctx, source.Spec.Git)
+	}
+	return "", nil, errors.New("policy source has neither OCI nor Git")
+}
+
+// fetchOCI pulls the policy layers of an artifact pushed with `kyverno oci push`, the revision is the artifact digest.
+func (c *controller) fetchOCI(ctx context.Context, source *kyvernov2alpha1.OCIPolicySource) (string, [][]byte, error) {
+	rclient := c.rclient
+	if len(source.ImagePullSecrets) > 0 {
+		client, err := registryclient.New(registryclient.WithKeychainPullSecrets(c.secretLister, source.ImagePullSecrets...))
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to create registry client: %w", err)
+		}
+		rclient = client
+	}
+	imageRef := source.Image
+	if source.PublicKey != "" {
+		response, err := cosign.NewVerifier().VerifySignature(ctx, images.Options{
+			ImageRef:   imageRef,
+			Client:     rclient,
+			Key:        source.PublicKey,
+			IgnoreTlog: true,
+			IgnoreSCT:  true,
+		})
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to verify image signature: %w", err)
+		}
+		ref, err := name.ParseReference(imageRef, rclient.NameOptions()...)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to parse image reference: %w", err)
+		}
+		// pull the digest that was verified, the tag could have moved since
+		imageRef = ref.Context().Digest(response.Digest).String()
+	}
+	desc, err := rclient.FetchImageDescriptor(ctx, imageRef)
+	if err != nil {
+		return "", nil, err
+	}
+	img, err := desc.Image()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read image: %w", err)
+	}
+	manifest, err := img.Manifest()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read image manifest: %w", err)
+	}
+	if manifest.Config.MediaType != ociutils.PolicyConfigMediaType {
+		return "", nil, fmt.Errorf("image %s is not a policy artifact, config media type is %s", source.Image, manifest.Config.MediaType)
+	}
+	layers, err := img.Layers()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read image layers: %w", err)
+	}
+	var documents [][]byte
+	for i, layer := range layers {
+		if manifest.Layers[i].MediaType != ociutils.PolicyLayerMediaType {
+			continue
+		}
+		content, err := readLayer(layer)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to read image layer: %w", err)
+		}
+		documents = append(documents, content)
+	}
+	return desc.Digest.String(), documents, nil
+}
+
+func readLayer(layer v1.Layer) ([]byte, error) {
+	reader, err := layer.Uncompressed()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
+
+// fetchGit clones a repository and reads the YAML files under the source path, the revision is the commit hash.
+func (c *controller) fetchGit(ctx context.Context, source *kyvernov2alpha1.GitPolicySource) (string, [][]byte, error) {
+	var auth http.BasicAuth
+	if source.SecretRef != "" {
+		secret, err := c.secretLister.Get(source.SecretRef)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to get secret %s: %w", source.SecretRef, err)
+		}
+		auth.Username = string(secret.Data["username"])
+		auth.Password = string(secret.Data["password"])
+	}
+	branch := source.Branch
+	if branch == "" {
+		branch = "main"
+	}
+	fs := memfs.New()
+	repo, err := gitutils.CloneContext(ctx, source.URL, fs, branch, auth)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to clone repository %s: %w", source.URL, err)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get repository head: %w", err)
+	}
+	if source.PublicKeys != "" {
+		commit, err := repo.CommitObject(head.Hash())
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to get commit %s: %w", head.Hash(), err)
+		}
+		if _, err := commit.Verify(source.PublicKeys); err != nil {
+			return "", nil, fmt.Errorf("failed to verify commit %s signature: %w", head.Hash(), err)
+		}
+	}
+	path := source.Path
+	if path == "" {
+		path = "/"
+	}
+	files, err := gitutils.ListYamls(fs, path)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to list files in %s: %w", path, err)
+	}
+	var documents [][]byte
+	for _, file := range files {
+		content, err := readFile(fs, file)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to read file %s: %w", file, err)
+		}
+		contents, err := extyaml.SplitDocuments(content)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to split file %s: %w", file, err)
+		}
+		documents = append(documents, contents...)
+	}
+	return head.Hash().String(), documents, nil
+}
+
+func readFile(fs billy.Filesystem, path string) ([]byte, error) {
+	file, err := fs.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return io.ReadAll(file)
+}
@@ -0,0 +1,218 @@
+package policysource
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/kyverno/kyverno/api/kyverno"
+	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	kyvernov2alpha1 "github.com/kyverno/kyverno/api/kyverno/v2alpha1"
+	policiesv1beta1 "github.com/kyverno/kyverno/api/policies.kyverno.io/v1beta1"
+	"go.uber.org/multierr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+// fieldManager is the field manager policies are applied with, prefixed with `kyverno-` by the client
+const fieldManager = "policy-source"
+
+// supportedKinds are the kinds of policies and exceptions a source can contain, with their scope
+var supportedKinds = map[schema.GroupKind]bool{
+	{Group: kyvernov1.GroupName, Kind: "ClusterPolicy"}:                         false,
+	{Group: kyvernov1.GroupName, Kind: "Policy"}:                                true,
+	{Group: kyvernov1.GroupName, Kind: "PolicyException"}:                       true,
+	{Group: policiesv1beta1.GroupName, Kind: "ValidatingPolicy"}:                false,
+	{Group: policiesv1beta1.GroupName, Kind: "NamespacedValidatingPolicy"}:      true,
+	{Group: policiesv1beta1.GroupName, Kind: "ImageValidatingPolicy"}:           false,
+	{Group: policiesv1beta1.GroupName, Kind: "NamespacedImageValidatingPolicy"}: true,
+	{Group: policiesv1beta1.GroupName, Kind: "MutatingPolicy"}:                  false,
+	{Group: policiesv1beta1.GroupName, Kind: "NamespacedMutatingPolicy"}:        true,
+	{Group: policiesv1beta1.GroupName, Kind: "GeneratingPolicy"}:                false,
+	{Group: policiesv1beta1.GroupName, Kind: "NamespacedGeneratingPolicy"}:      true,
+	{Group: policiesv1beta1.GroupName, Kind: "DeletingPolicy"}:                  false,
+	{Group: policiesv1beta1.GroupName, Kind: "NamespacedDeletingPolicy"}:        true,
+	{Group: policiesv1beta1.GroupName, Kind: "PolicyException"}:                 true,
+}
+
+// parse returns the policies and exceptions of a source, other documents like tests and resources are skipped.
+func parse(documents [][]byte) ([]unstructured.Unstructured, error) {
+	var objects []unstructured.Unstructured
+	seen := map[string]bool{}
+	for _, document := range documents {
+		var object unstructured.Unstructured
+		if err := yaml.Unmarshal(document, &object.Object); err != nil {
+			return nil, fmt.Errorf("failed to parse document: %w", err)
+		}
+		if object.Object == nil {
+			continue
+		}
+		gk := object.GroupVersionKind().GroupKind()
+		namespaced, ok := supportedKinds[gk]
+		if !ok {
+			continue
+		}
+		if object.GetName() == "" {
+			return nil, fmt.Errorf("%s has no name", gk)
+		}
+		// sources are cluster wide, namespaced policies must say which namespace they belong to
+		if !namespaced {
+			object.SetNamespace("")
+		} else if object.GetNamespace() == "" {
+			return nil, fmt.Errorf("%s %s has no namespace", gk, object.GetName())
+		}
+		key := policyKey(gk, object.GetNamespace(), object.GetName())
+		if seen[key] {
+			return nil, fmt.Errorf("%s is declared more than once", key)
+		}
+		seen[key] = true
+		unstructured.RemoveNestedField(object.Object, "status")
+		objects = append(objects, object)
+	}
+	return objects, nil
+}
+
+func policyKey(gk schema.GroupKind, namespace, name string) string {
+	if namespace == "" {
+		return fmt.Sprintf("%s/%s", gk, name)
+	}
+	return fmt.Sprintf("%s/%s/%s", gk, namespace, name)
+}
+
+func syncedPolicyKey(policy kyvernov2alpha1.SyncedPolicy) string {
+	gv, _ := schema.ParseGroupVersion(policy.APIVersion)
+	return policyKey(gv.WithKind(policy.Kind).GroupKind(), policy.Namespace, policy.Name)
+}
+
+// drifted returns true if a policy synced previously was changed or deleted in the cluster.
+func drifted(previous *kyvernov2alpha1.SyncedPolicy, live *unstructured.Unstructured) bool {
+	if previous == nil {
+		return false
+	}
+	return live == nil || live.GetGeneration() != previous.Generation
+}
+
+// sync applies the policies of a source, reverting the changes made in the cluster, and prunes the ones removed from it.
+func (c *controller) sync(ctx context.Context, logger logr.Logger, source *kyvernov2alpha1.PolicySource, status *kyvernov2alpha1.PolicySourceStatus) error {
+	revision, documents, err := c.fetch(ctx, source)
+	if err != nil {
+		return err
+	}
+	objects, err := parse(documents)
+	if err != nil {
+		return err
+	}
+	previous := map[string]*kyvernov2alpha1.SyncedPolicy{}
+	for i := range status.Policies {
+		previous[syncedPolicyKey(status.Policies[i])] = &status.Policies[i]
+	}
+	var errs []error
+	var policies []kyvernov2alpha1.SyncedPolicy
+	var driftedPolicies []string
+	desired := map[string]bool{}
+	for i := range objects {
+		object := &objects[i]
+		key := policyKey(object.GroupVersionKind().GroupKind(), object.GetNamespace(), object.GetName())
+		desired[key] = true
+		policy, err := c.apply(ctx, source.GetName(), object, previous[key])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to apply %s: %w", key, err))
+			// keep track of the policy so that a failure doesn't prevent pruning it later
+			if prev := previous[key]; prev != nil {
+				policies = append(policies, *prev)
+			}
+			continue
+		}
+		if policy.Drifted {
+			logger.V(2).Info("policy was changed in the cluster, reverted it", "policy", key)
+			driftedPolicies = append(driftedPolicies, key)
+		}
+		policies = append(policies, *policy)
+	}
+	for key, policy := range previous {
+		if desired[key] {
+			continue
+		}
+		if !source.Spec.PruneEnabled() {
+			continue
+		}
+		if err := c.prune(ctx, source.GetName(), policy); err != nil {
+			errs = append(errs, fmt.Errorf("failed to prune %s: %w", key, err))
+			policies = append(policies, *policy)
+			continue
+		}
+		logger.V(2).Info("policy was removed from the source, pruned it", "policy", key)
+	}
+	sort.Slice(policies, func(i, j int) bool {
+		return syncedPolicyKey(policies[i]) < syncedPolicyKey(policies[j])
+	})
+	status.Revision = revision
+	status.Policies = policies
+	if len(driftedPolicies) > 0 {
+		sort.Strings(driftedPolicies)
+		status.SetDrifted(true, fmt.Sprintf("policies changed in the cluster were reverted: %s", strings.Join(driftedPolicies, ", ")))
+	} else {
+		status.SetDrifted(false, "policies match the source")
+	}
+	if err := multierr.Combine(errs...); err != nil {
+		return err
+	}
+	status.LastSyncTime = metav1.Now()
+	status.SetReady(true, kyvernov2alpha1.PolicySourceReasonSucceeded, fmt.Sprintf("%d policies synced from revision %s", len(policies), revision))
+	return nil
+}
+
+// apply applies a policy with server side apply, policies that exist and were not synced from the source are left untouched.
+func (c *controller) apply(ctx context.Context, source string, object *unstructured.Unstructured, previous *kyvernov2alpha1.SyncedPolicy) (*kyvernov2alpha1.SyncedPolicy, error) {
+	apiVersion, kind, namespace, name := object.GetAPIVersion(), object.GetKind(), object.GetNamespace(), object.GetName()
+	live, err := c.client.GetResource(ctx, apiVersion, kind, namespace, name)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+		live = nil
+	} else if live.GetLabels()[kyverno.LabelPolicySource] != source {
+		return nil, fmt.Errorf("%s already exists and is not synced from this source", name)
+	}
+	labels := object.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[kyverno.LabelPolicySource] = source
+	object.SetLabels(labels)
+	applied, err := c.client.ApplyResource(ctx, apiVersion, kind, namespace, name, object, false, fieldManager)
+	if err != nil {
+		return nil, err
+	}
+	return &kyvernov2alpha1.SyncedPolicy{
+		APIVersion: apiVersion,
+		Kind:       kind,
+		Namespace:  namespace,
+		Name:       name,
+		Generation: applied.GetGeneration(),
+		Drifted:    drifted(previous, live),
+	}, nil
+}
+
+// prune deletes a policy removed from the source, unless it was taken over by something else.
+func (c *controller) prune(ctx context.Context, source string, policy *kyvernov2alpha1.SyncedPolicy) error {
+	live, err := c.client.GetResource(ctx, policy.APIVersion, policy.Kind, policy.Namespace, policy.Name)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if live.GetLabels()[kyverno.LabelPolicySource] != source {
+		return nil
+	}
+	if err := c.client.DeleteResource(ctx, policy.APIVersion, policy.Kind, policy.Namespace, policy.Name, false, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package policysource
+
+import (
+	"testing"
+
+	kyvernov2alpha1 "github.com/kyverno/kyverno/api/kyverno/v2alpha1"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func Test_parse(t *testing.T) {
+	tests := []struct {
+		name      string
+		documents []string
+		want      []string
+		wantErr   bool
+	}{{
+		name: "policies and exceptions",
+		documents: []string{
+			`
+apiVersion: kyverno.io/v1
+kind: ClusterPolicy
+metadata:
+  name: require-labels
+  namespace: ignored
+spec: {}
+status:
+  ready: true`,
+			`
+apiVersion: policies.kyverno.io/v1beta1
+kind: NamespacedValidatingPolicy
+metadata:
+  name: check-labels
+  namespace: team-a
+spec: {}`,
+			`
+apiVersion: policies.kyverno.io/v1beta1
+kind: PolicyException
+metadata:
+  name: allow-labels
+  namespace: kyverno
+spec: {}`,
+		},
+		want: []string{
+			"ClusterPolicy.kyverno.io/require-labels",
+			"NamespacedValidatingPolicy.policies.kyverno.io/team-a/check-labels",
+			"PolicyException.policies.kyverno.io/kyverno/allow-labels",
+		},
+	}, {
+		name: "tests and resources are skipped",
+		documents: []string{
+			`
+apiVersion: cli.kyverno.io/v1alpha1
+kind: Test
+metadata:
+  name: test`,
+			`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: pod`,
+			``,
+		},
+	}, {
+		name: "duplicated policy",
+		documents: []string{
+			`
+apiVersion: policies.kyverno.io/v1beta1
+kind: ValidatingPolicy
+metadata:
+  name: check-labels`,
+			`
+apiVersion: policies.kyverno.io/v1beta1
+kind: ValidatingPolicy
+metadata:
+  name: check-labels`,
+		},
+		wantErr: true,
+	}, {
+		name: "policy without name",
+		documents: []string{
+			`
+apiVersion: kyverno.io/v1
+kind: Policy
+metadata:
+  namespace: default`,
+		},
+		wantErr: true,
+	}, {
+		name: "namespaced policy without namespace",
+		documents: []string{
+			`
+apiVersion: policies.kyverno.io/v1beta1
+kind: NamespacedValidatingPolicy
+metadata:
+  name: check-labels`,
+		},
+		wantErr: true,
+	}, {
+		name:      "invalid document",
+		documents: []string{`kind: [`},
+		wantErr:   true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var documents [][]byte
+			for _, document := range tt.documents {
+				documents = append(documents, []byte(document))
+			}
+			objects, err := parse(documents)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			var got []string
+			for _, object := range objects {
+				assert.NotContains(t, object.Object, "status")
+				got = append(got, policyKey(object.GroupVersionKind().GroupKind(), object.GetNamespace(), object.GetName()))
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_drifted(t *testing.T) {
+	live := &unstructured.Unstructured{}
+	live.SetGeneration(2)
+	tests := []struct {
+		name     string
+		previous *kyvernov2alpha1.SyncedPolicy
+		live     *unstructured.Unstructured
+		want     bool
+	}{{
+		name: "new policy",
+		live: nil,
+		want: false,
+	}, {
+		name:     "unchanged policy",
+		previous: &kyvernov2alpha1.SyncedPolicy{Generation: 2},
+		live:     live,
+		want:     false,
+	}, {
+		name:     "changed policy",
+		previous: &kyvernov2alpha1.SyncedPolicy{Generation: 1},
+		live:     live,
+		want:     true,
+	}, {
+		name:     "deleted policy",
+		previous: &kyvernov2alpha1.SyncedPolicy{Generation: 2},
+		live:     nil,
+		want:     true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, drifted(tt.previous, tt.live))
+		})
+	}
+}
+
+func Test_syncedPolicyKey(t *testing.T) {
+	policy := kyvernov2alpha1.SyncedPolicy{
+		APIVersion: "policies.kyverno.io/v1beta1",
+		Kind:       "NamespacedMutatingPolicy",
+		Namespace:  "team-a",
+		Name:       "add-labels",
+	}
+	assert.Equal(t, "NamespacedMutatingPolicy.policies.kyverno.io/team-a/add-labels", syncedPolicyKey(policy))
+}
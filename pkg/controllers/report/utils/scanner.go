@@ -11,6 +11,7 @@ import (
 	"github.com/kyverno/kyverno/pkg/admissionpolicy"
 	celengine "github.com/kyverno/kyverno/pkg/cel/engine"
 	"github.com/kyverno/kyverno/pkg/cel/libs"
+	"github.com/kyverno/kyverno/pkg/cel/libs/http"
 	"github.com/kyverno/kyverno/pkg/cel/matching"
 	ivpolengine "github.com/kyverno/kyverno/pkg/cel/policies/ivpol/engine"
 	mpolcompiler "github.com/kyverno/kyverno/pkg/cel/policies/mpol/compiler"
@@ -23,6 +24,7 @@ import (
 	engineapi "github.com/kyverno/kyverno/pkg/engine/api"
 	"github.com/kyverno/kyverno/pkg/engine/jmespath"
 	gctxstore "github.com/kyverno/kyverno/pkg/globalcontext/store"
+	"github.com/kyverno/kyverno/pkg/imageverifycache"
 	"github.com/kyverno/kyverno/pkg/metrics"
 	reportutils "github.com/kyverno/kyverno/pkg/utils/report"
 	"go.uber.org/multierr"
@@ -48,6 +50,8 @@ type scanner struct {
 	gctxStore       gctxstore.Store
 	mapper          meta.RESTMapper
 	typeConverter   patch.TypeConverterManager
+	httpConfig      *http.Config
+	verifyCache     imageverifycache.Client
 }
 
 type ScanResult struct {
@@ -79,6 +83,8 @@ func NewScanner(
 	gctxStore gctxstore.Store,
 	mapper meta.RESTMapper,
 	typeConverter patch.TypeConverterManager,
+	httpConfig *http.Config,
+	verifyCache imageverifycache.Client,
 ) Scanner {
 	return &scanner{
 		logger:          logger,
@@ -90,6 +96,8 @@ func NewScanner(
 		gctxStore:       gctxStore,
 		mapper:          mapper,
 		typeConverter:   typeConverter,
+		httpConfig:      httpConfig,
+		verifyCache:     verifyCache,
 	}
 }
 
@@ -178,7 +186,7 @@ func (s *scanner) ScanResource(
 
 	for i, policy := range vpols {
 		if pol := policy.AsValidatingPolicy(); pol != nil {
-			compiler := vpolcompiler.NewCompiler()
+			compiler := vpolcompiler.NewCompiler(vpolcompiler.WithHTTPConfig(s.httpConfig))
 			provider, err := vpolengine.NewProvider(compiler, []policiesv1beta1.ValidatingPolicyLike{pol}, exceptions)
 			if err != nil {
 				logger.Error(err, "failed to create policy provider")
@@ -237,7 +245,7 @@ func (s *scanner) ScanResource(
 
 	for i, policy := range mpols {
 		if pol := policy.AsMutatingPolicy(); pol != nil {
-			compiler := mpolcompiler.NewCompiler()
+			compiler := mpolcompiler.NewCompiler(mpolcompiler.WithHTTPConfig(s.httpConfig))
 			provider, err := mpolengine.NewProvider(compiler, []policiesv1beta1.MutatingPolicyLike{pol}, exceptions)
 			if err != nil {
 				logger.Error(err, "failed to create policy provider")
@@ -318,6 +326,8 @@ func (s *scanner) ScanResource(
 				matching.NewMatcher(),
 				s.client.GetKubeClient().CoreV1().Secrets(""),
 				nil,
+				s.httpConfig,
+				s.verifyCache,
 			), metrics.BackgroundScan)
 			context, err := libs.NewContextProvider(s.client, nil, gctxstore.New(), s.mapper, false)
 			if err != nil {
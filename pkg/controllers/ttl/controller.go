@@ -6,12 +6,14 @@ import (
 
 	"github.com/go-logr/logr"
 	"github.com/kyverno/kyverno/api/kyverno"
+	"github.com/kyverno/kyverno/pkg/archive"
 	"github.com/kyverno/kyverno/pkg/metrics"
 	controllerutils "github.com/kyverno/kyverno/pkg/utils/controller"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/metadata"
 	"k8s.io/client-go/tools/cache"
@@ -27,6 +29,8 @@ const (
 type controller struct {
 	name         string
 	client       metadata.Getter
+	objectClient dynamic.NamespaceableResourceInterface
+	archive      archive.Sink
 	queue        workqueue.TypedRateLimitingInterface[any]
 	lister       cache.GenericLister
 	informer     cache.SharedIndexInformer
@@ -36,21 +40,23 @@ type controller struct {
 	gvr          schema.GroupVersionResource
 }
 
-func newController(client metadata.Getter, metainformer informers.GenericInformer, logger logr.Logger, gvr schema.GroupVersionResource) (*controller, error) {
+func newController(client metadata.Getter, objectClient dynamic.NamespaceableResourceInterface, archive archive.Sink, metainformer informers.GenericInformer, logger logr.Logger, gvr schema.GroupVersionResource) (*controller, error) {
 	name := gvr.Version + "/" + gvr.Resource
 	if gvr.Group != "" {
 		name = gvr.Group + "/" + name
 	}
 	queue := workqueue.NewTypedRateLimitingQueueWithConfig(workqueue.DefaultTypedControllerRateLimiter[any](), workqueue.TypedRateLimitingQueueConfig[any]{Name: name})
 	c := &controller{
-		name:     name,
-		client:   client,
-		queue:    queue,
-		lister:   metainformer.Lister(),
-		informer: metainformer.Informer(),
-		logger:   logger,
-		metrics:  metrics.GetTTLInfoMetrics(),
-		gvr:      gvr,
+		name:         name,
+		client:       client,
+		objectClient: objectClient,
+		archive:      archive,
+		queue:        queue,
+		lister:       metainformer.Lister(),
+		informer:     metainformer.Informer(),
+		logger:       logger,
+		metrics:      metrics.GetTTLInfoMetrics(),
+		gvr:          gvr,
 	}
 	enqueue := controllerutils.LogError(logger, controllerutils.Parse(controllerutils.MetaNamespaceKey, controllerutils.Queue(queue)))
 	registration, err := controllerutils.AddEventHandlers(
@@ -153,6 +159,25 @@ func (c *controller) reconcile(ctx context.Context, logger logr.Logger, itemKey
 		deleteOptions := metav1.DeleteOptions{
 			PropagationPolicy: determinePropagationPolicy(metaObj, logger),
 		}
+		if c.archive != nil {
+			// the informer only holds metadata, the full manifest is fetched before being archived
+			object, err := c.objectClient.Namespace(namespace).Get(ctx, metaObj.GetName(), metav1.GetOptions{})
+			if err != nil {
+				if apierrors.IsNotFound(err) {
+					return nil
+				}
+				return err
+			}
+			if err := archive.Archive(ctx, c.archive, object, "", "", archive.ReasonTTLExpired); err != nil {
+				logger.Error(err, "failed to archive resource, it will not be deleted")
+				if c.metrics != nil {
+					c.metrics.RecordTTLFailure(context.Background(), c.gvr, metaObj.GetNamespace())
+				}
+				return err
+			}
+			// make sure the archived object is the one being deleted
+			deleteOptions.Preconditions = metav1.NewUIDPreconditions(string(object.GetUID()))
+		}
 		err = c.client.Namespace(namespace).Delete(context.Background(), metaObj.GetName(), deleteOptions)
 		if err != nil {
 			logger.Error(err, "failed to delete resource")
@@ -8,6 +8,7 @@ import (
 
 	"github.com/go-logr/logr"
 	"github.com/kyverno/kyverno/api/kyverno"
+	"github.com/kyverno/kyverno/pkg/archive"
 	"github.com/kyverno/kyverno/pkg/auth/checker"
 	"github.com/kyverno/kyverno/pkg/controllers"
 	"github.com/kyverno/kyverno/pkg/logging"
@@ -18,6 +19,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/metadata"
 	"k8s.io/client-go/metadata/metadatainformer"
 	"k8s.io/client-go/tools/cache"
@@ -32,7 +34,9 @@ const (
 
 type manager struct {
 	metadataClient  metadata.Interface
+	dynamicClient   dynamic.Interface
 	discoveryClient discovery.DiscoveryInterface
+	archive         archive.Sink
 	checker         checker.AuthChecker
 	resController   map[schema.GroupVersionResource]stopFunc
 	logger          logr.Logger
@@ -48,12 +52,16 @@ func NewManager(
 	checker checker.AuthChecker,
 	timeInterval time.Duration,
 	resyncPeriod time.Duration,
+	dynamicInterface dynamic.Interface,
+	archive archive.Sink,
 ) controllers.Controller {
 	logger := logging.WithName(ControllerName)
 
 	mgr := &manager{
 		metadataClient:  metadataInterface,
+		dynamicClient:   dynamicInterface,
 		discoveryClient: discoveryInterface,
+		archive:         archive,
 		checker:         checker,
 		resController:   map[schema.GroupVersionResource]stopFunc{},
 		logger:          logger,
@@ -157,7 +165,11 @@ func (m *manager) start(ctx context.Context, gvr schema.GroupVersionResource, wo
 		stopInformer()
 		return fmt.Errorf("failed to wait for cache sync: %s", gvr.Resource)
 	}
-	controller, err := newController(m.metadataClient.Resource(gvr), informer, logger, gvr)
+	var objectClient dynamic.NamespaceableResourceInterface
+	if m.archive != nil {
+		objectClient = m.dynamicClient.Resource(gvr)
+	}
+	controller, err := newController(m.metadataClient.Resource(gvr), objectClient, m.archive, informer, logger, gvr)
 	if err != nil {
 		stopInformer()
 		return err
@@ -178,9 +190,14 @@ func (m *manager) start(ctx context.Context, gvr schema.GroupVersionResource, wo
 
 func (m *manager) filterPermissionsResource(resources []schema.GroupVersionResource) []schema.GroupVersionResource {
 	validResources := []schema.GroupVersionResource{}
+	var verbs []string
+	if m.archive != nil {
+		// the full manifest is fetched before being archived
+		verbs = append(verbs, "get")
+	}
 	for _, resource := range resources {
 		// Check if the service account has the necessary permissions
-		if HasResourcePermissions(m.logger, resource, m.checker) {
+		if HasResourcePermissions(m.logger, resource, m.checker, verbs...) {
 			validResources = append(validResources, resource)
 		}
 	}
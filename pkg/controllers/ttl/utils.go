@@ -42,8 +42,10 @@ func discoverResources(logger logr.Logger, discoveryClient discovery.DiscoveryIn
 	return resources, nil
 }
 
-func HasResourcePermissions(logger logr.Logger, resource schema.GroupVersionResource, s checker.AuthChecker) bool {
-	can, err := checker.Check(context.TODO(), s, resource.Group, resource.Version, resource.Resource, "", "", "watch", "list", "delete")
+// HasResourcePermissions checks the permissions required to watch and delete the resource, along with the extra verbs.
+func HasResourcePermissions(logger logr.Logger, resource schema.GroupVersionResource, s checker.AuthChecker, verbs ...string) bool {
+	verbs = append([]string{"watch", "list", "delete"}, verbs...)
+	can, err := checker.Check(context.TODO(), s, resource.Group, resource.Version, resource.Resource, "", "", verbs...)
 	if err != nil {
 		logger.Error(err, "failed to check permissions")
 		return false
@@ -1,10 +1,15 @@
 package ttl
 
 import (
+	"context"
 	"testing"
 	"time"
 
+	"github.com/go-logr/logr"
+	"github.com/kyverno/kyverno/pkg/auth/checker"
+	"github.com/stretchr/testify/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 type mockMetaObj struct {
@@ -71,3 +76,18 @@ func TestParseDeletionTime(t *testing.T) {
 		}
 	}
 }
+
+type verbsChecker map[string]bool
+
+func (c verbsChecker) Check(_ context.Context, _, _, _, _, _, _, verb string) (*checker.AuthResult, error) {
+	return &checker.AuthResult{Allowed: c[verb]}, nil
+}
+
+func TestHasResourcePermissions(t *testing.T) {
+	gvr := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	s := verbsChecker{"watch": true, "list": true, "delete": true}
+	assert.True(t, HasResourcePermissions(logr.Discard(), gvr, s))
+	assert.False(t, HasResourcePermissions(logr.Discard(), gvr, s, "get"))
+	s["get"] = true
+	assert.True(t, HasResourcePermissions(logr.Discard(), gvr, s, "get"))
+}
@@ -33,6 +33,9 @@ func New(
 	}
 
 	executor := NewExecutor(logger, entry.Name, client, apiCallConfig)
+	if entry.APICall.CacheTTL != nil {
+		executor.cacheTTL = entry.APICall.CacheTTL.Duration
+	}
 
 	return &apiCall{
 		logger:   logger,
@@ -2,11 +2,20 @@ package apicall
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/json"
+	"encoding/pem"
 	"io"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/go-logr/logr"
 	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
@@ -305,3 +314,83 @@ func Test_serviceHeaders(t *testing.T) {
 	assert.Equal(t, "application/json", responseHeaders["Content-Type"][0])
 	assert.Equal(t, "CustomVal", responseHeaders["Custom-Key"][0])
 }
+
+type testCredentials struct {
+	certificate *tls.Certificate
+	tokens      map[string]string
+}
+
+func (c testCredentials) ClientCertificate(string) (*tls.Certificate, error) {
+	return c.certificate, nil
+}
+
+func (c testCredentials) Token(audience string) (string, error) {
+	return c.tokens[audience], nil
+}
+
+func newClientCertificate(t *testing.T) *tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NilError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "kyverno"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NilError(t, err)
+	return &tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+func Test_serviceClientCredentials(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/resource", func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.PeerCertificates) == 0 || r.TLS.PeerCertificates[0].Subject.CommonName != "kyverno" {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer audience-token" {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"ok":true}`))
+	})
+	s := httptest.NewUnstartedServer(mux)
+	s.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	s.StartTLS()
+	defer s.Close()
+	caBundle := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: s.Certificate().Raw}))
+
+	entry := kyvernov1.ContextEntry{
+		Name: "test",
+		APICall: &kyvernov1.ContextAPICall{
+			APICall: kyvernov1.APICall{
+				Method: "GET",
+				Service: &kyvernov1.ServiceCall{
+					URL:              s.URL + "/resource",
+					CABundle:         caBundle,
+					ClientCertSecret: "client-cert",
+					TokenAudience:    "example",
+				},
+			},
+		},
+	}
+	ctx := enginecontext.NewContext(jp)
+
+	// credentials can't be resolved without a provider
+	call, err := New(logr.Discard(), jp, entry, ctx, nil, apiConfig)
+	assert.NilError(t, err)
+	_, err = call.FetchAndLoad(context.TODO())
+	assert.ErrorContains(t, err, "no credentials provider configured")
+
+	config := apiConfig.WithCredentials(testCredentials{
+		certificate: newClientCertificate(t),
+		tokens:      map[string]string{"example": "audience-token"},
+	})
+	call, err = New(logr.Discard(), jp, entry, ctx, nil, config)
+	assert.NilError(t, err)
+	data, err := call.FetchAndLoad(context.TODO())
+	assert.NilError(t, err)
+	assert.Equal(t, `{"ok":true}`, string(data))
+}
@@ -0,0 +1,190 @@
+package apicall
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ResponseCache is a memory bounded LRU cache of service call responses.
+// It is shared across policy evaluations and honours the Cache-Control, Expires and ETag response headers.
+type ResponseCache struct {
+	lock     sync.Mutex
+	maxBytes int64
+	size     int64
+	lru      *list.List
+	entries  map[string]*list.Element
+	now      func() time.Time
+}
+
+type cachedResponse struct {
+	key          string
+	body         []byte
+	etag         string
+	lastModified string
+	expires      time.Time
+}
+
+func (r *cachedResponse) isFresh(now time.Time) bool {
+	return now.Before(r.expires)
+}
+
+func (r *cachedResponse) hasValidators() bool {
+	return r.etag != "" || r.lastModified != ""
+}
+
+// NewResponseCache returns a cache holding at most maxBytes of response bodies.
+func NewResponseCache(maxBytes int64) *ResponseCache {
+	return &ResponseCache{
+		maxBytes: maxBytes,
+		lru:      list.New(),
+		entries:  map[string]*list.Element{},
+		now:      time.Now,
+	}
+}
+
+func (c *ResponseCache) get(key string) (cachedResponse, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	element, ok := c.entries[key]
+	if !ok {
+		return cachedResponse{}, false
+	}
+	c.lru.MoveToFront(element)
+	return *element.Value.(*cachedResponse), true
+}
+
+func (c *ResponseCache) set(response cachedResponse) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.remove(response.key)
+	size := int64(len(response.body))
+	if size > c.maxBytes {
+		return
+	}
+	for c.size+size > c.maxBytes {
+		c.remove(c.lru.Back().Value.(*cachedResponse).key)
+	}
+	c.entries[response.key] = c.lru.PushFront(&response)
+	c.size += size
+}
+
+func (c *ResponseCache) delete(key string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.remove(key)
+}
+
+func (c *ResponseCache) remove(key string) {
+	if element, ok := c.entries[key]; ok {
+		c.lru.Remove(element)
+		delete(c.entries, key)
+		c.size -= int64(len(element.Value.(*cachedResponse).body))
+	}
+}
+
+// store caches a successful response, it returns false if the response must not be cached.
+func (c *ResponseCache) store(key string, header http.Header, body []byte, ttl time.Duration) bool {
+	lifetime, ok := responseLifetime(header, ttl, c.now())
+	response := cachedResponse{
+		key:          key,
+		body:         body,
+		etag:         header.Get("ETag"),
+		lastModified: header.Get("Last-Modified"),
+		expires:      c.now().Add(lifetime),
+	}
+	// a response that is immediately stale is only useful if it can be revalidated
+	if !ok || (lifetime <= 0 && !response.hasValidators()) {
+		c.delete(key)
+		return false
+	}
+	c.set(response)
+	return true
+}
+
+// revalidate extends the lifetime of a cached response after the server answered 304 Not Modified.
+func (c *ResponseCache) revalidate(response cachedResponse, header http.Header, ttl time.Duration) {
+	lifetime, ok := responseLifetime(header, ttl, c.now())
+	if !ok {
+		c.delete(response.key)
+		return
+	}
+	response.expires = c.now().Add(lifetime)
+	if etag := header.Get("ETag"); etag != "" {
+		response.etag = etag
+	}
+	c.set(response)
+}
+
+// responseLifetime returns how long a response can be served from the cache without revalidation.
+// Caching is opt-in, the response must not be stored if no ttl is configured.
+// The max-age directive, or the Expires header if no max-age is set, is capped by the configured ttl,
+// the ttl is used if the response sets neither.
+// The boolean is false if the response must not be stored.
+func responseLifetime(header http.Header, ttl time.Duration, now time.Time) (time.Duration, bool) {
+	if ttl <= 0 {
+		return 0, false
+	}
+	var maxAge *time.Duration
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		switch strings.ToLower(name) {
+		case "no-store":
+			return 0, false
+		case "no-cache":
+			return 0, true
+		case "max-age":
+			if seconds, err := strconv.ParseInt(strings.Trim(value, `"`), 10, 64); err == nil && seconds >= 0 {
+				age := time.Duration(seconds) * time.Second
+				maxAge = &age
+			}
+		}
+	}
+	if maxAge == nil {
+		if expires := header.Get("Expires"); expires != "" {
+			// an invalid Expires header means the response is already expired
+			var age time.Duration
+			if expiresAt, err := http.ParseTime(expires); err == nil {
+				date := now
+				if served, err := http.ParseTime(header.Get("Date")); err == nil {
+					date = served
+				}
+				age = max(expiresAt.Sub(date), 0)
+			}
+			maxAge = &age
+		}
+	}
+	if maxAge == nil || ttl < *maxAge {
+		return ttl, true
+	}
+	return *maxAge, true
+}
+
+// requestCacheKey identifies a request by its method, URL, headers and body.
+func requestCacheKey(req *http.Request) (string, error) {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s %s\n", req.Method, req.URL.String())
+	for _, key := range slices.Sorted(maps.Keys(req.Header)) {
+		fmt.Fprintf(hash, "%s: %s\n", key, strings.Join(req.Header.Values(key), ","))
+	}
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return "", err
+		}
+		defer body.Close()
+		if _, err := io.Copy(hash, body); err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,194 @@
+package apicall
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	"gotest.tools/assert"
+)
+
+func Test_responseLifetime(t *testing.T) {
+	now := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name         string
+		cacheControl string
+		expires      string
+		date         string
+		ttl          time.Duration
+		want         time.Duration
+		wantStore    bool
+	}{{
+		name:      "no header no ttl",
+		want:      0,
+		wantStore: false,
+	}, {
+		name:         "max age no ttl",
+		cacheControl: "public, max-age=30",
+		want:         0,
+		wantStore:    false,
+	}, {
+		name:      "no header with ttl",
+		ttl:       time.Minute,
+		want:      time.Minute,
+		wantStore: true,
+	}, {
+		name:         "max age",
+		cacheControl: "public, max-age=30",
+		ttl:          time.Minute,
+		want:         30 * time.Second,
+		wantStore:    true,
+	}, {
+		name:         "max age capped by ttl",
+		cacheControl: "max-age=3600",
+		ttl:          time.Minute,
+		want:         time.Minute,
+		wantStore:    true,
+	}, {
+		name:      "expires",
+		expires:   now.Add(30 * time.Second).Format(http.TimeFormat),
+		ttl:       time.Minute,
+		want:      30 * time.Second,
+		wantStore: true,
+	}, {
+		name:      "expires relative to date",
+		expires:   now.Add(30 * time.Second).Format(http.TimeFormat),
+		date:      now.Add(-30 * time.Second).Format(http.TimeFormat),
+		ttl:       time.Hour,
+		want:      time.Minute,
+		wantStore: true,
+	}, {
+		name:      "expires capped by ttl",
+		expires:   now.Add(time.Hour).Format(http.TimeFormat),
+		ttl:       time.Minute,
+		want:      time.Minute,
+		wantStore: true,
+	}, {
+		name:      "invalid expires",
+		expires:   "0",
+		ttl:       time.Minute,
+		want:      0,
+		wantStore: true,
+	}, {
+		name:         "max age overrides expires",
+		cacheControl: "max-age=10",
+		expires:      now.Add(30 * time.Second).Format(http.TimeFormat),
+		ttl:          time.Minute,
+		want:         10 * time.Second,
+		wantStore:    true,
+	}, {
+		name:         "no-cache",
+		cacheControl: "no-cache",
+		ttl:          time.Minute,
+		want:         0,
+		wantStore:    true,
+	}, {
+		name:         "no-store",
+		cacheControl: "max-age=60, no-store",
+		ttl:          time.Minute,
+		want:         0,
+		wantStore:    false,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.cacheControl != "" {
+				header.Set("Cache-Control", tt.cacheControl)
+			}
+			if tt.expires != "" {
+				header.Set("Expires", tt.expires)
+			}
+			if tt.date != "" {
+				header.Set("Date", tt.date)
+			}
+			got, store := responseLifetime(header, tt.ttl, now)
+			assert.Equal(t, got, tt.want)
+			assert.Equal(t, store, tt.wantStore)
+		})
+	}
+}
+
+func Test_responseCacheEviction(t *testing.T) {
+	cache := NewResponseCache(10)
+	cache.set(cachedResponse{key: "a", body: []byte("12345")})
+	cache.set(cachedResponse{key: "b", body: []byte("12345")})
+	// access a so that b is the least recently used
+	_, ok := cache.get("a")
+	assert.Assert(t, ok)
+	cache.set(cachedResponse{key: "c", body: []byte("123")})
+	_, ok = cache.get("b")
+	assert.Assert(t, !ok)
+	_, ok = cache.get("a")
+	assert.Assert(t, ok)
+	_, ok = cache.get("c")
+	assert.Assert(t, ok)
+	assert.Equal(t, cache.size, int64(8))
+	// responses larger than the cache are not stored
+	cache.set(cachedResponse{key: "d", body: []byte("12345678901")})
+	_, ok = cache.get("d")
+	assert.Assert(t, !ok)
+}
+
+func Test_serviceCallCache(t *testing.T) {
+	calls := 0
+	revalidations := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/resource", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			revalidations++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{"day": "monday"}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	cache := NewResponseCache(1000)
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+	call := &kyvernov1.APICall{
+		Method: "GET",
+		Service: &kyvernov1.ServiceCall{
+			URL:     server.URL + "/resource",
+			Headers: []kyvernov1.HTTPHeader{{Key: "Authorization", Value: "Bearer 1234567890"}},
+		},
+	}
+	executor := NewExecutor(logr.Discard(), "test", nil, apiConfig.WithResponseCache(cache))
+	executor.cacheTTL = time.Minute
+
+	data, err := executor.Execute(context.TODO(), call)
+	assert.NilError(t, err)
+	assert.Equal(t, string(data), `{"day": "monday"}`)
+	assert.Equal(t, calls, 1)
+	// fresh responses are served from the cache
+	data, err = executor.Execute(context.TODO(), call)
+	assert.NilError(t, err)
+	assert.Equal(t, string(data), `{"day": "monday"}`)
+	assert.Equal(t, calls, 1)
+	// stale responses are revalidated
+	now = now.Add(2 * time.Minute)
+	data, err = executor.Execute(context.TODO(), call)
+	assert.NilError(t, err)
+	assert.Equal(t, string(data), `{"day": "monday"}`)
+	assert.Equal(t, calls, 2)
+	assert.Equal(t, revalidations, 1)
+	// different requests are cached separately
+	call.Method = "POST"
+	_, err = executor.Execute(context.TODO(), call)
+	assert.NilError(t, err)
+	assert.Equal(t, calls, 3)
+	// calls without ttl are not cached
+	call.Method = "GET"
+	executor.cacheTTL = 0
+	_, err = executor.Execute(context.TODO(), call)
+	assert.NilError(t, err)
+	assert.Equal(t, calls, 4)
+	assert.Equal(t, revalidations, 1)
+}
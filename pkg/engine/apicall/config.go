@@ -1,7 +1,13 @@
 package apicall
 
+import (
+	"github.com/kyverno/kyverno/pkg/utils/credentials"
+)
+
 type APICallConfiguration struct {
 	maxAPICallResponseLength int64
+	responseCache            *ResponseCache
+	credentials              credentials.Provider
 }
 
 func NewAPICallConfiguration(maxLen int64) APICallConfiguration {
@@ -9,3 +15,15 @@ func NewAPICallConfiguration(maxLen int64) APICallConfiguration {
 		maxAPICallResponseLength: maxLen,
 	}
 }
+
+// WithResponseCache returns a copy of the configuration caching service call responses in the given cache.
+func (c APICallConfiguration) WithResponseCache(cache *ResponseCache) APICallConfiguration {
+	c.responseCache = cache
+	return c
+}
+
+// WithCredentials returns a copy of the configuration resolving service call client certificates and tokens with the given provider.
+func (c APICallConfiguration) WithCredentials(provider credentials.Provider) APICallConfiguration {
+	c.credentials = provider
+	return c
+}
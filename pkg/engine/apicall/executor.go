@@ -3,17 +3,18 @@ package apicall
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
-	"crypto/x509"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/go-logr/logr"
 	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/breaker"
 	"github.com/kyverno/kyverno/pkg/tracing"
+	"github.com/kyverno/kyverno/pkg/utils/credentials"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
@@ -26,6 +27,8 @@ type executor struct {
 	name   string
 	client ClientInterface
 	config APICallConfiguration
+	// cacheTTL is the maximum duration service call responses are cached
+	cacheTTL time.Duration
 }
 
 func NewExecutor(
@@ -77,11 +80,43 @@ func (a *executor) executeServiceCall(ctx context.Context, apiCall *kyvernov1.AP
 		return nil, fmt.Errorf("failed to build HTTP request for APICall %s: %w", a.name, err)
 	}
 
-	resp, err := client.Do(req)
+	// caching is opt-in, responses are only cached for calls configuring a ttl
+	cache := a.config.responseCache
+	if a.cacheTTL <= 0 {
+		cache = nil
+	}
+	var cacheKey string
+	var cached cachedResponse
+	var isCached bool
+	if cache != nil {
+		if cacheKey, err = requestCacheKey(req); err != nil {
+			return nil, fmt.Errorf("failed to compute cache key for APICall %s: %w", a.name, err)
+		}
+		if cached, isCached = cache.get(cacheKey); isCached {
+			if cached.isFresh(cache.now()) {
+				a.logger.V(4).Info("served service APICall from cache", "name", a.name, "len", len(cached.body))
+				return cached.body, nil
+			}
+			if cached.etag != "" {
+				req.Header.Set("If-None-Match", cached.etag)
+			}
+			if cached.lastModified != "" {
+				req.Header.Set("If-Modified-Since", cached.lastModified)
+			}
+		}
+	}
+
+	resp, err := breaker.HostCircuitBreaker("apicall", req.URL.Host).DoRequest(client, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute HTTP request for APICall %s: %w", a.name, err)
 	}
 	defer resp.Body.Close()
+
+	if isCached && resp.StatusCode == http.StatusNotModified {
+		cache.revalidate(cached, resp.Header, a.cacheTTL)
+		a.logger.V(4).Info("revalidated cached service APICall", "name", a.name, "len", len(cached.body))
+		return cached.body, nil
+	}
 	var w http.ResponseWriter
 
 	if a.config.maxAPICallResponseLength != 0 {
@@ -106,10 +141,41 @@ func (a *executor) executeServiceCall(ctx context.Context, apiCall *kyvernov1.AP
 		}
 	}
 
+	if cache != nil {
+		cache.store(cacheKey, resp.Header, body, a.cacheTTL)
+	}
+
 	a.logger.V(4).Info("executed service APICall", "name", a.name, "len", len(body))
 	return body, nil
 }
 
+// Stream opens a long lived GET request to the service and returns the response body.
+// It is used to consume server-sent events streams, the caller must close the returned body.
+func (a *executor) Stream(ctx context.Context, service *kyvernov1.ServiceCall) (io.ReadCloser, error) {
+	client, err := a.buildHTTPClient(service)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, service.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build stream request for APICall %s: %w", a.name, err)
+	}
+	if err := a.addHTTPHeaders(req, service); err != nil {
+		return nil, fmt.Errorf("failed to add headers for APICall %s: %w", a.name, err)
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open stream for APICall %s: %w", a.name, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("HTTP %s", resp.Status)
+	}
+	a.logger.V(4).Info("opened service stream", "name", a.name, "url", service.URL)
+	return resp.Body, nil
+}
+
 func (a *executor) buildHTTPRequest(ctx context.Context, apiCall *kyvernov1.APICall) (*http.Request, error) {
 	if apiCall.Service == nil {
 		return nil, fmt.Errorf("missing service")
@@ -133,21 +199,29 @@ func (a *executor) buildHTTPRequest(ctx context.Context, apiCall *kyvernov1.APIC
 		return nil, fmt.Errorf("failed to build request for APICall %s: %w", a.name, err)
 	}
 
-	if err := a.addHTTPHeaders(req, apiCall.Service.Headers); err != nil {
+	if err := a.addHTTPHeaders(req, apiCall.Service); err != nil {
 		return nil, fmt.Errorf("failed to add headers for APICall %s: %w", a.name, err)
 	}
 
 	return req, nil
 }
 
-func (a *executor) addHTTPHeaders(req *http.Request, headers []kyvernov1.HTTPHeader) error {
-	for _, header := range headers {
+func (a *executor) addHTTPHeaders(req *http.Request, service *kyvernov1.ServiceCall) error {
+	for _, header := range service.Headers {
 		req.Header.Add(header.Key, header.Value)
 	}
 
 	if req.Header.Get("Authorization") == "" {
-		token := a.getToken()
-		if token != "" {
+		if service.TokenAudience != "" {
+			if a.config.credentials == nil {
+				return fmt.Errorf("failed to get token for audience %s: %w", service.TokenAudience, credentials.ErrNoProvider)
+			}
+			token, err := a.config.credentials.Token(service.TokenAudience)
+			if err != nil {
+				return err
+			}
+			req.Header.Add("Authorization", "Bearer "+token)
+		} else if token := a.getToken(); token != "" {
 			req.Header.Add("Authorization", "Bearer "+token)
 		}
 	}
@@ -167,18 +241,15 @@ func (a *executor) getToken() string {
 }
 
 func (a *executor) buildHTTPClient(service *kyvernov1.ServiceCall) (*http.Client, error) {
-	if service == nil || service.CABundle == "" {
+	if service == nil || (service.CABundle == "" && service.ClientCertSecret == "") {
 		return http.DefaultClient, nil
 	}
-	caCertPool := x509.NewCertPool()
-	if ok := caCertPool.AppendCertsFromPEM([]byte(service.CABundle)); !ok {
-		return nil, fmt.Errorf("failed to parse PEM CA bundle for APICall %s", a.name)
+	tlsConfig, err := credentials.TLSConfig(a.config.credentials, service.CABundle, service.ClientCertSecret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure TLS for APICall %s: %w", a.name, err)
 	}
 	transport := &http.Transport{
-		TLSClientConfig: &tls.Config{
-			RootCAs:    caCertPool,
-			MinVersion: tls.VersionTLS12,
-		},
+		TLSClientConfig: tlsConfig,
 	}
 	return &http.Client{
 		Transport: tracing.Transport(transport, otelhttp.WithFilter(tracing.RequestFilterIsInSpan)),
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
@@ -100,7 +101,11 @@ func (g *gctxLoader) loadGctxData() ([]byte, error) {
 		g.logger.Error(err, "")
 		return nil, err
 	}
-	data, err = storeEntry.Get(projectionName)
+	var maxAge time.Duration
+	if rc.MaxAge != nil {
+		maxAge = rc.MaxAge.Duration
+	}
+	data, err = store.GetWithMaxAge(storeEntry, projectionName, maxAge)
 	if err != nil {
 		g.logger.Error(err, "failed to fetch data from entry")
 		return nil, err
@@ -11,6 +11,7 @@ import (
 	"github.com/kyverno/kyverno/pkg/config"
 	engineapi "github.com/kyverno/kyverno/pkg/engine/api"
 	enginecontext "github.com/kyverno/kyverno/pkg/engine/context"
+	"github.com/kyverno/kyverno/pkg/engine/explain"
 	"github.com/kyverno/kyverno/pkg/engine/handlers"
 	"github.com/kyverno/kyverno/pkg/engine/internal"
 	"github.com/kyverno/kyverno/pkg/engine/jmespath"
@@ -72,11 +73,13 @@ func (e *engine) Validate(
 	startTime := time.Now()
 	response := engineapi.NewEngineResponseFromPolicyContext(policyContext)
 	logger := internal.LoggerWithPolicyContext(logging.WithName("engine.validate"), policyContext)
-	if internal.MatchPolicyContext(logger, e.client, policyContext, e.configuration) {
+	ctx, step := explainPolicy(ctx, "validate", policyContext)
+	if e.matchPolicyContext(ctx, logger, policyContext) {
 		policyResponse := e.validate(ctx, logger, policyContext)
 		response = response.WithPolicyResponse(policyResponse)
 	}
 	response = response.WithStats(engineapi.NewExecutionStats(startTime, time.Now()))
+	step.EndWithResponses(response.PolicyResponse.Rules...)
 
 	if e.metrics != nil {
 		e.metrics.RecordResponse(ctx, policyContext.Operation(), policyContext.AdmissionOperation(), policyContext.AdmissionInfo(), response)
@@ -91,13 +94,15 @@ func (e *engine) Mutate(
 	startTime := time.Now()
 	response := engineapi.NewEngineResponseFromPolicyContext(policyContext)
 	logger := internal.LoggerWithPolicyContext(logging.WithName("engine.mutate"), policyContext)
-	if internal.MatchPolicyContext(logger, e.client, policyContext, e.configuration) {
+	ctx, step := explainPolicy(ctx, "mutate", policyContext)
+	if e.matchPolicyContext(ctx, logger, policyContext) {
 		policyResponse, patchedResource := e.mutate(ctx, logger, policyContext)
 		response = response.
 			WithPatchedResource(patchedResource).
 			WithPolicyResponse(policyResponse)
 	}
 	response = response.WithStats(engineapi.NewExecutionStats(startTime, time.Now()))
+	step.EndWithResponses(response.PolicyResponse.Rules...)
 	if e.metrics != nil {
 		e.metrics.RecordResponse(ctx, policyContext.Operation(), policyContext.AdmissionOperation(), policyContext.AdmissionInfo(), response)
 	}
@@ -111,11 +116,13 @@ func (e *engine) Generate(
 	startTime := time.Now()
 	response := engineapi.NewEngineResponseFromPolicyContext(policyContext)
 	logger := internal.LoggerWithPolicyContext(logging.WithName("engine.generate"), policyContext)
-	if internal.MatchPolicyContext(logger, e.client, policyContext, e.configuration) {
+	ctx, step := explainPolicy(ctx, "generate", policyContext)
+	if e.matchPolicyContext(ctx, logger, policyContext) {
 		policyResponse := e.generateResponse(logger, policyContext)
 		response = response.WithPolicyResponse(policyResponse)
 	}
 	response = response.WithStats(engineapi.NewExecutionStats(startTime, time.Now()))
+	step.EndWithResponses(response.PolicyResponse.Rules...)
 	if e.metrics != nil {
 		e.metrics.RecordResponse(ctx, policyContext.Operation(), policyContext.AdmissionOperation(), policyContext.AdmissionInfo(), response)
 	}
@@ -130,13 +137,15 @@ func (e *engine) VerifyAndPatchImages(
 	response := engineapi.NewEngineResponseFromPolicyContext(policyContext)
 	ivm := engineapi.ImageVerificationMetadata{}
 	logger := internal.LoggerWithPolicyContext(logging.WithName("engine.verify"), policyContext)
-	if internal.MatchPolicyContext(logger, e.client, policyContext, e.configuration) {
+	ctx, step := explainPolicy(ctx, "verifyImages", policyContext)
+	if e.matchPolicyContext(ctx, logger, policyContext) {
 		policyResponse, patchedResource, innerIvm := e.verifyAndPatchImages(ctx, logger, policyContext)
 		response, ivm = response.
 			WithPolicyResponse(policyResponse).
 			WithPatchedResource(patchedResource), innerIvm
 	}
 	response = response.WithStats(engineapi.NewExecutionStats(startTime, time.Now()))
+	step.EndWithResponses(response.PolicyResponse.Rules...)
 	if e.metrics != nil {
 		e.metrics.RecordResponse(ctx, policyContext.Operation(), policyContext.AdmissionOperation(), policyContext.AdmissionInfo(), response)
 	}
@@ -150,17 +159,32 @@ func (e *engine) ApplyBackgroundChecks(
 	startTime := time.Now()
 	response := engineapi.NewEngineResponseFromPolicyContext(policyContext)
 	logger := internal.LoggerWithPolicyContext(logging.WithName("engine.background"), policyContext)
-	if internal.MatchPolicyContext(logger, e.client, policyContext, e.configuration) {
+	ctx, step := explainPolicy(ctx, "backgroundChecks", policyContext)
+	if e.matchPolicyContext(ctx, logger, policyContext) {
 		policyResponse := e.applyBackgroundChecks(logger, policyContext)
 		response = response.WithPolicyResponse(policyResponse)
 	}
 	response = response.WithStats(engineapi.NewExecutionStats(startTime, time.Now()))
+	step.EndWithResponses(response.PolicyResponse.Rules...)
 	if e.metrics != nil {
 		e.metrics.RecordResponse(ctx, policyContext.Operation(), policyContext.AdmissionOperation(), policyContext.AdmissionInfo(), response)
 	}
 	return response
 }
 
+// matchPolicyContext checks if the policy applies to the resource and records a mismatch in the explain trace of ctx.
+func (e *engine) matchPolicyContext(
+	ctx context.Context,
+	logger logr.Logger,
+	policyContext engineapi.PolicyContext,
+) bool {
+	if internal.MatchPolicyContext(logger, e.client, policyContext, e.configuration) {
+		return true
+	}
+	explain.Record(ctx, explain.TypeMatch, "", explain.Bool(false), "policy namespace, resource filters or webhook match conditions don't match the resource", nil)
+	return false
+}
+
 func (e *engine) ContextLoader(
 	policy kyvernov1.PolicyInterface,
 	rule kyvernov1.Rule,
@@ -178,6 +202,25 @@ func (e *engine) ContextLoader(
 	}
 }
 
+// explainPolicy records the evaluation of the policy in the explain trace of ctx, subsequent steps are nested in the policy step.
+func explainPolicy(
+	ctx context.Context,
+	operation string,
+	policyContext engineapi.PolicyContext,
+) (context.Context, *explain.Step) {
+	if !explain.Enabled(ctx) {
+		return ctx, nil
+	}
+	policy := policyContext.Policy()
+	name := policy.GetName()
+	if policy.GetNamespace() != "" {
+		name = policy.GetNamespace() + "/" + name
+	}
+	ctx, step := explain.Begin(ctx, explain.TypePolicy, name)
+	step.Set("operation", operation)
+	return ctx, step
+}
+
 // matches checks if either the new or old resource satisfies the filter conditions defined in the rule
 func (e *engine) matches(
 	rule kyvernov1.Rule,
@@ -237,11 +280,17 @@ func (e *engine) invokeRuleHandler(
 		"pkg/engine",
 		fmt.Sprintf("RULE %s", rule.Name),
 		func(ctx context.Context, span trace.Span) (patchedResource unstructured.Unstructured, results []engineapi.RuleResponse) {
+			ctx, step := explain.Begin(ctx, explain.TypeRule, rule.Name)
+			defer func() {
+				explainRuleResponses(ctx, step, results)
+			}()
 			// check if resource and rule match
 			if err := e.matches(rule, policyContext, resource); err != nil {
 				logger.V(4).Info("rule not matched", "reason", err.Error())
+				explain.Record(ctx, explain.TypeMatch, "", explain.Bool(false), err.Error(), nil)
 				return resource, nil
 			}
+			explain.Record(ctx, explain.TypeMatch, "", explain.Bool(true), "", nil)
 			if handlerFactory == nil {
 				return resource, handlers.WithError(rule, ruleType, "failed to instantiate handler", nil)
 			} else if handler, err := handlerFactory(); err != nil {
@@ -266,8 +315,10 @@ func (e *engine) invokeRuleHandler(
 					}
 					return resource, handlers.WithError(rule, ruleType, "failed to load context", err)
 				}
+				explainContext(ctx, policyContext.JSONContext(), rule.Context)
 				// check preconditions
 				preconditionsPassed, msg, err := internal.CheckPreconditions(logger, policyContext.JSONContext(), rule.GetAnyAllConditions())
+				internal.ExplainConditions(ctx, logger, policyContext.JSONContext(), "preconditions", rule.GetAnyAllConditions(), preconditionsPassed, msg, err)
 				if err != nil {
 					return resource, handlers.WithError(rule, ruleType, "failed to evaluate preconditions", err)
 				}
@@ -293,3 +344,34 @@ func (e *engine) invokeRuleHandler(
 		},
 	)
 }
+
+// explainContext records the values of the context entries loaded for a rule in the explain trace of ctx.
+func explainContext(ctx context.Context, jsonContext enginecontext.Interface, entries []kyvernov1.ContextEntry) {
+	if !explain.Enabled(ctx) || len(entries) == 0 {
+		return
+	}
+	values := make(map[string]any, len(entries))
+	for _, entry := range entries {
+		if value, err := jsonContext.Query(entry.Name); err == nil {
+			values[entry.Name] = value
+		}
+	}
+	explain.Record(ctx, explain.TypeContext, "", "loaded", "", values)
+}
+
+// explainRuleResponses ends the rule step with the responses of the rule, responses are nested in the rule step when there are several.
+func explainRuleResponses(ctx context.Context, step *explain.Step, responses []engineapi.RuleResponse) {
+	if step == nil {
+		return
+	}
+	if len(responses) > 1 {
+		for _, response := range responses {
+			explain.Record(ctx, explain.TypeResponse, response.Name(), string(response.Status()), response.Message(), nil)
+		}
+	}
+	if len(responses) == 0 {
+		step.End(string(engineapi.RuleStatusSkip), "rule not applied")
+		return
+	}
+	step.EndWithResponses(responses...)
+}
@@ -0,0 +1,175 @@
+package explain
+
+import (
+	"context"
+	"sync"
+)
+
+// Step types recorded by the engines.
+const (
+	TypeResource   = "resource"
+	TypePolicy     = "policy"
+	TypeRule       = "rule"
+	TypeMatch      = "match"
+	TypeContext    = "context"
+	TypeConditions = "conditions"
+	TypeCondition  = "condition"
+	TypePattern    = "pattern"
+	TypeForEach    = "foreach"
+	TypeExpression = "expression"
+	TypeVariable   = "variable"
+	TypeResponse   = "response"
+)
+
+// Step is an evaluated step of a policy, steps are nested in the step that evaluated them.
+type Step struct {
+	Type    string         `json:"type"`
+	Name    string         `json:"name,omitempty"`
+	Result  string         `json:"result,omitempty"`
+	Message string         `json:"message,omitempty"`
+	Values  map[string]any `json:"values,omitempty"`
+	Steps   []*Step        `json:"steps,omitempty"`
+
+	trace *Trace
+}
+
+// Set records a resolved value on the step.
+func (s *Step) Set(key string, value any) {
+	if s == nil {
+		return
+	}
+	s.trace.lock.Lock()
+	defer s.trace.lock.Unlock()
+	if s.Values == nil {
+		s.Values = map[string]any{}
+	}
+	s.Values[key] = value
+}
+
+// End sets the result of the step.
+func (s *Step) End(result, message string) {
+	if s == nil {
+		return
+	}
+	s.trace.lock.Lock()
+	defer s.trace.lock.Unlock()
+	s.Result = result
+	s.Message = message
+}
+
+// Trace records the steps evaluated by the engines, it is safe for concurrent use.
+type Trace struct {
+	lock  sync.Mutex
+	steps []*Step
+}
+
+func NewTrace() *Trace {
+	return &Trace{}
+}
+
+// Steps returns the root steps recorded in the trace.
+func (t *Trace) Steps() []*Step {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return append([]*Step(nil), t.steps...)
+}
+
+// Reset removes the steps recorded in the trace.
+func (t *Trace) Reset() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.steps = nil
+}
+
+func (t *Trace) add(parent *Step, step *Step) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if parent == nil {
+		t.steps = append(t.steps, step)
+	} else {
+		parent.Steps = append(parent.Steps, step)
+	}
+}
+
+// root returns the root step of a type and name, it is recorded if it doesn't exist.
+func (t *Trace) root(stepType, name string) *Step {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	for _, step := range t.steps {
+		if step.Type == stepType && step.Name == name {
+			return step
+		}
+	}
+	step := &Step{Type: stepType, Name: name, trace: t}
+	t.steps = append(t.steps, step)
+	return step
+}
+
+type frame struct {
+	trace  *Trace
+	parent *Step
+}
+
+type frameKey struct{}
+
+// WithTrace returns a context recording the steps evaluated by the engines in trace.
+func WithTrace(ctx context.Context, trace *Trace) context.Context {
+	if trace == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, frameKey{}, frame{trace: trace})
+}
+
+// Enabled returns true if ctx records the evaluated steps,
+// callers use it to avoid computing values that are only needed by the trace.
+func Enabled(ctx context.Context) bool {
+	_, ok := ctx.Value(frameKey{}).(frame)
+	return ok
+}
+
+// Begin records a step and returns a context in which subsequent steps are nested in it.
+// The returned step is nil when ctx doesn't record steps, all step methods are nil safe.
+func Begin(ctx context.Context, stepType, name string) (context.Context, *Step) {
+	f, ok := ctx.Value(frameKey{}).(frame)
+	if !ok {
+		return ctx, nil
+	}
+	step := &Step{Type: stepType, Name: name, trace: f.trace}
+	f.trace.add(f.parent, step)
+	return context.WithValue(ctx, frameKey{}, frame{trace: f.trace, parent: step}), step
+}
+
+// Resume is like Begin for a root step, except that the root step of the same type and name recorded earlier is
+// reused, so that engines evaluating the same resource one after the other nest their steps in a single step.
+func Resume(ctx context.Context, stepType, name string) (context.Context, *Step) {
+	f, ok := ctx.Value(frameKey{}).(frame)
+	if !ok {
+		return ctx, nil
+	}
+	step := f.trace.root(stepType, name)
+	return context.WithValue(ctx, frameKey{}, frame{trace: f.trace, parent: step}), step
+}
+
+// Record records a step without nested steps.
+func Record(ctx context.Context, stepType, name, result, message string, values map[string]any) {
+	f, ok := ctx.Value(frameKey{}).(frame)
+	if !ok {
+		return
+	}
+	f.trace.add(f.parent, &Step{
+		Type:    stepType,
+		Name:    name,
+		Result:  result,
+		Message: message,
+		Values:  values,
+		trace:   f.trace,
+	})
+}
+
+// Bool converts the outcome of a condition or expression to a step result.
+func Bool(value bool) string {
+	if value {
+		return "true"
+	}
+	return "false"
+}
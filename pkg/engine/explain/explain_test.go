@@ -0,0 +1,104 @@
+package explain
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	engineapi "github.com/kyverno/kyverno/pkg/engine/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBegin(t *testing.T) {
+	trace := NewTrace()
+	ctx := WithTrace(context.TODO(), trace)
+	assert.True(t, Enabled(ctx))
+	policyCtx, policy := Begin(ctx, TypePolicy, "require-labels")
+	ruleCtx, rule := Begin(policyCtx, TypeRule, "check-team")
+	Record(ruleCtx, TypeMatch, "", Bool(true), "", nil)
+	Record(ruleCtx, TypePattern, "", "fail", "invalid value", map[string]any{"path": "/metadata/labels/"})
+	rule.EndWithResponses(*engineapi.RuleFail("check-team", engineapi.Validation, "label required", nil))
+	policy.Set("operation", "validate")
+	policy.EndWithResponses(
+		*engineapi.RulePass("check-app", engineapi.Validation, "", nil),
+		*engineapi.RuleFail("check-team", engineapi.Validation, "label required", nil),
+		*engineapi.RuleSkip("check-env", engineapi.Validation, "", nil),
+	)
+	steps := trace.Steps()
+	assert.Len(t, steps, 1)
+	assert.Equal(t, "fail", steps[0].Result)
+	assert.Equal(t, "3 rule responses", steps[0].Message)
+	assert.Equal(t, map[string]any{"operation": "validate"}, steps[0].Values)
+	assert.Len(t, steps[0].Steps, 1)
+	assert.Equal(t, "fail", steps[0].Steps[0].Result)
+	assert.Equal(t, "label required", steps[0].Steps[0].Message)
+	assert.Len(t, steps[0].Steps[0].Steps, 2)
+	trace.Reset()
+	assert.Empty(t, trace.Steps())
+}
+
+func TestResume(t *testing.T) {
+	trace := NewTrace()
+	ctx := WithTrace(context.TODO(), trace)
+	first, _ := Begin(ctx, TypeResource, "Pod default/nginx")
+	Record(first, TypePolicy, "require-labels", "pass", "", nil)
+	second, resource := Resume(ctx, TypeResource, "Pod default/nginx")
+	Record(second, TypePolicy, "check-images", "fail", "", nil)
+	Resume(ctx, TypeResource, "Pod default/redis")
+	steps := trace.Steps()
+	assert.Len(t, steps, 2)
+	assert.Equal(t, steps[0], resource)
+	assert.Len(t, steps[0].Steps, 2)
+	assert.Equal(t, "check-images", steps[0].Steps[1].Name)
+	assert.Equal(t, "Pod default/redis", steps[1].Name)
+	got, step := Resume(context.TODO(), TypeResource, "Pod default/nginx")
+	assert.Equal(t, context.TODO(), got)
+	assert.Nil(t, step)
+}
+
+func TestBegin_Disabled(t *testing.T) {
+	ctx := context.TODO()
+	assert.False(t, Enabled(ctx))
+	assert.Equal(t, ctx, WithTrace(ctx, nil))
+	got, step := Begin(ctx, TypePolicy, "require-labels")
+	assert.Equal(t, ctx, got)
+	assert.Nil(t, step)
+	// steps methods and recording are no-ops
+	step.Set("operation", "validate")
+	step.End("pass", "")
+	step.EndWithResponses()
+	Record(ctx, TypeMatch, "", Bool(true), "", nil)
+}
+
+func TestWriteTree(t *testing.T) {
+	trace := NewTrace()
+	ctx := WithTrace(context.TODO(), trace)
+	ctx, rule := Begin(ctx, TypeRule, "check-team")
+	Record(ctx, TypeMatch, "", Bool(true), "", nil)
+	Record(ctx, TypeCondition, "all[0] Equals", Bool(false), "", map[string]any{"key": "nginx", "value": "skip-me"})
+	rule.End("skip", "preconditions not met\nname is not skip-me")
+	var out bytes.Buffer
+	WriteTree(&out, trace.Steps())
+	expected := `
+rule check-team: skip (preconditions not met name is not skip-me)
+├─ match: true
+└─ condition all[0] Equals: false
+     key = "nginx"
+     value = "skip-me"`
+	assert.Equal(t, strings.TrimSpace(expected), strings.TrimSpace(out.String()))
+}
+
+func TestWriteJSON(t *testing.T) {
+	var out bytes.Buffer
+	assert.NoError(t, WriteJSON(&out, nil))
+	assert.Equal(t, "[]", strings.TrimSpace(out.String()))
+	out.Reset()
+	trace := NewTrace()
+	Record(WithTrace(context.TODO(), trace), TypeExpression, "validations[0]", Bool(false), "", nil)
+	assert.NoError(t, WriteJSON(&out, trace.Steps()))
+	var steps []*Step
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &steps))
+	assert.Equal(t, []*Step{{Type: TypeExpression, Name: "validations[0]", Result: "false"}}, steps)
+}
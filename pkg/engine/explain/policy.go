@@ -0,0 +1,31 @@
+package explain
+
+import (
+	"context"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// BeginPolicy records the evaluation of a policy in the trace of ctx, subsequent steps are nested in the policy step.
+func BeginPolicy(ctx context.Context, policy metav1.Object) (context.Context, *Step) {
+	if !Enabled(ctx) {
+		return ctx, nil
+	}
+	key, _ := cache.MetaNamespaceKeyFunc(policy)
+	return Begin(ctx, TypePolicy, key)
+}
+
+// RecordMatch records the outcome of the match constraints of a policy in the trace of ctx, the matched resources
+// tell apart the policies generated for pod controllers.
+func RecordMatch(ctx context.Context, rules []admissionregistrationv1.NamedRuleWithOperations, matches bool) {
+	if !Enabled(ctx) {
+		return
+	}
+	var resources []string
+	for _, rule := range rules {
+		resources = append(resources, rule.Resources...)
+	}
+	Record(ctx, TypeMatch, "matchConstraints", Bool(matches), "", map[string]any{"resources": resources})
+}
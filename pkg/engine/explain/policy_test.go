@@ -0,0 +1,36 @@
+package explain
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestBeginPolicy(t *testing.T) {
+	rules := []admissionregistrationv1.NamedRuleWithOperations{{
+		RuleWithOperations: admissionregistrationv1.RuleWithOperations{
+			Rule: admissionregistrationv1.Rule{Resources: []string{"deployments", "statefulsets"}},
+		},
+	}}
+	policy := &metav1.ObjectMeta{Namespace: "default", Name: "require-labels"}
+
+	ctx, step := BeginPolicy(context.TODO(), policy)
+	assert.Nil(t, step)
+	RecordMatch(ctx, rules, true)
+
+	trace := NewTrace()
+	ctx, step = BeginPolicy(WithTrace(context.TODO(), trace), policy)
+	RecordMatch(ctx, rules, false)
+	step.End(Bool(false), "")
+	steps := trace.Steps()
+	assert.Len(t, steps, 1)
+	assert.Equal(t, TypePolicy, steps[0].Type)
+	assert.Equal(t, "default/require-labels", steps[0].Name)
+	assert.Len(t, steps[0].Steps, 1)
+	assert.Equal(t, TypeMatch, steps[0].Steps[0].Type)
+	assert.Equal(t, Bool(false), steps[0].Steps[0].Result)
+	assert.Equal(t, map[string]any{"resources": []string{"deployments", "statefulsets"}}, steps[0].Steps[0].Values)
+}
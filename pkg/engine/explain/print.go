@@ -0,0 +1,82 @@
+package explain
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// WriteTree prints steps as a tree, resolved values are printed below the step they belong to.
+func WriteTree(out io.Writer, steps []*Step) {
+	for _, step := range steps {
+		fmt.Fprintln(out, step.line())
+		writeValues(out, "", step)
+		writeSteps(out, "", step.Steps)
+	}
+}
+
+// WriteJSON prints steps as indented JSON.
+func WriteJSON(out io.Writer, steps []*Step) error {
+	if steps == nil {
+		steps = []*Step{}
+	}
+	data, err := json.MarshalIndent(steps, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(out, string(data))
+	return err
+}
+
+func writeSteps(out io.Writer, prefix string, steps []*Step) {
+	for i, step := range steps {
+		branch, indent := "├─ ", "│  "
+		if i == len(steps)-1 {
+			branch, indent = "└─ ", "   "
+		}
+		fmt.Fprintln(out, prefix+branch+step.line())
+		writeValues(out, prefix+indent, step)
+		writeSteps(out, prefix+indent, step.Steps)
+	}
+}
+
+func writeValues(out io.Writer, prefix string, step *Step) {
+	keys := make([]string, 0, len(step.Values))
+	for key := range step.Values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	indent := "  "
+	if len(step.Steps) > 0 {
+		indent = "│ "
+	}
+	for _, key := range keys {
+		value, err := json.Marshal(step.Values[key])
+		if err != nil {
+			value = []byte(fmt.Sprint(step.Values[key]))
+		}
+		fmt.Fprintf(out, "%s%s%s = %s\n", prefix, indent, key, value)
+	}
+}
+
+func (s *Step) line() string {
+	var b strings.Builder
+	b.WriteString(s.Type)
+	if s.Name != "" {
+		b.WriteString(" ")
+		b.WriteString(s.Name)
+	}
+	if s.Result != "" {
+		b.WriteString(": ")
+		b.WriteString(s.Result)
+	}
+	if s.Message != "" {
+		b.WriteString(" (")
+		// messages can span several lines, the tree prints one line per step
+		b.WriteString(strings.Join(strings.Fields(s.Message), " "))
+		b.WriteString(")")
+	}
+	return b.String()
+}
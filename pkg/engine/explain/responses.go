@@ -0,0 +1,37 @@
+package explain
+
+import (
+	"fmt"
+
+	engineapi "github.com/kyverno/kyverno/pkg/engine/api"
+)
+
+var statusSeverity = map[engineapi.RuleStatus]int{
+	engineapi.RuleStatusSkip:  0,
+	engineapi.RuleStatusPass:  1,
+	engineapi.RuleStatusWarn:  2,
+	engineapi.RuleStatusFail:  3,
+	engineapi.RuleStatusError: 4,
+}
+
+// EndWithResponses sets the result of the step from the rule responses it produced,
+// the result of several responses is the most severe of their statuses.
+func (s *Step) EndWithResponses(responses ...engineapi.RuleResponse) {
+	if s == nil {
+		return
+	}
+	switch len(responses) {
+	case 0:
+		s.End(string(engineapi.RuleStatusSkip), "no rule response")
+	case 1:
+		s.End(string(responses[0].Status()), responses[0].Message())
+	default:
+		status := responses[0].Status()
+		for _, response := range responses[1:] {
+			if statusSeverity[response.Status()] > statusSeverity[status] {
+				status = response.Status()
+			}
+		}
+		s.End(string(status), fmt.Sprintf("%d rule responses", len(responses)))
+	}
+}
@@ -11,6 +11,7 @@ import (
 	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
 	kyvernov2 "github.com/kyverno/kyverno/api/kyverno/v2"
 	engineapi "github.com/kyverno/kyverno/pkg/engine/api"
+	"github.com/kyverno/kyverno/pkg/engine/explain"
 	"github.com/kyverno/kyverno/pkg/engine/handlers"
 	"github.com/kyverno/kyverno/pkg/engine/internal"
 	engineutils "github.com/kyverno/kyverno/pkg/engine/utils"
@@ -131,6 +132,10 @@ func (v *validator) validate(ctx context.Context) *engineapi.RuleResponse {
 		return engineapi.RuleError(v.rule.Name, engineapi.Validation, "failed to load context", err, v.rule.ReportProperties)
 	}
 	preconditionsPassed, msg, err := internal.CheckPreconditions(v.log, v.policyContext.JSONContext(), v.anyAllConditions)
+	// rule preconditions are explained by the engine before the handler is invoked
+	if v.nesting > 0 {
+		internal.ExplainConditions(ctx, v.log, v.policyContext.JSONContext(), "preconditions", v.anyAllConditions, preconditionsPassed, msg, err)
+	}
 	if err != nil {
 		return engineapi.RuleError(v.rule.Name, engineapi.Validation, "failed to evaluate preconditions", err, v.rule.ReportProperties)
 	}
@@ -141,13 +146,13 @@ func (v *validator) validate(ctx context.Context) *engineapi.RuleResponse {
 
 	var ruleResponse *engineapi.RuleResponse
 	if v.deny != nil {
-		ruleResponse = v.validateDeny()
+		ruleResponse = v.validateDeny(ctx)
 	} else if v.pattern != nil || v.anyPattern != nil {
 		if err = v.substitutePatterns(); err != nil {
 			return engineapi.RuleError(v.rule.Name, engineapi.Validation, "variable substitution failed", err, v.rule.ReportProperties)
 		}
 
-		ruleResponse = v.validateResourceWithRule()
+		ruleResponse = v.validateResourceWithRule(ctx)
 	} else if v.forEach != nil {
 		ruleResponse = v.validateForEach(ctx)
 	} else {
@@ -267,11 +272,14 @@ func (v *validator) validateElements(ctx context.Context, foreach kyvernov1.ForE
 			return engineapi.RuleError(v.rule.Name, engineapi.Validation, "failed to create foreach validator", err, v.rule.ReportProperties), applyCount
 		}
 
-		r := foreachValidator.validate(ctx)
+		elementCtx, step := explain.Begin(ctx, explain.TypeForEach, fmt.Sprintf("%s[%d]", foreach.List, index))
+		r := foreachValidator.validate(elementCtx)
 		if r == nil {
+			step.End(string(engineapi.RuleStatusSkip), "empty result")
 			v.log.V(2).Info("skip rule due to empty result")
 			continue
 		}
+		step.EndWithResponses(*r)
 		status := r.Status()
 		if status == engineapi.RuleStatusSkip {
 			v.log.V(2).Info("skip rule", "reason", r.Message())
@@ -306,8 +314,10 @@ func (v *validator) loadContext(ctx context.Context) error {
 	return nil
 }
 
-func (v *validator) validateDeny() *engineapi.RuleResponse {
-	if deny, msg, err := internal.CheckDenyPreconditions(v.log, v.policyContext.JSONContext(), v.deny.GetAnyAllConditions()); err != nil {
+func (v *validator) validateDeny(ctx context.Context) *engineapi.RuleResponse {
+	deny, msg, err := internal.CheckDenyPreconditions(v.log, v.policyContext.JSONContext(), v.deny.GetAnyAllConditions())
+	internal.ExplainConditions(ctx, v.log, v.policyContext.JSONContext(), "deny", v.deny.GetAnyAllConditions(), deny, msg, err)
+	if err != nil {
 		return engineapi.RuleError(v.rule.Name, engineapi.Validation, "failed to check deny conditions", err, v.rule.ReportProperties)
 	} else {
 		if deny {
@@ -340,23 +350,25 @@ func (v *validator) getDenyMessage(deny bool, msg string) string {
 	}
 }
 
-func (v *validator) validateResourceWithRule() *engineapi.RuleResponse {
+func (v *validator) validateResourceWithRule(ctx context.Context) *engineapi.RuleResponse {
 	element := v.policyContext.Element()
 	if !engineutils.IsEmptyUnstructured(&element) {
-		return v.validatePatterns(element)
+		return v.validatePatterns(ctx, element)
 	}
 	if engineutils.IsDeleteRequest(v.policyContext) {
 		v.log.V(3).Info("skipping validation on deleted resource")
 		return nil
 	}
-	resp := v.validatePatterns(v.policyContext.NewResource())
+	resp := v.validatePatterns(ctx, v.policyContext.NewResource())
 	return resp
 }
 
 // validatePatterns validate pattern and anyPattern
-func (v *validator) validatePatterns(resource unstructured.Unstructured) *engineapi.RuleResponse {
+func (v *validator) validatePatterns(ctx context.Context, resource unstructured.Unstructured) *engineapi.RuleResponse {
 	if v.pattern != nil {
-		if err := validate.MatchPattern(v.log, resource.Object, v.pattern); err != nil {
+		err := validate.MatchPattern(v.log, resource.Object, v.pattern)
+		explainPattern(ctx, "", err)
+		if err != nil {
 			pe, ok := err.(*validate.PatternError)
 			if ok {
 				v.log.V(3).Info("validation error", "path", pe.Path, "error", err.Error())
@@ -392,6 +404,7 @@ func (v *validator) validatePatterns(resource unstructured.Unstructured) *engine
 
 		for idx, pattern := range anyPatterns {
 			err := validate.MatchPattern(v.log, resource.Object, pattern)
+			explainPattern(ctx, fmt.Sprintf("anyPattern[%d]", idx), err)
 			if err == nil {
 				msg := fmt.Sprintf("validation rule '%s' anyPattern[%d] passed.", v.rule.Name, idx)
 				return engineapi.RulePass(v.rule.Name, engineapi.Validation, msg, v.rule.ReportProperties)
@@ -438,6 +451,25 @@ func (v *validator) validatePatterns(resource unstructured.Unstructured) *engine
 	return engineapi.RulePass(v.rule.Name, engineapi.Validation, v.rule.Validation.Message, v.rule.ReportProperties)
 }
 
+// explainPattern records the outcome of matching a pattern in the explain trace of ctx.
+func explainPattern(ctx context.Context, name string, err error) {
+	if err == nil {
+		explain.Record(ctx, explain.TypePattern, name, string(engineapi.RuleStatusPass), "", nil)
+		return
+	}
+	result := string(engineapi.RuleStatusFail)
+	var values map[string]any
+	if pe, ok := err.(*validate.PatternError); ok {
+		if pe.Skip {
+			result = string(engineapi.RuleStatusSkip)
+		}
+		if pe.Path != "" {
+			values = map[string]any{"path": pe.Path}
+		}
+	}
+	explain.Record(ctx, explain.TypePattern, name, result, err.Error(), values)
+}
+
 func deserializeAnyPattern(anyPattern apiextensions.JSON) ([]interface{}, error) {
 	if anyPattern == nil {
 		return nil, nil
@@ -0,0 +1,78 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	enginecontext "github.com/kyverno/kyverno/pkg/engine/context"
+	"github.com/kyverno/kyverno/pkg/engine/explain"
+	"github.com/kyverno/kyverno/pkg/engine/utils"
+	"github.com/kyverno/kyverno/pkg/engine/variables"
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+)
+
+// ExplainConditions records the outcome of a conditions block in the explain trace of ctx.
+// Conditions are evaluated again one by one to record their resolved key and value, this only happens when ctx records a trace.
+func ExplainConditions(
+	ctx context.Context,
+	logger logr.Logger,
+	jsonContext enginecontext.Interface,
+	name string,
+	anyAllConditions apiextensions.JSON,
+	result bool,
+	message string,
+	err error,
+) {
+	if !explain.Enabled(ctx) || anyAllConditions == nil {
+		return
+	}
+	ctx, step := explain.Begin(ctx, explain.TypeConditions, name)
+	if err != nil {
+		step.End("error", err.Error())
+		return
+	}
+	step.End(explain.Bool(result), message)
+	typeConditions, err := utils.TransformConditions(anyAllConditions)
+	if err != nil {
+		return
+	}
+	switch typed := typeConditions.(type) {
+	case kyvernov1.AnyAllConditions:
+		explainAnyAllConditions(ctx, logger, jsonContext, typed)
+	case *kyvernov1.AnyAllConditions:
+		explainAnyAllConditions(ctx, logger, jsonContext, *typed)
+	case []kyvernov1.Condition:
+		// conditions lists are evaluated as a logical AND
+		explainConditionList(ctx, logger, jsonContext, "all", typed)
+	}
+}
+
+func explainAnyAllConditions(ctx context.Context, logger logr.Logger, jsonContext enginecontext.Interface, conditions kyvernov1.AnyAllConditions) {
+	if conditions.AnyConditions != nil {
+		explainConditionList(ctx, logger, jsonContext, "any", conditions.AnyConditions)
+	}
+	if conditions.AllConditions != nil {
+		explainConditionList(ctx, logger, jsonContext, "all", conditions.AllConditions)
+	}
+}
+
+func explainConditionList(ctx context.Context, logger logr.Logger, jsonContext enginecontext.Interface, block string, conditions []kyvernov1.Condition) {
+	for i, condition := range conditions {
+		name := fmt.Sprintf("%s[%d] %s", block, i, condition.Operator)
+		values := map[string]any{}
+		if key, err := variables.SubstituteAllInPreconditions(logger, jsonContext, condition.GetKey()); err == nil {
+			values["key"] = key
+		}
+		if value, err := variables.SubstituteAllInPreconditions(logger, jsonContext, condition.GetValue()); err == nil {
+			values["value"] = value
+		}
+		result, message, err := variables.Evaluate(logger, jsonContext, condition)
+		if err != nil {
+			explain.Record(ctx, explain.TypeCondition, name, "error", err.Error(), values)
+		} else {
+			explain.Record(ctx, explain.TypeCondition, name, explain.Bool(result), message, values)
+		}
+	}
+}
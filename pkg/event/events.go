@@ -344,6 +344,38 @@ func NewCleanupPolicyEvent(policy kyvernov2.CleanupPolicyInterface, resource uns
 	}
 }
 
+func NewCleanupPolicyDryRunEvent(policy kyvernov2.CleanupPolicyInterface, count int) Info {
+	return Info{
+		Regarding: corev1.ObjectReference{
+			APIVersion: "kyverno.io/v2",
+			Kind:       policy.GetKind(),
+			Name:       policy.GetName(),
+			Namespace:  policy.GetNamespace(),
+			UID:        policy.GetUID(),
+		},
+		Source:  CleanupController,
+		Action:  None,
+		Reason:  PolicyApplied,
+		Message: fmt.Sprintf("dry run: %d target resource(s) would have been cleaned up", count),
+	}
+}
+
+func NewCleanupPolicyBudgetExceededEvent(policy kyvernov2.CleanupPolicyInterface, err error) Info {
+	return Info{
+		Regarding: corev1.ObjectReference{
+			APIVersion: "kyverno.io/v2",
+			Kind:       policy.GetKind(),
+			Name:       policy.GetName(),
+			Namespace:  policy.GetNamespace(),
+			UID:        policy.GetUID(),
+		},
+		Source:  CleanupController,
+		Action:  None,
+		Reason:  PolicyError,
+		Message: fmt.Sprintf("cleanup run aborted: %v", err),
+	}
+}
+
 func NewValidatingAdmissionPolicyEvent(policy engineapi.GenericPolicy, vapName, vapBindingName string) []Info {
 	regarding := corev1.ObjectReference{
 		// TODO: iirc it's not safe to assume api version is set
@@ -437,6 +469,38 @@ func NewDeletingPolicyEvent(policy v1beta1.DeletingPolicyLike, resource unstruct
 	}
 }
 
+func NewDeletingPolicyDryRunEvent(policy v1beta1.DeletingPolicyLike, count int) Info {
+	return Info{
+		Regarding: corev1.ObjectReference{
+			APIVersion: v1alpha1.SchemeGroupVersion.String(),
+			Kind:       policy.GetKind(),
+			Name:       policy.GetName(),
+			Namespace:  policy.GetNamespace(),
+			UID:        policy.GetUID(),
+		},
+		Source:  CleanupController,
+		Action:  None,
+		Reason:  PolicyApplied,
+		Message: fmt.Sprintf("dry run: %d target resource(s) would have been deleted", count),
+	}
+}
+
+func NewDeletingPolicyBudgetExceededEvent(policy v1beta1.DeletingPolicyLike, err error) Info {
+	return Info{
+		Regarding: corev1.ObjectReference{
+			APIVersion: v1alpha1.SchemeGroupVersion.String(),
+			Kind:       policy.GetKind(),
+			Name:       policy.GetName(),
+			Namespace:  policy.GetNamespace(),
+			UID:        policy.GetUID(),
+		},
+		Source:  CleanupController,
+		Action:  None,
+		Reason:  PolicyError,
+		Message: fmt.Sprintf("deleting run aborted: %v", err),
+	}
+}
+
 func resourceKey(resource unstructured.Unstructured) string {
 	if resource.GetNamespace() != "" {
 		return strings.Join([]string{resource.GetKind(), resource.GetNamespace(), resource.GetName()}, "/")
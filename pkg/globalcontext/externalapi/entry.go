@@ -10,6 +10,7 @@ import (
 	"github.com/go-logr/logr"
 	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
 	kyvernov2alpha1 "github.com/kyverno/kyverno/api/kyverno/v2alpha1"
+	"github.com/kyverno/kyverno/pkg/breaker"
 	"github.com/kyverno/kyverno/pkg/client/clientset/versioned"
 	kyvernov2alpha1listers "github.com/kyverno/kyverno/pkg/client/listers/kyverno/v2alpha1"
 	"github.com/kyverno/kyverno/pkg/engine/apicall"
@@ -30,6 +31,7 @@ type entry struct {
 	err         error
 	stop        func()
 	projections []store.Projection
+	lastRefresh time.Time
 }
 
 func New(
@@ -42,7 +44,7 @@ func New(
 	client apicall.ClientInterface,
 	call kyvernov1.APICall,
 	period time.Duration,
-	maxResponseLength int64,
+	config apicall.APICallConfiguration,
 	shouldUpdateStatus bool,
 	jp jmespath.Interface,
 ) (store.Entry, error) {
@@ -73,12 +75,20 @@ func New(
 		projections: projections,
 	}
 
-	group.StartWithContext(ctx, func(ctx context.Context) {
-		config := apicall.NewAPICallConfiguration(maxResponseLength)
-		caller := apicall.NewExecutor(logger, "globalcontext", client, config)
+	caller := apicall.NewExecutor(logger, "globalcontext", client, config)
+	circuit := breaker.GetCircuitBreaker("globalcontext/" + gce.Name)
+	// trigger is notified when the watched stream reports a change of the data
+	trigger := make(chan struct{}, 1)
 
-		wait.UntilWithContext(ctx, func(ctx context.Context) {
-			if data, err := doCall(ctx, caller, call, gce.Spec.APICall.RetryLimit); err != nil {
+	group.StartWithContext(ctx, func(ctx context.Context) {
+		poll(ctx, period, trigger, func(ctx context.Context) {
+			var data any
+			err := circuit.Do(ctx, func(ctx context.Context) error {
+				var err error
+				data, err = doCall(ctx, caller, call, gce.Spec.APICall.RetryLimit)
+				return err
+			})
+			if err != nil {
 				e.setData(nil, err)
 
 				logger.Error(err, "failed to get data from api caller")
@@ -90,20 +100,45 @@ func New(
 					Namespace:  gce.Namespace,
 					UID:        gce.UID,
 				}, err))
+
+				if shouldUpdateStatus {
+					if updateErr := updateStatus(ctx, gce, kyvernoClient, err); updateErr != nil {
+						logger.Error(updateErr, "failed to update status")
+					}
+				}
 			} else {
 				e.setData(data, nil)
 
 				logger.V(4).Info("api call success", "data", data)
 
 				if shouldUpdateStatus {
-					if updateErr := updateStatus(ctx, gce, kyvernoClient); updateErr != nil {
+					if updateErr := updateStatus(ctx, gce, kyvernoClient, nil); updateErr != nil {
 						logger.Error(updateErr, "failed to update status")
 					}
 				}
 			}
-		}, period)
+		})
 	})
 
+	if gce.Spec.APICall.Watch != nil {
+		watch := gce.Spec.APICall.Watch
+		group.StartWithContext(ctx, func(ctx context.Context) {
+			wait.UntilWithContext(ctx, func(ctx context.Context) {
+				stream, err := caller.Stream(ctx, watch)
+				if err != nil {
+					logger.Error(err, "failed to open watch stream", "url", watch.URL)
+					return
+				}
+				defer stream.Close()
+				// events may have been missed while the stream was not connected
+				notify(trigger)
+				if err := readEvents(stream, func() { notify(trigger) }); err != nil && ctx.Err() == nil {
+					logger.Error(err, "failed to read watch stream", "url", watch.URL)
+				}
+			}, watchRetryPeriod)
+		})
+	}
+
 	return e, nil
 }
 
@@ -157,11 +192,41 @@ func (e *entry) setData(data any, err error) {
 				}
 				e.dataMap[projection.Name] = result
 			}
-			e.err = nil
 		}
+		e.err = nil
+		e.lastRefresh = time.Now()
 	}
 }
 
+func (e *entry) Snapshot() (store.Snapshot, bool) {
+	e.Lock()
+	defer e.Unlock()
+
+	if e.lastRefresh.IsZero() {
+		return store.Snapshot{}, false
+	}
+	data := make(map[string]any, len(e.dataMap))
+	for name, value := range e.dataMap {
+		data[name] = value
+	}
+	return store.Snapshot{
+		Data:            data,
+		LastRefreshTime: e.lastRefresh,
+		LastUpdateTime:  e.lastRefresh,
+	}, true
+}
+
+func (e *entry) LastRefreshTime() time.Time {
+	e.Lock()
+	defer e.Unlock()
+
+	return e.lastRefresh
+}
+
+func (e *entry) LastUpdateTime() time.Time {
+	return e.LastRefreshTime()
+}
+
 func doCall(ctx context.Context, caller apicall.Executor, call kyvernov1.APICall, retryLimit int) (any, error) {
 	var result any
 	backoff := wait.Backoff{
@@ -182,7 +247,7 @@ func doCall(ctx context.Context, caller apicall.Executor, call kyvernov1.APICall
 	return result, retryError
 }
 
-func updateStatus(ctx context.Context, gce *kyvernov2alpha1.GlobalContextEntry, kyvernoClient versioned.Interface) error {
+func updateStatus(ctx context.Context, gce *kyvernov2alpha1.GlobalContextEntry, kyvernoClient versioned.Interface, refreshErr error) error {
 	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
 		// Fetch the latest version of the GlobalContextEntry
 		latest, err := kyvernoClient.KyvernoV2alpha1().GlobalContextEntries().Get(ctx, gce.GetName(), metav1.GetOptions{})
@@ -194,7 +259,11 @@ func updateStatus(ctx context.Context, gce *kyvernov2alpha1.GlobalContextEntry,
 			if latest == nil {
 				return fmt.Errorf("failed to update status: %s", gce.GetName())
 			}
-			latest.Status.UpdateRefreshTime()
+			if refreshErr != nil {
+				latest.Status.AddRefreshError(refreshErr)
+			} else {
+				latest.Status.UpdateRefreshTime()
+			}
 			return nil
 		}, nil)
 	})
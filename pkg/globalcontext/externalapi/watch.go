@@ -0,0 +1,70 @@
+package externalapi
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"strings"
+	"time"
+)
+
+// watchRetryPeriod is the delay before reconnecting a closed watch stream
+const watchRetryPeriod = 5 * time.Second
+
+// poll calls refresh immediately, then every period or as soon as the trigger is notified.
+// The period is reset after every refresh so that polling only acts as a fallback when the trigger is used.
+func poll(ctx context.Context, period time.Duration, trigger <-chan struct{}, refresh func(context.Context)) {
+	timer := time.NewTimer(period)
+	defer timer.Stop()
+	for {
+		refresh(ctx)
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(period)
+		select {
+		case <-ctx.Done():
+			return
+		case <-trigger:
+		case <-timer.C:
+		}
+	}
+}
+
+// notify signals the trigger without blocking, pending notifications are coalesced.
+func notify(trigger chan<- struct{}) {
+	select {
+	case trigger <- struct{}{}:
+	default:
+	}
+}
+
+// readEvents reads a server-sent events stream and calls onEvent for every dispatched event.
+// Comments (used as keep alive) and empty events are ignored.
+// It returns when the stream is closed.
+func readEvents(stream io.Reader, onEvent func()) error {
+	scanner := bufio.NewScanner(stream)
+	pending := false
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			// an empty line dispatches the event
+			if pending {
+				onEvent()
+			}
+			pending = false
+		case strings.HasPrefix(line, ":"):
+			// comment
+		default:
+			field, _, _ := strings.Cut(line, ":")
+			if field == "data" || field == "event" {
+				pending = true
+			}
+		}
+	}
+	return scanner.Err()
+}
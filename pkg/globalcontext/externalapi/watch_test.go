@@ -0,0 +1,73 @@
+package externalapi
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_readEvents(t *testing.T) {
+	tests := []struct {
+		name   string
+		stream string
+		want   int
+	}{{
+		name:   "empty",
+		stream: "",
+		want:   0,
+	}, {
+		name:   "single event",
+		stream: "data: changed\n\n",
+		want:   1,
+	}, {
+		name:   "multi line event",
+		stream: "event: update\ndata: a\ndata: b\n\n",
+		want:   1,
+	}, {
+		name:   "comments are ignored",
+		stream: ": keep-alive\n\n: keep-alive\n\n",
+		want:   0,
+	}, {
+		name:   "undispatched event is ignored",
+		stream: "data: changed\n\ndata: incomplete\n",
+		want:   1,
+	}, {
+		name:   "id only event is ignored",
+		stream: "id: 1\n\nretry: 1000\n\ndata\n\n",
+		want:   1,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			count := 0
+			err := readEvents(strings.NewReader(tt.stream), func() { count++ })
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, count)
+		})
+	}
+}
+
+func Test_poll(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	trigger := make(chan struct{}, 1)
+	refreshed := make(chan struct{})
+	go poll(ctx, time.Hour, trigger, func(context.Context) {
+		refreshed <- struct{}{}
+	})
+	// refreshes immediately
+	select {
+	case <-refreshed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected initial refresh")
+	}
+	// refreshes when triggered
+	notify(trigger)
+	select {
+	case <-refreshed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected triggered refresh")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/go-logr/logr"
 	kyvernov2alpha1 "github.com/kyverno/kyverno/api/kyverno/v2alpha1"
@@ -35,6 +36,13 @@ type entry struct {
 	// Raw data is read directly from the lister to avoid memory duplication
 	projectedMu sync.RWMutex
 	projected   map[string]interface{}
+
+	// data is read from the informer cache, it is up to date from the cache sync until the watch fails
+	stateMu   sync.RWMutex
+	syncedAt  time.Time
+	stoppedAt time.Time
+	// updatedAt is the time of the last informer event, data is only persisted when it moves forward
+	updatedAt time.Time
 }
 
 func New(
@@ -64,23 +72,6 @@ func New(
 		group.Wait()
 	}
 
-	err := informer.Informer().SetWatchErrorHandler(func(r *cache.Reflector, err error) {
-		eventErr := fmt.Errorf("failed to run informer for %s", gvr)
-		eventGen.Add(entryevent.NewErrorEvent(corev1.ObjectReference{
-			APIVersion: gce.APIVersion,
-			Kind:       gce.Kind,
-			Name:       gce.Name,
-			Namespace:  gce.Namespace,
-			UID:        gce.UID,
-		}, eventErr))
-
-		stop()
-	})
-	if err != nil {
-		logger.Error(err, "failed to set watch error handler")
-		return nil, err
-	}
-
 	var projections []store.Projection
 	if len(gce.Spec.Projections) > 0 {
 		for _, p := range gce.Spec.Projections {
@@ -105,18 +96,39 @@ func New(
 		projected:   make(map[string]interface{}),
 	}
 
-	// Only add event handlers if projections are defined
+	err := informer.Informer().SetWatchErrorHandler(func(r *cache.Reflector, err error) {
+		eventErr := fmt.Errorf("failed to run informer for %s", gvr)
+		eventGen.Add(entryevent.NewErrorEvent(corev1.ObjectReference{
+			APIVersion: gce.APIVersion,
+			Kind:       gce.Kind,
+			Name:       gce.Name,
+			Namespace:  gce.Namespace,
+			UID:        gce.UID,
+		}, eventErr))
+
+		e.setStopped()
+		stop()
+	})
+	if err != nil {
+		logger.Error(err, "failed to set watch error handler")
+		return nil, err
+	}
+
+	// Projections are only recomputed if defined
 	// This avoids unnecessary processing when projections are not used
-	if len(projections) > 0 {
-		_, err := informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
-			AddFunc:    func(obj interface{}) { e.recomputeProjections() },
-			UpdateFunc: func(oldObj, newObj interface{}) { e.recomputeProjections() },
-			DeleteFunc: func(obj interface{}) { e.recomputeProjections() },
-		})
-		if err != nil {
-			return nil, err
+	onEvent := func() {
+		e.setUpdated()
+		if len(projections) > 0 {
+			e.recomputeProjections()
 		}
 	}
+	if _, err := informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { onEvent() },
+		UpdateFunc: func(oldObj, newObj interface{}) { onEvent() },
+		DeleteFunc: func(obj interface{}) { onEvent() },
+	}); err != nil {
+		return nil, err
+	}
 
 	group.StartWithContext(ctx, func(ctx context.Context) {
 		informer.Informer().Run(ctx.Done())
@@ -140,9 +152,31 @@ func New(
 		e.recomputeProjections()
 	}
 
+	e.stateMu.Lock()
+	e.syncedAt = time.Now()
+	e.updatedAt = e.syncedAt
+	e.stateMu.Unlock()
+
 	return e, nil
 }
 
+func (e *entry) setUpdated() {
+	e.stateMu.Lock()
+	defer e.stateMu.Unlock()
+	// events received before the cache sync are covered by the sync time
+	if !e.syncedAt.IsZero() {
+		e.updatedAt = time.Now()
+	}
+}
+
+func (e *entry) setStopped() {
+	e.stateMu.Lock()
+	defer e.stateMu.Unlock()
+	if e.stoppedAt.IsZero() {
+		e.stoppedAt = time.Now()
+	}
+}
+
 // listObjects retrieves all objects from the lister and returns them as a slice of map[string]interface{}
 // Since we use DynamicInformer, objects are *unstructured.Unstructured and can be used directly
 func (e *entry) listObjects() ([]interface{}, error) {
@@ -209,6 +243,52 @@ func (e *entry) Get(projection string) (any, error) {
 	return nil, fmt.Errorf("projection %q not found", projection)
 }
 
+func (e *entry) Snapshot() (store.Snapshot, bool) {
+	refreshed := e.LastRefreshTime()
+	updated := e.LastUpdateTime()
+	if refreshed.IsZero() {
+		return store.Snapshot{}, false
+	}
+	list, err := e.listObjects()
+	if err != nil {
+		return store.Snapshot{}, false
+	}
+	e.projectedMu.RLock()
+	defer e.projectedMu.RUnlock()
+
+	data := make(map[string]any, len(e.projected)+1)
+	for name, value := range e.projected {
+		data[name] = value
+	}
+	data[""] = list
+	return store.Snapshot{
+		Data:            data,
+		LastRefreshTime: refreshed,
+		LastUpdateTime:  updated,
+	}, true
+}
+
+func (e *entry) LastRefreshTime() time.Time {
+	e.stateMu.RLock()
+	defer e.stateMu.RUnlock()
+
+	if e.syncedAt.IsZero() {
+		return time.Time{}
+	}
+	if !e.stoppedAt.IsZero() {
+		return e.stoppedAt
+	}
+	return time.Now()
+}
+
+// LastUpdateTime returns the time of the last informer event, or the cache sync time if no event was received since.
+func (e *entry) LastUpdateTime() time.Time {
+	e.stateMu.RLock()
+	defer e.stateMu.RUnlock()
+
+	return e.updatedAt
+}
+
 func (e *entry) Stop() {
 	e.stop()
 }
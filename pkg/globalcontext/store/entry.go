@@ -1,6 +1,15 @@
 package store
 
-import "github.com/kyverno/kyverno/pkg/engine/jmespath"
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kyverno/kyverno/pkg/engine/jmespath"
+)
+
+// ErrStaleData is returned when the entry data is older than the allowed max age.
+var ErrStaleData = errors.New("global context entry data is stale")
 
 type Projection struct {
 	Name string
@@ -11,3 +20,27 @@ type Entry interface {
 	Get(projection string) (any, error)
 	Stop()
 }
+
+// Freshness is implemented by entries tracking when their data was last refreshed.
+type Freshness interface {
+	// LastRefreshTime returns the time the entry data was last refreshed successfully,
+	// the zero time is returned if the entry was never refreshed.
+	LastRefreshTime() time.Time
+}
+
+// GetWithMaxAge returns the entry data for the given projection if the data was refreshed within maxAge.
+// A zero maxAge disables the check, entries not tracking their refresh time are considered fresh.
+func GetWithMaxAge(entry Entry, projection string, maxAge time.Duration) (any, error) {
+	if maxAge > 0 {
+		if freshness, ok := entry.(Freshness); ok {
+			refreshed := freshness.LastRefreshTime()
+			if refreshed.IsZero() {
+				return nil, fmt.Errorf("%w: entry was never refreshed", ErrStaleData)
+			}
+			if age := time.Since(refreshed); age > maxAge {
+				return nil, fmt.Errorf("%w: last refreshed %s ago, max age is %s", ErrStaleData, age.Round(time.Second), maxAge)
+			}
+		}
+	}
+	return entry.Get(projection)
+}
@@ -0,0 +1,90 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const snapshotFileExt = ".json"
+
+type fileBackend struct {
+	dir string
+}
+
+// NewFileBackend returns a Backend storing one json file per entry in the given directory.
+func NewFileBackend(dir string) (Backend, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create snapshot directory %s: %w", dir, err)
+	}
+	return &fileBackend{
+		dir: dir,
+	}, nil
+}
+
+func (b *fileBackend) Load() (map[string]Snapshot, error) {
+	files, err := os.ReadDir(b.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot directory %s: %w", b.dir, err)
+	}
+	snapshots := make(map[string]Snapshot, len(files))
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), snapshotFileExt) {
+			continue
+		}
+		key, err := url.PathUnescape(strings.TrimSuffix(file.Name(), snapshotFileExt))
+		if err != nil {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(b.dir, file.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read snapshot %s: %w", key, err)
+		}
+		var snapshot Snapshot
+		if err := json.Unmarshal(data, &snapshot); err != nil {
+			// a corrupted snapshot must not prevent other entries from loading
+			continue
+		}
+		snapshots[key] = snapshot
+	}
+	return snapshots, nil
+}
+
+func (b *fileBackend) Save(key string, snapshot Snapshot) error {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot %s: %w", key, err)
+	}
+	tmp, err := os.CreateTemp(b.dir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot %s: %w", key, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write snapshot %s: %w", key, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write snapshot %s: %w", key, err)
+	}
+	// rename is atomic, a crash never leaves a partially written snapshot behind
+	if err := os.Rename(tmp.Name(), b.path(key)); err != nil {
+		return fmt.Errorf("failed to write snapshot %s: %w", key, err)
+	}
+	return nil
+}
+
+func (b *fileBackend) Delete(key string) error {
+	if err := os.Remove(b.path(key)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete snapshot %s: %w", key, err)
+	}
+	return nil
+}
+
+func (b *fileBackend) path(key string) string {
+	return filepath.Join(b.dir, url.PathEscape(key)+snapshotFileExt)
+}
@@ -0,0 +1,58 @@
+package store
+
+import (
+	"fmt"
+	"time"
+)
+
+// Snapshot holds the persisted state of an entry.
+type Snapshot struct {
+	// Data holds the entry data indexed by projection name, raw data is stored under the empty name.
+	Data map[string]any `json:"data,omitempty"`
+	// LastRefreshTime is the time the entry data was last refreshed successfully.
+	LastRefreshTime time.Time `json:"lastRefreshTime"`
+	// LastUpdateTime is the time the entry data last changed.
+	LastUpdateTime time.Time `json:"lastUpdateTime,omitempty"`
+}
+
+// Snapshotter is implemented by entries whose data can be persisted.
+type Snapshotter interface {
+	Freshness
+	// LastUpdateTime returns the time the entry data last changed, the zero time is returned if the entry has no data yet.
+	// Unlike LastRefreshTime it only moves forward when the data changes, entries are persisted only when it does.
+	LastUpdateTime() time.Time
+	// Snapshot returns the current entry data, false is returned if the entry has no data yet.
+	Snapshot() (Snapshot, bool)
+}
+
+// Backend persists entry snapshots.
+type Backend interface {
+	Load() (map[string]Snapshot, error)
+	Save(key string, snapshot Snapshot) error
+	Delete(key string) error
+}
+
+// Pruner is implemented by stores holding persisted data for entries that may no longer exist.
+type Pruner interface {
+	// Prune removes the persisted data of entries for which keep returns false.
+	Prune(keep func(key string) bool) error
+}
+
+// snapshotEntry serves the last known data of an entry until the live entry completes its first refresh.
+type snapshotEntry struct {
+	snapshot Snapshot
+}
+
+func (e *snapshotEntry) Get(projection string) (any, error) {
+	data := e.snapshot.Data[projection]
+	if data == nil {
+		return nil, fmt.Errorf("no data available")
+	}
+	return data, nil
+}
+
+func (e *snapshotEntry) Stop() {}
+
+func (e *snapshotEntry) LastRefreshTime() time.Time {
+	return e.snapshot.LastRefreshTime
+}
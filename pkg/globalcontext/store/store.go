@@ -1,7 +1,13 @@
 package store
 
 import (
+	"context"
+	"errors"
 	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/util/wait"
 )
 
 type Store interface {
@@ -10,9 +16,23 @@ type Store interface {
 	Delete(key string)
 }
 
+// PersistentStore is a Store backed by a Backend.
+// Entries implementing Snapshotter are persisted when flushed and
+// the persisted data is served until the live entry completes its first refresh.
+type PersistentStore interface {
+	Store
+	Pruner
+	// Flush persists entries whose data changed since the last flush.
+	Flush() error
+	// Run flushes the store periodically until the context is cancelled.
+	Run(ctx context.Context, logger logr.Logger, period time.Duration)
+}
+
 type store struct {
 	sync.RWMutex
-	store map[string]Entry
+	store     map[string]Entry
+	backend   Backend
+	snapshots map[string]Snapshot
 }
 
 func New() Store {
@@ -21,6 +41,19 @@ func New() Store {
 	}
 }
 
+// NewPersistent returns a PersistentStore warm-started from the snapshots loaded from the given backend.
+func NewPersistent(backend Backend) (PersistentStore, error) {
+	snapshots, err := backend.Load()
+	if err != nil {
+		return nil, err
+	}
+	return &store{
+		store:     make(map[string]Entry),
+		backend:   backend,
+		snapshots: snapshots,
+	}, nil
+}
+
 func (l *store) Set(key string, val Entry) {
 	l.Lock()
 	defer l.Unlock()
@@ -36,6 +69,17 @@ func (l *store) Get(key string) (Entry, bool) {
 	l.RLock()
 	defer l.RUnlock()
 	entry, ok := l.store[key]
+	snapshot, hasSnapshot := l.snapshots[key]
+	if !hasSnapshot {
+		return entry, ok
+	}
+	// serve last known data until the live entry completes its first refresh
+	if !ok {
+		return &snapshotEntry{snapshot: snapshot}, true
+	}
+	if snapshotter, isSnapshotter := entry.(Snapshotter); isSnapshotter && snapshotter.LastRefreshTime().IsZero() {
+		return &snapshotEntry{snapshot: snapshot}, true
+	}
 	return entry, ok
 }
 
@@ -47,4 +91,74 @@ func (l *store) Delete(key string) {
 		entry.Stop()
 	}
 	delete(l.store, key)
+	if l.backend != nil {
+		delete(l.snapshots, key)
+		// best effort, a leftover snapshot is only served until the entry is recreated
+		_ = l.backend.Delete(key)
+	}
+}
+
+func (l *store) Flush() error {
+	if l.backend == nil {
+		return nil
+	}
+	l.RLock()
+	entries := make(map[string]Entry, len(l.store))
+	for key, entry := range l.store {
+		if snapshotter, ok := entry.(Snapshotter); ok {
+			if updated := snapshotter.LastUpdateTime(); !updated.IsZero() && updated.After(l.snapshots[key].LastUpdateTime) {
+				entries[key] = entry
+			}
+		}
+	}
+	l.RUnlock()
+	var errs []error
+	for key, entry := range entries {
+		snapshot, ok := entry.(Snapshotter).Snapshot()
+		if !ok {
+			continue
+		}
+		if err := l.backend.Save(key, snapshot); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		l.Lock()
+		// the entry may have been replaced or deleted while saving
+		if l.store[key] == entry {
+			l.snapshots[key] = snapshot
+		}
+		l.Unlock()
+	}
+	return errors.Join(errs...)
+}
+
+func (l *store) Prune(keep func(key string) bool) error {
+	if l.backend == nil {
+		return nil
+	}
+	l.Lock()
+	defer l.Unlock()
+	var errs []error
+	for key := range l.snapshots {
+		if keep(key) {
+			continue
+		}
+		delete(l.snapshots, key)
+		if err := l.backend.Delete(key); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (l *store) Run(ctx context.Context, logger logr.Logger, period time.Duration) {
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := l.Flush(); err != nil {
+			logger.Error(err, "failed to persist global context entries")
+		}
+	}, period)
+	// persist the latest data before exiting
+	if err := l.Flush(); err != nil {
+		logger.Error(err, "failed to persist global context entries")
+	}
 }
@@ -0,0 +1,146 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeEntry struct {
+	data        map[string]any
+	lastRefresh time.Time
+	lastUpdate  time.Time
+	stopped     bool
+}
+
+func (e *fakeEntry) Get(projection string) (any, error) {
+	return e.data[projection], nil
+}
+
+func (e *fakeEntry) Stop() {
+	e.stopped = true
+}
+
+func (e *fakeEntry) Snapshot() (Snapshot, bool) {
+	if e.lastRefresh.IsZero() {
+		return Snapshot{}, false
+	}
+	return Snapshot{Data: e.data, LastRefreshTime: e.lastRefresh, LastUpdateTime: e.lastUpdate}, true
+}
+
+func (e *fakeEntry) LastRefreshTime() time.Time {
+	return e.lastRefresh
+}
+
+func (e *fakeEntry) LastUpdateTime() time.Time {
+	return e.lastUpdate
+}
+
+func TestFileBackend(t *testing.T) {
+	backend, err := NewFileBackend(t.TempDir())
+	assert.NoError(t, err)
+	refreshed := time.Now().UTC().Truncate(time.Second)
+	assert.NoError(t, backend.Save("foo", Snapshot{Data: map[string]any{"": "bar"}, LastRefreshTime: refreshed}))
+	assert.NoError(t, backend.Save("a/b", Snapshot{Data: map[string]any{"p": 1.0}, LastRefreshTime: refreshed}))
+	snapshots, err := backend.Load()
+	assert.NoError(t, err)
+	assert.Len(t, snapshots, 2)
+	assert.Equal(t, "bar", snapshots["foo"].Data[""])
+	assert.Equal(t, 1.0, snapshots["a/b"].Data["p"])
+	assert.True(t, refreshed.Equal(snapshots["foo"].LastRefreshTime))
+	assert.NoError(t, backend.Delete("foo"))
+	assert.NoError(t, backend.Delete("foo"))
+	snapshots, err = backend.Load()
+	assert.NoError(t, err)
+	assert.Len(t, snapshots, 1)
+}
+
+func TestPersistentStore(t *testing.T) {
+	dir := t.TempDir()
+	backend, err := NewFileBackend(dir)
+	assert.NoError(t, err)
+	s, err := NewPersistent(backend)
+	assert.NoError(t, err)
+	// unknown keys are not found
+	_, ok := s.Get("foo")
+	assert.False(t, ok)
+	// entries are persisted once refreshed
+	s.Set("foo", &fakeEntry{})
+	assert.NoError(t, s.Flush())
+	snapshots, err := backend.Load()
+	assert.NoError(t, err)
+	assert.Empty(t, snapshots)
+	now := time.Now()
+	persisted := &fakeEntry{data: map[string]any{"": "bar"}, lastRefresh: now, lastUpdate: now}
+	s.Set("foo", persisted)
+	assert.NoError(t, s.Flush())
+	// entries refreshed without changes are not persisted again
+	persisted.data = map[string]any{"": "qux"}
+	persisted.lastRefresh = now.Add(time.Minute)
+	assert.NoError(t, s.Flush())
+	// a new store serves the persisted data
+	s, err = NewPersistent(backend)
+	assert.NoError(t, err)
+	entry, ok := s.Get("foo")
+	assert.True(t, ok)
+	data, err := entry.Get("")
+	assert.NoError(t, err)
+	assert.Equal(t, "bar", data)
+	// until the live entry is refreshed
+	live := &fakeEntry{data: map[string]any{"": "baz"}}
+	s.Set("foo", live)
+	entry, _ = s.Get("foo")
+	data, _ = entry.Get("")
+	assert.Equal(t, "bar", data)
+	live.lastRefresh = time.Now()
+	entry, _ = s.Get("foo")
+	data, _ = entry.Get("")
+	assert.Equal(t, "baz", data)
+	// deleted entries are removed from the backend
+	s.Delete("foo")
+	assert.True(t, live.stopped)
+	_, ok = s.Get("foo")
+	assert.False(t, ok)
+	snapshots, err = backend.Load()
+	assert.NoError(t, err)
+	assert.Empty(t, snapshots)
+}
+
+func TestPersistentStorePrune(t *testing.T) {
+	backend, err := NewFileBackend(t.TempDir())
+	assert.NoError(t, err)
+	now := time.Now()
+	assert.NoError(t, backend.Save("foo", Snapshot{Data: map[string]any{"": "bar"}, LastRefreshTime: now, LastUpdateTime: now}))
+	assert.NoError(t, backend.Save("baz", Snapshot{Data: map[string]any{"": "qux"}, LastRefreshTime: now, LastUpdateTime: now}))
+	s, err := NewPersistent(backend)
+	assert.NoError(t, err)
+	// snapshots of entries that no longer exist are removed
+	assert.NoError(t, s.Prune(func(key string) bool { return key == "foo" }))
+	_, ok := s.Get("foo")
+	assert.True(t, ok)
+	_, ok = s.Get("baz")
+	assert.False(t, ok)
+	snapshots, err := backend.Load()
+	assert.NoError(t, err)
+	assert.Len(t, snapshots, 1)
+	assert.Contains(t, snapshots, "foo")
+}
+
+func TestGetWithMaxAge(t *testing.T) {
+	entry := &fakeEntry{data: map[string]any{"": "bar"}}
+	// max age is not checked if zero
+	data, err := GetWithMaxAge(entry, "", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "bar", data)
+	// entries never refreshed are stale
+	_, err = GetWithMaxAge(entry, "", time.Minute)
+	assert.ErrorIs(t, err, ErrStaleData)
+	entry.lastRefresh = time.Now().Add(-time.Hour)
+	_, err = GetWithMaxAge(entry, "", time.Minute)
+	assert.ErrorIs(t, err, ErrStaleData)
+	entry.lastRefresh = time.Now()
+	data, err = GetWithMaxAge(entry, "", time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, "bar", data)
+}
@@ -16,12 +16,14 @@ import (
 	"github.com/kyverno/kyverno/pkg/cel/libs/user"
 	"github.com/kyverno/kyverno/pkg/imageverification/imagedataloader"
 	ivpolvar "github.com/kyverno/kyverno/pkg/imageverification/variables"
+	"github.com/kyverno/kyverno/pkg/imageverifycache"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/apimachinery/pkg/util/version"
 	apiservercel "k8s.io/apiserver/pkg/cel"
 	"k8s.io/apiserver/pkg/cel/environment"
 	k8scorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
 )
 
 var ivpolCompilerVersion = version.MajorMinor(1, 0)
@@ -30,18 +32,41 @@ type Compiler interface {
 	Compile(policiesv1beta1.ImageValidatingPolicyLike, []*policiesv1alpha1.PolicyException) (CompiledPolicy, field.ErrorList)
 }
 
-func NewCompiler(ictx imagedataloader.ImageContext, lister k8scorev1.SecretInterface, reqGVR *metav1.GroupVersionResource) Compiler {
-	return &compilerImpl{
+// Option configures a Compiler.
+type Option func(*compilerImpl)
+
+// WithHTTPConfig configures the requests sent by the http library.
+func WithHTTPConfig(config *http.Config) Option {
+	return func(c *compilerImpl) {
+		c.httpConfig = config
+	}
+}
+
+// WithVerificationCache configures the cache of successful image signature verifications.
+func WithVerificationCache(cache imageverifycache.Client) Option {
+	return func(c *compilerImpl) {
+		c.verificationCache = cache
+	}
+}
+
+func NewCompiler(ictx imagedataloader.ImageContext, lister k8scorev1.SecretInterface, reqGVR *metav1.GroupVersionResource, opts ...Option) Compiler {
+	c := &compilerImpl{
 		ictx:   ictx,
 		lister: lister,
 		reqGVR: reqGVR,
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 type compilerImpl struct {
-	ictx   imagedataloader.ImageContext
-	lister k8scorev1.SecretInterface
-	reqGVR *metav1.GroupVersionResource
+	ictx              imagedataloader.ImageContext
+	lister            k8scorev1.SecretInterface
+	reqGVR            *metav1.GroupVersionResource
+	httpConfig        *http.Config
+	verificationCache imageverifycache.Client
 }
 
 func (c *compilerImpl) Compile(ivpolicy policiesv1beta1.ImageValidatingPolicyLike, exceptions []*policiesv1alpha1.PolicyException) (CompiledPolicy, field.ErrorList) {
@@ -138,7 +163,10 @@ func (c *compilerImpl) Compile(ivpolicy policiesv1beta1.ImageValidatingPolicyLik
 		return nil, allErrs
 	}
 
+	key, _ := cache.MetaNamespaceKeyFunc(ivpolicy)
 	return &compiledPolicy{
+		kind:                 ivpolicy.GetKind(),
+		name:                 key,
 		failurePolicy:        ivpolicy.GetFailurePolicy(),
 		matchConditions:      matchConditions,
 		matchImageReferences: matchImageReferences,
@@ -203,6 +231,7 @@ func (c *compilerImpl) createBaseIvpolEnv(ivpol policiesv1beta1.ImageValidatingP
 					globalcontext.Latest(),
 				),
 				http.Lib(
+					c.httpConfig,
 					http.Latest(),
 				),
 				image.Lib(
@@ -212,7 +241,7 @@ func (c *compilerImpl) createBaseIvpolEnv(ivpol policiesv1beta1.ImageValidatingP
 					imagedata.Latest(),
 				),
 				imageverify.Lib(
-					imageverify.Latest(), c.ictx, ivpol, c.lister,
+					imageverify.Latest(), c.ictx, ivpol, c.lister, c.verificationCache,
 				),
 				resource.Lib(
 					ivpol.GetNamespace(),
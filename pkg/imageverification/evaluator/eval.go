@@ -7,6 +7,8 @@ import (
 	policiesv1alpha1 "github.com/kyverno/kyverno/api/policies.kyverno.io/v1alpha1"
 	"github.com/kyverno/kyverno/api/policies.kyverno.io/v1beta1"
 	policiesv1beta1 "github.com/kyverno/kyverno/api/policies.kyverno.io/v1beta1"
+	engineapi "github.com/kyverno/kyverno/pkg/engine/api"
+	"github.com/kyverno/kyverno/pkg/engine/explain"
 	"github.com/kyverno/kyverno/pkg/imageverification/imagedataloader"
 	admissionv1 "k8s.io/api/admission/v1"
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
@@ -15,6 +17,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apiserver/pkg/admission"
 	k8scorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
 )
 
 type CompiledImageValidatingPolicy struct {
@@ -46,7 +49,10 @@ func Evaluate(ctx context.Context, ivpols []*CompiledImageValidatingPolicy, requ
 			return nil, fmt.Errorf("failed to compile policy %v", errList)
 		}
 
-		result, err := p.Evaluate(ctx, ictx, admissionAttr, request, namespace, isAdmissionRequest, nil)
+		key, _ := cache.MetaNamespaceKeyFunc(ivpol.Policy)
+		policyCtx, step := explain.Begin(ctx, explain.TypePolicy, key)
+		result, err := p.Evaluate(policyCtx, ictx, admissionAttr, request, namespace, isAdmissionRequest, nil)
+		explainResult(step, result, err)
 		if err != nil {
 			return nil, err
 		}
@@ -55,6 +61,24 @@ func Evaluate(ctx context.Context, ivpols []*CompiledImageValidatingPolicy, requ
 	return results, nil
 }
 
+// explainResult ends the explain step of a policy with the result of its evaluation.
+func explainResult(step *explain.Step, result *EvaluationResult, err error) {
+	switch {
+	case err != nil:
+		step.End(string(engineapi.RuleStatusError), err.Error())
+	case result == nil:
+		step.End(string(engineapi.RuleStatusSkip), "match conditions not met")
+	case result.Error != nil:
+		step.End(string(engineapi.RuleStatusError), result.Error.Error())
+	case len(result.Exceptions) > 0:
+		step.End(string(engineapi.RuleStatusSkip), "policy exception")
+	case result.Result:
+		step.End(string(engineapi.RuleStatusPass), "")
+	default:
+		step.End(string(engineapi.RuleStatusFail), result.Message)
+	}
+}
+
 func isK8s(request interface{}) bool {
 	_, ok := request.(*admissionv1.AdmissionRequest)
 	return ok
@@ -42,6 +42,8 @@ type CompiledPolicy interface {
 }
 
 type compiledPolicy struct {
+	kind                 string
+	name                 string
 	failurePolicy        admissionregistrationv1.FailurePolicyType
 	matchConditions      []cel.Program
 	matchImageReferences []engine.MatchImageReference
@@ -56,7 +58,8 @@ type compiledPolicy struct {
 }
 
 func (c *compiledPolicy) Evaluate(ctx context.Context, ictx imagedataloader.ImageContext, attr admission.Attributes, request interface{}, namespace runtime.Object, isK8s bool, context libs.Context) (*EvaluationResult, error) {
-	matched, err := c.match(ctx, attr, request, namespace, c.matchConditions)
+	tracer := engine.NewTracer(ctx, c.kind, c.name)
+	matched, err := c.match(ctx, attr, request, namespace, c.matchConditions, tracer)
 	if err != nil {
 		return nil, err
 	}
@@ -67,7 +70,7 @@ func (c *compiledPolicy) Evaluate(ctx context.Context, ictx imagedataloader.Imag
 	if len(c.exceptions) > 0 {
 		matchedExceptions := make([]*policiesv1alpha1.PolicyException, 0)
 		for _, polex := range c.exceptions {
-			match, err := c.match(ctx, attr, request, namespace, polex.MatchConditions)
+			match, err := c.match(ctx, attr, request, namespace, polex.MatchConditions, nil)
 			if err != nil {
 				return nil, err
 			}
@@ -83,7 +86,9 @@ func (c *compiledPolicy) Evaluate(ctx context.Context, ictx imagedataloader.Imag
 	vars := lazy.NewMapValue(engine.VariablesType)
 	for name, variable := range c.variables {
 		vars.Append(name, func(*lazy.MapValue) ref.Val {
+			start := tracer.Start()
 			out, _, err := variable.ContextEval(ctx, data)
+			tracer.Variable(name, start, out, err)
 			if out != nil {
 				return out
 			}
@@ -153,12 +158,16 @@ func (c *compiledPolicy) Evaluate(ctx context.Context, ictx imagedataloader.Imag
 		return nil, err
 	}
 	for i, v := range c.validations {
+		start := tracer.Start()
 		out, _, err := v.Program.ContextEval(ctx, data)
 		if err != nil {
+			tracer.Done(fmt.Sprintf("validations[%d]", i), start, false, err)
 			return nil, err
 		}
+		outcome, err := utils.ConvertToNative[bool](out)
+		tracer.Done(fmt.Sprintf("validations[%d]", i), start, err == nil && outcome, err)
 		// evaluate only when rule fails
-		if outcome, err := utils.ConvertToNative[bool](out); err == nil && !outcome {
+		if err == nil && !outcome {
 			message := v.Message
 			if v.MessageExpression != nil {
 				if out, _, err := v.MessageExpression.ContextEval(ctx, data); err != nil {
@@ -202,6 +211,7 @@ func (p *compiledPolicy) match(
 	request interface{},
 	namespace runtime.Object,
 	matchConditions []cel.Program,
+	tracer *engine.Tracer,
 ) (bool, error) {
 	data := make(map[string]any)
 	if isK8s(request) {
@@ -229,16 +239,19 @@ func (p *compiledPolicy) match(
 		data[engine.ObjectKey] = request
 	}
 	var errs []error
-	for _, matchCondition := range matchConditions {
+	for index, matchCondition := range matchConditions {
 		// evaluate the condition
+		start := tracer.Start()
 		out, _, err := matchCondition.ContextEval(ctx, data)
 		// check error
 		if err != nil {
+			tracer.Done(fmt.Sprintf("matchConditions[%d]", index), start, false, err)
 			errs = append(errs, err)
 			continue
 		}
 		// try to convert to a bool
 		result, err := utils.ConvertToNative[bool](out)
+		tracer.Done(fmt.Sprintf("matchConditions[%d]", index), start, err == nil && result, err)
 		// check error
 		if err != nil {
 			errs = append(errs, err)
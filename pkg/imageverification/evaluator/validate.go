@@ -88,13 +88,13 @@ func MakeImageVerifyOutcomePatch(hasAnnotations bool, responses map[string]Image
 	return patches, nil
 }
 
-func Validate(ivpol policiesv1beta1.ImageValidatingPolicyLike, lister k8scorev1.SecretInterface) ([]string, error) {
+func Validate(ivpol policiesv1beta1.ImageValidatingPolicyLike, lister k8scorev1.SecretInterface, opts ...Option) ([]string, error) {
 	ictx, er := imagedataloader.NewImageContext(lister)
 	if er != nil {
 		return nil, nil
 	}
 
-	compiler := NewCompiler(ictx, lister, nil)
+	compiler := NewCompiler(ictx, lister, nil, opts...)
 	_, err := compiler.Compile(ivpol, nil)
 	if err == nil {
 		return nil, nil
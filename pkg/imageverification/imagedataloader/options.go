@@ -10,6 +10,7 @@ import (
 	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/kyverno/kyverno/pkg/breaker"
 	"github.com/kyverno/kyverno/pkg/tracing"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
@@ -102,7 +103,7 @@ func makeBaseOptions(opts ...Option) []remote.Option {
 	}
 
 	remoteOpts = append(remoteOpts,
-		remote.WithTransport(transport),
+		remote.WithTransport(breaker.Transport("registry", transport)),
 		remote.WithUserAgent(UserAgent),
 	)
 
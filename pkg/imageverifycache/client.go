@@ -2,11 +2,16 @@ package imageverifycache
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
 	"time"
 
 	"github.com/dgraph-io/ristretto"
 	"github.com/go-logr/logr"
 	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/metrics"
 )
 
 const (
@@ -20,6 +25,7 @@ type cache struct {
 	maxSize        int64
 	ttl            time.Duration
 	cache          *ristretto.Cache
+	store          Store
 }
 
 type Option = func(*cache) error
@@ -87,8 +93,45 @@ func WithTTLDuration(t time.Duration) Option {
 	}
 }
 
+// WithStore persists verified images in the given store, in addition to the in-memory cache.
+func WithStore(s Store) Option {
+	return func(c *cache) error {
+		c.store = s
+		return nil
+	}
+}
+
+// generateKey identifies a verified image for a rule, the key changes when the image verification
+// configuration of the rule changes so that stale entries are never used.
 func generateKey(policy kyvernov1.PolicyInterface, ruleName string, imageRef string) string {
-	return string(policy.GetUID()) + ";" + policy.GetResourceVersion() + ";" + ruleName + ";" + imageRef
+	return string(policy.GetUID()) + ";" + verifyImagesHash(policy, ruleName) + ";" + ruleName + ";" + imageRef
+}
+
+// verifyImagesHash returns a hash of the image verification configuration of the rule,
+// or the policy resource version when the rule can't be found.
+func verifyImagesHash(policy kyvernov1.PolicyInterface, ruleName string) string {
+	spec := policy.GetSpec()
+	// autogen rules share the configuration of the rule they were generated from
+	for _, name := range []string{ruleName, strings.TrimPrefix(ruleName, "autogen-cronjob-"), strings.TrimPrefix(ruleName, "autogen-")} {
+		for _, rule := range spec.Rules {
+			if rule.Name != name {
+				continue
+			}
+			data, err := json.Marshal(rule.VerifyImages)
+			if err != nil {
+				return policy.GetResourceVersion()
+			}
+			sum := sha256.Sum256(data)
+			return hex.EncodeToString(sum[:])
+		}
+	}
+	return policy.GetResourceVersion()
+}
+
+// storeKey returns a fixed length key valid in any store.
+func storeKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
 }
 
 func (c *cache) Set(ctx context.Context, policy kyvernov1.PolicyInterface, ruleName string, imageRef string, useCache bool) (bool, error) {
@@ -99,14 +142,7 @@ func (c *cache) Set(ctx context.Context, policy kyvernov1.PolicyInterface, ruleN
 		// Else If enabled globally then return if locally disabled
 		return false, nil
 	}
-	key := generateKey(policy, ruleName, imageRef)
-
-	stored := c.cache.SetWithTTL(key, nil, 1, c.ttl)
-	c.cache.Wait()
-	if stored {
-		return true, nil
-	}
-	return false, nil
+	return c.set(ctx, generateKey(policy, ruleName, imageRef), c.ttl)
 }
 
 func (c *cache) Get(ctx context.Context, policy kyvernov1.PolicyInterface, ruleName string, imageRef string, useCache bool) (bool, error) {
@@ -117,10 +153,71 @@ func (c *cache) Get(ctx context.Context, policy kyvernov1.PolicyInterface, ruleN
 		// Else If enabled globally then return if locally disabled
 		return false, nil
 	}
-	key := generateKey(policy, ruleName, imageRef)
+	policyType := "ClusterPolicy"
+	if policy.IsNamespaced() {
+		policyType = "Policy"
+	}
+	return c.get(ctx, generateKey(policy, ruleName, imageRef), policyType)
+}
+
+func (c *cache) SetVerification(ctx context.Context, key VerificationKey, ttl time.Duration) (bool, error) {
+	if !c.isCacheEnabled || key.PolicyUID == "" {
+		return false, nil
+	}
+	if ttl <= 0 || ttl > c.ttl {
+		ttl = c.ttl
+	}
+	return c.set(ctx, key.String(), ttl)
+}
+
+func (c *cache) GetVerification(ctx context.Context, key VerificationKey) (bool, error) {
+	if !c.isCacheEnabled || key.PolicyUID == "" {
+		return false, nil
+	}
+	return c.get(ctx, key.String(), "ImageValidatingPolicy")
+}
+
+func (c *cache) set(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	stored := c.cache.SetWithTTL(key, nil, 1, ttl)
+	c.cache.Wait()
+	if c.store != nil {
+		if err := c.store.Set(ctx, storeKey(key), time.Now().Add(ttl)); err != nil {
+			return stored, err
+		}
+		return true, nil
+	}
+	if stored {
+		return true, nil
+	}
+	return false, nil
+}
+
+func (c *cache) get(ctx context.Context, key string, policyType string) (bool, error) {
+	found, err := c.lookup(ctx, key)
+	if err == nil {
+		if m := metrics.GetImageVerifyCacheMetrics(); m != nil {
+			m.RecordLookup(ctx, policyType, found)
+		}
+	}
+	return found, err
+}
+
+func (c *cache) lookup(ctx context.Context, key string) (bool, error) {
 	_, found := c.cache.Get(key)
 	if found {
 		return true, nil
 	}
+	if c.store != nil {
+		expiry, found, err := c.store.Get(ctx, storeKey(key))
+		if err != nil {
+			return false, err
+		}
+		if ttl := time.Until(expiry); found && ttl > 0 {
+			// entries verified by another replica or before a restart are kept in memory until they expire
+			c.cache.SetWithTTL(key, nil, 1, ttl)
+			c.cache.Wait()
+			return true, nil
+		}
+	}
 	return false, nil
 }
@@ -0,0 +1,94 @@
+package imageverifycache
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newPolicy(resourceVersion string, key string) *kyvernov1.ClusterPolicy {
+	return &kyvernov1.ClusterPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "verify", UID: "uid", ResourceVersion: resourceVersion},
+		Spec: kyvernov1.Spec{
+			Rules: []kyvernov1.Rule{{
+				Name: "check-image",
+				VerifyImages: []kyvernov1.ImageVerification{{
+					ImageReferences: []string{"ghcr.io/*"},
+					Attestors: []kyvernov1.AttestorSet{{
+						Entries: []kyvernov1.Attestor{{
+							Keys: &kyvernov1.StaticKeyAttestor{PublicKeys: key},
+						}},
+					}},
+				}},
+			}},
+		},
+	}
+}
+
+func newTestCache(t *testing.T, store Store) Client {
+	c, err := New(
+		WithCacheEnableFlag(true),
+		WithMaxSize(100),
+		WithTTLDuration(time.Hour),
+		WithStore(store),
+	)
+	assert.NoError(t, err)
+	return c
+}
+
+func Test_cache_store(t *testing.T) {
+	ctx := context.TODO()
+	store, err := NewFileStore(filepath.Join(t.TempDir(), "cache.json"), 100)
+	assert.NoError(t, err)
+	policy := newPolicy("1", "key-1")
+	set, err := newTestCache(t, store).Set(ctx, policy, "check-image", "ghcr.io/kyverno/app:v1", true)
+	assert.NoError(t, err)
+	assert.True(t, set)
+	// a new cache sharing the store finds the entry
+	c := newTestCache(t, store)
+	found, err := c.Get(ctx, policy, "check-image", "ghcr.io/kyverno/app:v1", true)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	// changes not affecting attestors keep the entry
+	found, err = c.Get(ctx, newPolicy("2", "key-1"), "check-image", "ghcr.io/kyverno/app:v1", true)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	// changes to attestors invalidate the entry
+	found, err = c.Get(ctx, newPolicy("3", "key-2"), "check-image", "ghcr.io/kyverno/app:v1", true)
+	assert.NoError(t, err)
+	assert.False(t, found)
+	found, err = c.Get(ctx, policy, "check-image", "ghcr.io/kyverno/app:v2", true)
+	assert.NoError(t, err)
+	assert.False(t, found)
+}
+
+func Test_cache_verification(t *testing.T) {
+	ctx := context.TODO()
+	c := newTestCache(t, nil)
+	key := VerificationKey{PolicyUID: "uid", Generation: 1, Attestor: "notary", Digest: "sha256:4f5c8e0f1c3d"}
+	found, err := c.GetVerification(ctx, key)
+	assert.NoError(t, err)
+	assert.False(t, found)
+	set, err := c.SetVerification(ctx, key, time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, set)
+	found, err = c.GetVerification(ctx, key)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	key.Generation = 2
+	found, err = c.GetVerification(ctx, key)
+	assert.NoError(t, err)
+	assert.False(t, found)
+	// policies without uid are not cached
+	set, err = c.SetVerification(ctx, VerificationKey{Digest: "sha256:4f5c8e0f1c3d"}, time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, set)
+	found, err = DisabledImageVerifyCache().GetVerification(ctx, key)
+	assert.NoError(t, err)
+	assert.False(t, found)
+}
@@ -2,8 +2,11 @@ package imageverifycache
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 type Client interface {
@@ -15,4 +18,26 @@ type Client interface {
 	// Get Searches for the image verified using the rule in the policy in the cache
 	// Returns true when the cache entry is found
 	Get(ctx context.Context, policy kyvernov1.PolicyInterface, ruleName string, imagerRef string, useCache bool) (bool, error)
+
+	// SetVerification adds the verification of an image digest by an attestor of an ImageValidatingPolicy to the cache
+	// The entry expires after ttl, or the configured TTL when ttl is 0 or larger
+	// Returns true when the cache entry is added
+	SetVerification(ctx context.Context, key VerificationKey, ttl time.Duration) (bool, error)
+
+	// GetVerification searches for the verification of an image digest by an attestor of an ImageValidatingPolicy in the cache
+	// Returns true when the cache entry is found
+	GetVerification(ctx context.Context, key VerificationKey) (bool, error)
+}
+
+// VerificationKey identifies the verification of an image digest by an attestor of an ImageValidatingPolicy.
+// Entries are invalidated when the policy generation changes.
+type VerificationKey struct {
+	PolicyUID  types.UID
+	Generation int64
+	Attestor   string
+	Digest     string
+}
+
+func (k VerificationKey) String() string {
+	return fmt.Sprintf("ivpol;%s;%d;%s;%s", k.PolicyUID, k.Generation, k.Attestor, k.Digest)
 }
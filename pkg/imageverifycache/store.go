@@ -0,0 +1,227 @@
+package imageverifycache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+// Store persists verified cache entries so that they survive restarts and can be shared between replicas.
+// Entries are trusted as is: anyone able to write the backing file or ConfigMap can mark any image as verified,
+// access to them must be restricted like access to Kyverno itself.
+type Store interface {
+	// Get returns the expiry time of the entry, the bool is false if the entry doesn't exist
+	Get(ctx context.Context, key string) (time.Time, bool, error)
+	// Set stores the entry until the expiry time
+	Set(ctx context.Context, key string, expiry time.Time) error
+}
+
+// entries maps keys to their expiry time.
+type entries map[string]time.Time
+
+// prune removes expired entries and evicts the entries expiring first until at most maxSize remain.
+func (e entries) prune(now time.Time, maxSize int64) {
+	for key, expiry := range e {
+		if !expiry.After(now) {
+			delete(e, key)
+		}
+	}
+	if maxSize <= 0 || int64(len(e)) <= maxSize {
+		return
+	}
+	keys := make([]string, 0, len(e))
+	for key := range e {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return e[keys[i]].Before(e[keys[j]])
+	})
+	for _, key := range keys[:int64(len(keys))-maxSize] {
+		delete(e, key)
+	}
+}
+
+type fileStore struct {
+	lock    sync.Mutex
+	path    string
+	maxSize int64
+	info    os.FileInfo
+	entries entries
+	now     func() time.Time
+}
+
+// NewFileStore creates a store persisting entries in a local file.
+// The file is reloaded when it changes, it can be shared between replicas using a shared volume.
+func NewFileStore(path string, maxSize int64) (Store, error) {
+	s := &fileStore{
+		path:    path,
+		maxSize: maxSize,
+		entries: entries{},
+		now:     time.Now,
+	}
+	if err := s.reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *fileStore) Get(_ context.Context, key string) (time.Time, bool, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if err := s.reload(); err != nil {
+		return time.Time{}, false, err
+	}
+	expiry, ok := s.entries[key]
+	return expiry, ok, nil
+}
+
+func (s *fileStore) Set(_ context.Context, key string, expiry time.Time) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if err := s.reload(); err != nil {
+		return err
+	}
+	s.entries[key] = expiry
+	s.entries.prune(s.now(), s.maxSize)
+	data, err := json.Marshal(s.entries)
+	if err != nil {
+		return err
+	}
+	// write to a temporary file first so that readers never see a partial file
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return err
+	}
+	if info, err := os.Stat(s.path); err == nil {
+		s.info = info
+	}
+	return nil
+}
+
+// reload reads the file again if it was modified since it was last read.
+func (s *fileStore) reload() error {
+	info, err := os.Stat(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	// files are replaced on every write, comparing the file identity detects changes made within the timestamp granularity
+	if s.info != nil && os.SameFile(info, s.info) && info.ModTime().Equal(s.info.ModTime()) && info.Size() == s.info.Size() {
+		return nil
+	}
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return err
+	}
+	loaded := entries{}
+	if len(data) != 0 {
+		if err := json.Unmarshal(data, &loaded); err != nil {
+			return fmt.Errorf("failed to decode image verify cache file %s: %w", s.path, err)
+		}
+	}
+	s.entries = loaded
+	s.info = info
+	return nil
+}
+
+type configMapStore struct {
+	client  corev1client.ConfigMapInterface
+	lister  corev1listers.ConfigMapNamespaceLister
+	name    string
+	maxSize int64
+	now     func() time.Time
+}
+
+// NewConfigMapStore creates a store persisting entries in a ConfigMap shared by all replicas.
+// Entries are read from lister, writes go through client.
+func NewConfigMapStore(client corev1client.ConfigMapInterface, lister corev1listers.ConfigMapNamespaceLister, name string, maxSize int64) Store {
+	return &configMapStore{
+		client:  client,
+		lister:  lister,
+		name:    name,
+		maxSize: maxSize,
+		now:     time.Now,
+	}
+}
+
+func (s *configMapStore) Get(_ context.Context, key string) (time.Time, bool, error) {
+	cm, err := s.lister.Get(s.name)
+	if apierrors.IsNotFound(err) {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	value, ok := cm.Data[key]
+	if !ok {
+		return time.Time{}, false, nil
+	}
+	expiry, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid expiry for image verify cache entry %s: %w", key, err)
+	}
+	return expiry, true, nil
+}
+
+func (s *configMapStore) Set(ctx context.Context, key string, expiry time.Time) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm, err := s.client.Get(ctx, s.name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			cm = &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: s.name},
+				Data: map[string]string{
+					key: expiry.UTC().Format(time.RFC3339),
+				},
+			}
+			_, err = s.client.Create(ctx, cm, metav1.CreateOptions{})
+			if apierrors.IsAlreadyExists(err) {
+				// another replica created it, retry as a conflict
+				return apierrors.NewConflict(corev1.Resource("configmaps"), s.name, err)
+			}
+			return err
+		}
+		if err != nil {
+			return err
+		}
+		stored := entries{}
+		for k, v := range cm.Data {
+			if t, err := time.Parse(time.RFC3339, v); err == nil {
+				stored[k] = t
+			}
+		}
+		stored[key] = expiry
+		stored.prune(s.now(), s.maxSize)
+		cm.Data = make(map[string]string, len(stored))
+		for k, t := range stored {
+			cm.Data[k] = t.UTC().Format(time.RFC3339)
+		}
+		_, err = s.client.Update(ctx, cm, metav1.UpdateOptions{})
+		return err
+	})
+}
@@ -0,0 +1,88 @@
+package imageverifycache
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_entries_prune(t *testing.T) {
+	now := time.Now()
+	e := entries{
+		"expired": now.Add(-time.Minute),
+		"first":   now.Add(time.Minute),
+		"second":  now.Add(2 * time.Minute),
+		"third":   now.Add(3 * time.Minute),
+	}
+	e.prune(now, 2)
+	assert.Equal(t, entries{
+		"second": now.Add(2 * time.Minute),
+		"third":  now.Add(3 * time.Minute),
+	}, e)
+}
+
+func testStore(t *testing.T, first, second Store) {
+	ctx := context.TODO()
+	expiry := time.Now().Add(time.Hour).Truncate(time.Second)
+	_, found, err := first.Get(ctx, "key")
+	assert.NoError(t, err)
+	assert.False(t, found)
+	assert.NoError(t, first.Set(ctx, "key", expiry))
+	// entries are visible to other instances sharing the same storage
+	got, found, err := second.Get(ctx, "key")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.True(t, expiry.Equal(got))
+	assert.NoError(t, second.Set(ctx, "other", expiry))
+	_, found, err = first.Get(ctx, "other")
+	assert.NoError(t, err)
+	assert.True(t, found)
+}
+
+func TestFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	first, err := NewFileStore(path, 10)
+	assert.NoError(t, err)
+	second, err := NewFileStore(path, 10)
+	assert.NoError(t, err)
+	testStore(t, first, second)
+	// entries survive restarts
+	restarted, err := NewFileStore(path, 10)
+	assert.NoError(t, err)
+	_, found, err := restarted.Get(context.TODO(), "key")
+	assert.NoError(t, err)
+	assert.True(t, found)
+}
+
+func TestConfigMapStore(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	kubeClient := fake.NewSimpleClientset()
+	factory := kubeinformers.NewSharedInformerFactoryWithOptions(kubeClient, 0, kubeinformers.WithNamespace("kyverno"))
+	lister := factory.Core().V1().ConfigMaps().Lister().ConfigMaps("kyverno")
+	factory.Start(ctx.Done())
+	factory.WaitForCacheSync(ctx.Done())
+	client := kubeClient.CoreV1().ConfigMaps("kyverno")
+	first := NewConfigMapStore(client, lister, "cache", 10)
+	second := NewConfigMapStore(client, lister, "cache", 10)
+	expiry := time.Now().Add(time.Hour).Truncate(time.Second)
+	_, found, err := first.Get(ctx, "key")
+	assert.NoError(t, err)
+	assert.False(t, found)
+	assert.NoError(t, first.Set(ctx, "key", expiry))
+	assert.NoError(t, second.Set(ctx, "other", expiry))
+	// entries are read from the informer cache
+	assert.Eventually(t, func() bool {
+		got, found, err := second.Get(ctx, "key")
+		return err == nil && found && expiry.Equal(got)
+	}, time.Second, 10*time.Millisecond)
+	assert.Eventually(t, func() bool {
+		_, found, err := first.Get(ctx, "other")
+		return err == nil && found
+	}, time.Second, 10*time.Millisecond)
+}
@@ -19,6 +19,7 @@ func GetBreakerMetrics() BreakerMetrics {
 type breakerMetrics struct {
 	drops metric.Int64Counter
 	total metric.Int64Counter
+	state metric.Int64Gauge
 
 	logger logr.Logger
 }
@@ -26,6 +27,7 @@ type breakerMetrics struct {
 type BreakerMetrics interface {
 	RecordTotalIncrease(ctx context.Context, name string)
 	RecordDrop(ctx context.Context, name string)
+	RecordState(ctx context.Context, name string, state int64)
 }
 
 func (m *breakerMetrics) init(meter metric.Meter) {
@@ -45,6 +47,13 @@ func (m *breakerMetrics) init(meter metric.Meter) {
 	if err != nil {
 		m.logger.Error(err, "Failed to create instrument, kyverno_breaker_total")
 	}
+	m.state, err = meter.Int64Gauge(
+		"kyverno_breaker_state",
+		metric.WithDescription("track the state of circuit breakers (0 closed, 1 half-open, 2 open)"),
+	)
+	if err != nil {
+		m.logger.Error(err, "Failed to create instrument, kyverno_breaker_state")
+	}
 }
 
 func (m *breakerMetrics) RecordTotalIncrease(ctx context.Context, breakerName string) {
@@ -62,3 +71,11 @@ func (m *breakerMetrics) RecordDrop(ctx context.Context, breakerName string) {
 
 	m.drops.Add(ctx, 1, metric.WithAttributes(attribute.String("circuit_name", breakerName)))
 }
+
+func (m *breakerMetrics) RecordState(ctx context.Context, breakerName string, state int64) {
+	if m.state == nil {
+		return
+	}
+
+	m.state.Record(ctx, state, metric.WithAttributes(attribute.String("circuit_name", breakerName)))
+}
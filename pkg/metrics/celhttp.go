@@ -0,0 +1,70 @@
+package metrics
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+func GetCELHTTPMetrics() CELHTTPMetrics {
+	if metricsConfig == nil {
+		return nil
+	}
+
+	return metricsConfig.CELHTTPMetrics()
+}
+
+type celHTTPMetrics struct {
+	blocked metric.Int64Counter
+	cache   metric.Int64Counter
+
+	logger logr.Logger
+}
+
+type CELHTTPMetrics interface {
+	RecordBlocked(ctx context.Context, reason string)
+	RecordCache(ctx context.Context, hit bool)
+}
+
+func (m *celHTTPMetrics) init(meter metric.Meter) {
+	var err error
+
+	m.blocked, err = meter.Int64Counter(
+		"kyverno_cel_http_blocked_requests",
+		metric.WithDescription("can be used to track the number of http requests from policies blocked by the egress configuration"),
+	)
+	if err != nil {
+		m.logger.Error(err, "Failed to create instrument, kyverno_cel_http_blocked_requests")
+	}
+	m.cache, err = meter.Int64Counter(
+		"kyverno_cel_http_cache_lookups",
+		metric.WithDescription("can be used to track the hit/miss ratio of the http response cache used by policies"),
+	)
+	if err != nil {
+		m.logger.Error(err, "Failed to create instrument, kyverno_cel_http_cache_lookups")
+	}
+}
+
+func (m *celHTTPMetrics) RecordBlocked(ctx context.Context, reason string) {
+	if m.blocked == nil {
+		return
+	}
+
+	m.blocked.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("reason", reason),
+	))
+}
+
+func (m *celHTTPMetrics) RecordCache(ctx context.Context, hit bool) {
+	if m.cache == nil {
+		return
+	}
+
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	m.cache.Add(ctx, 1, metric.WithAttributes(attribute.String("result", result)))
+}
@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+func GetImageVerifyCacheMetrics() ImageVerifyCacheMetrics {
+	if metricsConfig == nil {
+		return nil
+	}
+
+	return metricsConfig.ImageVerifyCacheMetrics()
+}
+
+type imageVerifyCacheMetrics struct {
+	lookups metric.Int64Counter
+
+	logger logr.Logger
+}
+
+type ImageVerifyCacheMetrics interface {
+	RecordLookup(ctx context.Context, policyType string, hit bool)
+}
+
+func (m *imageVerifyCacheMetrics) init(meter metric.Meter) {
+	var err error
+
+	m.lookups, err = meter.Int64Counter(
+		"kyverno_image_verify_cache_lookups",
+		metric.WithDescription("can be used to track the hit/miss ratio of the image verification cache"),
+	)
+	if err != nil {
+		m.logger.Error(err, "Failed to create instrument, kyverno_image_verify_cache_lookups")
+	}
+}
+
+func (m *imageVerifyCacheMetrics) RecordLookup(ctx context.Context, policyType string, hit bool) {
+	if m.lookups == nil {
+		return
+	}
+
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	m.lookups.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("policy_type", policyType),
+		attribute.String("result", result),
+	))
+}
@@ -48,6 +48,8 @@ type MetricsConfig struct {
 	eventMetrics        *eventMetrics
 	admissionMetrics    *admissionMetrics
 	httpMetrics         *httpMetrics
+	celHTTPMetrics      *celHTTPMetrics
+	ivCacheMetrics      *imageVerifyCacheMetrics
 	vpolMetrics         *validatingMetrics
 	ivpolMetrics        *imageValidatingMetrics
 	mpolMetrics         *mutatingMetrics
@@ -72,6 +74,8 @@ type MetricsConfigManager interface {
 	EventMetrics() EventMetrics
 	AdmissionMetrics() AdmissionMetrics
 	HTTPMetrics() HTTPMetrics
+	CELHTTPMetrics() CELHTTPMetrics
+	ImageVerifyCacheMetrics() ImageVerifyCacheMetrics
 	VPOLMetrics() ValidatingMetrics
 	IVPOLMetrics() ImageValidatingMetrics
 	MPOLMetrics() MutatingMetrics
@@ -122,6 +126,14 @@ func (m *MetricsConfig) HTTPMetrics() HTTPMetrics {
 	return m.httpMetrics
 }
 
+func (m *MetricsConfig) CELHTTPMetrics() CELHTTPMetrics {
+	return m.celHTTPMetrics
+}
+
+func (m *MetricsConfig) ImageVerifyCacheMetrics() ImageVerifyCacheMetrics {
+	return m.ivCacheMetrics
+}
+
 func (m *MetricsConfig) VPOLMetrics() ValidatingMetrics {
 	return m.vpolMetrics
 }
@@ -173,6 +185,8 @@ func (m *MetricsConfig) initializeMetrics(meterProvider metric.MeterProvider) er
 	m.eventMetrics.init(meter)
 	m.admissionMetrics.init(meter)
 	m.httpMetrics.init(meter)
+	m.celHTTPMetrics.init(meter)
+	m.ivCacheMetrics.init(meter)
 	m.vpolMetrics.init(meter)
 	m.ivpolMetrics.init(meter)
 	m.mpolMetrics.init(meter)
@@ -322,6 +336,8 @@ func NewMetricsConfigManager(logger logr.Logger, metricsConfiguration kconfig.Me
 		eventMetrics:        &eventMetrics{logger: logger.WithName("event")},
 		admissionMetrics:    &admissionMetrics{logger: logger.WithName("admission")},
 		httpMetrics:         &httpMetrics{logger: logger.WithName("http")},
+		celHTTPMetrics:      &celHTTPMetrics{logger: logger.WithName("cel-http")},
+		ivCacheMetrics:      &imageVerifyCacheMetrics{logger: logger.WithName("image-verify-cache")},
 		vpolMetrics:         &validatingMetrics{logger: logger.WithName("validating-policy")},
 		ivpolMetrics:        &imageValidatingMetrics{logger: logger.WithName("image-validating-policy")},
 		mpolMetrics:         &mutatingMetrics{logger: logger.WithName("mutating-policy")},
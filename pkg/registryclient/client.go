@@ -10,6 +10,7 @@ import (
 	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
 	gcrremote "github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/kyverno/kyverno/pkg/breaker"
 	"github.com/kyverno/kyverno/pkg/imageverification/imagedataloader"
 	"github.com/kyverno/kyverno/pkg/tracing"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
@@ -143,7 +144,7 @@ func WithTracing() Option {
 func (c *client) Options(ctx context.Context) ([]gcrremote.Option, error) {
 	opts := []gcrremote.Option{
 		gcrremote.WithAuthFromKeychain(c.keychain),
-		gcrremote.WithTransport(c.transport),
+		gcrremote.WithTransport(breaker.Transport("registry", c.transport)),
 		gcrremote.WithContext(ctx),
 		gcrremote.WithUserAgent(userAgent),
 	}
@@ -0,0 +1,181 @@
+package credentials
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+)
+
+// DefaultTokenDir is the directory where projected service account tokens are mounted by default.
+const DefaultTokenDir = "/var/run/secrets/tokens"
+
+var (
+	ErrNoProvider = errors.New("no credentials provider configured")
+	// ErrNotAllowed is returned when a secret or audience is not in the allow-list configured by the administrator.
+	ErrNotAllowed = errors.New("not allowed by the service call credentials allow-list")
+)
+
+// Provider resolves the credentials used to authenticate outgoing service calls.
+// Credentials are re-read when the underlying secret or token file changes.
+type Provider interface {
+	// ClientCertificate returns the client certificate stored in the given kubernetes.io/tls secret.
+	ClientCertificate(secret string) (*tls.Certificate, error)
+	// Token returns the projected service account token issued for the given audience.
+	Token(audience string) (string, error)
+}
+
+type cachedCertificate struct {
+	resourceVersion string
+	certificate     *tls.Certificate
+}
+
+type cachedToken struct {
+	modTime time.Time
+	size    int64
+	token   string
+}
+
+type provider struct {
+	secretLister corev1listers.SecretNamespaceLister
+	tokenDir     string
+	secrets      sets.Set[string]
+	audiences    sets.Set[string]
+	lock         sync.Mutex
+	certificates map[string]cachedCertificate
+	tokens       map[string]cachedToken
+}
+
+// NewProvider returns a Provider reading client certificates from secrets in the given lister
+// and projected tokens from the given directory.
+// Tokens are read from the file named after the audience, with characters other than letters,
+// digits, '.', '-' and '_' replaced by '_'.
+// Policies can reference any destination, only the given secrets and audiences can be used,
+// nothing is allowed if they are empty.
+func NewProvider(secretLister corev1listers.SecretNamespaceLister, tokenDir string, secrets []string, audiences []string) Provider {
+	if tokenDir == "" {
+		tokenDir = DefaultTokenDir
+	}
+	return &provider{
+		secretLister: secretLister,
+		tokenDir:     tokenDir,
+		secrets:      sets.New(secrets...),
+		audiences:    sets.New(audiences...),
+		certificates: map[string]cachedCertificate{},
+		tokens:       map[string]cachedToken{},
+	}
+}
+
+func (p *provider) ClientCertificate(name string) (*tls.Certificate, error) {
+	if !p.secrets.Has(name) {
+		return nil, fmt.Errorf("failed to get client certificate secret %s: %w", name, ErrNotAllowed)
+	}
+	if p.secretLister == nil {
+		return nil, fmt.Errorf("failed to get client certificate secret %s: secrets are not available", name)
+	}
+	secret, err := p.secretLister.Get(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client certificate secret %s: %w", name, err)
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if cached, ok := p.certificates[name]; ok && cached.resourceVersion == secret.ResourceVersion {
+		return cached.certificate, nil
+	}
+	certificate, err := tls.X509KeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse client certificate secret %s: %w", name, err)
+	}
+	p.certificates[name] = cachedCertificate{
+		resourceVersion: secret.ResourceVersion,
+		certificate:     &certificate,
+	}
+	return &certificate, nil
+}
+
+func (p *provider) Token(audience string) (string, error) {
+	if audience == "" {
+		return "", fmt.Errorf("token audience must not be empty")
+	}
+	if !p.audiences.Has(audience) {
+		return "", fmt.Errorf("failed to get token for audience %s: %w", audience, ErrNotAllowed)
+	}
+	path := filepath.Join(p.tokenDir, TokenFileName(audience))
+	// projected volumes are updated atomically, the file is re-read when it changes
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read token for audience %s: %w", audience, err)
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if cached, ok := p.tokens[audience]; ok && cached.modTime.Equal(info.ModTime()) && cached.size == info.Size() {
+		return cached.token, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read token for audience %s: %w", audience, err)
+	}
+	token := string(bytes.TrimSpace(data))
+	p.tokens[audience] = cachedToken{
+		modTime: info.ModTime(),
+		size:    info.Size(),
+		token:   token,
+	}
+	return token, nil
+}
+
+// TokenFileName returns the name of the file holding the projected token for the given audience.
+func TokenFileName(audience string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, audience)
+	// never escape the token directory
+	if name == "." || name == ".." {
+		name = strings.Repeat("_", len(name))
+	}
+	return name
+}
+
+// TLSConfig returns a TLS configuration trusting the given CA bundle (or the system roots if empty) and
+// presenting the client certificate stored in the given secret (if not empty).
+// The client certificate is resolved on every handshake so that rotated secrets are picked up by existing clients.
+func TLSConfig(provider Provider, caBundle string, clientCertSecret string) (*tls.Config, error) {
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+	if caBundle != "" {
+		caCertPool := x509.NewCertPool()
+		if ok := caCertPool.AppendCertsFromPEM([]byte(caBundle)); !ok {
+			return nil, fmt.Errorf("failed to parse PEM CA bundle")
+		}
+		config.RootCAs = caCertPool
+	}
+	if clientCertSecret != "" {
+		if provider == nil {
+			return nil, fmt.Errorf("failed to load client certificate secret %s: %w", clientCertSecret, ErrNoProvider)
+		}
+		// fail early if the certificate can't be loaded
+		if _, err := provider.ClientCertificate(clientCertSecret); err != nil {
+			return nil, err
+		}
+		config.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return provider.ClientCertificate(clientCertSecret)
+		}
+	}
+	return config, nil
+}
@@ -0,0 +1,150 @@
+package credentials
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func newKeyPair(t *testing.T, commonName string) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+}
+
+func newSecret(t *testing.T, resourceVersion, commonName string) *corev1.Secret {
+	cert, key := newKeyPair(t, commonName)
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "client", Namespace: "kyverno", ResourceVersion: resourceVersion},
+		Type:       corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       cert,
+			corev1.TLSPrivateKeyKey: key,
+		},
+	}
+}
+
+func commonName(t *testing.T, certificate []byte) string {
+	cert, err := x509.ParseCertificate(certificate)
+	assert.NoError(t, err)
+	return cert.Subject.CommonName
+}
+
+func TestProvider_ClientCertificate(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	assert.NoError(t, indexer.Add(newSecret(t, "1", "first")))
+	p := NewProvider(corev1listers.NewSecretLister(indexer).Secrets("kyverno"), "", []string{"client", "missing"}, nil)
+	certificate, err := p.ClientCertificate("client")
+	assert.NoError(t, err)
+	assert.Equal(t, "first", commonName(t, certificate.Certificate[0]))
+	// the certificate is reloaded when the secret changes
+	assert.NoError(t, indexer.Update(newSecret(t, "2", "second")))
+	certificate, err = p.ClientCertificate("client")
+	assert.NoError(t, err)
+	assert.Equal(t, "second", commonName(t, certificate.Certificate[0]))
+	_, err = p.ClientCertificate("missing")
+	assert.Error(t, err)
+	// secrets not in the allow-list are rejected even if they exist
+	assert.NoError(t, indexer.Add(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "kyverno"}}))
+	_, err = p.ClientCertificate("other")
+	assert.ErrorIs(t, err, ErrNotAllowed)
+	// secrets without a key pair are rejected
+	assert.NoError(t, indexer.Update(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "client", Namespace: "kyverno", ResourceVersion: "3"}}))
+	_, err = p.ClientCertificate("client")
+	assert.Error(t, err)
+}
+
+func TestProvider_Token(t *testing.T) {
+	dir := t.TempDir()
+	p := NewProvider(nil, dir, nil, []string{"https://vault.example.com"})
+	_, err := p.Token("https://vault.example.com")
+	assert.Error(t, err)
+	path := filepath.Join(dir, "https___vault.example.com")
+	assert.NoError(t, os.WriteFile(path, []byte("first\n"), 0o600))
+	// audiences not in the allow-list are rejected even if a token exists
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "other"), []byte("other"), 0o600))
+	_, err = p.Token("other")
+	assert.ErrorIs(t, err, ErrNotAllowed)
+	token, err := p.Token("https://vault.example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, "first", token)
+	// the token is reloaded when the file changes
+	assert.NoError(t, os.WriteFile(path, []byte("second-token"), 0o600))
+	assert.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(time.Minute)))
+	token, err = p.Token("https://vault.example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, "second-token", token)
+	_, err = p.Token("")
+	assert.Error(t, err)
+}
+
+func TestTokenFileName(t *testing.T) {
+	tests := []struct {
+		audience string
+		want     string
+	}{{
+		audience: "vault",
+		want:     "vault",
+	}, {
+		audience: "https://vault.example.com:8200",
+		want:     "https___vault.example.com_8200",
+	}, {
+		audience: "../token",
+		want:     ".._token",
+	}, {
+		audience: "..",
+		want:     "__",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.audience, func(t *testing.T) {
+			assert.Equal(t, tt.want, TokenFileName(tt.audience))
+		})
+	}
+}
+
+func TestTLSConfig(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	assert.NoError(t, indexer.Add(newSecret(t, "1", "first")))
+	p := NewProvider(corev1listers.NewSecretLister(indexer).Secrets("kyverno"), "", []string{"client", "missing"}, nil)
+	config, err := TLSConfig(nil, "", "")
+	assert.NoError(t, err)
+	assert.Nil(t, config.RootCAs)
+	assert.Nil(t, config.GetClientCertificate)
+	_, err = TLSConfig(nil, "invalid", "")
+	assert.Error(t, err)
+	_, err = TLSConfig(nil, "", "client")
+	assert.ErrorIs(t, err, ErrNoProvider)
+	_, err = TLSConfig(p, "", "missing")
+	assert.Error(t, err)
+	config, err = TLSConfig(p, "", "client")
+	assert.NoError(t, err)
+	assert.NoError(t, indexer.Update(newSecret(t, "2", "second")))
+	certificate, err := config.GetClientCertificate(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "second", commonName(t, certificate.Certificate[0]))
+}
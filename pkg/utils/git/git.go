@@ -1,6 +1,7 @@
 package git
 
 import (
+	"context"
 	"fmt"
 	"io/fs"
 	"os"
@@ -14,6 +15,11 @@ import (
 )
 
 func Clone(path string, fs billy.Filesystem, branch string, auth http.BasicAuth) (*git.Repository, error) {
+	return CloneContext(context.Background(), path, fs, branch, auth)
+}
+
+// CloneContext is like Clone, the clone is aborted when ctx is done.
+func CloneContext(ctx context.Context, path string, fs billy.Filesystem, branch string, auth http.BasicAuth) (*git.Repository, error) {
 	co := &git.CloneOptions{
 		URL:           path,
 		ReferenceName: plumbing.ReferenceName(fmt.Sprintf("refs/heads/%s", branch)),
@@ -24,7 +30,7 @@ func Clone(path string, fs billy.Filesystem, branch string, auth http.BasicAuth)
 	if auth.Username != "" && auth.Password != "" {
 		co.Auth = &auth
 	}
-	return git.Clone(memory.NewStorage(), fs, co)
+	return git.CloneContext(ctx, memory.NewStorage(), fs, co)
 }
 
 func ListFiles(fs billy.Filesystem, path string, predicate func(fs.FileInfo) bool) ([]string, error) {
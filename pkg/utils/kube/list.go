@@ -0,0 +1,54 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/tools/pager"
+)
+
+// ListPageSize is the maximum number of resources fetched per request when listing resources page by page.
+const ListPageSize = 500
+
+// EachResource lists resources page by page and calls fn for every resource, only one page is held in memory at a time.
+func EachResource(ctx context.Context, client dynamic.ResourceInterface, options metav1.ListOptions, fn func(unstructured.Unstructured) error) error {
+	p := pager.New(pager.SimplePageFunc(func(options metav1.ListOptions) (runtime.Object, error) {
+		return client.List(ctx, options)
+	}))
+	p.PageSize = ListPageSize
+	return p.EachListItemWithAlloc(ctx, options, func(obj runtime.Object) error {
+		resource, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			return fmt.Errorf("unexpected list item type %T", obj)
+		}
+		return fn(*resource)
+	})
+}
+
+// EachMetadata lists the metadata of resources page by page and calls fn for every resource, only one page is held in memory at a time.
+// Resources are returned as unstructured objects holding the metadata of the resources, with their api version and kind set to gvk.
+func EachMetadata(ctx context.Context, client metadata.ResourceInterface, gvk schema.GroupVersionKind, options metav1.ListOptions, fn func(unstructured.Unstructured) error) error {
+	p := pager.New(pager.SimplePageFunc(func(options metav1.ListOptions) (runtime.Object, error) {
+		return client.List(ctx, options)
+	}))
+	p.PageSize = ListPageSize
+	return p.EachListItemWithAlloc(ctx, options, func(obj runtime.Object) error {
+		partial, ok := obj.(*metav1.PartialObjectMetadata)
+		if !ok {
+			return fmt.Errorf("unexpected list item type %T", obj)
+		}
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&partial.ObjectMeta)
+		if err != nil {
+			return err
+		}
+		resource := unstructured.Unstructured{Object: map[string]any{"metadata": content}}
+		resource.SetGroupVersionKind(gvk)
+		return fn(resource)
+	})
+}
@@ -0,0 +1,111 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/metadata"
+)
+
+// page returns the bounds of the page requested by the list options and the continue token of the next page
+func page(options metav1.ListOptions, count int) (int, int, string) {
+	start := 0
+	if options.Continue != "" {
+		start, _ = strconv.Atoi(options.Continue)
+	}
+	end := min(start+int(options.Limit), count)
+	if options.Limit == 0 {
+		end = count
+	}
+	if end < count {
+		return start, end, strconv.Itoa(end)
+	}
+	return start, end, ""
+}
+
+type pagedResourceClient struct {
+	dynamic.ResourceInterface
+	count    int
+	requests int
+}
+
+func (c *pagedResourceClient) List(_ context.Context, options metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	c.requests++
+	start, end, next := page(options, c.count)
+	list := &unstructured.UnstructuredList{}
+	for i := start; i < end; i++ {
+		resource := unstructured.Unstructured{}
+		resource.SetAPIVersion("v1")
+		resource.SetKind("ConfigMap")
+		resource.SetName(fmt.Sprintf("cm-%d", i))
+		list.Items = append(list.Items, resource)
+	}
+	list.SetContinue(next)
+	return list, nil
+}
+
+type pagedMetadataClient struct {
+	metadata.ResourceInterface
+	count    int
+	requests int
+}
+
+func (c *pagedMetadataClient) List(_ context.Context, options metav1.ListOptions) (*metav1.PartialObjectMetadataList, error) {
+	c.requests++
+	start, end, next := page(options, c.count)
+	list := &metav1.PartialObjectMetadataList{ListMeta: metav1.ListMeta{Continue: next}}
+	for i := start; i < end; i++ {
+		list.Items = append(list.Items, metav1.PartialObjectMetadata{
+			TypeMeta: metav1.TypeMeta{APIVersion: "meta.k8s.io/v1", Kind: "PartialObjectMetadata"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("cm-%d", i),
+				Namespace: "default",
+				Labels:    map[string]string{"app": "test"},
+			},
+		})
+	}
+	return list, nil
+}
+
+func TestEachResource(t *testing.T) {
+	client := &pagedResourceClient{count: 1200}
+	var names []string
+	err := EachResource(context.Background(), client, metav1.ListOptions{}, func(resource unstructured.Unstructured) error {
+		names = append(names, resource.GetName())
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Len(t, names, 1200)
+	assert.Equal(t, "cm-1199", names[1199])
+	assert.Equal(t, 3, client.requests)
+}
+
+func TestEachMetadata(t *testing.T) {
+	client := &pagedMetadataClient{count: 600}
+	gvk := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+	var resources []unstructured.Unstructured
+	err := EachMetadata(context.Background(), client, gvk, metav1.ListOptions{}, func(resource unstructured.Unstructured) error {
+		resources = append(resources, resource)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Len(t, resources, 600)
+	assert.Equal(t, 2, client.requests)
+	assert.Equal(t, gvk, resources[0].GroupVersionKind())
+	assert.Equal(t, "cm-0", resources[0].GetName())
+	assert.Equal(t, "default", resources[0].GetNamespace())
+	assert.Equal(t, map[string]string{"app": "test"}, resources[0].GetLabels())
+
+	// an error returned by fn stops the listing
+	err = EachMetadata(context.Background(), client, gvk, metav1.ListOptions{}, func(resource unstructured.Unstructured) error {
+		return fmt.Errorf("stop")
+	})
+	assert.EqualError(t, err, "stop")
+}
@@ -0,0 +1,13 @@
+package oci
+
+// Media types and annotations of the OCI artifacts holding policies, as pushed with `kyverno oci push`.
+const (
+	PolicyConfigMediaType = "application/vnd.cncf.kyverno.config.v1+json"
+	PolicyLayerMediaType  = "application/vnd.cncf.kyverno.policy.layer.v1+yaml"
+	TestLayerMediaType    = "application/vnd.cncf.kyverno.test.layer.v1+yaml"
+	AnnotationKind        = "io.kyverno.image.kind"
+	AnnotationName        = "io.kyverno.image.name"
+	AnnotationApiVersion  = "io.kyverno.image.apiVersion"
+	// AnnotationPath is the path of the file a layer comes from, relative to the pushed directory
+	AnnotationPath = "io.kyverno.image.path"
+)
@@ -0,0 +1,91 @@
+package report
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	reportsv1 "github.com/kyverno/kyverno/api/reports/v1"
+	"github.com/kyverno/kyverno/pkg/client/clientset/versioned"
+	"github.com/kyverno/kyverno/pkg/openreports"
+	controllerutils "github.com/kyverno/kyverno/pkg/utils/controller"
+	openreportsv1alpha1 "github.com/openreports/reports-api/apis/openreports.io/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/cache"
+)
+
+// MaxDryRunResources is the maximum number of resources recorded in the status and the report of a policy by a dry run execution.
+const MaxDryRunResources = 100
+
+// DryRunReportName returns the name of the report holding the resources selected by the dry run execution of a policy.
+func DryRunReportName(kind, name string) string {
+	return "dryrun-" + strings.ToLower(kind) + "-" + name
+}
+
+// BuildDryRunReport builds a policy report with a result for each of the first MaxDryRunResources resources that the
+// dry run execution of a cleanup or deleting policy would have deleted, resources are expected to be sorted with
+// SortDryRunResources. The total count is recorded in the policy status. The report is created in the namespace of
+// the policy and owned by it.
+func BuildDryRunReport(policy metav1.Object, apiVersion, kind string, resources []unstructured.Unstructured) reportsv1.ReportInterface {
+	policyName, _ := cache.MetaNamespaceKeyFunc(policy)
+	timestamp := metav1.Timestamp{Seconds: time.Now().Unix()}
+	if len(resources) > MaxDryRunResources {
+		resources = resources[:MaxDryRunResources]
+	}
+	results := make([]openreportsv1alpha1.ReportResult, 0, len(resources))
+	for _, resource := range resources {
+		results = append(results, openreportsv1alpha1.ReportResult{
+			Source:      SourceKyverno,
+			Policy:      policyName,
+			Description: "dry run: the resource would have been deleted",
+			Result:      openreports.StatusWarn,
+			Timestamp:   timestamp,
+			Subjects: []corev1.ObjectReference{{
+				APIVersion: resource.GetAPIVersion(),
+				Kind:       resource.GetKind(),
+				Namespace:  resource.GetNamespace(),
+				Name:       resource.GetName(),
+				UID:        resource.GetUID(),
+			}},
+		})
+	}
+	report := NewPolicyReport(policy.GetNamespace(), DryRunReportName(kind, policy.GetName()), nil, false, results...)
+	controllerutils.SetOwner(report, apiVersion, kind, policy.GetName(), policy.GetUID())
+	return report
+}
+
+// ApplyDryRunReport creates the dry run report of a policy, or replaces the results of the report of a previous execution.
+func ApplyDryRunReport(ctx context.Context, report reportsv1.ReportInterface, client versioned.Interface) (reportsv1.ReportInterface, error) {
+	var existing metav1.Object
+	var err error
+	if report.GetNamespace() == "" {
+		existing, err = client.Wgpolicyk8sV1alpha2().ClusterPolicyReports().Get(ctx, report.GetName(), metav1.GetOptions{})
+	} else {
+		existing, err = client.Wgpolicyk8sV1alpha2().PolicyReports(report.GetNamespace()).Get(ctx, report.GetName(), metav1.GetOptions{})
+	}
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return CreatePermanentReport(ctx, report, client, nil)
+		}
+		return nil, err
+	}
+	report.SetResourceVersion(existing.GetResourceVersion())
+	return UpdateReport(ctx, report, client, nil)
+}
+
+// SortDryRunResources sorts the resources selected by a dry run execution by api version, kind, namespace and name.
+func SortDryRunResources(resources []unstructured.Unstructured) {
+	slices.SortFunc(resources, func(a, b unstructured.Unstructured) int {
+		return cmp.Or(
+			cmp.Compare(a.GetAPIVersion(), b.GetAPIVersion()),
+			cmp.Compare(a.GetKind(), b.GetKind()),
+			cmp.Compare(a.GetNamespace(), b.GetNamespace()),
+			cmp.Compare(a.GetName(), b.GetName()),
+		)
+	})
+}
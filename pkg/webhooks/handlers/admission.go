@@ -9,6 +9,7 @@ import (
 
 	"github.com/go-logr/logr"
 	"github.com/julienschmidt/httprouter"
+	httplib "github.com/kyverno/kyverno/pkg/cel/libs/http"
 	admissionv1 "k8s.io/api/admission/v1"
 )
 
@@ -54,7 +55,9 @@ func (inner AdmissionHandler) withAdmission(logger logr.Logger) HttpHandler {
 			AdmissionRequest: *admissionReview.Request,
 			URLParams:        params.ByName("policy"),
 		}
-		admissionResponse := inner(request.Context(), logger, admissionRequest, startTime)
+		// responses cached with the request scope are shared by the policies evaluated for this request
+		ctx := httplib.WithRequestCache(request.Context())
+		admissionResponse := inner(ctx, logger, admissionRequest, startTime)
 		admissionReview.Response = &admissionResponse
 		responseJSON, err := json.Marshal(admissionReview)
 		if err != nil {
@@ -7,10 +7,15 @@ import (
 
 	"github.com/go-logr/logr"
 	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/cel/libs/http"
 	dpolvalidation "github.com/kyverno/kyverno/pkg/cel/policies/dpol"
+	dpolcompiler "github.com/kyverno/kyverno/pkg/cel/policies/dpol/compiler"
 	gpolvalidation "github.com/kyverno/kyverno/pkg/cel/policies/gpol"
+	gpolcompiler "github.com/kyverno/kyverno/pkg/cel/policies/gpol/compiler"
 	mpolvalidation "github.com/kyverno/kyverno/pkg/cel/policies/mpol"
+	mpolcompiler "github.com/kyverno/kyverno/pkg/cel/policies/mpol/compiler"
 	vpolvalidation "github.com/kyverno/kyverno/pkg/cel/policies/vpol"
+	vpolcompiler "github.com/kyverno/kyverno/pkg/cel/policies/vpol/compiler"
 	"github.com/kyverno/kyverno/pkg/clients/dclient"
 	eval "github.com/kyverno/kyverno/pkg/imageverification/evaluator"
 	admissionutils "github.com/kyverno/kyverno/pkg/utils/admission"
@@ -22,13 +27,15 @@ type policyHandlers struct {
 	client                       dclient.Interface
 	backgroundServiceAccountName string
 	reportsServiceAccountName    string
+	httpConfig                   *http.Config
 }
 
-func NewHandlers(client dclient.Interface, backgroundSA, reportsSA string) *policyHandlers {
+func NewHandlers(client dclient.Interface, backgroundSA, reportsSA string, httpConfig *http.Config) *policyHandlers {
 	return &policyHandlers{
 		client:                       client,
 		backgroundServiceAccountName: backgroundSA,
 		reportsServiceAccountName:    reportsSA,
+		httpConfig:                   httpConfig,
 	}
 }
 
@@ -40,7 +47,7 @@ func (h *policyHandlers) Validate(ctx context.Context, logger logr.Logger, reque
 	}
 
 	if vpol := policy.AsValidatingPolicy(); vpol != nil {
-		warnings, err := vpolvalidation.Validate(vpol)
+		warnings, err := vpolvalidation.Validate(vpol, vpolcompiler.WithHTTPConfig(h.httpConfig))
 		if err != nil {
 			logger.Error(err, "ValidatingPolicy validation errors")
 		}
@@ -48,7 +55,7 @@ func (h *policyHandlers) Validate(ctx context.Context, logger logr.Logger, reque
 	}
 
 	if nvpol := policy.AsNamespacedValidatingPolicy(); nvpol != nil {
-		warnings, err := vpolvalidation.Validate(nvpol)
+		warnings, err := vpolvalidation.Validate(nvpol, vpolcompiler.WithHTTPConfig(h.httpConfig))
 		if err != nil {
 			logger.Error(err, "NamespacedValidatingPolicy validation errors")
 		}
@@ -56,7 +63,7 @@ func (h *policyHandlers) Validate(ctx context.Context, logger logr.Logger, reque
 	}
 
 	if ivpol := policy.AsImageValidatingPolicy(); ivpol != nil {
-		warnings, err := eval.Validate(ivpol, h.client.GetKubeClient().CoreV1().Secrets(""))
+		warnings, err := eval.Validate(ivpol, h.client.GetKubeClient().CoreV1().Secrets(""), eval.WithHTTPConfig(h.httpConfig))
 		if err != nil {
 			logger.Error(err, "ImageValidatingPolicy validation errors")
 		}
@@ -64,7 +71,7 @@ func (h *policyHandlers) Validate(ctx context.Context, logger logr.Logger, reque
 	}
 
 	if nivpol := policy.AsNamespacedImageValidatingPolicy(); nivpol != nil {
-		warnings, err := eval.Validate(policy.AsNamespacedImageValidatingPolicy(), h.client.GetKubeClient().CoreV1().Secrets(""))
+		warnings, err := eval.Validate(policy.AsNamespacedImageValidatingPolicy(), h.client.GetKubeClient().CoreV1().Secrets(""), eval.WithHTTPConfig(h.httpConfig))
 		if err != nil {
 			logger.Error(err, "NamespacedImageValidatingPolicy validation errors")
 		}
@@ -72,7 +79,7 @@ func (h *policyHandlers) Validate(ctx context.Context, logger logr.Logger, reque
 	}
 
 	if mpol := policy.AsMutatingPolicy(); mpol != nil {
-		warnings, err := mpolvalidation.Validate(mpol)
+		warnings, err := mpolvalidation.Validate(mpol, mpolcompiler.WithHTTPConfig(h.httpConfig))
 		if err != nil {
 			logger.Error(err, "MutatingPolicy validation errors")
 		}
@@ -80,7 +87,7 @@ func (h *policyHandlers) Validate(ctx context.Context, logger logr.Logger, reque
 	}
 
 	if nmpol := policy.AsNamespacedMutatingPolicy(); nmpol != nil {
-		warnings, err := mpolvalidation.Validate(nmpol)
+		warnings, err := mpolvalidation.Validate(nmpol, mpolcompiler.WithHTTPConfig(h.httpConfig))
 		if err != nil {
 			logger.Error(err, "NamespacedMutatingPolicy validation errors")
 		}
@@ -88,7 +95,7 @@ func (h *policyHandlers) Validate(ctx context.Context, logger logr.Logger, reque
 	}
 
 	if gpol := policy.AsGeneratingPolicy(); gpol != nil {
-		warnings, err := gpolvalidation.Validate(gpol)
+		warnings, err := gpolvalidation.Validate(gpol, gpolcompiler.WithHTTPConfig(h.httpConfig))
 		if err != nil {
 			logger.Error(err, "GeneratingPolicy validation errors")
 		}
@@ -96,7 +103,7 @@ func (h *policyHandlers) Validate(ctx context.Context, logger logr.Logger, reque
 	}
 
 	if ngpol := policy.AsNamespacedGeneratingPolicy(); ngpol != nil {
-		warnings, err := gpolvalidation.Validate(ngpol)
+		warnings, err := gpolvalidation.Validate(ngpol, gpolcompiler.WithHTTPConfig(h.httpConfig))
 		if err != nil {
 			logger.Error(err, "NamespacedGeneratingPolicy validation errors")
 		}
@@ -104,7 +111,7 @@ func (h *policyHandlers) Validate(ctx context.Context, logger logr.Logger, reque
 	}
 
 	if dpol := policy.AsDeletingPolicy(); dpol != nil {
-		warnings, err := dpolvalidation.Validate(dpol)
+		warnings, err := dpolvalidation.Validate(dpol, dpolcompiler.WithHTTPConfig(h.httpConfig))
 		if err != nil {
 			logger.Error(err, "DeletingPolicy validation errors")
 		}